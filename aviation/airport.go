@@ -41,9 +41,24 @@ type Airport struct {
 	CRDARegions map[string]*CRDARegion `json:"crda_regions"`
 	CRDAPairs   []CRDAPair             `json:"crda_pairs"`
 
+	NTZs []NTZ `json:"ntzs,omitempty"`
+
 	ATPAVolumes           map[string]*ATPAVolume `json:"atpa_volumes"`
 	OmitArrivalScratchpad bool                   `json:"omit_arrival_scratchpad"`
 	DepartureRunwaysAsOne []string               `json:"departure_runways_as_one"`
+
+	Metering *Metering `json:"metering,omitempty"`
+}
+
+// Metering specifies time-based flow management (TBFM) for arrivals to an
+// airport: arrivals are scheduled to cross the meter fixes no more often
+// than the airport's acceptance rate allows.
+type Metering struct {
+	MeterFixes     []string `json:"meter_fixes"`
+	AcceptanceRate int      `json:"acceptance_rate"` // arrivals per hour
+	// Minutes before reaching the meter fix at which an arrival's
+	// scheduled time is frozen.
+	FreezeHorizon int `json:"freeze_horizon_minutes"`
 }
 
 type VFRRandomsSpec struct {
@@ -79,6 +94,96 @@ type CRDAPair struct {
 	ConvergencePoint      math.Point2LL
 }
 
+// NTZ is a No Transgression Zone between the finals of two parallel
+// runways that are used for simultaneous independent approaches. The
+// zone is centered between the two final approach courses and starts
+// abeam the nearer threshold; a monitor controller is alerted when a
+// track enters it.
+type NTZ struct {
+	Runways   [2]string `json:"runways"`
+	Length    float32   `json:"length"`     // nm, default 10
+	WidthFeet float32   `json:"width_feet"` // default 2000
+	// Monitor is the final monitor position, which may break out aircraft
+	// on either final even though it isn't talking to them.
+	Monitor ControlPosition `json:"monitor,omitempty"`
+
+	// Set during deserialize.
+	Polygon [4]math.Point2LL
+}
+
+// Inside returns true if the given point is inside the NTZ.
+func (n *NTZ) Inside(p math.Point2LL) bool {
+	return math.PointInPolygon2LL(p, n.Polygon[:])
+}
+
+// HasRunway returns true if the given runway is one of the NTZ's runways.
+func (n *NTZ) HasRunway(rwy string) bool {
+	return n.Runways[0] == rwy || n.Runways[1] == rwy
+}
+
+// OtherRunway returns the NTZ runway other than the given one.
+func (n *NTZ) OtherRunway(rwy string) string {
+	if n.Runways[0] == rwy {
+		return n.Runways[1]
+	}
+	return n.Runways[0]
+}
+
+func (n *NTZ) initialize(icao string, nmPerLongitude, magneticVariation float32, e *util.ErrorLogger) {
+	if n.Length == 0 {
+		n.Length = 10
+	}
+	if n.WidthFeet == 0 {
+		n.WidthFeet = 2000
+	}
+
+	if n.Runways[0] == n.Runways[1] {
+		e.ErrorString("must specify two different runways")
+		return
+	}
+	var rwys [2]Runway
+	for i, id := range n.Runways {
+		rwy, ok := LookupRunway(icao, id)
+		if !ok {
+			e.ErrorString("runway %q is unknown. Options: %s", id, DB.Airports[icao].ValidRunways())
+			return
+		}
+		rwys[i] = rwy
+	}
+	if math.HeadingDifference(rwys[0].Heading, rwys[1].Heading) > 15 {
+		e.ErrorString("runways %s and %s are not parallel", n.Runways[0], n.Runways[1])
+		return
+	}
+
+	// Work in nm coordinates with the mean final approach course; d is
+	// the direction aircraft fly toward the runways and perp is
+	// perpendicular to it.
+	hdg := math.MagneticToTrue(rwys[0].Heading, magneticVariation)
+	d := math.SinCos(math.Radians(float32(hdg)))
+	perp := [2]float32{d[1], -d[0]}
+
+	p0 := math.LL2NM(rwys[0].Threshold, nmPerLongitude)
+	p1 := math.LL2NM(rwys[1].Threshold, nmPerLongitude)
+	mid := math.Scale2f(math.Add2f(p0, p1), 0.5)
+
+	width := n.WidthFeet * math.FeetToNauticalMiles
+	if spacing := math.Abs(math.Dot(math.Sub2f(p0, p1), perp)); spacing <= width {
+		e.ErrorString("runway centerlines are %.0f feet apart, which isn't more than the NTZ width",
+			spacing*math.NauticalMilesToFeet)
+		return
+	}
+
+	// Start abeam whichever threshold is farther out along the final.
+	start := math.Scale2f(d, min(math.Dot(math.Sub2f(p0, mid), d), math.Dot(math.Sub2f(p1, mid), d)))
+	start = math.Add2f(mid, start)
+	end := math.Sub2f(start, math.Scale2f(d, n.Length))
+	hw := math.Scale2f(perp, width/2)
+
+	for i, p := range [4][2]float32{math.Add2f(start, hw), math.Add2f(end, hw), math.Sub2f(end, hw), math.Sub2f(start, hw)} {
+		n.Polygon[i] = math.NM2LL(p, nmPerLongitude)
+	}
+}
+
 type GhostTrack struct {
 	ADSBCallsign        ADSBCallsign
 	Position            math.Point2LL
@@ -267,6 +372,14 @@ func (ap *Airport) PostDeserialize(icao string, loc Locator, nmPerLongitude floa
 				for _, wps := range dbAppr.Waypoints {
 					appr.Waypoints = append(appr.Waypoints, deep.MustCopy(wps))
 				}
+				if dbAppr.MissedApproach != nil {
+					if appr.MissedApproach != nil {
+						e.ErrorString(`"missed_approach" cannot be given with "cifp_id" approaches`)
+					} else {
+						ma := deep.MustCopy(*dbAppr.MissedApproach)
+						appr.MissedApproach = &ma
+					}
+				}
 			}
 		} else {
 			if appr.Type == UnknownApproach {
@@ -660,6 +773,17 @@ func (ap *Airport) PostDeserialize(icao string, loc Locator, nmPerLongitude floa
 		e.Pop()
 	}
 
+	for i := range ap.NTZs {
+		e.Push("NTZ " + ap.NTZs[i].Runways[0] + "/" + ap.NTZs[i].Runways[1])
+		ap.NTZs[i].initialize(icao, nmPerLongitude, magneticVariation, e)
+		if m := ap.NTZs[i].Monitor; m != "" {
+			if _, ok := controlPositions[m]; !ok {
+				e.ErrorString("monitor %q unknown", m)
+			}
+		}
+		e.Pop()
+	}
+
 	// Generate reasonable default ATPA volumes for any runways they aren't
 	// specified for.
 	if ap.ATPAVolumes == nil {
@@ -723,6 +847,27 @@ func (ap *Airport) PostDeserialize(icao string, loc Locator, nmPerLongitude floa
 
 		e.Pop()
 	}
+
+	if m := ap.Metering; m != nil {
+		e.Push("metering")
+		if len(m.MeterFixes) == 0 {
+			e.ErrorString(`must specify at least one fix in "meter_fixes"`)
+		}
+		for _, fix := range m.MeterFixes {
+			if _, ok := loc.Locate(fix); !ok {
+				e.ErrorString("meter fix %q is unknown", fix)
+			}
+		}
+		if m.AcceptanceRate <= 0 || m.AcceptanceRate > 120 {
+			e.ErrorString(`"acceptance_rate" must be between 1 and 120 arrivals per hour`)
+		}
+		if m.FreezeHorizon < 0 {
+			e.ErrorString(`"freeze_horizon_minutes" cannot be negative`)
+		} else if m.FreezeHorizon == 0 {
+			m.FreezeHorizon = 20
+		}
+		e.Pop()
+	}
 }
 
 func (ap Airport) VFRRateSum() float32 {
@@ -849,6 +994,20 @@ func (at *ApproachType) UnmarshalJSON(b []byte) error {
 	}
 }
 
+// ApproachManeuver describes how an aircraft gets from the end of an
+// instrument approach to the runway it lands on.
+type ApproachManeuver int
+
+const (
+	StraightInLanding ApproachManeuver = iota // land on the approach's runway
+	CircleToLand                              // circle to land on another runway
+	SidestepManeuver                          // sidestep to a parallel runway
+)
+
+func (m ApproachManeuver) String() string {
+	return []string{"Straight-in", "Circle to land", "Sidestep"}[m]
+}
+
 type Approach struct {
 	Id        string          `json:"cifp_id"`
 	FullName  string          `json:"full_name"`
@@ -856,11 +1015,25 @@ type Approach struct {
 	Runway    string          `json:"runway"`
 	Waypoints []WaypointArray `json:"waypoints"`
 
+	MissedApproach *MissedApproach `json:"missed_approach,omitempty"`
+
 	// Set in Airport PostDeserialize()
 	Threshold         math.Point2LL
 	OppositeThreshold math.Point2LL
 }
 
+// MissedApproach is an approach's published missed approach procedure:
+// the route flown after the missed approach point and the hold, if any,
+// that it ends with.
+type MissedApproach struct {
+	// Heading is flown from the missed approach point when the
+	// procedure starts with a heading leg rather than a fix; 0 if unset.
+	Heading   int           `json:"heading"`
+	Altitude  int           `json:"altitude"`
+	Waypoints WaypointArray `json:"waypoints"`
+	Hold      *Hold         `json:"hold"`
+}
+
 // InitializeWaypoints resolves waypoint locations and adds the runway
 // threshold waypoint to each route. It also sets the OnApproach flag,
 // Threshold, and OppositeThreshold fields.
@@ -900,6 +1073,27 @@ func (ap *Approach) InitializeWaypoints(icao string, loc Locator, nmPerLongitude
 			ap.Waypoints[i][j].SetOnApproach(true)
 		}
 	}
+
+	if ma := ap.MissedApproach; ma != nil {
+		var mae util.ErrorLogger
+		ma.Waypoints = ma.Waypoints.InitializeLocations(loc, nmPerLongitude, magneticVariation, false, &mae)
+		if ma.Hold != nil && !slices.ContainsFunc(ma.Waypoints, func(wp Waypoint) bool { return wp.Fix == ma.Hold.Fix }) {
+			mae.ErrorString("hold fix %q is not in the missed approach route", ma.Hold.Fix)
+		}
+
+		if mae.HaveErrors() {
+			if ap.Id != "" {
+				// The CIFP missed approach may go to fixes that are
+				// outside of the scenario's area; aircraft use the
+				// scenario's go around procedure instead.
+				ap.MissedApproach = nil
+			} else {
+				e.Push("Missed approach")
+				e.MergeFrom(&mae)
+				e.Pop()
+			}
+		}
+	}
 }
 
 // Find the FAF: return the corresponding waypoint array and the index of the FAF within it.
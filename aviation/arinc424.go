@@ -1024,6 +1024,8 @@ func parseApproach(recs []ssaRecord, fixes map[string]Fix, navaids map[string]Na
 
 	markTBarNoPT(transitions, recs, fixes, navaids)
 
+	appr.MissedApproach = parseMissedApproach(recs)
+
 	if len(transitions) == 1 {
 		appr.Waypoints = []WaypointArray{transitions[""]}
 	} else {
@@ -1045,6 +1047,70 @@ func parseApproach(recs []ssaRecord, fixes map[string]Fix, navaids map[string]Na
 	return &appr
 }
 
+// parseMissedApproach returns the published missed approach from an
+// approach's records: the legs of the final approach segment that follow
+// the missed approach point, ending with the hold at the missed approach
+// fix, if there is one. nil is returned if there is no missed approach.
+func parseMissedApproach(recs []ssaRecord) *MissedApproach {
+	var ma MissedApproach
+	missed := false
+legs:
+	for _, rec := range recs {
+		if (rec.continuation != '0' && rec.continuation != '1') || rec.transition != "" {
+			continue
+		}
+		if !missed {
+			// The runway or the missed approach point is the last fix of
+			// the approach itself; everything after it is the missed
+			// approach.
+			missed = rec.waypointDescription[0] == 'G' || rec.waypointDescription[3] == 'M'
+			continue
+		}
+
+		if !empty(rec.alt0) {
+			ma.Altitude = max(ma.Altitude, parseAltitude(rec.alt0))
+		}
+
+		switch rec.pathAndTermination {
+		case "IF", "TF", "CF", "DF", "AF", "RF": // legs that end at a fix
+			if wp, arc, ok := rec.GetWaypoint(); ok && wp.Fix != "" {
+				if arc != nil && len(ma.Waypoints) > 0 {
+					ma.Waypoints[len(ma.Waypoints)-1].InitExtra().Arc = arc
+				}
+				ma.Waypoints = append(ma.Waypoints, wp)
+			}
+
+		case "FM", "VM": // heading until manually terminated
+			hdg := (parseInt(rec.outboundMagneticCourse) + 5) / 10
+			if n := len(ma.Waypoints); n > 0 {
+				ma.Waypoints[n-1].Heading = int16(hdg)
+			} else {
+				ma.Heading = hdg
+			}
+			break legs
+
+		case "HM", "HA", "HF": // hold at the missed approach fix
+			if h, ok := extractHoldsFromSSA(rec, rec.id, "IAP"); ok {
+				if n := len(ma.Waypoints); n == 0 || ma.Waypoints[n-1].Fix != h.Fix {
+					ma.Waypoints = append(ma.Waypoints, Waypoint{Fix: h.Fix})
+				}
+				ma.Hold = &h
+			}
+			break legs
+
+		default:
+			// Legs that terminate at an altitude, a DME distance, or by
+			// intercepting the next leg; the aircraft climbs and then
+			// proceeds to the next fix.
+		}
+	}
+
+	if !missed || (len(ma.Waypoints) == 0 && ma.Heading == 0) {
+		return nil
+	}
+	return &ma
+}
+
 // parseHoldingPattern extracts a holding pattern from an ARINC-424 record
 func parseHoldingPattern(line []byte) (Hold, bool) {
 	// Validate record type - must be 'S' (Standard)
@@ -212,3 +212,88 @@ func TestParseARINC424LocalizerDoesNotOverwriteDME(t *testing.T) {
 		t.Fatalf("expected preserved DME elevation 33, got %d", nav.DMEElevation)
 	}
 }
+
+// makeApproachRecord returns a KJFK I04R final approach segment record
+// with the given fields.
+func makeApproachRecord(seq, fix, desc string, turn byte, pathTerm, course, dist string, altDescrip byte, alt string) ssaRecord {
+	line := []byte(strings.Repeat(" ", 132))
+	copy(line[0:], "SUSAP KJFKK6FI04R  I")
+	copy(line[26:], seq)
+	copy(line[29:], fix)
+	line[38] = '0'
+	copy(line[39:], desc)
+	line[43] = turn
+	copy(line[47:], pathTerm)
+	copy(line[70:], course)
+	copy(line[74:], dist)
+	line[82] = altDescrip
+	copy(line[84:], alt)
+	return parseSSA(line)
+}
+
+func TestParseMissedApproach(t *testing.T) {
+	faf := makeApproachRecord("020", "ZALPO", "E  F", ' ', "CF", "0430", "0050", 'G', "01500")
+	rwy := makeApproachRecord("030", "RW04R", "GY M", ' ', "CF", "0430", "0040", ' ', "")
+
+	t.Run("hold at missed approach fix", func(t *testing.T) {
+		recs := []ssaRecord{
+			faf,
+			rwy,
+			makeApproachRecord("040", "", "    ", ' ', "CA", "0430", "", '+', "00500"),
+			makeApproachRecord("050", "DPK", "VY  ", ' ', "CF", "0410", "0080", '+', "04000"),
+			makeApproachRecord("060", "DPK", "VE  ", 'L', "HM", "2581", "T010", '+', "04000"),
+		}
+
+		appr := parseApproach(recs, nil, nil)
+		if len(appr.Waypoints) != 1 || len(appr.Waypoints[0]) != 1 || appr.Waypoints[0][0].Fix != "ZALPO" {
+			t.Errorf("expected approach route [ZALPO], got %+v", appr.Waypoints)
+		}
+
+		ma := appr.MissedApproach
+		if ma == nil {
+			t.Fatal("expected a missed approach")
+		}
+		if ma.Altitude != 4000 {
+			t.Errorf("expected missed approach altitude 4000, got %d", ma.Altitude)
+		}
+		if ma.Heading != 0 {
+			t.Errorf("expected no missed approach heading, got %d", ma.Heading)
+		}
+		if len(ma.Waypoints) != 1 || ma.Waypoints[0].Fix != "DPK" {
+			t.Errorf("expected missed approach route [DPK], got %s", ma.Waypoints.Encode())
+		}
+		want := Hold{
+			Fix:             "DPK",
+			InboundCourse:   258.1,
+			TurnDirection:   TurnLeft,
+			LegMinutes:      1,
+			MinimumAltitude: 4000,
+			Procedure:       "I04R",
+		}
+		if ma.Hold == nil || !holdsEqual(*ma.Hold, want) {
+			t.Errorf("expected hold %+v, got %+v", want, ma.Hold)
+		}
+	})
+
+	t.Run("heading leg", func(t *testing.T) {
+		recs := []ssaRecord{
+			faf,
+			rwy,
+			makeApproachRecord("040", "", "    ", ' ', "VM", "0400", "", '+', "03000"),
+		}
+
+		ma := parseMissedApproach(recs)
+		if ma == nil {
+			t.Fatal("expected a missed approach")
+		}
+		if ma.Heading != 40 || ma.Altitude != 3000 || len(ma.Waypoints) != 0 || ma.Hold != nil {
+			t.Errorf("expected heading 40 to 3000 with no route or hold, got %+v", ma)
+		}
+	})
+
+	t.Run("no missed approach", func(t *testing.T) {
+		if ma := parseMissedApproach([]ssaRecord{faf, rwy}); ma != nil {
+			t.Errorf("expected no missed approach, got %+v", ma)
+		}
+	})
+}
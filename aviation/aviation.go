@@ -1755,6 +1755,20 @@ func (r RadioTransmissionType) String() string {
 	}
 }
 
+///////////////////////////////////////////////////////////////////////////
+// Separation minima
+
+// Radar separation minima for IFR aircraft. Lateral separation may be
+// reduced to ReducedLateralSeparation when both aircraft are at or below
+// ReducedSeparationCeiling.
+const (
+	LateralSeparation        = 5.0   // nm
+	ReducedLateralSeparation = 3.0   // nm
+	ReducedSeparationCeiling = 23000 // ft; reduced separation airspace is at or below FL230
+	VerticalSeparation       = 1000  // ft
+	VerticalSeparationSlop   = 5     // ft; keeps aircraft exactly 1000' apart from being flagged due to float error
+)
+
 ///////////////////////////////////////////////////////////////////////////
 // CWT functions
 
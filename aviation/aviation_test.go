@@ -7,6 +7,7 @@ package aviation
 import (
 	"testing"
 
+	"github.com/mmp/vice/math"
 	"github.com/mmp/vice/rand"
 	"github.com/mmp/vice/util"
 )
@@ -325,3 +326,69 @@ func TestLocalSquawkCodePool(t *testing.T) {
 		t.Errorf("unexpected code %s", c)
 	}
 }
+
+func TestNTZGeometry(t *testing.T) {
+	oldDB := DB
+	t.Cleanup(func() { DB = oldDB })
+
+	const nmPerLongitude = 45
+	nm := func(x, y float32) math.Point2LL { return math.NM2LL([2]float32{x, y}, nmPerLongitude) }
+	DB = &StaticDatabase{Airports: map[string]FAAAirport{
+		"KTST": {
+			Id: "KTST",
+			Runways: []Runway{
+				{Id: "36L", Heading: 360, Threshold: nm(0, 0)},
+				{Id: "36R", Heading: 360, Threshold: nm(0.8, 0.5)},
+				{Id: "9", Heading: 90, Threshold: nm(-1, 2)},
+			},
+		},
+	}}
+
+	var e util.ErrorLogger
+	ntz := NTZ{Runways: [2]string{"36L", "36R"}}
+	ntz.initialize("KTST", nmPerLongitude, 0, &e)
+	if e.HaveErrors() {
+		t.Fatalf("unexpected errors: %s", e.String())
+	}
+	if ntz.Length != 10 || ntz.WidthFeet != 2000 {
+		t.Errorf("defaults not applied: length %f width %f", ntz.Length, ntz.WidthFeet)
+	}
+
+	for _, test := range []struct {
+		name   string
+		p      math.Point2LL
+		inside bool
+	}{
+		{"between finals", nm(0.4, -3), true},
+		{"near far end", nm(0.4, -9.5), true},
+		{"beyond far end", nm(0.4, -10.5), false},
+		{"abeam farther threshold", nm(0.4, 0.2), false},
+		{"left final", nm(0, -3), false},
+		{"right final", nm(0.8, -3), false},
+		{"edge of zone", nm(0.55, -3), true},
+	} {
+		if ntz.Inside(test.p) != test.inside {
+			t.Errorf("%s: expected inside=%v", test.name, test.inside)
+		}
+	}
+
+	if ntz.OtherRunway("36L") != "36R" || ntz.OtherRunway("36R") != "36L" {
+		t.Errorf("OtherRunway returned the wrong runway")
+	}
+
+	for _, rwys := range [][2]string{{"36L", "36L"}, {"36L", "9"}, {"36L", "18"}} {
+		var e util.ErrorLogger
+		ntz := NTZ{Runways: rwys}
+		ntz.initialize("KTST", nmPerLongitude, 0, &e)
+		if !e.HaveErrors() {
+			t.Errorf("%v: expected validation error", rwys)
+		}
+	}
+
+	var ew util.ErrorLogger
+	wide := NTZ{Runways: [2]string{"36L", "36R"}, WidthFeet: 6000}
+	wide.initialize("KTST", nmPerLongitude, 0, &ew)
+	if !ew.HaveErrors() {
+		t.Errorf("expected an error for an NTZ wider than the runway spacing")
+	}
+}
@@ -53,6 +53,7 @@ type StaticDatabase struct {
 	BravoAirspace       map[string][]AirspaceVolume
 	CharlieAirspace     map[string][]AirspaceVolume
 	DeltaAirspace       map[string][]AirspaceVolume
+	Terrain             *Terrain // nil if the terrain resource isn't available
 }
 
 type FAAAirport struct {
@@ -383,6 +384,7 @@ func doInitDB() {
 	wg.Go(func() { db.MagneticGrid = parseMagneticGrid() })
 	wg.Go(func() { db.ARTCCs, db.TRACONs, db.ATCTs = parseFacilities() })
 	wg.Go(func() { db.MVAs = parseMVAs() })
+	wg.Go(func() { db.Terrain = parseTerrain() })
 	wg.Go(func() { db.ERAMAdaptations = parseAdaptations() })
 	wg.Go(func() {
 		db.BravoAirspace = parseAirspace("bravo-airspace.json.zst")
@@ -908,6 +910,23 @@ type MVAHorizontalProjection struct {
 	Surface MVASurface `xml:"Surface"`
 }
 
+// To update the terrain data:
+// % go run ./cmd/terrainingest -o terrain.zip ~/srtm/*.hgt
+// % mv terrain.zip ~/vice/resources/
+//
+// No terrain data is currently included, so terrain-aware MSAW and VFR
+// altitudes are inactive until it is.
+func parseTerrain() *Terrain {
+	if !util.ResourceExists("terrain.zip") {
+		return nil
+	}
+	t, err := MakeTerrain(util.LoadResourceBytes("terrain.zip"))
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
 // To update the MVA data:
 // % go run util/scrapemva.go # download the XML files
 // % parallel zstd -19 {} ::: *xml
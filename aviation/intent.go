@@ -676,6 +676,16 @@ func (n NavigationIntent) Render(rt *RadioTransmission, r *rand.Rand) {
 	}
 }
 
+// ExpectFurtherClearanceIntent is the readback of an EFC time for an
+// aircraft in a hold; Time is UTC in HHMM form.
+type ExpectFurtherClearanceIntent struct {
+	Time int
+}
+
+func (e ExpectFurtherClearanceIntent) Render(rt *RadioTransmission, r *rand.Rand) {
+	rt.Add("[expect further clearance|further clearance] [at|] {time}", e.Time)
+}
+
 ///////////////////////////////////////////////////////////////////////////
 // Approach Intents
 
@@ -738,10 +748,12 @@ func (a ApproachIntent) Render(rt *RadioTransmission, r *rand.Rand) {
 
 // ClearedApproachIntent represents approach clearance
 type ClearedApproachIntent struct {
-	Approach    string
-	StraightIn  bool
-	CancelHold  bool
-	LAHSORunway string
+	Approach       string
+	StraightIn     bool
+	CancelHold     bool
+	LAHSORunway    string
+	Maneuver       ApproachManeuver // circle to land or sidestep after the approach
+	ManeuverRunway string           // runway to land on for Maneuver
 }
 
 func (c ClearedApproachIntent) Render(rt *RadioTransmission, r *rand.Rand) {
@@ -762,11 +774,43 @@ func (c ClearedApproachIntent) Render(rt *RadioTransmission, r *rand.Rand) {
 	} else {
 		rt.Add(prefix+"cleared {appr}"+suffix, c.Approach)
 	}
+	switch c.Maneuver {
+	case CircleToLand:
+		rt.Add("[circle to land|circling|circle to] runway {rwy}", c.ManeuverRunway)
+	case SidestepManeuver:
+		rt.Add("[sidestep|side step to] runway {rwy}", c.ManeuverRunway)
+	}
 	if c.LAHSORunway != "" {
 		rt.Add("[and we'll hold short of|hold short of] runway {rwy}", c.LAHSORunway)
 	}
 }
 
+// BreakoutIntent represents the readback of a breakout instruction from
+// the final monitor controller during simultaneous approaches.
+type BreakoutIntent struct {
+	Heading   math.MagneticHeading
+	Turn      HeadingTurn
+	Altitude  float32
+	Direction AltitudeDirection
+}
+
+func (b BreakoutIntent) Render(rt *RadioTransmission, r *rand.Rand) {
+	if b.Turn == HeadingTurnToLeft {
+		rt.Add("[left heading|turning left heading|left] {hdg}", b.Heading)
+	} else {
+		rt.Add("[right heading|turning right heading|right] {hdg}", b.Heading)
+	}
+	switch b.Direction {
+	case AltitudeClimb:
+		rt.Add("[climb-and-maintain|up to] {alt}", b.Altitude)
+	case AltitudeDescend:
+		rt.Add("[descend-and-maintain|down to] {alt}", b.Altitude)
+	default:
+		rt.Add("[maintain|maintaining] {alt}", b.Altitude)
+	}
+	rt.Add("[breaking out|]")
+}
+
 ///////////////////////////////////////////////////////////////////////////
 // Procedure Intents
 
@@ -187,3 +187,50 @@ func TestCompoundSpeedReadbackIncludesQualifiers(t *testing.T) {
 		}
 	}
 }
+
+func TestExpectFurtherClearanceReadback(t *testing.T) {
+	for seed := uint64(1); seed <= 20; seed++ {
+		readback := renderIntentForTest(ExpectFurtherClearanceIntent{Time: 905}, seed)
+		if !strings.Contains(readback, "further clearance") || !strings.Contains(readback, "0905") {
+			t.Fatalf("EFC readback missing time: %q", readback)
+		}
+
+		r := rand.Make()
+		r.Seed(seed)
+		spoken := RenderIntents([]CommandIntent{ExpectFurtherClearanceIntent{Time: 905}}, r).Spoken(r)
+		if !strings.Contains(spoken, "zero niner zero five") {
+			t.Fatalf("EFC time not spoken digit by digit: %q", spoken)
+		}
+	}
+}
+
+func TestClearedApproachManeuverReadback(t *testing.T) {
+	for seed := uint64(1); seed <= 20; seed++ {
+		readback := renderIntentForTest(ClearedApproachIntent{
+			Approach:       "ILS Runway 4R",
+			Maneuver:       CircleToLand,
+			ManeuverRunway: "31",
+		}, seed)
+		assertContainsAny(t, readback, "circle to land runway 31", "circling runway 31", "circle to runway 31")
+
+		readback = renderIntentForTest(ClearedApproachIntent{
+			Approach:       "ILS Runway 22L",
+			Maneuver:       SidestepManeuver,
+			ManeuverRunway: "22R",
+		}, seed)
+		assertContainsAny(t, readback, "sidestep runway 22r", "side step to runway 22r")
+	}
+}
+
+func TestBreakoutReadback(t *testing.T) {
+	for seed := uint64(1); seed <= 20; seed++ {
+		readback := renderIntentForTest(BreakoutIntent{
+			Heading:   270,
+			Turn:      HeadingTurnToLeft,
+			Altitude:  3000,
+			Direction: AltitudeClimb,
+		}, seed)
+		assertContainsAny(t, readback, "left heading 270", "left 270")
+		assertContainsAny(t, readback, "climb-and-maintain 3,000", "up to 3,000")
+	}
+}
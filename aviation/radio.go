@@ -199,6 +199,7 @@ var (
 		"mach":     &MachSnippetFormatter{},
 		"spd":      &SpeedSnippetFormatter{},
 		"star":     &STARSnippetFormatter{},
+		"time":     &TimeSnippetFormatter{},
 	}
 )
 
@@ -1447,6 +1448,30 @@ func (GroupFormSnippetFormatter) Validate(arg any) error {
 	return nil
 }
 
+///////////////////////////////////////////////////////////////////////////
+// TimeSnippetFormatter
+
+// TimeSnippetFormatter formats a UTC time given as an HHMM integer; the
+// digits are spoken individually.
+type TimeSnippetFormatter struct{}
+
+func (TimeSnippetFormatter) Written(arg any) string {
+	return fmt.Sprintf("%04d", intArg(arg))
+}
+
+func (TimeSnippetFormatter) Spoken(r *rand.Rand, arg any) string {
+	return sayDigits(intArg(arg), 4)
+}
+
+func (TimeSnippetFormatter) Validate(arg any) error {
+	if v, ok := arg.(int); !ok {
+		return fmt.Errorf("expected int arg, got %T", arg)
+	} else if v < 0 || v/100 > 23 || v%100 > 59 {
+		return fmt.Errorf("%04d: invalid HHMM time", v)
+	}
+	return nil
+}
+
 ///////////////////////////////////////////////////////////////////////////
 // BeaconCodeSnippetFormatter
 
@@ -0,0 +1,215 @@
+// aviation/terrain.go
+// Copyright(c) 2022-2026 vice contributors, licensed under the GNU Public License, Version 3.
+// SPDX: GPL-3.0-only
+
+package aviation
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+
+	"github.com/mmp/vice/math"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Terrain provides terrain elevations from a digital elevation model
+// (DEM). The DEM is stored in a zip file with one zstd-compressed entry
+// for each 1x1 degree tile; see cmd/terrainingest for how it's made.
+// Tiles are only decompressed the first time they're needed since most
+// of the country is of no interest to a given sim.
+//
+// Each tile stores TerrainSamplesPerDegree x TerrainSamplesPerDegree
+// little-endian int16 values, in rows from the southern edge of the tile
+// to the northern edge and west to east within a row. Each value is the
+// maximum elevation in feet over the corresponding cell, so that lookups
+// are conservative with respect to the terrain's high points.
+type Terrain struct {
+	mu    sync.Mutex
+	files map[[2]int]*zip.File
+	tiles map[[2]int][]int16 // tiles that have been decoded so far
+}
+
+// TerrainSamplesPerDegree gives the resolution of the terrain tiles:
+// 120 samples per degree is 30 arc-seconds, or 0.5nm of latitude.
+const TerrainSamplesPerDegree = 120
+
+// TerrainTileName returns the name used for the tile whose southwest
+// corner is at the given integer latitude and longitude, following the
+// SRTM convention (e.g., "N35W107").
+func TerrainTileName(lat, lon int) string {
+	ns, ew := "N", "E"
+	if lat < 0 {
+		ns, lat = "S", -lat
+	}
+	if lon < 0 {
+		ew, lon = "W", -lon
+	}
+	return fmt.Sprintf("%s%02d%s%03d", ns, lat, ew, lon)
+}
+
+func parseTerrainTileName(name string) (lat, lon int, err error) {
+	name = strings.TrimSuffix(name, ".zst")
+	var ns, ew byte
+	if _, err = fmt.Sscanf(name, "%c%02d%c%03d", &ns, &lat, &ew, &lon); err != nil {
+		return 0, 0, fmt.Errorf("%s: invalid terrain tile name: %w", name, err)
+	}
+	switch ns {
+	case 'N':
+	case 'S':
+		lat = -lat
+	default:
+		return 0, 0, fmt.Errorf("%s: invalid terrain tile latitude", name)
+	}
+	switch ew {
+	case 'E':
+	case 'W':
+		lon = -lon
+	default:
+		return 0, 0, fmt.Errorf("%s: invalid terrain tile longitude", name)
+	}
+	return lat, lon, nil
+}
+
+// EncodeTerrainTile returns the uncompressed representation of a
+// terrain tile with the given elevations, which must be laid out as
+// described in the Terrain documentation.
+func EncodeTerrainTile(elev []int16) []byte {
+	if len(elev) != TerrainSamplesPerDegree*TerrainSamplesPerDegree {
+		panic(fmt.Sprintf("%d: unexpected number of terrain samples", len(elev)))
+	}
+	b := make([]byte, 2*len(elev))
+	for i, e := range elev {
+		binary.LittleEndian.PutUint16(b[2*i:], uint16(e))
+	}
+	return b
+}
+
+func decodeTerrainTile(b []byte) ([]int16, error) {
+	const n = TerrainSamplesPerDegree * TerrainSamplesPerDegree
+	if len(b) != 2*n {
+		return nil, fmt.Errorf("%d: unexpected terrain tile size", len(b))
+	}
+	elev := make([]int16, n)
+	for i := range elev {
+		elev[i] = int16(binary.LittleEndian.Uint16(b[2*i:]))
+	}
+	return elev, nil
+}
+
+// MakeTerrain returns a Terrain for the given zip file contents.
+func MakeTerrain(z []byte) (*Terrain, error) {
+	zr, err := zip.NewReader(bytes.NewReader(z), int64(len(z)))
+	if err != nil {
+		return nil, err
+	}
+
+	t := &Terrain{
+		files: make(map[[2]int]*zip.File),
+		tiles: make(map[[2]int][]int16),
+	}
+	for _, f := range zr.File {
+		lat, lon, err := parseTerrainTileName(f.Name)
+		if err != nil {
+			return nil, err
+		}
+		t.files[[2]int{lat, lon}] = f
+	}
+	return t, nil
+}
+
+func (t *Terrain) getTile(lat, lon int) []int16 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	key := [2]int{lat, lon}
+	if tile, ok := t.tiles[key]; ok {
+		return tile
+	}
+
+	f, ok := t.files[key]
+	if !ok {
+		return nil
+	}
+
+	tile, err := func() ([]int16, error) {
+		r, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer r.Close()
+
+		zr, err := zstd.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		defer zr.Close()
+
+		b, err := io.ReadAll(zr)
+		if err != nil {
+			return nil, err
+		}
+		return decodeTerrainTile(b)
+	}()
+	if err != nil {
+		// Treat the tile as if it weren't in the DEM; the nil tile is
+		// cached so that the error is only reported once.
+		slog.Error("unable to decode terrain tile", slog.String("tile", f.Name), slog.Any("error", err))
+		tile = nil
+	}
+
+	t.tiles[key] = tile
+	return tile
+}
+
+// Covers reports whether the DEM has terrain data for the tile containing p.
+func (t *Terrain) Covers(p math.Point2LL) bool {
+	return t != nil && t.getTile(int(math.Floor(p[1])), int(math.Floor(p[0]))) != nil
+}
+
+// Elevation returns the maximum terrain elevation in feet in the DEM cell
+// containing p. It returns 0 if there is no terrain data for p (including
+// if t is nil, as it is if the terrain resource isn't available.)
+func (t *Terrain) Elevation(p math.Point2LL) int {
+	if t == nil {
+		return 0
+	}
+
+	lat, lon := math.Floor(p[1]), math.Floor(p[0])
+	tile := t.getTile(int(lat), int(lon))
+	if tile == nil {
+		return 0
+	}
+
+	row := min(int((p[1]-lat)*TerrainSamplesPerDegree), TerrainSamplesPerDegree-1)
+	col := min(int((p[0]-lon)*TerrainSamplesPerDegree), TerrainSamplesPerDegree-1)
+	return int(tile[row*TerrainSamplesPerDegree+col])
+}
+
+// MaxElevation returns the maximum terrain elevation in feet within
+// radius nm of p, or 0 if there's no terrain data there.
+func (t *Terrain) MaxElevation(p math.Point2LL, radius float32, nmPerLongitude float32) int {
+	if t == nil {
+		return 0
+	}
+
+	// Walk the DEM cells in the bounding box of the circle.
+	const step = 1. / TerrainSamplesPerDegree
+	dlat, dlon := radius/math.NMPerLatitude, radius/nmPerLongitude
+	elev := t.Elevation(p)
+	for lat := p[1] - dlat; lat <= p[1]+dlat; lat += step {
+		for lon := p[0] - dlon; lon <= p[0]+dlon; lon += step {
+			q := math.Point2LL{lon, lat}
+			if math.NMDistance2LLFast(p, q, nmPerLongitude) <= radius {
+				elev = max(elev, t.Elevation(q))
+			}
+		}
+	}
+	return elev
+}
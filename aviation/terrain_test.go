@@ -0,0 +1,150 @@
+// aviation/terrain_test.go
+// Copyright(c) 2022-2026 vice contributors, licensed under the GNU Public License, Version 3.
+// SPDX: GPL-3.0-only
+
+package aviation
+
+import (
+	"archive/zip"
+	"bytes"
+	"testing"
+
+	"github.com/mmp/vice/math"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+func makeTestTerrain(t *testing.T, tiles map[[2]int][]int16) *Terrain {
+	t.Helper()
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for ll, elev := range tiles {
+		w, err := zw.Create(TerrainTileName(ll[0], ll[1]) + ".zst")
+		if err != nil {
+			t.Fatal(err)
+		}
+		enc, err := zstd.NewWriter(w)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := enc.Write(EncodeTerrainTile(elev)); err != nil {
+			t.Fatal(err)
+		}
+		if err := enc.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	terrain, err := MakeTerrain(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return terrain
+}
+
+func TestTerrainTileName(t *testing.T) {
+	for _, tc := range []struct {
+		lat, lon int
+		name     string
+	}{
+		{35, -107, "N35W107"},
+		{5, 7, "N05E007"},
+		{-12, -45, "S12W045"},
+	} {
+		if name := TerrainTileName(tc.lat, tc.lon); name != tc.name {
+			t.Errorf("TerrainTileName(%d, %d) = %q; expected %q", tc.lat, tc.lon, name, tc.name)
+		}
+		lat, lon, err := parseTerrainTileName(tc.name + ".zst")
+		if err != nil {
+			t.Errorf("%s: %v", tc.name, err)
+		} else if lat != tc.lat || lon != tc.lon {
+			t.Errorf("%s: parsed %d, %d; expected %d, %d", tc.name, lat, lon, tc.lat, tc.lon)
+		}
+	}
+
+	if _, _, err := parseTerrainTileName("X35W107"); err == nil {
+		t.Errorf("expected error for invalid tile name")
+	}
+}
+
+func TestTerrainElevation(t *testing.T) {
+	// A tile that's 5000' everywhere except for a 9000' peak in the cell
+	// at row 60, column 30.
+	const n = TerrainSamplesPerDegree
+	elev := make([]int16, n*n)
+	for i := range elev {
+		elev[i] = 5000
+	}
+	elev[60*n+30] = 9000
+
+	terrain := makeTestTerrain(t, map[[2]int][]int16{{35, -107}: elev})
+
+	peak := math.Point2LL{-107 + 30.5/n, 35 + 60.5/n}
+	if e := terrain.Elevation(peak); e != 9000 {
+		t.Errorf("peak elevation %d; expected 9000", e)
+	}
+	if e := terrain.Elevation(math.Point2LL{-106.5, 35.9}); e != 5000 {
+		t.Errorf("elevation %d; expected 5000", e)
+	}
+	// No tile here.
+	if e := terrain.Elevation(math.Point2LL{-105.5, 35.5}); e != 0 {
+		t.Errorf("elevation %d outside of the DEM; expected 0", e)
+	}
+	if !terrain.Covers(peak) || terrain.Covers(math.Point2LL{-105.5, 35.5}) {
+		t.Errorf("Covers should only report the tile in the DEM")
+	}
+
+	const nmPerLongitude = 49
+	near := math.Offset2LL(peak, 90, 1.5, nmPerLongitude)
+	if e := terrain.MaxElevation(near, 2, nmPerLongitude); e != 9000 {
+		t.Errorf("max elevation %d within 2nm of the peak; expected 9000", e)
+	}
+	far := math.Offset2LL(peak, 90, 5, nmPerLongitude)
+	if e := terrain.MaxElevation(far, 2, nmPerLongitude); e != 5000 {
+		t.Errorf("max elevation %d 5nm from the peak; expected 5000", e)
+	}
+
+	// A nil Terrain (no DEM available) never reports terrain.
+	var none *Terrain
+	if e := none.Elevation(peak); e != 0 {
+		t.Errorf("nil terrain elevation %d; expected 0", e)
+	}
+	if e := none.MaxElevation(peak, 5, nmPerLongitude); e != 0 {
+		t.Errorf("nil terrain max elevation %d; expected 0", e)
+	}
+	if none.Covers(peak) {
+		t.Errorf("nil terrain shouldn't cover anything")
+	}
+}
+
+func TestTerrainCorruptTile(t *testing.T) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	w, err := zw.Create(TerrainTileName(35, -106) + ".zst")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Write([]byte("not zstd")); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	terrain, err := MakeTerrain(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A tile that can't be decoded is treated as if it isn't in the DEM.
+	p := math.Point2LL{-105.5, 35.5}
+	if e := terrain.Elevation(p); e != 0 {
+		t.Errorf("elevation %d from a corrupt tile; expected 0", e)
+	}
+	if terrain.Covers(p) {
+		t.Errorf("corrupt tile shouldn't be covered")
+	}
+}
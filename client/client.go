@@ -5,7 +5,9 @@
 package client
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"net"
 	"net/rpc"
 	"slices"
@@ -58,9 +60,17 @@ type ControlClient struct {
 	lastUpdateApplied time.Time
 	lastReturnedTime  sim.Time
 	updateCall        *pendingCall
+	// GenerationIndex of the last GetStateUpdate result applied. If our
+	// state is still at that generation, the server can send the next
+	// update as a delta relative to it.
+	updateCallGen int
 
 	pendingCalls []*pendingCall
 
+	// reconnecting is set while the ConnectionManager is trying to
+	// reestablish a lost connection to the server.
+	reconnecting bool
+
 	SessionStats SessionStats
 
 	// This is all read-only data that we expect other parts of the system
@@ -73,10 +83,13 @@ type Server struct {
 	*RPCClient
 
 	AvailableWXByFacility map[string][]util.TimeInterval
+	RequiresAccount       bool // users must provide credentials to create or join sims
 
 	name        string
 	catalogs    map[string]map[string]*server.ScenarioCatalog
 	runningSims map[string]*server.RunningSim
+	// scheduledSims is only updated for the remote server.
+	scheduledSims map[string]*server.ScheduledSim
 }
 
 type SessionStats struct {
@@ -260,10 +273,61 @@ func (c *ControlClient) Status() string {
 		stats := c.SessionStats
 		deparr := fmt.Sprintf(" [ %d departures %d arrivals %d intrafacility %d overflights ]",
 			stats.Departures, stats.Arrivals, stats.IntraFacility, stats.Overflights)
-		return string(c.State.UserTCW) + c.State.SimDescription + deparr
+		return util.Select(c.reconnecting, "[reconnecting] ", "") + string(c.State.UserTCW) +
+			c.State.SimDescription + deparr
 	}
 }
 
+// Reconnecting returns whether the connection to the server has been lost
+// and the client is trying to reconnect.
+func (c *ControlClient) Reconnecting() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.reconnecting
+}
+
+// connectionLost closes the client's connection to the server, which
+// completes any outstanding calls with errors, and marks the client as
+// reconnecting.
+func (c *ControlClient) connectionLost() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.reconnecting = true
+	c.client.Close()
+}
+
+// reconnected switches the client to a new connection to the server and
+// resets its state to the state returned by the server's Reconnect RPC.
+// Calls that were outstanding when the connection was lost report their
+// errors now.
+func (c *ControlClient) reconnected(client *RPCClient, result server.NewSimResult) {
+	c.mu.Lock()
+	calls := c.pendingCalls
+	c.client = client
+	c.controllerToken = result.ControllerToken
+	c.State = SimState{*result.SimState}
+	c.pendingCalls = nil
+	c.updateCall = nil
+	c.updateCallGen = 0
+	c.lastUpdateApplied = time.Now()
+	c.reconnecting = false
+	c.mu.Unlock()
+
+	for _, call := range calls {
+		if call.CheckFinished() {
+			call.InvokeCallback(c)
+		}
+	}
+}
+
+// isConnectionLost returns whether the error from an RPC call indicates
+// that the network connection to the server has been lost.
+func isConnectionLost(err error) bool {
+	return errors.Is(err, server.ErrRPCTimeout) || errors.Is(err, rpc.ErrShutdown) ||
+		errors.Is(err, io.ErrUnexpectedEOF)
+}
+
 func (c *ControlClient) Disconnect() {
 	c.mu.Lock()
 	defer c.mu.Unlock()
@@ -311,6 +375,9 @@ func (c *ControlClient) GetUpdates(p platform.Platform, onErr func(error)) {
 			updateCallFinished = c.updateCall
 			c.updateCall = nil
 			c.SessionStats.Update(&c.State)
+			if err := updateCallFinished.Call.Error; isConnectionLost(err) {
+				callbackErr = err
+			}
 		} else {
 			callbackErr = c.checkTimeout(c.updateCall)
 		}
@@ -319,7 +386,11 @@ func (c *ControlClient) GetUpdates(p platform.Platform, onErr func(error)) {
 	// State delivery is server-paced: keep exactly one GetStateUpdate outstanding.
 	if c.updateCall == nil { // First call or just got an update
 		var update server.SimStateUpdate
-		c.updateCall = makeStateUpdateRPCCall(c.client.Go(server.GetStateUpdateRPC, c.controllerToken, &update, nil), &update, nil)
+		args := &server.GetStateUpdateArgs{ControllerToken: c.controllerToken}
+		if c.updateCallGen != 0 && c.updateCallGen == c.State.GenerationIndex {
+			args.Generation = c.updateCallGen
+		}
+		c.updateCall = makeStateUpdateRPCCall(c.client.Go(server.GetStateUpdateRPC, args, &update, nil), &update, nil)
 	}
 
 	c.updateSpeech(p)
@@ -328,8 +399,9 @@ func (c *ControlClient) GetUpdates(p platform.Platform, onErr func(error)) {
 	// We request contacts when the server has TTS capability, even if the user
 	// has disabled TTS locally. This ensures pilots still join the frequency
 	// and text transmissions appear. Audio playback is controlled separately.
-	// The actual request is made after releasing the lock.
-	shouldRequestContact := c.transmissions.ShouldRequestContact()
+	// The actual request is made after releasing the lock. Observers
+	// leave the contacts to the controller at the TCW.
+	shouldRequestContact := !c.State.UserIsObserver && c.transmissions.ShouldRequestContact()
 
 	if callbackErr == nil {
 		completedCalls, callbackErr = c.checkPendingRPCs()
@@ -357,6 +429,7 @@ func (c *ControlClient) GetUpdates(p platform.Platform, onErr func(error)) {
 			now := time.Now()
 			c.mu.Lock()
 			c.lastUpdateApplied = now
+			c.updateCallGen = c.State.GenerationIndex
 			c.mu.Unlock()
 		}
 	}
@@ -574,6 +647,14 @@ func (s *Server) GetRunningSims() map[string]*server.RunningSim {
 	return s.runningSims
 }
 
+func (s *Server) setScheduledSims(ss map[string]*server.ScheduledSim) {
+	s.scheduledSims = ss
+}
+
+func (s *Server) GetScheduledSims() map[string]*server.ScheduledSim {
+	return s.scheduledSims
+}
+
 func getClient(hostname string, lg *log.Logger) (*RPCClient, error) {
 	conn, err := net.Dial("tcp", hostname)
 	if err != nil {
@@ -593,33 +674,67 @@ func getClient(hostname string, lg *log.Logger) (*RPCClient, error) {
 func TryConnectRemoteServer(hostname string, lg *log.Logger) chan *serverConnection {
 	ch := make(chan *serverConnection, 1)
 	go func() {
-		if client, err := getClient(hostname, lg); err != nil {
-			ch <- &serverConnection{Err: err}
+		srv, err := connectRemoteServer(hostname, lg)
+		ch <- &serverConnection{Server: srv, Err: err}
+	}()
+
+	return ch
+}
+
+func connectRemoteServer(hostname string, lg *log.Logger) (*Server, error) {
+	client, err := getClient(hostname, lg)
+	if err != nil {
+		return nil, err
+	}
+
+	var cr server.ConnectResult
+	start := time.Now()
+	if err := client.callWithTimeout(server.ConnectRPC, server.ViceRPCVersion, &cr); err != nil {
+		client.Close()
+		return nil, err
+	}
+	lg.Debugf("%s: server returned configuration in %s", hostname, time.Since(start))
+
+	return &Server{
+		RPCClient:             client,
+		AvailableWXByFacility: cr.AvailableWXByFacility,
+		RequiresAccount:       cr.RequiresAccount,
+		name:                  "Network (Multi-controller)",
+		catalogs:              cr.ScenarioCatalogs,
+		runningSims:           cr.RunningSims,
+	}, nil
+}
+
+type reconnectResult struct {
+	server *Server
+	result server.NewSimResult
+	err    error
+}
+
+// tryReconnect asynchronously connects to the server again and resumes
+// the sim connection for the given controller token.
+func tryReconnect(hostname, token string, lg *log.Logger) chan reconnectResult {
+	ch := make(chan reconnectResult, 1)
+	go func() {
+		srv, err := connectRemoteServer(hostname, lg)
+		if err != nil {
+			ch <- reconnectResult{err: server.TryDecodeError(err)}
+			return
+		}
+
+		var result server.NewSimResult
+		if err := srv.callWithTimeout(server.ReconnectRPC, token, &result); err != nil {
+			srv.Close()
+			ch <- reconnectResult{err: server.TryDecodeError(err)}
 			return
-		} else {
-			var cr server.ConnectResult
-			start := time.Now()
-			if err := client.callWithTimeout(server.ConnectRPC, server.ViceRPCVersion, &cr); err != nil {
-				ch <- &serverConnection{Err: err}
-			} else {
-				lg.Debugf("%s: server returned configuration in %s", hostname, time.Since(start))
-				ch <- &serverConnection{
-					Server: &Server{
-						RPCClient:             client,
-						AvailableWXByFacility: cr.AvailableWXByFacility,
-						name:                  "Network (Multi-controller)",
-						catalogs:              cr.ScenarioCatalogs,
-						runningSims:           cr.RunningSims,
-					},
-				}
-			}
 		}
+		ch <- reconnectResult{server: srv, result: result}
 	}()
 
 	return ch
 }
 
-func BroadcastMessage(hostname, msg, password string, lg *log.Logger) {
+func BroadcastMessage(hostname, msg, username, password string, lg *log.Logger) {
 	client, err := getClient(hostname, lg)
 	if err != nil {
 		lg.Errorf("unable to get client for broadcast: %v", err)
@@ -627,6 +742,7 @@ func BroadcastMessage(hostname, msg, password string, lg *log.Logger) {
 	}
 
 	err = client.callWithTimeout(server.BroadcastRPC, &server.BroadcastMessage{
+		Username: username,
 		Password: password,
 		Message:  msg,
 	}, nil)
@@ -30,6 +30,8 @@ type ConnectionManager struct {
 	updateRunningSimsCall  *pendingCall
 	updateRunningSimsError error
 
+	updateScheduledSimsCall *pendingCall
+
 	LocalServer   *Server
 	RemoteServer  *Server
 	serverAddress string
@@ -38,11 +40,18 @@ type ConnectionManager struct {
 	connectionStartTime time.Time
 	disableTTSPtr       *bool // Pointer to config's DisableTextToSpeech for runtime toggle
 
+	// When the connection to the remote server is lost, we try to
+	// reconnect to the sim with exponential backoff; see updateReconnect.
+	reconnectStart   time.Time
+	reconnectAttempt int
+	nextReconnect    time.Time
+	reconnectChan    chan reconnectResult
+
 	onNewClient func(*ControlClient)
 	onError     func(error)
 }
 
-func MakeServerManager(serverAddress, additionalScenario, additionalVideoMap, additionalScenarioBrief string, disableTTSPtr *bool, lg *log.Logger,
+func MakeServerManager(serverAddress, additionalScenario, additionalVideoMap, additionalScenarioBrief, recordDir string, disableTTSPtr *bool, lg *log.Logger,
 	onNewClient func(*ControlClient), onError func(error)) (*ConnectionManager, util.ErrorLogger, string) {
 	cm := &ConnectionManager{
 		serverAddress:           serverAddress,
@@ -60,6 +69,7 @@ func MakeServerManager(serverAddress, additionalScenario, additionalVideoMap, ad
 		ExtraScenarioBrief: additionalScenarioBrief,
 		ServerAddress:      serverAddress,
 		IsLocal:            true,
+		RecordDir:          recordDir,
 	}, lg)
 
 	if !errorLogger.HaveErrors() {
@@ -174,6 +184,11 @@ func (cm *ConnectionManager) Disconnect() {
 }
 
 func (cm *ConnectionManager) UpdateRunningSims() error {
+	if cm.updateScheduledSimsCall != nil && cm.updateScheduledSimsCall.CheckFinished() {
+		cm.updateScheduledSimsCall.InvokeCallback(nil)
+		cm.updateScheduledSimsCall = nil
+	}
+
 	if cm.updateRunningSimsCall != nil && cm.updateRunningSimsCall.CheckFinished() {
 		cm.updateRunningSimsCall.InvokeCallback(nil)
 		cm.updateRunningSimsCall = nil
@@ -202,6 +217,41 @@ func (cm *ConnectionManager) UpdateRunningSims() error {
 					}
 				}
 			})
+
+		if cm.updateScheduledSimsCall == nil {
+			var ss map[string]*server.ScheduledSim
+			cm.updateScheduledSimsCall = makeRPCCall(cm.RemoteServer.Go(server.GetScheduledSimsRPC, 0, &ss, nil),
+				func(err error) {
+					if err == nil && cm.RemoteServer != nil {
+						cm.RemoteServer.setScheduledSims(ss)
+					}
+				})
+		}
+	}
+	return nil
+}
+
+// ScheduleSim schedules a sim to start on the server at a future time.
+func (cm *ConnectionManager) ScheduleSim(req server.ScheduleSimRequest, srv *Server) error {
+	if err := srv.callWithTimeout(server.ScheduleSimRPC, req, nil); err != nil {
+		return server.TryDecodeError(err)
+	}
+	return nil
+}
+
+// SignUpForSim reserves a position in a scheduled sim; it returns the
+// position reserved or "" if the controller was added to the waitlist.
+func (cm *ConnectionManager) SignUpForSim(req server.SimSignUpRequest, srv *Server) (sim.TCP, error) {
+	var tcp sim.TCP
+	if err := srv.callWithTimeout(server.SignUpForSimRPC, req, &tcp); err != nil {
+		return "", server.TryDecodeError(err)
+	}
+	return tcp, nil
+}
+
+func (cm *ConnectionManager) CancelSimSignUp(req server.SimSignUpRequest, srv *Server) error {
+	if err := srv.callWithTimeout(server.CancelSimSignUpRPC, req, nil); err != nil {
+		return server.TryDecodeError(err)
 	}
 	return nil
 }
@@ -257,7 +307,9 @@ func (cm *ConnectionManager) Update(p platform.Platform, lg *log.Logger) {
 		cm.remoteSimServerChan = TryConnectRemoteServer(cm.serverAddress, lg)
 	}
 
-	if cm.client != nil {
+	if cm.client != nil && cm.client.Reconnecting() {
+		cm.updateReconnect(lg)
+	} else if cm.client != nil {
 		client := cm.client
 		client.GetUpdates(p,
 			func(err error) {
@@ -265,21 +317,90 @@ func (cm *ConnectionManager) Update(p platform.Platform, lg *log.Logger) {
 					Type:        sim.StatusMessageEvent,
 					WrittenText: "Error getting update from server: " + err.Error(),
 				})
-				if err == server.ErrRPCTimeout || util.IsRPCServerError(err) {
-					cm.RemoteServer = nil
-					if cm.client != nil {
-						cm.client.Disconnect()
-						cm.client = nil
-					}
-					if cm.onNewClient != nil {
-						cm.onNewClient(nil)
-					}
-					if cm.onError != nil {
-						cm.onError(server.ErrServerDisconnected)
-					}
+				if isConnectionLost(err) && !cm.ClientIsLocal() {
+					cm.startReconnect(lg)
+				} else if err == server.ErrRPCTimeout || util.IsRPCServerError(err) {
+					cm.disconnectLostClient()
 				} else if cm.onError != nil {
 					cm.onError(err)
 				}
 			})
 	}
 }
+
+func (cm *ConnectionManager) disconnectLostClient() {
+	cm.RemoteServer = nil
+	if cm.client != nil {
+		cm.client.Disconnect()
+		cm.client = nil
+	}
+	if cm.onNewClient != nil {
+		cm.onNewClient(nil)
+	}
+	if cm.onError != nil {
+		cm.onError(server.ErrServerDisconnected)
+	}
+}
+
+// maxReconnectTime is how long we keep trying to reconnect to a sim after
+// the connection to the server is lost. It matches the time the server
+// holds on to a dropped connection.
+const maxReconnectTime = 5 * time.Minute
+
+func (cm *ConnectionManager) startReconnect(lg *log.Logger) {
+	lg.Warn("lost connection to server; reconnecting")
+
+	cm.RemoteServer = nil
+	cm.client.connectionLost()
+	cm.reconnectStart = time.Now()
+	cm.reconnectAttempt = 0
+	cm.nextReconnect = time.Now()
+
+	cm.client.PostEvent(sim.Event{
+		Type:        sim.StatusMessageEvent,
+		WrittenText: "Lost connection to the server. Reconnecting...",
+	})
+}
+
+// updateReconnect tries to reconnect a client that has lost its
+// connection to the server, backing off exponentially between attempts.
+// Once reconnected, the client's state is resynchronized and the server
+// delivers the events it missed with the next state update.
+func (cm *ConnectionManager) updateReconnect(lg *log.Logger) {
+	if cm.reconnectChan == nil {
+		if time.Now().After(cm.nextReconnect) {
+			cm.reconnectChan = tryReconnect(cm.serverAddress, cm.client.controllerToken, lg)
+		}
+		return
+	}
+
+	select {
+	case r := <-cm.reconnectChan:
+		cm.reconnectChan = nil
+
+		if r.err == nil {
+			lg.Info("reconnected to server", slog.Duration("elapsed", time.Since(cm.reconnectStart)))
+			cm.RemoteServer = r.server
+			cm.client.reconnected(r.server.RPCClient, r.result)
+			cm.client.PostEvent(sim.Event{
+				Type:        sim.StatusMessageEvent,
+				WrittenText: "Reconnected to the server.",
+			})
+			return
+		}
+
+		lg.Info("unable to reconnect", slog.Any("error", r.err), slog.Int("attempt", cm.reconnectAttempt))
+		if r.err == server.ErrNoSimForControllerToken || r.err == server.ErrRPCVersionMismatch ||
+			time.Since(cm.reconnectStart) > maxReconnectTime {
+			// The sim is gone or we've been at it too long; give up.
+			cm.client = nil
+			cm.disconnectLostClient()
+			return
+		}
+
+		cm.reconnectAttempt++
+		cm.nextReconnect = time.Now().Add(min(time.Second<<cm.reconnectAttempt, 30*time.Second))
+
+	default:
+	}
+}
@@ -373,6 +373,17 @@ func (c *ControlClient) GetAircraftDisplayState(callsign av.ADSBCallsign) (sim.A
 	return state, err
 }
 
+// GetScorecard returns the scorecard for the given TCW; if tcw is empty,
+// the user's own scorecard is returned.
+func (c *ControlClient) GetScorecard(tcw sim.TCW) (sim.Scorecard, error) {
+	var sc sim.Scorecard
+	err := c.client.callWithTimeout(server.GetScorecardRPC, &server.GetScorecardArgs{
+		ControllerToken: c.controllerToken,
+		TCW:             tcw,
+	}, &sc)
+	return sc, err
+}
+
 func (c *ControlClient) GetSerializeSim() (*sim.Sim, error) {
 	var s sim.Sim
 	err := c.client.callWithTimeout(server.GetSerializeSimRPC, c.controllerToken, &s)
@@ -404,6 +415,72 @@ func (c *ControlClient) FastForward() {
 	c.addCall(makeStateUpdateRPCCall(c.client.Go(server.FastForwardRPC, c.controllerToken, &update, nil), &update, nil))
 }
 
+// Rewind rolls the sim back to the latest checkpoint that is at least d
+// before the current sim time. Only privileged TCWs may rewind the sim.
+func (c *ControlClient) Rewind(d time.Duration, callback func(error)) {
+	var update server.SimStateUpdate
+	c.addCall(makeStateUpdateRPCCall(c.client.Go(server.RewindRPC, &server.RewindArgs{
+		ControllerToken: c.controllerToken,
+		Duration:        d,
+	}, &update, nil), &update, callback))
+}
+
+// The following are instructor actions; only privileged TCWs may use them.
+
+func (c *ControlClient) SpawnInstructorAircraft(spawn sim.InstructorSpawn, callback func(error)) {
+	var update server.SimStateUpdate
+	c.addCall(makeStateUpdateRPCCall(c.client.Go(server.SpawnInstructorAircraftRPC, &server.SpawnInstructorAircraftArgs{
+		ControllerToken: c.controllerToken,
+		Spawn:           spawn,
+	}, &update, nil), &update, callback))
+}
+
+func (c *ControlClient) ForcePilotError(callsign av.ADSBCallsign, pe sim.PilotError, callback func(error)) {
+	var update server.SimStateUpdate
+	c.addCall(makeStateUpdateRPCCall(c.client.Go(server.ForcePilotErrorRPC, &server.ForcePilotErrorArgs{
+		ControllerToken: c.controllerToken,
+		Callsign:        callsign,
+		PilotError:      pe,
+	}, &update, nil), &update, callback))
+}
+
+func (c *ControlClient) AdjustPerformance(callsign av.ADSBCallsign, adj sim.PerformanceAdjustment, callback func(error)) {
+	var update server.SimStateUpdate
+	c.addCall(makeStateUpdateRPCCall(c.client.Go(server.AdjustPerformanceRPC, &server.AdjustPerformanceArgs{
+		ControllerToken: c.controllerToken,
+		Callsign:        callsign,
+		Adjustment:      adj,
+	}, &update, nil), &update, callback))
+}
+
+func (c *ControlClient) SetRunwayClosed(airport, runway string, closed bool, callback func(error)) {
+	var update server.SimStateUpdate
+	c.addCall(makeStateUpdateRPCCall(c.client.Go(server.SetRunwayClosedRPC, &server.SetRunwayClosedArgs{
+		ControllerToken: c.controllerToken,
+		Airport:         airport,
+		Runway:          runway,
+		Closed:          closed,
+	}, &update, nil), &update, callback))
+}
+
+func (c *ControlClient) SwitchScenario(scenario string, callback func(error)) {
+	var update server.SimStateUpdate
+	c.addCall(makeStateUpdateRPCCall(c.client.Go(server.SwitchScenarioRPC, &server.SwitchScenarioArgs{
+		ControllerToken: c.controllerToken,
+		ScenarioName:    scenario,
+	}, &update, nil), &update, callback))
+}
+
+func (c *ControlClient) GetScenarioConfigurations(callback func(server.ScenarioConfigurations, error)) {
+	var result server.ScenarioConfigurations
+	c.addCall(makeRPCCall(c.client.Go(server.GetScenarioConfigurationsRPC, c.controllerToken, &result, nil),
+		func(err error) {
+			if callback != nil {
+				callback(result, err)
+			}
+		}))
+}
+
 func (c *ControlClient) SetSimRate(r float32) {
 	c.addCall(makeRPCCall(c.client.Go(server.SetSimRateRPC,
 		&server.SetSimRateArgs{
@@ -609,6 +686,42 @@ func (c *ControlClient) DeconsolidateTCP(tcp sim.TCP, callback func(error)) {
 	}, &update, nil), &update, callback))
 }
 
+// SetAutoControlled enables or disables the autocontroller for the given
+// unstaffed TCW.
+func (c *ControlClient) SetAutoControlled(tcw sim.TCW, auto bool, callback func(error)) {
+	var update server.SimStateUpdate
+	c.addCall(makeStateUpdateRPCCall(c.client.Go(server.SetAutoControlledRPC, &server.SetAutoControlledArgs{
+		ControllerToken: c.controllerToken,
+		TCW:             tcw,
+		AutoControlled:  auto,
+	}, &update, nil), &update, callback))
+}
+
+// LinkFederatedSim links the sim to a sim for a neighboring facility
+// running on another server; see server/federation.go.
+func (c *ControlClient) LinkFederatedSim(address, simName, password, facility, secret string, account server.Credentials,
+	callback func(error)) {
+	c.addCall(makeRPCCall(c.client.Go(server.LinkFederatedSimRPC, &server.LinkFederatedSimArgs{
+		ControllerToken: c.controllerToken,
+		Address:         address,
+		SimName:         simName,
+		Password:        password,
+		Account:         account,
+		Facility:        facility,
+		Secret:          secret,
+	}, nil, nil), callback))
+}
+
+// SetFederationSecret allows a sim for the given facility on another
+// server to link to this one if it gives the secret.
+func (c *ControlClient) SetFederationSecret(facility, secret string, callback func(error)) {
+	c.addCall(makeRPCCall(c.client.Go(server.SetFederationSecretRPC, &server.SetFederationSecretArgs{
+		ControllerToken: c.controllerToken,
+		Facility:        facility,
+		Secret:          secret,
+	}, nil, nil), callback))
+}
+
 func (c *ControlClient) PushFlightStrip(acid sim.ACID, toTCP sim.TCP) {
 	c.addCall(makeRPCCall(c.client.Go(server.PushFlightStripRPC, &server.PushFlightStripArgs{
 		ControllerToken: c.controllerToken,
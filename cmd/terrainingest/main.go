@@ -0,0 +1,157 @@
+// cmd/terrainingest/main.go
+// Copyright(c) 2022-2026 vice contributors, licensed under the GNU Public License, Version 3.
+// SPDX: GPL-3.0-only
+
+// terrainingest converts SRTM elevation data into the compact terrain
+// DEM that vice loads from resources/terrain.zip. It takes one or more
+// SRTM .hgt files (e.g., N35W107.hgt, either 1 or 3 arc-second), each of
+// which covers a 1x1 degree tile, and downsamples them to
+// av.TerrainSamplesPerDegree samples per degree, storing the maximum
+// elevation in feet over each output cell.
+//
+// Usage:
+//
+//	go run ./cmd/terrainingest -o terrain.zip ~/srtm/*.hgt
+//	mv terrain.zip resources/
+package main
+
+import (
+	"archive/zip"
+	"encoding/binary"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+
+	av "github.com/mmp/vice/aviation"
+	"github.com/mmp/vice/math"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+var output = flag.String("o", "terrain.zip", "Output zip file")
+
+// SRTM voids (no data) are marked with this value.
+const srtmVoid = -32768
+
+const feetPerMeter = 3.28084
+
+func main() {
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		fmt.Fprintf(os.Stderr, "usage: terrainingest [-o terrain.zip] file.hgt...\n")
+		os.Exit(1)
+	}
+
+	files := slices.Clone(flag.Args())
+	slices.Sort(files)
+
+	// Downsample the tiles in parallel; the zip file is written
+	// afterward so that its entries are in a consistent order.
+	tiles := make([][]byte, len(files))
+	var eg errgroup.Group
+	eg.SetLimit(runtime.NumCPU())
+	for i, fn := range files {
+		eg.Go(func() error {
+			elev, err := downsampleHGT(fn)
+			if err != nil {
+				return fmt.Errorf("%s: %w", fn, err)
+			}
+			tiles[i], err = compress(av.EncodeTerrainTile(elev))
+			return err
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+
+	f, err := os.Create(*output)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+
+	zw := zip.NewWriter(f)
+	for i, fn := range files {
+		// The data is already compressed, so store it as is.
+		w, err := zw.CreateHeader(&zip.FileHeader{Name: tileName(fn) + ".zst", Method: zip.Store})
+		if err == nil {
+			_, err = w.Write(tiles[i])
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", *output, err)
+			os.Exit(1)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", *output, err)
+		os.Exit(1)
+	}
+	if err := f.Close(); err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", *output, err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Wrote %d tiles to %s\n", len(files), *output)
+}
+
+// tileName returns the tile name (e.g., "N35W107") for an SRTM file.
+func tileName(fn string) string {
+	base := filepath.Base(fn)
+	return strings.ToUpper(strings.TrimSuffix(base, filepath.Ext(base)))
+}
+
+// downsampleHGT reads an SRTM .hgt file and returns its elevations laid
+// out as an av.Terrain tile.
+func downsampleHGT(fn string) ([]int16, error) {
+	b, err := os.ReadFile(fn)
+	if err != nil {
+		return nil, err
+	}
+
+	// .hgt files are square arrays of big-endian int16 elevations in
+	// meters; rows run north to south and the edge rows and columns
+	// duplicate those of the neighboring tiles.
+	var size int
+	switch len(b) {
+	case 2 * 3601 * 3601:
+		size = 3601
+	case 2 * 1201 * 1201:
+		size = 1201
+	default:
+		return nil, fmt.Errorf("%d: unexpected file size", len(b))
+	}
+
+	const n = av.TerrainSamplesPerDegree
+	elev := make([]int16, n*n)
+	for i := range size {
+		// Rows of the output tile run south to north.
+		row := min((size-1-i)*n/(size-1), n-1)
+		for j := range size {
+			col := min(j*n/(size-1), n-1)
+
+			m := int16(binary.BigEndian.Uint16(b[2*(i*size+j):]))
+			if m == srtmVoid {
+				continue
+			}
+			ft := int16(math.Ceil(float32(m) * feetPerMeter))
+			elev[row*n+col] = max(elev[row*n+col], ft)
+		}
+	}
+	return elev, nil
+}
+
+func compress(b []byte) ([]byte, error) {
+	enc, err := zstd.NewWriter(nil, zstd.WithEncoderLevel(zstd.SpeedBestCompression))
+	if err != nil {
+		return nil, err
+	}
+	defer enc.Close()
+	return enc.EncodeAll(b, nil), nil
+}
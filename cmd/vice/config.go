@@ -6,6 +6,7 @@ package main
 
 import (
 	"bytes"
+	"crypto/rand"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -68,6 +69,10 @@ type ConfigNoSim struct {
 
 	UserWorkstation    string
 	ControllerInitials string
+	// ClientKey identifies this installation to servers that don't
+	// require accounts, e.g. so that only it can cancel the sims it has
+	// scheduled.
+	ClientKey string
 
 	ScenarioFile      string
 	VideoMapFile      string
@@ -288,6 +293,9 @@ func LoadOrMakeDefaultConfig(lg *log.Logger) (config *Config, configErr error) {
 	if config.TTSPlaybackSpeed == 0 {
 		config.TTSPlaybackSpeed = tts.DefaultPlaybackSpeed
 	}
+	if config.ClientKey == "" {
+		config.ClientKey = rand.Text()
+	}
 	tts.SetPlaybackSpeed(config.TTSPlaybackSpeed)
 	config.Version = server.ViceSerializeVersion
 
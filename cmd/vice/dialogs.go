@@ -325,10 +325,16 @@ func (c *ConfigurationModalClient) Buttons() []ModalDialogButton {
 
 	// Create button
 	create := ModalDialogButton{
-		text:     "Create",
+		text:     util.Select(c.simConfig.Scheduling(), "Schedule", "Create"),
 		disabled: c.simConfig.ConfigurationDisabled(c.config),
 		action: func() bool {
+			scheduling := c.simConfig.Scheduling()
 			c.simConfig.displayError = c.simConfig.Start(c.config)
+			if c.simConfig.displayError == nil && scheduling {
+				// Nothing is running yet; go back to the scenario
+				// selection screen, where the scheduled sim is listed.
+				prev.action()
+			}
 			return c.simConfig.displayError == nil
 		},
 	}
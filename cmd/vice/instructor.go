@@ -0,0 +1,302 @@
+// cmd/vice/instructor.go
+// Copyright(c) 2022-2026 vice contributors, licensed under the GNU Public License, Version 3.
+// SPDX: GPL-3.0-only
+
+package main
+
+import (
+	"slices"
+	"strings"
+
+	av "github.com/mmp/vice/aviation"
+	"github.com/mmp/vice/client"
+	"github.com/mmp/vice/log"
+	"github.com/mmp/vice/platform"
+	"github.com/mmp/vice/server"
+	"github.com/mmp/vice/sim"
+	"github.com/mmp/vice/util"
+
+	"github.com/AllenDang/cimgui-go/imgui"
+)
+
+// InstructorWindow collects the tools that instructors use to create
+// problems for the controllers during a session.
+type InstructorWindow struct {
+	client *client.ControlClient
+	lg     *log.Logger
+
+	// Spawn
+	callsign   string
+	acType     string
+	vfr        bool
+	position   string // fix or lat-long
+	altitude   int32
+	heading    int32
+	speed      int32
+	controller sim.TCP
+
+	// Selected aircraft
+	aircraft    av.ADSBCallsign
+	performance sim.PerformanceAdjustment
+
+	// Runway closures
+	airport string
+
+	// Runway configuration
+	configurations   *server.ScenarioConfigurations
+	fetchingConfigs  bool
+	selectedScenario string
+
+	// Federation
+	fedAddress  string
+	fedSimName  string
+	fedPassword string
+	fedFacility string
+	fedSecret   string
+	fedAccount  server.Credentials
+}
+
+func MakeInstructorWindow(c *client.ControlClient, lg *log.Logger) *InstructorWindow {
+	return &InstructorWindow{
+		client:      c,
+		lg:          lg,
+		altitude:    5000,
+		heading:     360,
+		performance: sim.PerformanceAdjustment{Climb: 1, Descent: 1, Acceleration: 1},
+		airport:     c.State.PrimaryAirport,
+	}
+}
+
+func (iw *InstructorWindow) Draw(show *bool, p platform.Platform, config *Config) {
+	applyPinWindowClass("Instructor", config, p)
+	imgui.BeginV("Instructor", show, imgui.WindowFlagsAlwaysAutoResize)
+	drawPinButton("Instructor", config, p)
+
+	reportError := func(what string) func(error) {
+		return func(err error) {
+			if err != nil {
+				ShowErrorDialog(p, iw.lg, "Unable to %s: %v", what, err)
+			}
+		}
+	}
+
+	if imgui.CollapsingHeaderBoolPtr("Spawn Aircraft", nil) {
+		iw.drawSpawn(p, reportError)
+	}
+	if imgui.CollapsingHeaderBoolPtr("Pilot Errors and Performance", nil) {
+		iw.drawAircraft(reportError)
+	}
+	if imgui.CollapsingHeaderBoolPtr("Runway Closures", nil) {
+		iw.drawRunways(reportError)
+	}
+	if imgui.CollapsingHeaderBoolPtr("Runway Configuration", nil) {
+		iw.drawConfiguration(reportError)
+	}
+	if imgui.CollapsingHeaderBoolPtr("Federation", nil) {
+		iw.drawFederation(reportError)
+	}
+
+	imgui.End()
+}
+
+func (iw *InstructorWindow) drawSpawn(p platform.Platform, reportError func(string) func(error)) {
+	imgui.SetNextItemWidth(100)
+	imgui.InputTextWithHint("Callsign", "AAL123", &iw.callsign, imgui.InputTextFlagsCharsUppercase, nil)
+	imgui.SetNextItemWidth(100)
+	imgui.InputTextWithHint("Aircraft type", "B738", &iw.acType, imgui.InputTextFlagsCharsUppercase, nil)
+	imgui.SetNextItemWidth(200)
+	imgui.InputTextWithHint("Position", "fix or lat/long", &iw.position, imgui.InputTextFlagsCharsUppercase, nil)
+	imgui.SetNextItemWidth(100)
+	imgui.InputIntV("Altitude", &iw.altitude, 100, 1000, 0)
+	imgui.SetNextItemWidth(100)
+	imgui.InputIntV("Heading", &iw.heading, 5, 30, 0)
+	imgui.SetNextItemWidth(100)
+	imgui.InputIntV("Speed (0: default)", &iw.speed, 10, 50, 0)
+
+	if imgui.RadioButtonBool("IFR", !iw.vfr) {
+		iw.vfr = false
+	}
+	imgui.SameLine()
+	if imgui.RadioButtonBool("VFR", iw.vfr) {
+		iw.vfr = true
+	}
+
+	if !iw.vfr {
+		imgui.SetNextItemWidth(100)
+		if imgui.BeginCombo("Controller", string(iw.controller)) {
+			for pos := range util.SortedMap(iw.client.State.Controllers) {
+				if imgui.SelectableBoolV(string(pos), pos == iw.controller, 0, imgui.Vec2{}) {
+					iw.controller = pos
+				}
+			}
+			imgui.EndCombo()
+		}
+	}
+
+	if imgui.Button("Spawn") {
+		pos, ok := iw.client.State.Locate(iw.position)
+		if !ok {
+			ShowErrorDialog(p, iw.lg, "%s: unable to find position", iw.position)
+		} else {
+			iw.client.SpawnInstructorAircraft(sim.InstructorSpawn{
+				Callsign:     av.ADSBCallsign(strings.TrimSpace(iw.callsign)),
+				AircraftType: strings.TrimSpace(iw.acType),
+				Rules:        util.Select(iw.vfr, av.FlightRulesVFR, av.FlightRulesIFR),
+				Position:     pos,
+				Altitude:     float32(iw.altitude),
+				Heading:      int(iw.heading),
+				Speed:        float32(iw.speed),
+				Controller:   iw.controller,
+			}, reportError("spawn aircraft"))
+		}
+	}
+}
+
+func (iw *InstructorWindow) drawAircraft(reportError func(string) func(error)) {
+	imgui.SetNextItemWidth(150)
+	if imgui.BeginCombo("Aircraft", string(iw.aircraft)) {
+		for callsign := range util.SortedMap(iw.client.State.Tracks) {
+			if imgui.SelectableBoolV(string(callsign), callsign == iw.aircraft, 0, imgui.Vec2{}) {
+				iw.aircraft = callsign
+			}
+		}
+		imgui.EndCombo()
+	}
+	if _, ok := iw.client.State.Tracks[iw.aircraft]; !ok {
+		iw.aircraft = ""
+		return
+	}
+
+	for _, pe := range []sim.PilotError{sim.PilotErrorMixUp, sim.PilotErrorAltitudeBust, sim.PilotErrorNORDO} {
+		if imgui.Button(pe.String()) {
+			iw.client.ForcePilotError(iw.aircraft, pe, reportError("force pilot error"))
+		}
+		imgui.SameLine()
+	}
+	if imgui.Button("Restore") {
+		iw.client.ForcePilotError(iw.aircraft, sim.PilotErrorNone, reportError("restore pilot"))
+	}
+	if imgui.IsItemHovered() {
+		imgui.SetTooltip("Cancel pending pilot errors and restore radio contact")
+	}
+
+	const minFactor, maxFactor = 0.1, 2
+	imgui.SetNextItemWidth(150)
+	imgui.SliderFloatV("Climb rate", &iw.performance.Climb, minFactor, maxFactor, "%.2fx", 0)
+	imgui.SetNextItemWidth(150)
+	imgui.SliderFloatV("Descent rate", &iw.performance.Descent, minFactor, maxFactor, "%.2fx", 0)
+	imgui.SetNextItemWidth(150)
+	imgui.SliderFloatV("Acceleration", &iw.performance.Acceleration, minFactor, maxFactor, "%.2fx", 0)
+	if imgui.Button("Apply performance") {
+		iw.client.AdjustPerformance(iw.aircraft, iw.performance, reportError("adjust performance"))
+	}
+}
+
+func (iw *InstructorWindow) drawRunways(reportError func(string) func(error)) {
+	imgui.SetNextItemWidth(100)
+	if imgui.BeginCombo("Airport", iw.airport) {
+		for ap := range util.SortedMap(iw.client.State.Airports) {
+			if imgui.SelectableBoolV(ap, ap == iw.airport, 0, imgui.Vec2{}) {
+				iw.airport = ap
+			}
+		}
+		imgui.EndCombo()
+	}
+
+	ap, ok := av.DB.Airports[iw.airport]
+	if !ok {
+		return
+	}
+	closed := iw.client.State.ClosedRunways[iw.airport]
+	for _, rwy := range ap.Runways {
+		isClosed := slices.Contains(closed, rwy.Id) || slices.Contains(closed, av.OppositeRunwayId(rwy.Id))
+		if imgui.Checkbox("Close "+rwy.Id, &isClosed) {
+			iw.client.SetRunwayClosed(iw.airport, rwy.Id, isClosed, reportError("change runway closure"))
+		}
+	}
+}
+
+func (iw *InstructorWindow) drawConfiguration(reportError func(string) func(error)) {
+	if iw.configurations == nil {
+		if !iw.fetchingConfigs {
+			iw.fetchingConfigs = true
+			iw.client.GetScenarioConfigurations(func(cfg server.ScenarioConfigurations, err error) {
+				iw.fetchingConfigs = false
+				if err != nil {
+					reportError("get runway configurations")(err)
+				} else {
+					iw.configurations = &cfg
+				}
+			})
+		}
+		imgui.Text("Loading...")
+		return
+	}
+
+	imgui.Text("Current: " + iw.configurations.Current)
+	if len(iw.configurations.Available) == 0 {
+		imgui.Text("No other configurations are available")
+		return
+	}
+
+	imgui.SetNextItemWidth(250)
+	if imgui.BeginCombo("Configuration", iw.selectedScenario) {
+		for _, name := range iw.configurations.Available {
+			if imgui.SelectableBoolV(name, name == iw.selectedScenario, 0, imgui.Vec2{}) {
+				iw.selectedScenario = name
+			}
+		}
+		imgui.EndCombo()
+	}
+
+	if iw.selectedScenario != "" && imgui.Button("Switch") {
+		iw.client.SwitchScenario(iw.selectedScenario, func(err error) {
+			if err != nil {
+				reportError("switch runway configuration")(err)
+			}
+			// Refetch so that the old configuration becomes selectable.
+			iw.configurations = nil
+			iw.selectedScenario = ""
+		})
+	}
+	if imgui.IsItemHovered() {
+		imgui.SetTooltip("Change the active runways and controller assignments; airborne aircraft are kept")
+	}
+}
+
+func (iw *InstructorWindow) drawFederation(reportError func(string) func(error)) {
+	if facs := iw.client.State.FederatedFacilities; len(facs) > 0 {
+		imgui.Text("Linked: " + strings.Join(facs, ", "))
+	} else {
+		imgui.Text("Not linked to any other sims")
+	}
+	imgui.Separator()
+
+	imgui.SetNextItemWidth(200)
+	imgui.InputTextWithHint("Server", "host:port", &iw.fedAddress, 0, nil)
+	imgui.SetNextItemWidth(200)
+	imgui.InputTextWithHint("Sim name", "", &iw.fedSimName, 0, nil)
+	imgui.SetNextItemWidth(200)
+	imgui.InputTextWithHint("Sim password", "", &iw.fedPassword, imgui.InputTextFlagsPassword, nil)
+	imgui.SetNextItemWidth(100)
+	imgui.InputTextWithHint("Facility", "ZNY", &iw.fedFacility, imgui.InputTextFlagsCharsUppercase, nil)
+	imgui.SetNextItemWidth(200)
+	imgui.InputTextWithHint("Link secret", "", &iw.fedSecret, imgui.InputTextFlagsPassword, nil)
+	imgui.SetNextItemWidth(200)
+	imgui.InputTextWithHint("Account token", "if required", &iw.fedAccount.Token, imgui.InputTextFlagsPassword, nil)
+
+	if imgui.Button("Link") {
+		iw.client.LinkFederatedSim(strings.TrimSpace(iw.fedAddress), iw.fedSimName, iw.fedPassword,
+			strings.TrimSpace(iw.fedFacility), iw.fedSecret, iw.fedAccount, reportError("link sim"))
+	}
+	if imgui.IsItemHovered() {
+		imgui.SetTooltip("Link to the sim on the other server; its instructor must have allowed the link with the same secret")
+	}
+	imgui.SameLine()
+	if imgui.Button("Allow link") {
+		iw.client.SetFederationSecret(strings.TrimSpace(iw.fedFacility), iw.fedSecret, reportError("allow link"))
+	}
+	if imgui.IsItemHovered() {
+		imgui.SetTooltip("Allow a sim for the facility on another server to link to this one using the secret")
+	}
+}
@@ -57,6 +57,7 @@ var (
 	videoMapFilename      = flag.String("videomap", "", "`filename` of JSON file with video map definitions")
 	scenarioBriefFilename = flag.String("scenariobrief", "", "`filename` of markdown file with a scenario brief")
 	broadcastMessage      = flag.String("broadcast", "", "`message` to broadcast to all active clients on the server")
+	broadcastUsername     = flag.String("username", "", "`username` to authenticate with server for broadcast message, if it requires accounts")
 	broadcastPassword     = flag.String("password", "", "`password` to authenticate with server for broadcast message")
 	resetSim              = flag.Bool("resetsim", false, "discard the saved simulation and do not try to resume it")
 	showRoutes            = flag.String("routes", "", "display the STARS, SIDs, and approaches known for the given `airport`")
@@ -69,6 +70,9 @@ var (
 	replayMode            = flag.Bool("replay", false, "replay scenario from saved config")
 	replayDuration        = flag.String("replay-duration", "3600", "replay `duration` in seconds or 'until:CALLSIGN'")
 	waypointCommands      = flag.String("waypoint-commands", "", "waypoint `commands` in format 'FIX:CMD CMD CMD, FIX:CMD ...,'")
+	recordDir             = flag.String("record", "", "`directory` in which to write recordings of local sims for later playback")
+	playbackFile          = flag.String("playback", "", "re-run the recorded sim session in `file`")
+	playbackExtra         = flag.Int("playback-extra", 0, "`seconds` to keep running a played-back session after its last recorded input")
 	starsRandoms          = flag.Bool("starsrandoms", false, "run STARS command fuzz testing with full UI (randomly picks a scenario)")
 )
 
@@ -325,8 +329,46 @@ func runReplay(config *Config, configErr error, lg *log.Logger) error {
 	return config.Sim.ReplayScenario(*waypointCommands, *replayDuration, lg)
 }
 
+func runPlayback(lg *log.Logger) error {
+	if err := cliInit(); err != nil {
+		return err
+	}
+
+	nav.InitNavLog(*navLog, *navLogCategories, *navLogCallsign)
+
+	f, err := os.Open(*playbackFile)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	rec, err := sim.LoadRecording(f)
+	if err != nil {
+		return fmt.Errorf("%s: %w", *playbackFile, err)
+	}
+
+	fmt.Printf("Playing back %d inputs recorded starting %s (seed %d)\n", len(rec.Inputs),
+		rec.StartTime.Format(time.RFC3339), rec.Seed)
+
+	startTime := time.Now()
+	startSimTime := rec.Sim.State.SimTime
+
+	s, err := rec.Playback(nil, time.Duration(*playbackExtra)*time.Second, lg)
+	if err != nil {
+		return err
+	}
+
+	elapsed := time.Since(startTime)
+	simElapsed := s.SimTime().Sub(startSimTime)
+	fmt.Printf("Playback complete: %s simulated in %.2f seconds (%.1fx real-time)\n",
+		simElapsed, elapsed.Seconds(), simElapsed.Seconds()/elapsed.Seconds())
+	fmt.Printf("Final aircraft count: %d\n", len(s.Aircraft))
+
+	return nil
+}
+
 func runBroadcast(lg *log.Logger) error {
-	client.BroadcastMessage(*serverAddress, *broadcastMessage, *broadcastPassword, lg)
+	client.BroadcastMessage(*serverAddress, *broadcastMessage, *broadcastUsername, *broadcastPassword, lg)
 	return nil
 }
 
@@ -555,7 +597,7 @@ func runGUI(config *Config, configErr error, lg *log.Logger) error {
 		av.InitDB()
 		nav.InitNavLog(*navLog, *navLogCategories, *navLogCallsign)
 		mgr, errorLogger, extraScenarioErrors = client.MakeServerManager(*serverAddress, *scenarioFilename,
-			*videoMapFilename, *scenarioBriefFilename, &config.DisableTextToSpeech, lg,
+			*videoMapFilename, *scenarioBriefFilename, *recordDir, &config.DisableTextToSpeech, lg,
 			func(c *client.ControlClient) { // updated client
 				if c != nil {
 					// Determine if this is a STARS or ERAM scenario
@@ -809,6 +851,8 @@ func main() {
 		err = runSimulation(lg)
 	case *replayMode:
 		err = runReplay(config, configErr, lg)
+	case *playbackFile != "":
+		err = runPlayback(lg)
 	case *broadcastMessage != "":
 		err = runBroadcast(lg)
 	case *runServer:
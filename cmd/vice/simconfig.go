@@ -54,6 +54,12 @@ type NewSimConfiguration struct {
 	// New UI state for improved flow
 	filterText string // search/filter for scenario selection
 
+	// Scheduling sims for later and signing up for them
+	scheduleForLater     bool
+	scheduleStart        string // local time, scheduleTimeFormat
+	scheduleReservations string // e.g. "1N=MP 2N 3N"; see parseReservations
+	signUpPassword       string
+
 	// Weather filter UI state
 	weatherFilter      wx.WeatherFilter
 	weatherFilterError string
@@ -348,9 +354,40 @@ func (c *NewSimConfiguration) ConfigurationDisabled(config *Config) bool {
 	if len(config.ControllerInitials) != 2 {
 		return true
 	}
+	if c.Scheduling() {
+		if _, err := time.ParseInLocation(scheduleTimeFormat, c.scheduleStart, time.Local); err != nil {
+			return true
+		}
+		if _, err := parseReservations(c.scheduleReservations); err != nil {
+			return true
+		}
+	}
 	return c.newSimType == NewSimCreateRemote && (c.NewSimName == "" || (c.RequirePassword && c.Password == ""))
 }
 
+const scheduleTimeFormat = "2006-01-02 15:04"
+
+// Scheduling returns true if the sim is being scheduled to start later
+// rather than being started now.
+func (c *NewSimConfiguration) Scheduling() bool {
+	return c.newSimType == NewSimCreateRemote && c.scheduleForLater
+}
+
+// parseReservations parses a list of positions for a scheduled sim, each
+// optionally followed by "=" and the initials of the controller that it's
+// reserved for, e.g. "1N=MP 2N 3N".
+func parseReservations(s string) (map[sim.TCP]string, error) {
+	r := make(map[sim.TCP]string)
+	for _, f := range strings.FieldsFunc(s, func(ch rune) bool { return ch == ' ' || ch == ',' }) {
+		tcp, initials, _ := strings.Cut(f, "=")
+		if tcp == "" || (initials != "" && len(initials) != 2) {
+			return nil, fmt.Errorf("%s: invalid position reservation", f)
+		}
+		r[sim.TCP(tcp)] = initials
+	}
+	return r, nil
+}
+
 // getARTCCForFacility returns the ARTCC code for a given facility.
 func getARTCCForFacility(facility string, catalog *server.ScenarioCatalog) string {
 	if catalog != nil && catalog.ARTCC != "" {
@@ -467,6 +504,10 @@ func (c *NewSimConfiguration) DrawScenarioSelectionUI(p platform.Platform, confi
 
 			imgui.EndTable()
 		}
+
+		if scheduled := c.mgr.RemoteServer.GetScheduledSims(); len(scheduled) > 0 {
+			c.drawScheduledSims(scheduled, config)
+		}
 	} else {
 		imgui.PushStyleColorVec4(imgui.ColText, imgui.Vec4{1, .5, .5, 1})
 		imgui.Text("Unable to connect to the vice server; only local scenarios are available.")
@@ -949,10 +990,9 @@ func (c *NewSimConfiguration) DrawScenarioSelectionUI(p platform.Platform, confi
 		// Handle the case where selected TCW is no longer valid
 		if c.selectedTCW != "" {
 			if state, ok := rs.CurrentConsolidation[c.selectedTCW]; ok {
-				// Check if TCW is still valid for current mode
-				if c.showReliefPositions && !state.IsOccupied() {
-					c.selectedTCW = ""
-				} else if !c.showReliefPositions && state.IsOccupied() {
+				// Check if TCW is still valid for current mode; observers
+				// may watch any of them.
+				if !c.joinRequest.Observing && c.showReliefPositions != state.IsOccupied() {
 					c.selectedTCW = ""
 				}
 			} else {
@@ -1018,8 +1058,16 @@ func (c *NewSimConfiguration) DrawScenarioSelectionUI(p platform.Platform, confi
 			return result
 		}
 
+		if imgui.Checkbox("Observe only", &c.joinRequest.Observing) {
+			c.selectedTCW = ""
+			c.selectedTCPs = nil
+		}
+		if imgui.IsItemHovered() {
+			imgui.SetTooltip("Watch a position's scope and radio without signing in or controlling traffic")
+		}
+
 		// Checkbox for showing relief positions (only if some TCWs are occupied)
-		if len(coveredPrimaryTCPs) > 0 {
+		if len(coveredPrimaryTCPs) > 0 && !c.joinRequest.Observing {
 			if imgui.Checkbox("Join as relief (show occupied positions)", &c.showReliefPositions) {
 				// Clear selection when mode changes
 				c.selectedTCW = ""
@@ -1052,7 +1100,7 @@ func (c *NewSimConfiguration) DrawScenarioSelectionUI(p platform.Platform, confi
 				imgui.CalcTextSizeV("WW", false, 0).X + style.ItemSpacing().X
 			for tcw, cons := range util.SortedMap(rs.CurrentConsolidation) {
 				// Filter: relief shows only occupied, normal shows only unoccupied
-				if c.showReliefPositions != cons.IsOccupied() {
+				if !c.joinRequest.Observing && c.showReliefPositions != cons.IsOccupied() {
 					continue
 				}
 				// Skip internal positions
@@ -1078,18 +1126,22 @@ func (c *NewSimConfiguration) DrawScenarioSelectionUI(p platform.Platform, confi
 						c.selectedTCPs = nil
 					}
 				}
-				// Tooltip shows positions (and controller for relief mode)
-				if c.showReliefPositions && imgui.IsItemHovered() {
+				// Tooltip shows positions (and controller for relief and observer modes)
+				if (c.showReliefPositions || c.joinRequest.Observing) && imgui.IsItemHovered() {
 					tooltip := fmtTCPs(cons)
 					if len(cons.Initials) > 0 {
 						tooltip += " (" + strings.Join(cons.Initials, ", ") + ")"
 					}
 					imgui.SetTooltip(tooltip)
 				}
+				if reserved, ok := rs.Reservations[tcw]; ok && !c.showReliefPositions && !c.joinRequest.Observing &&
+					imgui.IsItemHovered() {
+					imgui.SetTooltip("Reserved for " + reserved)
+				}
 			}
 
-			// Row 2: Select positions (only for unoccupied TCW selection, not relief)
-			if c.selectedTCW != "" && !c.showReliefPositions {
+			// Row 2: Select positions (only for unoccupied TCW selection, not relief or observing)
+			if c.selectedTCW != "" && !c.showReliefPositions && !c.joinRequest.Observing {
 				imgui.TableNextRow()
 				imgui.TableNextColumn()
 				imgui.Text("Select positions:")
@@ -1170,6 +1222,11 @@ func (c *NewSimConfiguration) DrawScenarioSelectionUI(p platform.Platform, confi
 				imgui.InputTextWithHint("##pw", "", &c.joinRequest.Password, 0, nil)
 			}
 
+			// Row 5: Account (if the server requires one)
+			if c.selectedServer.RequiresAccount {
+				drawAccountRows(&c.NewSimRequest.Account)
+			}
+
 			imgui.EndTable()
 		}
 
@@ -1178,6 +1235,107 @@ func (c *NewSimConfiguration) DrawScenarioSelectionUI(p platform.Platform, confi
 	return false
 }
 
+// drawScheduledSims draws the sims that have been scheduled on the remote
+// server and allows the user to sign up for them.
+func (c *NewSimConfiguration) drawScheduledSims(scheduled map[string]*server.ScheduledSim, config *Config) {
+	if !imgui.CollapsingHeaderBoolPtr(fmt.Sprintf("Upcoming sessions (%d)", len(scheduled)), nil) {
+		return
+	}
+
+	imgui.Text("Initials:")
+	imgui.SameLine()
+	imgui.SetNextItemWidth(50)
+	imgui.InputTextWithHint("##signupinitials", "XX", &config.ControllerInitials, imgui.InputTextFlagsCharsUppercase, nil)
+	if util.SeqContainsFunc(maps.Values(scheduled), func(ss *server.ScheduledSim) bool { return ss.RequirePassword }) {
+		imgui.SameLine()
+		imgui.Text("Password:")
+		imgui.SameLine()
+		imgui.SetNextItemWidth(150)
+		imgui.InputTextWithHint("##signuppw", "if required", &c.signUpPassword, 0, nil)
+	}
+
+	names := slices.SortedFunc(maps.Keys(scheduled), func(a, b string) int {
+		return scheduled[a].StartAt.Compare(scheduled[b].StartAt)
+	})
+
+	flags := imgui.TableFlagsBordersV | imgui.TableFlagsBordersOuterH | imgui.TableFlagsRowBg | imgui.TableFlagsSizingFixedFit
+	if imgui.BeginTableV("scheduled", 6, flags, imgui.Vec2{}, 0) {
+		imgui.TableSetupColumn("Name")
+		imgui.TableSetupColumn("Scenario")
+		imgui.TableSetupColumn("Starts")
+		imgui.TableSetupColumn("Positions")
+		imgui.TableSetupColumn("Waitlist")
+		imgui.TableSetupColumn("##signup")
+		imgui.TableHeadersRow()
+
+		for _, name := range names {
+			ss := scheduled[name]
+
+			imgui.TableNextRow()
+			imgui.TableNextColumn()
+			imgui.Text(name)
+			imgui.TableNextColumn()
+			imgui.Text(ss.Facility + " " + ss.ScenarioName)
+			imgui.TableNextColumn()
+			imgui.Text(ss.StartAt.Local().Format("Mon Jan 2 15:04") + " (in " +
+				time.Until(ss.StartAt).Round(time.Minute).String() + ")")
+			imgui.TableNextColumn()
+			var positions []string
+			for tcp, initials := range util.SortedMap(ss.Reservations) {
+				positions = append(positions, string(tcp)+": "+util.Select(initials == "", "open", initials))
+			}
+			imgui.Text(strings.Join(positions, ", "))
+			imgui.TableNextColumn()
+			imgui.Text(strings.Join(ss.Waitlist, ", "))
+
+			imgui.TableNextColumn()
+			req := server.SimSignUpRequest{
+				SimName:   name,
+				Initials:  config.ControllerInitials,
+				Password:  c.signUpPassword,
+				Account:   c.NewSimRequest.Account,
+				ClientKey: config.ClientKey,
+			}
+			if ss.IsSignedUp(config.ControllerInitials) {
+				if imgui.Button("Cancel##" + name) {
+					c.displayError = c.mgr.CancelSimSignUp(req, c.mgr.RemoteServer)
+				}
+			} else {
+				noInitials := len(config.ControllerInitials) != 2
+				if noInitials {
+					imgui.BeginDisabled()
+				}
+				if imgui.Button("Sign up##" + name) {
+					_, c.displayError = c.mgr.SignUpForSim(req, c.mgr.RemoteServer)
+				}
+				if noInitials {
+					imgui.EndDisabled()
+				}
+			}
+		}
+		imgui.EndTable()
+	}
+	imgui.Separator()
+}
+
+// drawAccountRows draws table rows for the user's account credentials on
+// servers that require authentication.
+func drawAccountRows(acct *server.Credentials) {
+	imgui.TableNextRow()
+	imgui.TableNextColumn()
+	imgui.Text("Username:")
+	imgui.TableNextColumn()
+	imgui.SetNextItemWidth(150)
+	imgui.InputTextWithHint("##username", "", &acct.Username, 0, nil)
+
+	imgui.TableNextRow()
+	imgui.TableNextColumn()
+	imgui.Text("Account password:")
+	imgui.TableNextColumn()
+	imgui.SetNextItemWidth(150)
+	imgui.InputTextWithHint("##accountpw", "", &acct.Password, imgui.InputTextFlagsPassword, nil)
+}
+
 // drawSectionHeader draws a styled section header
 func drawSectionHeader(title string) {
 	imgui.Spacing()
@@ -1251,6 +1409,40 @@ func (c *NewSimConfiguration) DrawConfigurationUI(p platform.Platform, config *C
 				imgui.PopStyleColor()
 			}
 		}
+		if c.selectedServer.RequiresAccount {
+			if imgui.BeginTableV("account", 2, imgui.TableFlagsSizingFixedFit, imgui.Vec2{}, 0) {
+				drawAccountRows(&c.NewSimRequest.Account)
+				imgui.EndTable()
+			}
+		}
+
+		if imgui.Checkbox("Schedule for later", &c.scheduleForLater) && c.scheduleForLater && c.scheduleStart == "" {
+			c.scheduleStart = time.Now().Add(24 * time.Hour).Truncate(time.Hour).Format(scheduleTimeFormat)
+		}
+		if c.scheduleForLater {
+			imgui.SetNextItemWidth(150)
+			imgui.InputTextWithHint("Start (local time)", scheduleTimeFormat, &c.scheduleStart, 0, nil)
+			if _, err := time.ParseInLocation(scheduleTimeFormat, c.scheduleStart, time.Local); err != nil {
+				imgui.SameLine()
+				imgui.PushStyleColorVec4(imgui.ColText, imgui.Vec4{.7, .1, .1, 1})
+				imgui.Text(renderer.FontAwesomeIconExclamationTriangle)
+				imgui.PopStyleColor()
+			}
+
+			imgui.SetNextItemWidth(300)
+			imgui.InputTextWithHint("Positions", "1N=XX 2N 3N", &c.scheduleReservations,
+				imgui.InputTextFlagsCharsUppercase, nil)
+			if imgui.IsItemHovered() {
+				imgui.SetTooltip("Positions controllers can sign up for, optionally reserved for specific\n" +
+					"controllers' initials. Once all are taken, sign-ups go on a waitlist.")
+			}
+			if _, err := parseReservations(c.scheduleReservations); err != nil {
+				imgui.SameLine()
+				imgui.PushStyleColorVec4(imgui.ColText, imgui.Vec4{.7, .1, .1, 1})
+				imgui.Text(renderer.FontAwesomeIconExclamationTriangle + " " + err.Error())
+				imgui.PopStyleColor()
+			}
+		}
 		imgui.Spacing()
 	}
 
@@ -1384,7 +1576,7 @@ func (c *NewSimConfiguration) Start(config *Config) error {
 		// Set TCW from selection
 		c.joinRequest.TCW = c.selectedTCW
 		// Convert selected TCPs map to slice (only for non-relief)
-		if !c.joinRequest.JoiningAsRelief {
+		if !c.joinRequest.JoiningAsRelief && !c.joinRequest.Observing {
 			var tcps []sim.TCP
 			for tcp, selected := range c.selectedTCPs {
 				if selected {
@@ -1394,10 +1586,33 @@ func (c *NewSimConfiguration) Start(config *Config) error {
 			c.joinRequest.SelectedTCPs = tcps
 		}
 		c.joinRequest.Initials = config.ControllerInitials
+		c.joinRequest.Account = c.NewSimRequest.Account
 		if err := c.mgr.ConnectToSim(c.joinRequest, config.ControllerInitials, c.selectedServer, c.lg); err != nil {
 			c.lg.Errorf("ConnectToSim failed: %v", err)
 			return err
 		}
+	} else if c.Scheduling() {
+		startAt, err := time.ParseInLocation(scheduleTimeFormat, c.scheduleStart, time.Local)
+		if err != nil {
+			return err
+		}
+		reservations, err := parseReservations(c.scheduleReservations)
+		if err != nil {
+			return err
+		}
+		c.NewSimRequest.Initials = config.ControllerInitials
+		if err := c.mgr.ScheduleSim(server.ScheduleSimRequest{
+			NewSimRequest: c.NewSimRequest,
+			StartAt:       startAt,
+			Reservations:  reservations,
+			ClientKey:     config.ClientKey,
+		}, c.selectedServer); err != nil {
+			c.lg.Errorf("ScheduleSim failed: %v", err)
+			return err
+		}
+		// Pick a new name in case another sim is scheduled.
+		c.NewSimName = server.MakeNewSimRequest().NewSimName
+		c.scheduleForLater = false
 	} else {
 		// Create sim configuration for new sim
 		c.NewSimRequest.Initials = config.ControllerInitials
@@ -1548,6 +1763,8 @@ func drawArrivalUI(lc *sim.LaunchConfig, p platform.Platform) (changed bool) {
 	changed = imgui.SliderFloatV("Arrival/overflight rate scale", &lc.InboundFlowRateScale, 0, 5, "%.1f", imgui.SliderFlagsNoInput) || changed
 
 	changed = imgui.SliderFloatV("Go around probability", &lc.GoAroundRate, 0, 1, "%.02f", 0) || changed
+	changed = imgui.SliderFloatV("NTZ blunder probability", &lc.NTZBlunderRate, 0, 1, "%.02f", 0) || changed
+	changed = imgui.Checkbox("Arrivals declare minimum and emergency fuel", &lc.FuelEmergencies) || changed
 
 	changed = imgui.Checkbox("Include random arrival pushes", &lc.ArrivalPushes) || changed
 	if !lc.ArrivalPushes {
@@ -22,6 +22,7 @@ import (
 	"github.com/mmp/vice/panes"
 	"github.com/mmp/vice/platform"
 	"github.com/mmp/vice/renderer"
+	"github.com/mmp/vice/server"
 	"github.com/mmp/vice/sim"
 	"github.com/mmp/vice/tts"
 	"github.com/mmp/vice/util"
@@ -49,6 +50,7 @@ var (
 		newReleaseDialogChan chan *NewReleaseModalClient
 
 		launchControlWindow *LaunchControlWindow
+		instructorWindow    *InstructorWindow
 
 		// Scenario routes to draw on the scope
 		showSettings      bool
@@ -56,6 +58,7 @@ var (
 		showLaunchControl bool
 		showMessages      bool
 		showFlightStrips  bool
+		showInstructor    bool
 
 		brief struct {
 			markdown             string
@@ -211,6 +214,26 @@ func uiDraw(mgr *client.ConnectionManager, config *Config, p platform.Platform,
 			if controlClient.State.Paused {
 				imgui.EndDisabled()
 			}
+
+			if controlClient.State.TCWIsPrivileged(controlClient.State.UserTCW) {
+				if imgui.Button(renderer.FontAwesomeIconHistory) {
+					controlClient.Rewind(2*time.Minute, func(err error) {
+						if err != nil {
+							ShowErrorDialog(p, lg, "Unable to rewind the simulation: %v", err)
+						}
+					})
+				}
+				if imgui.IsItemHovered() {
+					imgui.SetTooltip("Rewind simulation by 2 minutes")
+				}
+
+				if imgui.Button(renderer.FontAwesomeIconBolt) {
+					ui.showInstructor = !ui.showInstructor
+				}
+				if imgui.IsItemHovered() {
+					imgui.SetTooltip("Toggle instructor window")
+				}
+			}
 		}
 
 		if imgui.Button(renderer.FontAwesomeIconRedo) {
@@ -281,6 +304,17 @@ func uiDraw(mgr *client.ConnectionManager, config *Config, p platform.Platform,
 			imgui.SetTooltip("Display online vice documentation")
 		}
 
+		if controlClient != nil && controlClient.Reconnecting() {
+			imgui.PushStyleColorVec4(imgui.ColText, imgui.Vec4{1, 1, 0, 1})
+			imgui.TextUnformatted(renderer.FontAwesomeIconExclamationTriangle + " Reconnecting to server...")
+			imgui.PopStyleColor()
+			if imgui.IsItemHovered() {
+				imgui.SetTooltip(fmt.Sprintf("The connection to the server was lost. You will be signed off your position after %s\n"+
+					"but will be signed back on to it if the connection is restored within %s and no one else has taken it.",
+					server.StateUpdateKick, server.ReconnectGracePeriod))
+			}
+		}
+
 		// Handle PTT key for STT recording
 		uiHandlePTTKey(p, controlClient, config, lg)
 
@@ -352,6 +386,13 @@ func uiDraw(mgr *client.ConnectionManager, config *Config, p platform.Platform,
 			ui.launchControlWindow.Draw(p, config)
 		}
 
+		if ui.showInstructor && controlClient.State.TCWIsPrivileged(controlClient.State.UserTCW) {
+			if ui.instructorWindow == nil {
+				ui.instructorWindow = MakeInstructorWindow(controlClient, lg)
+			}
+			ui.instructorWindow.Draw(&ui.showInstructor, p, config)
+		}
+
 		if ui.showMessages {
 			applyPinWindowClass("Messages", config, p)
 		}
@@ -397,6 +438,7 @@ func uiDraw(mgr *client.ConnectionManager, config *Config, p platform.Platform,
 
 func uiResetControlClient(c *client.ControlClient, config *Config, p platform.Platform, lg *log.Logger) {
 	ui.launchControlWindow = nil
+	ui.instructorWindow = nil
 	ui.showScenarioInfo = !config.NoBriefAtScenarioStart
 	clear(acknowledgedATIS)
 }
@@ -496,6 +538,8 @@ var secondaryAcCommands = [][3]string{
 	{"*H_fix*", `"Hold at _fix_ (published hold)".`, "*HJIMEE*"},
 	{"*H_fix*/[opts]",
 		`"Hold at _fix_ (controller-specified)." Options: *L*/*R* (turns), *xxNM*/*xxM* (legs), *Rxxx* (radial, req'd).`, "*HJIMEE/L/5NM/R090*"},
+	{"*EFC[_hhmm]*", `"Expect further clearance at _hhmm_." If no time is given, the next EFC time for the holding stack.`, "*EFC1530*"},
+	{"*DIH*", `"Descend in the hold" to the next lower altitude in the stack.`, "*DIH*"},
 	{"*C_fix*/A_alt*/S_kts*/M_mach*",
 		`"Cross _fix_ at _alt_ / _kts_ knots / mach." Any combination of *A*, *S*, and *M* may be specified.`, "*CCAMRN/A110+*"},
 	{"*C_fix*/_dist__dir*/A_alt*/S_kts*/M_mach*",
@@ -528,6 +572,9 @@ var secondaryAcCommands = [][3]string{
 	{"*A_fix*/D_alt", `"After _fix_, descend and maintain _alt_."`, "*AROSLY/D30*"},
 	{"*CAC*", `"Cancel approach clearance".`, "*CAC*"},
 	{"*CSI_appr", `"Cleared straight-in _appr_ approach.`, "*CSII6*"},
+	{"*C_appr*/CIR_rwy*", `"Cleared _appr_ approach, circle to land runway _rwy_."`, "*CI4R/CIR31L*"},
+	{"*C_appr*/SS_rwy*", `"Cleared _appr_ approach, sidestep runway _rwy_."`, "*CI22L/SS22R*"},
+	{"*BL_hdg*/C_alt*", `"Traffic alert, turn left immediately heading _hdg_, climb and maintain _alt_." Use *BR* to turn right; the altitude is optional.`, "*BL270/C30*"},
 	{"*I*", `"Intercept the localizer."`, "*I*"},
 	{"*CVA_rwy*", `"Cleared visual approach runway _rwy_." Requires field in sight, visual request, or traffic in sight.`, "*CVA13L*"},
 	{"*CDME_nm*/A_alt*", `"Cross _nm_ DME at _alt_" on a cleared visual approach. Append *+* for "or above", *-* for "or below". Combine with *S_spd* for a speed.`, "*CDME10/A30+*"},
@@ -0,0 +1,182 @@
+// cmd/vicebatch/controller.go
+// Copyright(c) 2022-2026 vice contributors, licensed under the GNU Public License, Version 3.
+// SPDX: GPL-3.0-only
+
+package main
+
+import (
+	av "github.com/mmp/vice/aviation"
+	"github.com/mmp/vice/math"
+	"github.com/mmp/vice/sim"
+	"github.com/mmp/vice/util"
+)
+
+const (
+	// How often the scripted controller looks at the traffic.
+	controllerInterval = 5
+	// Arrivals are cleared for the approach once they're within this
+	// distance of their airport and told to contact tower once they're
+	// within towerDistance of the runway threshold.
+	clearApproachDistance = 30
+	towerDistance         = 10
+)
+
+// scriptedController works all of the traffic for the TCW that vicebatch
+// signs in as. It is deliberately simple-minded: it accepts handoffs,
+// releases departures, gets arrivals onto an approach to an active
+// arrival runway, and switches aircraft to the next controller's
+// frequency once their tracks have been handed off. It issues the same
+// commands a human controller would, so command errors are a useful
+// signal that something is amiss.
+type scriptedController struct {
+	s   *sim.Sim
+	tcw sim.TCW
+
+	// Commands that have already been issued to each aircraft, so that we
+	// don't keep repeating ourselves.
+	issued map[av.ADSBCallsign]map[string]bool
+
+	CommandsIssued int
+	CommandErrors  int
+}
+
+func makeScriptedController(s *sim.Sim, tcw sim.TCW) *scriptedController {
+	return &scriptedController{
+		s:      s,
+		tcw:    tcw,
+		issued: make(map[av.ADSBCallsign]map[string]bool),
+	}
+}
+
+func (c *scriptedController) Update() {
+	if c.s.SimTime().Time().Unix()%controllerInterval != 0 {
+		return
+	}
+
+	// Grab the callsigns up front: the sim may remove aircraft in
+	// response to our commands.
+	callsigns := util.SortedMapKeys(c.s.Aircraft)
+	for _, callsign := range callsigns {
+		ac, ok := c.s.Aircraft[callsign]
+		if !ok || ac.NASFlightPlan == nil {
+			continue
+		}
+		fp := ac.NASFlightPlan
+
+		if fp.HandoffController != "" && c.controls(fp.HandoffController) {
+			if err := c.s.AcceptHandoff(c.tcw, fp.ACID); err != nil {
+				c.CommandErrors++
+			}
+			continue
+		}
+
+		if ac.HoldForRelease && !ac.Released {
+			c.s.ReleaseDeparture(c.tcw, callsign)
+			continue
+		}
+
+		if !c.controls(ac.ControllerFrequency) {
+			continue
+		}
+
+		if !c.controls(fp.TrackingController) {
+			// Someone else has the track now.
+			if fp.HandoffController == "" {
+				c.issue(ac, "FC")
+			}
+			continue
+		}
+
+		switch {
+		case ac.IsDeparture() && ac.SID != "":
+			c.issue(ac, "CVS")
+		case ac.IsArrival():
+			c.workArrival(ac)
+		}
+	}
+
+	for callsign := range c.issued {
+		if _, ok := c.s.Aircraft[callsign]; !ok {
+			delete(c.issued, callsign)
+		}
+	}
+}
+
+func (c *scriptedController) controls(pos sim.ControlPosition) bool {
+	return pos != "" && c.s.State.TCWControlsPosition(c.tcw, pos)
+}
+
+func (c *scriptedController) workArrival(ac *sim.Aircraft) {
+	if ac.STAR != "" {
+		c.issue(ac, "DVS")
+	}
+
+	appr := ac.Nav.Approach.Assigned
+	if appr == nil {
+		if id := c.s.PickArrivalApproach(ac); id != "" {
+			c.issue(ac, "E"+id)
+		}
+		return
+	}
+
+	ap, ok := c.s.State.Airports[ac.FlightPlan.ArrivalAirport]
+	if !ok {
+		return
+	}
+	nmPerLongitude := c.s.State.NmPerLongitude
+
+	if !ac.Nav.Approach.Cleared {
+		if math.NMDistance2LLFast(ac.Position(), ap.Location, nmPerLongitude) > clearApproachDistance {
+			return
+		}
+		if !c.issue(ac, "C"+ac.Nav.Approach.AssignedId) {
+			// The approach doesn't connect to the route; send the
+			// aircraft direct to the closest fix on the approach and
+			// try again next time.
+			if fix := closestApproachFix(appr, ac.Position(), nmPerLongitude); fix != "" {
+				c.issue(ac, "D"+fix)
+			}
+			delete(c.issued[ac.ADSBCallsign], "C"+ac.Nav.Approach.AssignedId)
+		}
+		return
+	}
+
+	if !ac.GotContactTower &&
+		math.NMDistance2LLFast(ac.Position(), appr.Threshold, nmPerLongitude) < towerDistance {
+		c.issue(ac, "TO")
+	}
+}
+
+func closestApproachFix(appr *av.Approach, p math.Point2LL, nmPerLongitude float32) string {
+	fix, dist := "", float32(0)
+	for _, wps := range appr.Waypoints {
+		for _, wp := range wps {
+			if wp.Location.IsZero() || wp.Fix == "" || wp.Fix[0] == '_' {
+				continue
+			}
+			if d := math.NMDistance2LLFast(p, wp.Location, nmPerLongitude); fix == "" || d < dist {
+				fix, dist = wp.Fix, d
+			}
+		}
+	}
+	return fix
+}
+
+// issue runs the given command for the aircraft unless it has already
+// been issued. It returns false if the command was rejected.
+func (c *scriptedController) issue(ac *sim.Aircraft, cmd string) bool {
+	if c.issued[ac.ADSBCallsign][cmd] {
+		return true
+	}
+	if c.issued[ac.ADSBCallsign] == nil {
+		c.issued[ac.ADSBCallsign] = make(map[string]bool)
+	}
+	c.issued[ac.ADSBCallsign][cmd] = true
+
+	c.CommandsIssued++
+	if result := c.s.RunAircraftControlCommands(c.tcw, ac.ADSBCallsign, cmd, 0); result.Error != nil {
+		c.CommandErrors++
+		return false
+	}
+	return true
+}
@@ -0,0 +1,289 @@
+// cmd/vicebatch
+// Copyright(c) 2022-2026 vice contributors, licensed under the GNU Public License, Version 3.
+// SPDX: GPL-3.0-only
+
+// vicebatch runs scenarios headlessly, in parallel, with a scripted
+// controller (or, with -autocontrol, the sim's autocontroller) working the
+// traffic, and writes metrics about each run. It's intended for
+// regression testing after changes to scenario definitions or to the
+// navigation code.
+//
+// Usage:
+//
+//	# Run every scenario for an hour of sim time
+//	go run ./cmd/vicebatch -out results
+//
+//	# Run just the N90 scenarios for two hours with a different seed
+//	go run ./cmd/vicebatch -match '^N90/' -duration 2h -seed 42
+//
+//	# Have the autocontroller vector the arrivals
+//	go run ./cmd/vicebatch -autocontrol
+//
+// For each scenario, a JSON file with its metrics is written to the
+// output directory, along with summary.csv, which has a row for each
+// scenario, and runways.csv, which has the arrival and departure counts
+// for each runway. The exit status is non-zero if any scenario failed to
+// run, had navigation validation failures, or had stuck aircraft.
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	av "github.com/mmp/vice/aviation"
+	"github.com/mmp/vice/log"
+	"github.com/mmp/vice/rand"
+	"github.com/mmp/vice/server"
+	"github.com/mmp/vice/sim"
+	"github.com/mmp/vice/util"
+	"github.com/mmp/vice/wx"
+)
+
+var (
+	logLevel              = flag.String("loglevel", "warn", "logging `level`: debug, info, warn, error")
+	logDir                = flag.String("logdir", "", "log file `directory`")
+	scenarioFilename      = flag.String("scenario", "", "`filename` of JSON file with a scenario definition")
+	videoMapFilename      = flag.String("videomap", "", "`filename` of JSON file with video map definitions")
+	scenarioBriefFilename = flag.String("scenariobrief", "", "`filename` of markdown file with a scenario brief")
+	match                 = flag.String("match", "", "only run scenarios whose TRACON/scenario name matches this `regexp`")
+	duration              = flag.Duration("duration", time.Hour, "sim `time` to run each scenario for")
+	parallel              = flag.Int("parallel", runtime.NumCPU(), "number of scenarios to run concurrently")
+	seed                  = flag.Uint64("seed", 1, "random `seed` used for every scenario")
+	linger                = flag.Duration("linger", 45*time.Minute, "report aircraft still in the sim at the end that have been there for longer than this")
+	outDir                = flag.String("out", "vicebatch-results", "`directory` to write results to")
+	autoControl           = flag.Bool("autocontrol", false, "work the traffic with the sim's autocontroller rather than the scripted controller")
+)
+
+func main() {
+	flag.Parse()
+
+	lg := log.New(true, *logLevel, log.DefaultLogDir(true, *logDir))
+
+	if err := run(lg); err != nil {
+		fmt.Fprintf(os.Stderr, "vicebatch: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(lg *log.Logger) error {
+	var re *regexp.Regexp
+	if *match != "" {
+		var err error
+		if re, err = regexp.Compile(*match); err != nil {
+			return err
+		}
+	}
+
+	av.InitDB()
+	wx.Init()
+
+	names, err := server.ListAllScenarios(*scenarioFilename, *videoMapFilename, lg)
+	if err != nil {
+		return err
+	}
+	if re != nil {
+		names = util.FilterSlice(names, re.MatchString)
+	}
+	if len(names) == 0 {
+		return fmt.Errorf("no scenarios to run")
+	}
+
+	var e util.ErrorLogger
+	scenarioGroups, configs, _, _, _ := server.LoadScenarioGroups(*scenarioFilename, *videoMapFilename, *scenarioBriefFilename, &e, lg)
+	if e.HaveErrors() {
+		e.PrintErrors(lg)
+		return fmt.Errorf("scenario loading failed")
+	}
+
+	// scenarioGroups's type is private to the server package, so close over
+	// it rather than passing it to runScenario.
+	simConfig := func(name string) (*sim.NewSimConfiguration, error) {
+		tracon, scenarioName, _ := strings.Cut(name, "/")
+		config, scenarioGroup, err := server.LookupScenario(tracon, scenarioName, scenarioGroups, configs)
+		if err != nil {
+			return nil, err
+		}
+		return server.CreateNewSimConfiguration(config, scenarioGroup, scenarioName)
+	}
+
+	if err := os.MkdirAll(*outDir, 0o755); err != nil {
+		return err
+	}
+
+	fmt.Printf("Running %d scenarios, %d at a time\n", len(names), *parallel)
+
+	results := make([]*ScenarioResult, len(names))
+	work := make(chan int)
+	var wg sync.WaitGroup
+	for range max(*parallel, 1) {
+		wg.Go(func() {
+			for i := range work {
+				results[i] = runScenario(names[i], simConfig, lg)
+				r := results[i]
+				fmt.Printf("%-40s %s spawned %d, remaining %d, stuck %d, nav failures %d (%s)\n", r.Scenario,
+					util.Select(r.Failed(), "FAIL", "ok  "), r.TotalSpawned(), r.Remaining, len(r.Stuck),
+					r.NavFailures, r.WallTime.Round(time.Millisecond))
+				if r.Error != "" {
+					fmt.Printf("    %s\n", r.Error)
+				}
+			}
+		})
+	}
+	for i := range names {
+		work <- i
+	}
+	close(work)
+	wg.Wait()
+
+	if err := writeResults(results); err != nil {
+		return err
+	}
+
+	failed := util.FilterSlice(results, func(r *ScenarioResult) bool { return r.Failed() })
+	if len(failed) > 0 {
+		return fmt.Errorf("%d of %d scenarios failed", len(failed), len(results))
+	}
+	return nil
+}
+
+func runScenario(name string, simConfig func(string) (*sim.NewSimConfiguration, error),
+	lg *log.Logger) (result *ScenarioResult) {
+	result = &ScenarioResult{
+		Scenario: name,
+		Seed:     *seed,
+		Spawned:  make(map[string]int),
+	}
+
+	wallStart := time.Now()
+	defer func() {
+		result.WallTime = time.Since(wallStart)
+		if err := recover(); err != nil {
+			result.Error = fmt.Sprintf("panic: %v", err)
+			lg.Errorf("%s: panic: %v", name, err)
+		}
+	}()
+
+	newSimConfig, err := simConfig(name)
+	if err != nil {
+		result.Error = err.Error()
+		return
+	}
+
+	// Derive the start time from the seed, following -runsim's choice of
+	// a time in November 2025.
+	r := rand.MakeSeeded(*seed)
+	day, hour, min, sec := 1+r.Intn(30), r.Intn(24), r.Intn(60), r.Intn(60)
+	newSimConfig.StartTime = time.Date(2025, time.November, day, hour, min, sec, 0, time.UTC)
+	result.StartTime = newSimConfig.StartTime
+
+	s := sim.NewSim(*newSimConfig, lg)
+	s.Rand.Seed(*seed)
+
+	rootController, _ := newSimConfig.ControllerConfiguration.RootPosition()
+	tcw := sim.TCW(rootController)
+	_, events, err := s.SignOn(tcw, s.AllScenarioPositions())
+	if err != nil {
+		result.Error = fmt.Sprintf("%s: unable to sign on: %v", rootController, err)
+		return
+	}
+	defer events.Unsubscribe()
+
+	s.Prespawn()
+
+	controller := makeScriptedController(s, tcw)
+	if *autoControl {
+		if err := s.SetAutoControlled(tcw, true); err != nil {
+			result.Error = fmt.Sprintf("%s: unable to start autocontroller: %v", rootController, err)
+			return
+		}
+	}
+	metrics := makeMetricsCollector(s, result, *linger)
+	for range int(duration.Seconds()) {
+		s.Step(time.Second)
+		events.Get() // keep the stream from accumulating events
+		if !*autoControl {
+			controller.Update()
+		}
+		metrics.Update()
+	}
+	metrics.Finish()
+
+	result.SimDuration = *duration
+	result.CommandsIssued, result.CommandErrors = controller.CommandsIssued, controller.CommandErrors
+	if sc, err := s.GetScorecard(tcw, tcw); err == nil {
+		result.Scores = make(map[string]int)
+		for _, ev := range sc.Events {
+			result.Scores[ev.Type.String()]++
+		}
+	}
+
+	return
+}
+
+func writeResults(results []*ScenarioResult) error {
+	summary, err := os.Create(filepath.Join(*outDir, "summary.csv"))
+	if err != nil {
+		return err
+	}
+	defer summary.Close()
+	runways, err := os.Create(filepath.Join(*outDir, "runways.csv"))
+	if err != nil {
+		return err
+	}
+	defer runways.Close()
+
+	sw, rw := csv.NewWriter(summary), csv.NewWriter(runways)
+	sw.Write([]string{"scenario", "failed", "error", "spawned", "arrivals", "departures", "overflights", "vfr",
+		"exited", "remaining", "lingering", "stuck", "nav_failures", "landed", "departed",
+		"commands", "command_errors", "wall_seconds"})
+	rw.Write([]string{"scenario", "airport", "runway", "departures", "arrivals"})
+
+	itoa := strconv.Itoa
+	for _, r := range results {
+		b, err := json.MarshalIndent(r, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(*outDir, resultFilename(r.Scenario)), b, 0o644); err != nil {
+			return err
+		}
+
+		landed, departed := 0, 0
+		for _, tp := range r.Runways {
+			landed += tp.Arrivals
+			departed += tp.Departures
+			rw.Write([]string{r.Scenario, tp.Airport, tp.Runway, itoa(tp.Departures), itoa(tp.Arrivals)})
+		}
+
+		sw.Write([]string{r.Scenario, strconv.FormatBool(r.Failed()), r.Error, itoa(r.TotalSpawned()),
+			itoa(r.Spawned["arrival"]), itoa(r.Spawned["departure"]), itoa(r.Spawned["overflight"]), itoa(r.Spawned["vfr"]),
+			itoa(r.Exited), itoa(r.Remaining), itoa(len(r.Lingering)), itoa(len(r.Stuck)), itoa(r.NavFailures),
+			itoa(landed), itoa(departed), itoa(r.CommandsIssued), itoa(r.CommandErrors),
+			strconv.FormatFloat(r.WallTime.Seconds(), 'f', 2, 64)})
+	}
+
+	sw.Flush()
+	rw.Flush()
+	return util.Select(sw.Error() != nil, sw.Error(), rw.Error())
+}
+
+// resultFilename returns the name of the JSON file for a scenario's
+// results; scenario names often have spaces and slashes in them.
+func resultFilename(scenario string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '-' || r == '_' || (r >= '0' && r <= '9') || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') {
+			return r
+		}
+		return '_'
+	}, scenario) + ".json"
+}
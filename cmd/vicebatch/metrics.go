@@ -0,0 +1,298 @@
+// cmd/vicebatch/metrics.go
+// Copyright(c) 2022-2026 vice contributors, licensed under the GNU Public License, Version 3.
+// SPDX: GPL-3.0-only
+
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	av "github.com/mmp/vice/aviation"
+	"github.com/mmp/vice/math"
+	"github.com/mmp/vice/sim"
+	"github.com/mmp/vice/util"
+)
+
+const (
+	// Aircraft positions are sampled this often for stuck detection.
+	stuckSampleInterval = time.Minute
+	// An airborne aircraft that has stayed within stuckRadius nm of
+	// where it was stuckWindow ago is considered to be stuck. This is
+	// large enough that aircraft flying a standard holding pattern don't
+	// trigger it.
+	stuckWindow = 10 * time.Minute
+	stuckRadius = 2
+	// Only this many nav validation failures are reported individually.
+	maxNavFailureReports = 25
+	// Arrivals that disappear within this distance of the threshold of
+	// the runway they were cleared to are taken to have landed.
+	landedDistance = 3
+)
+
+// ScenarioResult holds the metrics collected from running one scenario.
+type ScenarioResult struct {
+	Scenario    string
+	Seed        uint64
+	StartTime   time.Time // sim time
+	SimDuration time.Duration
+	WallTime    time.Duration
+	Error       string `json:",omitempty"`
+
+	Spawned map[string]int // by type of flight: arrival, departure, overflight, vfr
+	// Aircraft that were removed from the sim without landing, e.g. by
+	// leaving the scenario's airspace.
+	Exited    int
+	Remaining int
+	// Aircraft that were still in the sim at the end that had been there
+	// for longer than the -linger duration.
+	Lingering []AircraftReport `json:",omitempty"`
+	Stuck     []AircraftReport `json:",omitempty"`
+
+	// Number of aircraft whose navigation state failed validation.
+	NavFailures       int
+	NavFailureReports []AircraftReport `json:",omitempty"`
+
+	Runways []RunwayThroughput
+
+	CommandsIssued int
+	CommandErrors  int
+
+	Scores map[string]int `json:",omitempty"` // sim.ScoreEventType -> count
+}
+
+// AircraftReport describes a problem with a single aircraft.
+type AircraftReport struct {
+	Callsign av.ADSBCallsign
+	Time     time.Time // sim time
+	Position math.Point2LL
+	Altitude float32
+	Age      time.Duration
+	Message  string `json:",omitempty"`
+}
+
+type RunwayThroughput struct {
+	Airport    string
+	Runway     string
+	Departures int
+	Arrivals   int
+}
+
+func (r *ScenarioResult) Failed() bool {
+	return r.Error != "" || r.NavFailures > 0 || len(r.Stuck) > 0
+}
+
+func (r *ScenarioResult) TotalSpawned() int {
+	n := 0
+	for _, c := range r.Spawned {
+		n += c
+	}
+	return n
+}
+
+// metricsCollector watches a Sim as it runs and accumulates a
+// ScenarioResult.
+type metricsCollector struct {
+	s      *sim.Sim
+	linger time.Duration
+	result *ScenarioResult
+
+	firstSeen map[av.ADSBCallsign]sim.Time
+	// For each aircraft, positions sampled every stuckSampleInterval.
+	samples     map[av.ADSBCallsign][]math.Point2LL
+	lastSample  sim.Time
+	stuck       map[av.ADSBCallsign]bool
+	navFailures map[av.ADSBCallsign]bool
+
+	// Arrivals that are cleared for an approach, with the runway they are
+	// cleared to; used to attribute landings to runways.
+	cleared map[av.ADSBCallsign]clearedArrival
+	// The most recent departure from each airport's runways.
+	lastDeparture map[string]map[av.RunwayID]av.ADSBCallsign
+	throughput    map[[2]string]*RunwayThroughput // (airport, runway) -> throughput
+}
+
+type clearedArrival struct {
+	airport, runway string
+	distance        float32 // to the threshold
+}
+
+func makeMetricsCollector(s *sim.Sim, result *ScenarioResult, linger time.Duration) *metricsCollector {
+	m := &metricsCollector{
+		s:             s,
+		linger:        linger,
+		result:        result,
+		firstSeen:     make(map[av.ADSBCallsign]sim.Time),
+		samples:       make(map[av.ADSBCallsign][]math.Point2LL),
+		lastSample:    s.SimTime(),
+		stuck:         make(map[av.ADSBCallsign]bool),
+		navFailures:   make(map[av.ADSBCallsign]bool),
+		cleared:       make(map[av.ADSBCallsign]clearedArrival),
+		lastDeparture: make(map[string]map[av.RunwayID]av.ADSBCallsign),
+		throughput:    make(map[[2]string]*RunwayThroughput),
+	}
+	// Don't count departures launched before we started watching.
+	m.updateDepartures(false)
+	return m
+}
+
+// Update should be called after each sim step.
+func (m *metricsCollector) Update() {
+	now := m.s.SimTime()
+	sample := now.Sub(m.lastSample) >= stuckSampleInterval
+	if sample {
+		m.lastSample = now
+	}
+
+	for callsign, ac := range util.SortedMap(m.s.Aircraft) {
+		if _, ok := m.firstSeen[callsign]; !ok {
+			m.firstSeen[callsign] = now
+			m.result.Spawned[flightType(ac)]++
+		}
+
+		if err := ac.Nav.Validate(); err != nil && !m.navFailures[callsign] {
+			// Only report the first failure for each aircraft.
+			m.result.NavFailures++
+			if len(m.result.NavFailureReports) < maxNavFailureReports {
+				m.result.NavFailureReports = append(m.result.NavFailureReports, m.report(ac, err.Error()))
+			}
+			m.navFailures[callsign] = true
+		}
+
+		if appr := ac.Nav.Approach.Assigned; appr != nil && ac.Nav.Approach.Cleared {
+			m.cleared[callsign] = clearedArrival{
+				airport:  ac.FlightPlan.ArrivalAirport,
+				runway:   appr.Runway,
+				distance: math.NMDistance2LLFast(ac.Position(), appr.Threshold, m.s.State.NmPerLongitude),
+			}
+		} else {
+			delete(m.cleared, callsign)
+		}
+
+		if sample {
+			m.checkStuck(ac)
+		}
+	}
+
+	// Aircraft that have been removed from the sim.
+	for callsign := range util.SortedMap(m.firstSeen) {
+		if _, ok := m.s.Aircraft[callsign]; ok {
+			continue
+		}
+		if arr, ok := m.cleared[callsign]; ok && arr.distance < landedDistance {
+			m.runway(arr.airport, arr.runway).Arrivals++
+		} else {
+			m.result.Exited++
+		}
+		delete(m.firstSeen, callsign)
+		delete(m.samples, callsign)
+		delete(m.cleared, callsign)
+	}
+
+	m.updateDepartures(true)
+}
+
+// updateDepartures notes the latest departure from each runway, counting
+// any that are new if count is set.
+func (m *metricsCollector) updateDepartures(count bool) {
+	for airport, runways := range util.SortedMap(m.s.DepartureState) {
+		for rwy, state := range util.SortedMap(runways) {
+			if state.LastDeparture == nil {
+				continue
+			}
+			if m.lastDeparture[airport] == nil {
+				m.lastDeparture[airport] = make(map[av.RunwayID]av.ADSBCallsign)
+			}
+			if callsign := state.LastDeparture.ADSBCallsign; m.lastDeparture[airport][rwy] != callsign {
+				m.lastDeparture[airport][rwy] = callsign
+				if count {
+					m.runway(airport, rwy.Base()).Departures++
+				}
+			}
+		}
+	}
+}
+
+func (m *metricsCollector) checkStuck(ac *sim.Aircraft) {
+	callsign := ac.ADSBCallsign
+	if !ac.IsAirborne() || ac.WaitingForLaunch || m.stuck[callsign] {
+		delete(m.samples, callsign)
+		return
+	}
+
+	n := int(stuckWindow / stuckSampleInterval)
+	samples := append(m.samples[callsign], ac.Position())
+	if len(samples) > n+1 {
+		samples = samples[len(samples)-(n+1):]
+	}
+	m.samples[callsign] = samples
+
+	if len(samples) == n+1 {
+		for _, p := range samples {
+			if math.NMDistance2LLFast(p, ac.Position(), m.s.State.NmPerLongitude) > stuckRadius {
+				return
+			}
+		}
+		m.stuck[callsign] = true
+		m.result.Stuck = append(m.result.Stuck, m.report(ac,
+			fmt.Sprintf("within %d nm for %s", stuckRadius, stuckWindow)))
+	}
+}
+
+// Finish should be called after the last sim step.
+func (m *metricsCollector) Finish() {
+	now := m.s.SimTime()
+	m.result.Remaining = len(m.s.Aircraft)
+	for _, ac := range util.SortedMap(m.s.Aircraft) {
+		if now.Sub(m.firstSeen[ac.ADSBCallsign]) > m.linger {
+			m.result.Lingering = append(m.result.Lingering, m.report(ac, ""))
+		}
+	}
+
+	for _, tp := range m.throughput {
+		m.result.Runways = append(m.result.Runways, *tp)
+	}
+	slices.SortFunc(m.result.Runways, func(a, b RunwayThroughput) int {
+		return cmp.Or(strings.Compare(a.Airport, b.Airport), strings.Compare(a.Runway, b.Runway))
+	})
+}
+
+func (m *metricsCollector) runway(airport, runway string) *RunwayThroughput {
+	key := [2]string{airport, runway}
+	tp, ok := m.throughput[key]
+	if !ok {
+		tp = &RunwayThroughput{Airport: airport, Runway: runway}
+		m.throughput[key] = tp
+	}
+	return tp
+}
+
+func (m *metricsCollector) report(ac *sim.Aircraft, msg string) AircraftReport {
+	now := m.s.SimTime()
+	return AircraftReport{
+		Callsign: ac.ADSBCallsign,
+		Time:     now.Time(),
+		Position: ac.Position(),
+		Altitude: ac.Altitude(),
+		Age:      now.Sub(m.firstSeen[ac.ADSBCallsign]),
+		Message:  msg,
+	}
+}
+
+func flightType(ac *sim.Aircraft) string {
+	switch {
+	case ac.FlightPlan.Rules == av.FlightRulesVFR:
+		return "vfr"
+	case ac.IsArrival():
+		return "arrival"
+	case ac.IsDeparture():
+		return "departure"
+	case ac.IsOverflight():
+		return "overflight"
+	default:
+		return "other"
+	}
+}
@@ -7,8 +7,12 @@
 package main
 
 import (
+	"bufio"
 	"flag"
+	"fmt"
+	"io"
 	"net"
+	"os"
 	"strconv"
 	"strings"
 
@@ -31,11 +35,24 @@ var (
 	navLogCategories      = flag.String("navlog-categories", "all", "navigation log `categories`")
 	navLogCallsign        = flag.String("navlog-callsign", "", "filter navigation logs to only show this `callsign`")
 	loadOnly              = flag.Bool("loadonly", false, "exit as soon as scenarios have loaded; useful for CI smoketests under -race")
+	recordDir             = flag.String("record", "", "`directory` in which to write recordings of all sims for later playback")
+	stateDir              = flag.String("statedir", "", "`directory` in which to save running sims so that they are restored after a restart")
+	usersFile             = flag.String("users", "", "JSON `file` of user accounts; if given, users must authenticate to create or join sims")
+	hashPassword          = flag.Bool("hashpassword", false, "read a password from stdin, print its hash for the -users file, and exit")
+	hashToken             = flag.Bool("hashtoken", false, "read an API token from stdin, print its hash for the -users file, and exit")
+	apiOrigins            = flag.String("api-origins", "", "comma-separated `origins` of web pages allowed to make cross-origin JSON API requests")
+	federation            = flag.Bool("federation", false, "allow sims to be linked with sims on other servers")
+	federationPeers       = flag.String("federation-peers", "", "comma-separated host:port `addresses` of servers that sims may be linked to")
 )
 
 func main() {
 	flag.Parse()
 
+	if *hashPassword || *hashToken {
+		printHash()
+		return
+	}
+
 	resolvedLogDir := log.DefaultLogDir(true, *logDir)
 	lg := log.New(true, *logLevel, resolvedLogDir)
 
@@ -48,6 +65,14 @@ func main() {
 
 	nav.InitNavLog(*navLogEnabled, *navLogCategories, *navLogCallsign)
 
+	var origins, peers []string
+	if *apiOrigins != "" {
+		origins = strings.Split(*apiOrigins, ",")
+	}
+	if *federationPeers != "" {
+		peers = strings.Split(*federationPeers, ",")
+	}
+
 	server.LaunchServer(server.ServerLaunchConfig{
 		Port:               *serverPort,
 		ExtraScenario:      *scenarioFilename,
@@ -56,5 +81,33 @@ func main() {
 		ServerAddress:      *serverAddress,
 		IsLocal:            false,
 		ExitAfterLoad:      *loadOnly,
+		RecordDir:          *recordDir,
+		StateDir:           *stateDir,
+		UsersFile:          *usersFile,
+		APIOrigins:         origins,
+		Federation:         *federation,
+		FederationPeers:    peers,
 	}, lg)
 }
+
+func printHash() {
+	s, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && err != io.EOF {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	s = strings.TrimRight(s, "\r\n")
+	if s == "" {
+		fmt.Fprintln(os.Stderr, "no input provided")
+		os.Exit(1)
+	}
+
+	if *hashToken {
+		fmt.Println(server.HashToken(s))
+	} else if h, err := server.HashPassword(s); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	} else {
+		fmt.Println(h)
+	}
+}
@@ -22,12 +22,6 @@ const (
 	caLookaheadSeconds = 240
 	caSampleStep       = 5 // seconds between prediction samples
 
-	caLateralMinimum        = 5.0   // nm
-	caReducedLateralMinimum = 3.0   // nm; applies when both targets are in reduced separation airspace
-	caReducedSepCeiling     = 23000 // ft; reduced separation airspace is at or below FL230
-	caVerticalMinimum       = 1000  // ft
-	caVerticalSlop          = 5     // ft; keeps exactly-1000-ft-separated targets from alerting on float error
-
 	caLevelRateThreshold  = 300 // ft/min; below this the target is treated as level
 	caLevelDBAltTolerance = 200 // ft; DB altitude within this of current altitude = at assigned altitude
 
@@ -89,7 +83,7 @@ func caConflict(a, b caTarget) bool {
 	// minima inside the window even at maximum closure.
 	sep := math.Length2f(math.Sub2f(a.pos, b.pos))
 	maxClosure := (math.Length2f(a.vel) + math.Length2f(b.vel)) * caLookaheadSeconds
-	if sep > caLateralMinimum+maxClosure {
+	if sep > av.LateralSeparation+maxClosure {
 		return false
 	}
 	bandSpan := func(t caTarget) float32 {
@@ -100,7 +94,7 @@ func caConflict(a, b caTarget) bool {
 	}
 	maxVertClosure := (math.Abs(a.rate)+math.Abs(b.rate))/60*caLookaheadSeconds +
 		bandSpan(a) + bandSpan(b)
-	if math.Abs(a.alt-b.alt) > caVerticalMinimum+maxVertClosure {
+	if math.Abs(a.alt-b.alt) > av.VerticalSeparation+maxVertClosure {
 		return false
 	}
 
@@ -110,13 +104,13 @@ func caConflict(a, b caTarget) bool {
 		alo, ahi := caAltitudeEnvelope(a, t)
 		blo, bhi := caAltitudeEnvelope(b, t)
 
-		latMin := float32(caLateralMinimum)
-		if ahi <= caReducedSepCeiling && bhi <= caReducedSepCeiling {
-			latMin = caReducedLateralMinimum
+		latMin := float32(av.LateralSeparation)
+		if ahi <= av.ReducedSeparationCeiling && bhi <= av.ReducedSeparationCeiling {
+			latMin = av.ReducedLateralSeparation
 		}
 
 		if math.Length2f(math.Sub2f(pa, pb)) < latMin &&
-			caIntervalGap(alo, ahi, blo, bhi) < caVerticalMinimum-caVerticalSlop {
+			caIntervalGap(alo, ahi, blo, bhi) < av.VerticalSeparation-av.VerticalSeparationSlop {
 			return true
 		}
 	}
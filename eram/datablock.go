@@ -412,6 +412,10 @@ func (ep *ERAMPane) getDatablock(ctx *panes.Context, trk sim.Track, dbType Datab
 			dbWriteText(db.line4[:], trk.FlightPlan.ArrivalAirport, line4Color, false)
 		} else if ps.Line4Type == Line4Type {
 			dbWriteText(db.line4[:], trk.FlightPlan.AircraftType, line4Color, false)
+		} else if ma, ok := ctx.Client.State.MeteredArrival(trk.ADSBCallsign); ok {
+			// Line 4 is otherwise empty; show the TBFM delay countdown, in
+			// minutes, to cross the meter fix at the STA.
+			dbWriteText(db.line4[:], fmt.Sprintf("%+d", ma.DelayMinutes()), line4Color, false)
 		}
 		return db
 	case EnhancedLimitedDatablock:
@@ -328,6 +328,9 @@ type ERAMPane struct {
 	wxScroll         ViewScrollState    `json:"-"`
 	wxSelect         ViewSelectionState `json:"-"`
 
+	// HOLD LIST window state (session)
+	holdListScroll ViewScrollState `json:"-"`
+
 	// Check list view toggle state (session). Each slice is parallel to
 	// checkListItems[…], with true = row is highlighted ("checked off").
 	posCheckToggled   []bool `json:"-"`
@@ -579,6 +582,7 @@ func (ep *ERAMPane) Draw(ctx *panes.Context, cb *renderer.CommandBuffer) {
 	ep.drawMessageCompositionArea(ctx, transforms, cb)
 	ep.drawAltimSetView(ctx, transforms, cb)
 	ep.drawWXView(ctx, transforms, cb)
+	ep.drawHoldListView(ctx, transforms, cb)
 	ep.drawBeaconCodeView(ctx, transforms, cb)
 	ep.drawTimeView(ctx, transforms, cb)
 	ep.drawCheckListView(ctx, transforms, cb)
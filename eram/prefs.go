@@ -85,6 +85,17 @@ type Preferences struct {
 		Bright         radar.Brightness // 0-100
 	}
 
+	// HOLD LIST view preferences
+	HoldList struct {
+		Visible    bool
+		Position   [2]float32
+		Opaque     bool
+		ShowBorder bool
+		Lines      int
+		Font       int              // 1-3
+		Bright     radar.Brightness // 0-100
+	}
+
 	// MCA (Message Composition Area) preferences
 	MCA struct {
 		Position [2]float32       // top-left of the feedback box
@@ -319,6 +330,14 @@ func makeDefaultPreferences() *Preferences {
 	prefs.WX.Font = 2
 	prefs.WX.Bright = 80
 
+	prefs.HoldList.Visible = false
+	prefs.HoldList.Position = [2]float32{100, 900}
+	prefs.HoldList.Opaque = false
+	prefs.HoldList.ShowBorder = true
+	prefs.HoldList.Lines = 8
+	prefs.HoldList.Font = 2
+	prefs.HoldList.Bright = 80
+
 	prefs.CheckList.Visible = checkListHidden
 	prefs.CheckList.Position = [2]float32{100, 900}
 	prefs.CheckList.Opaque = false
@@ -425,6 +444,14 @@ func (p *Preferences) Upgrade(from, to int) {
 			p.CheckList.Text = 76
 		}
 	}
+	if from < 82 {
+		// HOLD LIST view added.
+		p.HoldList.Position = [2]float32{100, 900}
+		p.HoldList.ShowBorder = true
+		p.HoldList.Lines = 8
+		p.HoldList.Font = 2
+		p.HoldList.Bright = 80
+	}
 }
 
 func (ep *ERAMPane) initPrefsForLoadedSim(ss client.SimState) *Preferences {
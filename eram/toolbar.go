@@ -705,8 +705,11 @@ func (ep *ERAMPane) drawToolbarMenu(ctx *panes.Context, scale float32) {
 		if ep.drawToolbarFullButton(ctx, "GROUP\nSUP", 0, scale, false, false) {
 			// handle GROUP SUP
 		}
-		if ep.drawToolbarFullButton(ctx, "HOLD\nLIST", 0, scale, false, false) {
-			// handle HOLD LIST
+		if ep.drawToolbarFullButton(ctx, "HOLD\nLIST", 0, scale, ps.HoldList.Visible, false) {
+			if _, ok := ep.popup.(*holdListPopup); ok {
+				ep.popup = nil
+			}
+			ps.HoldList.Visible = !ps.HoldList.Visible
 		}
 		if ep.drawToolbarFullButton(ctx, "INBND\nLIST", 0, scale, false, false) {
 			// handle INBND LIST
@@ -2457,6 +2460,11 @@ func (ep *ERAMPane) handleTornOffButtonClick(ctx *panes.Context, buttonName stri
 			ep.popup = nil
 		}
 		ps.WX.Visible = !ps.WX.Visible
+	case "HOLD\nLIST", "HOLD LIST", "HOLDLIST":
+		if _, ok := ep.popup.(*holdListPopup); ok {
+			ep.popup = nil
+		}
+		ps.HoldList.Visible = !ps.HoldList.Visible
 	case "CRR\nFIX":
 		ps.CRR.DisplayFixes = !ps.CRR.DisplayFixes
 	case "DELETE\nTEAROFF":
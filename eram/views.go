@@ -656,6 +656,80 @@ func (w *wxPopup) draw(ep *ERAMPane, ctx *panes.Context, transforms radar.ScopeT
 	ep.DrawERAMMenu(ctx, transforms, cb, w.origin, cfg)
 }
 
+///////////////////////////////////////////////////////////////////////////
+// HOLD LIST
+
+// drawHoldListView renders the HOLD LIST floating window: one row per
+// aircraft in a holding stack, grouped by fix with the top of each stack
+// first.
+func (ep *ERAMPane) drawHoldListView(ctx *panes.Context, transforms radar.ScopeTransformations, cb *renderer.CommandBuffer) {
+	ps := ep.currentPrefs()
+	if !ps.HoldList.Visible {
+		return
+	}
+
+	var rows []Row
+	for fix, stack := range util.SortedMap(ctx.Client.State.HoldingStacks) {
+		for _, ha := range slices.Backward(stack) {
+			efc := "----"
+			if !ha.EFC.IsZero() {
+				efc = ha.EFC.UTC().Format("1504")
+			}
+			rows = append(rows, Row{
+				ID:   string(ha.ADSBCallsign),
+				Body: fmt.Sprintf("%-5s %-7s %03d %s", fix, ha.ADSBCallsign, ha.Altitude/100, efc),
+			})
+		}
+	}
+
+	ep.DrawView(ctx, transforms, cb, View{
+		Position:   &ps.HoldList.Position,
+		ID:         "hold-list",
+		Title:      "HOLD LIST",
+		Opaque:     ps.HoldList.Opaque,
+		ShowBorder: ps.HoldList.ShowBorder,
+		Brightness: ps.HoldList.Bright,
+		OnMenu: ep.makeViewMenu(ctx, "hold-list", 5,
+			func(pb popupBase) popup { return &holdListPopup{popupBase: pb} }),
+		OnMinimize: func() { ps.HoldList.Visible = false },
+		RowSource: &ViewRowSource{
+			Rows:                  rows,
+			FontIndex:             ps.HoldList.Font,
+			ContentChars:          22,
+			MaxCols:               1,
+			VisibleRows:           ps.HoldList.Lines,
+			RowSpacing:            RowSpacingAiry,
+			ScrollState:           &ep.holdListScroll,
+			EmptyKeepsColumnWidth: true,
+		},
+	})
+}
+
+// holdListPopup is the configuration menu for the HOLD LIST view.
+type holdListPopup struct {
+	popupBase
+}
+
+func (h *holdListPopup) draw(ep *ERAMPane, ctx *panes.Context, transforms radar.ScopeTransformations, cb *renderer.CommandBuffer) {
+	ps := ep.currentPrefs()
+
+	rows := []ERAMMenuItem{
+		ep.makeBooleanMenuItem(&ps.HoldList.Opaque, "O", "T"),
+		ep.makeToggleMenuItem(&ps.HoldList.ShowBorder, "BORDER"),
+		makeIntMenuItem(ep, &ps.HoldList.Lines, "LINES", 3, 24, 1),
+		makeIntMenuItem(ep, &ps.HoldList.Font, "FONT", 1, 3, 1),
+		makeIntMenuItem(ep, &ps.HoldList.Bright, "BRIGHT", 0, 100, 1),
+	}
+
+	cfg := ERAMMenuConfig{
+		Title: "HOLD LIST",
+		Width: viewPopupWidth,
+		Font:  ep.ERAMFont(2),
+		Rows:  rows,
+	}
+	ep.DrawERAMMenu(ctx, transforms, cb, h.origin, cfg)
+}
+
 ///////////////////////////////////////////////////////////////////////////
 // CHECK LISTS (POS CHECK / EMERG CHECK)
 
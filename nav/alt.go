@@ -14,6 +14,27 @@ import (
 const MaximumRate = 100000
 const rateMaxDeltaPercent = 0.075
 
+// VFRTerrainClearance is the height in feet above the highest nearby
+// terrain that VFR aircraft maintain when they aren't arriving or
+// departing.
+const VFRTerrainClearance = 1000
+
+// VFRTerrainRadius is the distance in nm around a VFR aircraft's route
+// waypoints over which it keeps VFRTerrainClearance.
+const VFRTerrainRadius = 2
+
+// MinimumVFRAltitude returns the lowest altitude at which a VFR aircraft
+// anywhere within radius nm of p is VFRTerrainClearance above the
+// terrain. It's capped at 17,500' to keep VFRs out of class A and is 0 if
+// there is no terrain data for the area.
+func MinimumVFRAltitude(p math.Point2LL, radius, nmPerLongitude float32) float32 {
+	elev := av.DB.Terrain.MaxElevation(p, radius, nmPerLongitude)
+	if elev == 0 {
+		return 0
+	}
+	return float32(min(elev+VFRTerrainClearance, 17500))
+}
+
 func (nav *Nav) activatePendingAltitude(simTime Time) {
 	if nav.Altitude.ActivateAt.IsZero() || simTime.Before(nav.Altitude.ActivateAt) {
 		return
@@ -32,6 +53,28 @@ func (nav *Nav) activeAssignedAltitude() *float32 {
 	return nav.Altitude.Assigned
 }
 
+// BustAltitude causes the aircraft to fly through its assigned altitude
+// and level off overshoot feet beyond it without saying anything. The
+// controller-assigned altitude is unchanged, so assigning an altitude
+// again corrects the deviation. It returns false if there is no assigned
+// altitude.
+func (nav *Nav) BustAltitude(overshoot float32) bool {
+	if nav.Altitude.Assigned == nil {
+		return false
+	}
+
+	assigned := *nav.Altitude.Assigned
+	bust := assigned + overshoot
+	if assigned < nav.FlightState.Altitude {
+		bust = max(assigned-overshoot, nav.FlightState.ArrivalAirportElevation+500)
+	}
+	bust = min(bust, nav.Perf.Ceiling)
+
+	nav.Altitude.ActiveAssigned = &bust
+	nav.Altitude.ActivateAt = Time{}
+	return true
+}
+
 func (nav *Nav) updateAltitude(callsign string, targetAltitude, targetRate float32, geometricDescent bool, deltaKts float32, slowingTo250 bool, wxs wx.Sample, simTime Time) {
 	nav.FlightState.PrevAltitude = nav.FlightState.Altitude
 
@@ -495,7 +538,7 @@ func (nav *Nav) findAltitudeTarget() (altitudeTarget, bool) {
 	// stay high because the restriction is technically satisfied.
 	getRestriction := func(i int) *av.AltitudeRestriction {
 		ar := getChartedRestriction(i)
-		if ar != nil && nav.Approach.Cleared && wps[i].OnApproach() &&
+		if ar != nil && nav.flyingApproach() && wps[i].OnApproach() &&
 			ar.Range[1] == av.MaxAltitude && ar.Range[0] > 0 {
 			adjusted := av.MakeAtAltitudeRestriction(ar.Range[0])
 			return &adjusted
@@ -553,7 +596,7 @@ func (nav *Nav) findAltitudeTarget() (altitudeTarget, bool) {
 
 		// Ignore it if the aircraft is cleared for the approach and is below it.
 		// TODO: I think this can be 'break' rather than continue...
-		if nav.Approach.Cleared && nav.FlightState.Altitude < restr.Range[0] {
+		if nav.flyingApproach() && nav.FlightState.Altitude < restr.Range[0] {
 			continue
 		}
 
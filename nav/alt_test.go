@@ -973,3 +973,51 @@ func TestVectorOffSTARHoldsAltitude(t *testing.T) {
 		}
 	}
 }
+
+func TestBustAltitude(t *testing.T) {
+	f := NewArrivalFlight(t, ArrivalConfig{
+		Waypoints:        "SAJUL DETGY HAUPT",
+		DepartureAirport: "KMCO",
+		ArrivalAirport:   "KJFK",
+		AircraftType:     "A320",
+		InitialAltitude:  8000,
+		InitialSpeed:     250,
+	})
+
+	if f.nav.BustAltitude(800) {
+		t.Fatal("expected no bust without an assigned altitude")
+	}
+
+	// Bust a pending descent: the aircraft should head below the assigned
+	// altitude right away while the assignment itself is unchanged.
+	f.AssignAltitude(3000)
+	if !f.nav.BustAltitude(800) {
+		t.Fatal("expected bust with an assigned altitude")
+	}
+	if f.nav.Altitude.Assigned == nil || *f.nav.Altitude.Assigned != 3000 {
+		t.Errorf("expected Assigned=3000, got %v", f.nav.Altitude.Assigned)
+	}
+	if alt, _, _ := f.nav.TargetAltitude(); alt != 2200 {
+		t.Errorf("expected target altitude 2200, got %.0f", alt)
+	}
+	wxs := f.weather(f.nav.FlightState.Altitude)
+	f.nav.UpdateWithWeather(f.callsign, wxs, nil, &f.fp, f.simTime, nil)
+	f.AssertDescending()
+
+	// Climbing, the aircraft overshoots upward.
+	f.nav.FlightState.Altitude = 2000
+	f.nav.setAssignedAltitude(5000)
+	f.nav.BustAltitude(600)
+	if alt, _, _ := f.nav.TargetAltitude(); alt != 5600 {
+		t.Errorf("expected target altitude 5600, got %.0f", alt)
+	}
+
+	// Reassigning the altitude corrects the deviation once it takes effect.
+	f.AssignAltitude(5000)
+	f.simTime = f.nav.Altitude.ActivateAt
+	wxs = f.weather(f.nav.FlightState.Altitude)
+	f.nav.UpdateWithWeather(f.callsign, wxs, nil, &f.fp, f.simTime, nil)
+	if alt, _, _ := f.nav.TargetAltitude(); alt != 5000 {
+		t.Errorf("expected target altitude 5000 after reassignment, got %.0f", alt)
+	}
+}
@@ -41,7 +41,7 @@ func (nav *Nav) ApproachHeading(callsign string, wxs wx.Sample, simTime Time) (h
 		var ok bool
 		courseTrue, courseLine, interceptWaypoints, ok = nav.findInterceptSegment(ap, wxs)
 		if !ok {
-			nav.approachOvershootRequestVectors()
+			nav.approachOvershootRequestVectors(callsign)
 			return
 		}
 	}
@@ -52,7 +52,7 @@ func (nav *Nav) ApproachHeading(callsign string, wxs wx.Sample, simTime Time) (h
 		assignedTrue := math.MagneticToTrue(assignedMag, nav.FlightState.MagneticVariation)
 		if d := math.HeadingDifference(courseTrue, assignedTrue); d > 45 {
 			// Too big an intercept angle; request vectors
-			nav.approachOvershootRequestVectors()
+			nav.approachOvershootRequestVectors(callsign)
 			return
 		}
 		// If the aircraft is still turning to the assigned heading, don't
@@ -77,7 +77,7 @@ func (nav *Nav) ApproachHeading(callsign string, wxs wx.Sample, simTime Time) (h
 
 		case turnToInterceptTurn:
 			if hasLocalizer && !nav.approachRecoveryFeasible(ap) {
-				nav.approachOvershootRequestVectors()
+				nav.approachOvershootRequestVectors(callsign)
 				return
 			}
 			nav.Approach.InterceptState = TurningToJoin
@@ -91,7 +91,7 @@ func (nav *Nav) ApproachHeading(callsign string, wxs wx.Sample, simTime Time) (h
 
 		case turnToInterceptCorrectableOvershoot:
 			if hasLocalizer && !nav.approachRecoveryFeasible(ap) {
-				nav.approachOvershootRequestVectors()
+				nav.approachOvershootRequestVectors(callsign)
 				return
 			}
 			acftTrue := math.MagneticToTrue(nav.FlightState.Heading, nav.FlightState.MagneticVariation)
@@ -115,7 +115,7 @@ func (nav *Nav) ApproachHeading(callsign string, wxs wx.Sample, simTime Time) (h
 			if turningToAssigned {
 				return
 			}
-			nav.approachOvershootRequestVectors()
+			nav.approachOvershootRequestVectors(callsign)
 		}
 		return
 
@@ -169,7 +169,7 @@ func (nav *Nav) ApproachHeading(callsign string, wxs wx.Sample, simTime Time) (h
 
 		// Ignore the approach altitude constraints if the aircraft is only
 		// intercepting but isn't cleared.
-		if nav.Approach.Cleared {
+		if nav.flyingApproach() {
 			nav.clearAltitudeForApproach()
 		}
 		// As with the heading assignment above under the InitialHeading
@@ -243,12 +243,31 @@ func (nav *Nav) approachRecoveryFeasible(ap *av.Approach) bool {
 }
 
 // approachOvershootRequestVectors cancels the approach and flags the
-// pilot to request new vectors from ATC.
-func (nav *Nav) approachOvershootRequestVectors() {
+// pilot to request new vectors from ATC. If the aircraft was cleared for
+// the approach, it flies the published missed approach in the meantime.
+func (nav *Nav) approachOvershootRequestVectors(callsign string) {
+	wasCleared := nav.Approach.Cleared
+
 	nav.Approach.InterceptState = NotIntercepting
 	nav.Approach.Cleared = false
 	nav.Approach.RequestVectors = true
 	nav.Approach.MissedApproachIntercept = true
+
+	if ma := nav.Approach.Assigned.MissedApproach; wasCleared && ma != nil {
+		NavLog(callsign, Time{}, NavLogApproach, "unable to intercept: flying the published missed approach")
+
+		nav.DeferredNavHeading = nil
+		nav.Heading = NavHeading{}
+		nav.flyMissedApproach(*ma, nil)
+		if len(ma.Waypoints) == 0 {
+			hdg := math.MagneticHeading(ma.Heading)
+			nav.Heading = NavHeading{Assigned: &hdg}
+		} else if ma.Hold != nil && ma.Waypoints[0].Fix == ma.Hold.Fix {
+			// The hold is at the first fix, so there's no waypoint to
+			// pass before entering it.
+			nav.Heading = NavHeading{Hold: nav.makeFlyHold(callsign, *ma.Hold)}
+		}
+	}
 }
 
 func (nav *Nav) getApproach(airport *av.Airport, id string) (*av.Approach, []*av.Approach, error) {
@@ -689,10 +708,19 @@ func (nav *Nav) ClearedApproach(approach string, traffic *FollowTraffic, simTime
 	}
 
 	id, lahsoRunway, _ := strings.Cut(approach, "/LAHSO")
+	id, maneuver, maneuverRunway := parseApproachManeuver(id)
 	if id != "" && nav.Approach.AssignedId != id {
 		return av.MakeUnableIntent("unable. We were told to expect the {appr} approach.", ap.FullName)
 	}
 
+	if maneuver != av.StraightInLanding {
+		if intent := nav.checkApproachManeuver(ap, maneuver, maneuverRunway); intent != nil {
+			return intent
+		}
+	} else if nav.Approach.Cleared && nav.Approach.ManeuverStarted {
+		return av.MakeUnableIntent("unable, we're already maneuvering for runway {rwy}", nav.Approach.ManeuverRunway)
+	}
+
 	if ap.Type == av.VisualApproach {
 		return nav.ClearedVisualApproach(traffic, lahsoRunway)
 	}
@@ -702,11 +730,14 @@ func (nav *Nav) ClearedApproach(approach string, traffic *FollowTraffic, simTime
 		if straightIn {
 			nav.Approach.NoPT = true
 		}
+		nav.Approach.Maneuver, nav.Approach.ManeuverRunway = maneuver, maneuverRunway
 		return av.ClearedApproachIntent{
-			Approach:    ap.FullName,
-			StraightIn:  straightIn,
-			CancelHold:  cancelHold,
-			LAHSORunway: lahsoRunway,
+			Approach:       ap.FullName,
+			StraightIn:     straightIn,
+			CancelHold:     cancelHold,
+			LAHSORunway:    lahsoRunway,
+			Maneuver:       maneuver,
+			ManeuverRunway: maneuverRunway,
 		}
 	}
 
@@ -715,6 +746,8 @@ func (nav *Nav) ClearedApproach(approach string, traffic *FollowTraffic, simTime
 	}
 
 	cancelHold := nav.applyClearedApproachState()
+	nav.Approach.Maneuver, nav.Approach.ManeuverRunway = maneuver, maneuverRunway
+	nav.Approach.ManeuverStarted = false
 	if nav.Approach.PassedApproachFix {
 		// We've already passed an approach fix, so allow it to start descending.
 		nav.clearAltitudeForApproach()
@@ -734,13 +767,58 @@ func (nav *Nav) ClearedApproach(approach string, traffic *FollowTraffic, simTime
 	nav.flyProcedureTurnIfNecessary()
 
 	return av.ClearedApproachIntent{
-		Approach:    ap.FullName,
-		StraightIn:  straightIn,
-		CancelHold:  cancelHold,
-		LAHSORunway: lahsoRunway,
+		Approach:       ap.FullName,
+		StraightIn:     straightIn,
+		CancelHold:     cancelHold,
+		LAHSORunway:    lahsoRunway,
+		Maneuver:       maneuver,
+		ManeuverRunway: maneuverRunway,
 	}
 }
 
+// parseApproachManeuver splits a circle-to-land ("I4R/CIR31") or sidestep
+// ("I22L/SS22R") suffix off of an approach clearance, returning the
+// approach id, the maneuver, and the runway to land on.
+func parseApproachManeuver(approach string) (string, av.ApproachManeuver, string) {
+	if id, rwy, ok := strings.Cut(approach, "/CIR"); ok {
+		return id, av.CircleToLand, rwy
+	}
+	if id, rwy, ok := strings.Cut(approach, "/SS"); ok {
+		return id, av.SidestepManeuver, rwy
+	}
+	return approach, av.StraightInLanding, ""
+}
+
+// sidestepMaxHeadingDifference is the largest difference between the
+// approach runway's heading and a runway that we'll sidestep to.
+const sidestepMaxHeadingDifference = 15
+
+func (nav *Nav) checkApproachManeuver(ap *av.Approach, maneuver av.ApproachManeuver, runway string) av.CommandIntent {
+	if ap.Type == av.VisualApproach || ap.Type == av.ChartedVisualApproach {
+		return av.MakeUnableIntent("unable, we can only circle or sidestep from an instrument approach")
+	}
+	if na := nav.Approach; na.Cleared && na.ManeuverStarted && (na.Maneuver != maneuver || na.ManeuverRunway != runway) {
+		return av.MakeUnableIntent("unable, we're already maneuvering for runway {rwy}", na.ManeuverRunway)
+	}
+
+	arr := nav.FlightState.ArrivalAirport.Fix
+	rwy, ok := av.LookupRunway(arr, runway)
+	if !ok {
+		return av.MakeUnableIntent("unable, we don't know runway " + runway)
+	}
+	if rwy.Id == ap.Runway {
+		return av.MakeUnableIntent("unable, the {appr} approach is already to runway {rwy}", ap.FullName, runway)
+	}
+
+	if maneuver == av.SidestepManeuver {
+		apRwy, ok := av.LookupRunway(arr, ap.Runway)
+		if !ok || math.HeadingDifference(apRwy.Heading, rwy.Heading) > sidestepMaxHeadingDifference {
+			return av.MakeUnableIntent("unable, runway {rwy} isn't parallel to the approach runway", runway)
+		}
+	}
+	return nil
+}
+
 // ClearedVisualApproach finalizes a non-charted visual approach clearance for
 // the runway named on Nav.Approach.Assigned (a synthesized VisualApproach). It
 // picks the route the aircraft will fly:
@@ -947,15 +1025,170 @@ func (nav *Nav) visualApproachRouteFollowingTraffic(runway string, trafficPositi
 	return append([]av.Waypoint{join}, trafficRoute...)
 }
 
+const (
+	// Height above the landing runway at which a circling aircraft levels
+	// off and leaves the approach course; aircraft also start circling
+	// when they get within circlingStartDistance of the approach runway.
+	circlingAGL           = 700
+	circlingStartDistance = 2
+	// Aircraft that are sidestepping leave the approach course at
+	// sidestepAGL or within sidestepStartDistance of the approach runway.
+	sidestepAGL           = 1000
+	sidestepStartDistance = 3
+	// Distance from the landing runway's threshold of the final approach
+	// point and the offset of the downwind for circling.
+	maneuverFinalDistance = 1.5
+	circlingPatternOffset = 1.5
+)
+
+// updateApproachManeuver starts a circle-to-land or sidestep maneuver once
+// an aircraft established on the approach gets low enough or close enough
+// to the approach runway, replacing the rest of its route with one to the
+// runway it will land on.
+func (nav *Nav) updateApproachManeuver(callsign string, simTime Time) {
+	na := &nav.Approach
+	if na.Maneuver == av.StraightInLanding || na.ManeuverStarted || !na.Cleared || na.Assigned == nil {
+		return
+	}
+	if nav.Heading.Assigned != nil || nav.Heading.Hold != nil {
+		return
+	}
+	if !na.PassedFAF && na.InterceptState != OnApproachCourse {
+		return
+	}
+
+	// The route should end at the approach runway's threshold; if it
+	// doesn't, we've been sent somewhere else and there's nothing to do.
+	idx := slices.IndexFunc(nav.Waypoints, func(wp av.Waypoint) bool { return wp.Land() })
+	if idx == -1 {
+		return
+	}
+
+	rwy, ok := av.LookupRunway(nav.FlightState.ArrivalAirport.Fix, na.ManeuverRunway)
+	if !ok {
+		return
+	}
+	dist := math.NMDistance2LL(nav.FlightState.Position, nav.Waypoints[idx].Location)
+	agl := nav.FlightState.Altitude - float32(rwy.Elevation)
+
+	var wps []av.Waypoint
+	switch na.Maneuver {
+	case av.CircleToLand:
+		if agl > circlingAGL && dist > circlingStartDistance {
+			return
+		}
+		wps = nav.circleToLandRoute(rwy)
+	case av.SidestepManeuver:
+		if agl > sidestepAGL && dist > sidestepStartDistance {
+			return
+		}
+		wps = nav.sidestepRoute(rwy)
+	}
+
+	NavLog(callsign, simTime, NavLogApproach, "%s: leaving the approach course for runway %s", na.Maneuver, rwy.Id)
+
+	na.ManeuverStarted = true
+	// Fly the new route's descent exactly, as after the FAF.
+	na.PassedFAF = true
+	nav.Waypoints = append(wps, nav.FlightState.ArrivalAirport)
+}
+
+// circleToLandRoute returns the waypoints for a circle-to-land maneuver to
+// rwy from the aircraft's current position: downwind and base legs on the
+// aircraft's side of the runway as needed and then final.
+func (nav *Nav) circleToLandRoute(rwy av.Runway) []av.Waypoint {
+	nmPerLongitude := nav.FlightState.NmPerLongitude
+	hdg := math.MagneticToTrue(rwy.Heading, nav.FlightState.MagneticVariation)
+	threshold := math.Offset2LL(rwy.Threshold, hdg, rwy.DisplacedThresholdDistance, nmPerLongitude)
+
+	// Work out where the aircraft is relative to the runway: along is the
+	// distance past the threshold in the landing direction and the sign
+	// of across gives the side of the runway it's on.
+	dir := [2]float32{math.Sin(math.Radians(hdg)), math.Cos(math.Radians(hdg))}
+	v := math.Sub2f(math.LL2NM(nav.FlightState.Position, nmPerLongitude), math.LL2NM(threshold, nmPerLongitude))
+	along := math.Dot(v, dir)
+	across := math.Dot(v, [2]float32{dir[1], -dir[0]})
+	side := float32(90)
+	if across < 0 {
+		side = -90
+	}
+
+	circlingAlt := float32(rwy.Elevation + circlingAGL)
+	makeWaypoint := func(name string, p math.Point2LL, alt float32) av.Waypoint {
+		wp := av.Waypoint{Fix: "_" + rwy.Id + "_" + name, Location: p}
+		wp.SetAltitudeRestriction(av.MakeAtAltitudeRestriction(alt))
+		wp.SetOnApproach(true)
+		return wp
+	}
+
+	final := math.Offset2LL(threshold, math.OffsetHeading(hdg, 180), maneuverFinalDistance, nmPerLongitude)
+	// 3 degree glidepath from the final point to the threshold.
+	finalAlt := float32(rwy.Elevation+rwy.ThresholdCrossingHeight) + maneuverFinalDistance*318
+	base := math.Offset2LL(final, math.OffsetHeading(hdg, side), circlingPatternOffset, nmPerLongitude)
+	downwind := math.Offset2LL(threshold, math.OffsetHeading(hdg, side), circlingPatternOffset, nmPerLongitude)
+
+	var wps []av.Waypoint
+	if along > 0 {
+		// Past the threshold: fly a downwind.
+		wps = append(wps, makeWaypoint("DOWNWIND", downwind, circlingAlt))
+	}
+	if along > -maneuverFinalDistance {
+		wps = append(wps, makeWaypoint("BASE", base, circlingAlt))
+	}
+	wps = append(wps, makeWaypoint("FINAL", final, min(finalAlt, circlingAlt)))
+
+	return append(wps, nav.maneuverThresholdWaypoint(rwy, threshold))
+}
+
+// sidestepRoute returns the waypoints for a sidestep to rwy: a point on
+// its final approach course and then its threshold.
+func (nav *Nav) sidestepRoute(rwy av.Runway) []av.Waypoint {
+	nmPerLongitude := nav.FlightState.NmPerLongitude
+	hdg := math.MagneticToTrue(rwy.Heading, nav.FlightState.MagneticVariation)
+	threshold := math.Offset2LL(rwy.Threshold, hdg, rwy.DisplacedThresholdDistance, nmPerLongitude)
+
+	finalDist := min(maneuverFinalDistance,
+		0.5*math.NMDistance2LL(nav.FlightState.Position, threshold))
+	final := av.Waypoint{
+		Fix:      "_" + rwy.Id + "_FINAL",
+		Location: math.Offset2LL(threshold, math.OffsetHeading(hdg, 180), finalDist, nmPerLongitude),
+	}
+	final.SetAltitudeRestriction(av.MakeAtAltitudeRestriction(
+		float32(rwy.Elevation+rwy.ThresholdCrossingHeight) + finalDist*318))
+	final.SetOnApproach(true)
+
+	return []av.Waypoint{final, nav.maneuverThresholdWaypoint(rwy, threshold)}
+}
+
+func (nav *Nav) maneuverThresholdWaypoint(rwy av.Runway, threshold math.Point2LL) av.Waypoint {
+	wp := av.Waypoint{
+		Fix:      "_" + rwy.Id + "_THRESHOLD",
+		Location: threshold,
+		Flags:    av.WaypointFlagLand | av.WaypointFlagFlyOver,
+	}
+	wp.SetAltitudeRestriction(av.MakeAtAltitudeRestriction(float32(rwy.Elevation + rwy.ThresholdCrossingHeight)))
+	wp.SetOnApproach(true)
+	return wp
+}
+
 // applyClearedApproachState performs the nav-state reset common to every
-// approach clearance: cancel any hold, clear speed restrictions, mark the
-// approach as cleared and no longer standby. Returns true iff the aircraft
+// approach clearance: cancel the current hold and any missed approach
+// hold, clear speed restrictions, mark the approach as cleared and no
+// longer standby. Returns true iff the aircraft
 // was in a hold that is now being cancelled.
 func (nav *Nav) applyClearedApproachState() (cancelHold bool) {
 	cancelHold = nav.Heading.Hold != nil
 	if nav.Heading.Hold != nil {
 		nav.Heading.Hold.Cancel = true
 	}
+	// Also cancel the hold at the end of a missed approach if it hasn't
+	// been reached yet; holds that the controller assigned stay in place.
+	for fix, nfa := range nav.FixAssignments {
+		if nfa.Hold != nil && nfa.MissedApproachHold {
+			nfa.Hold, nfa.MissedApproachHold = nil, false
+			nav.FixAssignments[fix] = nfa
+		}
+	}
 	nav.Approach.Cleared = true
 	nav.Approach.StandbyApproach = false
 	nav.Approach.MissedApproachIntercept = false
@@ -1478,3 +1478,134 @@ func TestClearedVisualReissuedDoesNotRecomputeRoute(t *testing.T) {
 		}
 	}
 }
+
+func setupManeuverTestAirport(t *testing.T, nmPerLong float32) {
+	t.Helper()
+	const icao = "KTST"
+	old, hadAirport := av.DB.Airports[icao]
+	av.DB.Airports[icao] = av.FAAAirport{
+		Id: icao,
+		Runways: []av.Runway{
+			{Id: "36L", Heading: 360, Threshold: math.NM2LL([2]float32{0, 0}, nmPerLong), ThresholdCrossingHeight: 50},
+			{Id: "36R", Heading: 360, Threshold: math.NM2LL([2]float32{0.5, 0}, nmPerLong), ThresholdCrossingHeight: 50},
+			{Id: "27", Heading: 270, Threshold: math.NM2LL([2]float32{1, 0.5}, nmPerLong), ThresholdCrossingHeight: 50},
+		},
+	}
+	t.Cleanup(func() {
+		if hadAirport {
+			av.DB.Airports[icao] = old
+		} else {
+			delete(av.DB.Airports, icao)
+		}
+	})
+}
+
+func makeManeuverTestNav(pos [2]float32, alt float32, maneuver av.ApproachManeuver, runway string) *Nav {
+	nmPerLong := float32(60)
+	threshold := av.Waypoint{
+		Fix:      "_36L_THRESHOLD",
+		Location: math.NM2LL([2]float32{0, 0}, nmPerLong),
+		Flags:    av.WaypointFlagLand | av.WaypointFlagFlyOver,
+	}
+	threshold.SetAltitudeRestriction(av.MakeAtAltitudeRestriction(50))
+
+	return &Nav{
+		FlightState: FlightState{
+			Position:       math.NM2LL(pos, nmPerLong),
+			Heading:        360,
+			Altitude:       alt,
+			NmPerLongitude: nmPerLong,
+			ArrivalAirport: av.Waypoint{Fix: "KTST"},
+		},
+		Approach: NavApproach{
+			Assigned:       &av.Approach{Type: av.ILSApproach, Runway: "36L", FullName: "ILS Runway 36L"},
+			AssignedId:     "I36L",
+			Cleared:        true,
+			InterceptState: OnApproachCourse,
+			Maneuver:       maneuver,
+			ManeuverRunway: runway,
+		},
+		Waypoints: []av.Waypoint{threshold, {Fix: "KTST"}},
+	}
+}
+
+func TestSidestepManeuver(t *testing.T) {
+	setupManeuverTestAirport(t, 60)
+
+	// Still too high and too far out to sidestep.
+	n := makeManeuverTestNav([2]float32{0, -5}, 1500, av.SidestepManeuver, "36R")
+	n.updateApproachManeuver("TEST", Time{})
+	if n.Approach.ManeuverStarted || n.Waypoints[0].Fix != "_36L_THRESHOLD" {
+		t.Fatalf("sidestep started early: %s", av.WaypointArray(n.Waypoints).Encode())
+	}
+
+	n.FlightState.Position = math.NM2LL([2]float32{0, -2}, 60)
+	n.FlightState.Altitude = 800
+	n.updateApproachManeuver("TEST", Time{})
+	if !n.Approach.ManeuverStarted {
+		t.Fatal("expected sidestep to start")
+	}
+	var fixes []string
+	for _, wp := range n.Waypoints {
+		fixes = append(fixes, wp.Fix)
+	}
+	if !slices.Equal(fixes, []string{"_36R_FINAL", "_36R_THRESHOLD", "KTST"}) {
+		t.Errorf("sidestep route = %v", fixes)
+	}
+	if !n.Waypoints[1].Land() {
+		t.Errorf("expected to land at the 36R threshold")
+	}
+	if rwy := n.Approach.LandingRunway(); rwy != "36R" {
+		t.Errorf("landing runway = %q, want 36R", rwy)
+	}
+}
+
+func TestCircleToLandManeuver(t *testing.T) {
+	setupManeuverTestAirport(t, 60)
+
+	n := makeManeuverTestNav([2]float32{0, -1.5}, 1000, av.CircleToLand, "27")
+	n.updateApproachManeuver("TEST", Time{})
+	if !n.Approach.ManeuverStarted {
+		t.Fatal("expected circling to start")
+	}
+
+	// The aircraft is south of and past the threshold of runway 27, so it
+	// should fly a downwind and base south of the runway.
+	var fixes []string
+	for _, wp := range n.Waypoints {
+		fixes = append(fixes, wp.Fix)
+	}
+	if !slices.Equal(fixes, []string{"_27_DOWNWIND", "_27_BASE", "_27_FINAL", "_27_THRESHOLD", "KTST"}) {
+		t.Fatalf("circling route = %v", fixes)
+	}
+	for i, want := range [][2]float32{{1, -1}, {2.5, -1}, {2.5, 0.5}, {1, 0.5}} {
+		if got := math.LL2NM(n.Waypoints[i].Location, 60); math.Distance2f(got, want) > 0.05 {
+			t.Errorf("%s = %.2f, %.2f; want %.2f, %.2f", fixes[i], got[0], got[1], want[0], want[1])
+		}
+	}
+	if ar := n.Waypoints[0].AltitudeRestriction(); ar == nil || ar.TargetAltitude(1000) != circlingAGL {
+		t.Errorf("expected downwind at the circling altitude, got %v", ar)
+	}
+}
+
+func TestApproachManeuverValidation(t *testing.T) {
+	setupManeuverTestAirport(t, 60)
+
+	for _, tc := range []struct {
+		maneuver av.ApproachManeuver
+		runway   string
+		ok       bool
+	}{
+		{av.SidestepManeuver, "36R", true},
+		{av.CircleToLand, "27", true},
+		{av.SidestepManeuver, "27", false}, // not parallel
+		{av.CircleToLand, "36L", false},    // the approach runway
+		{av.CircleToLand, "18", false},     // unknown
+	} {
+		n := makeManeuverTestNav([2]float32{0, -5}, 2000, av.StraightInLanding, "")
+		intent := n.checkApproachManeuver(n.Approach.Assigned, tc.maneuver, tc.runway)
+		if _, unable := intent.(av.UnableIntent); unable == tc.ok {
+			t.Errorf("%s runway %s: got %v, want ok=%v", tc.maneuver, tc.runway, intent, tc.ok)
+		}
+	}
+}
@@ -26,6 +26,31 @@ func (nav *Nav) GoAroundWithProcedure(altitude float32, runwayEndWP av.Waypoint)
 	nav.Waypoints = av.WaypointArray{runwayEndWP, nav.FlightState.ArrivalAirport}
 }
 
+// GoAroundWithMissedApproach initiates a go-around that flies the
+// approach's published missed approach after runwayEndWP, which is as
+// for GoAroundWithProcedure.
+func (nav *Nav) GoAroundWithMissedApproach(ma av.MissedApproach, runwayEndWP av.Waypoint) {
+	nav.DeferredNavHeading = nil
+	nav.Speed = NavSpeed{}
+	nav.Approach = NavApproach{}
+	nav.flyMissedApproach(ma, av.WaypointArray{runwayEndWP})
+}
+
+// flyMissedApproach climbs to the missed approach altitude and flies the
+// missed approach route after the given waypoints, entering the published
+// hold at its end. Controller instructions override it as usual.
+func (nav *Nav) flyMissedApproach(ma av.MissedApproach, route av.WaypointArray) {
+	if ma.Altitude != 0 {
+		nav.setAssignedAltitude(float32(ma.Altitude))
+	}
+	route = append(route, ma.Waypoints...)
+	nav.Waypoints = append(route, nav.FlightState.ArrivalAirport)
+	if ma.Hold != nil {
+		hold := *ma.Hold
+		nav.FixAssignments[hold.Fix] = NavFixAssignment{Hold: &hold, MissedApproachHold: true}
+	}
+}
+
 func (nav *Nav) AssignAltitude(alt float32, afterSpeed bool, simTime Time, delayReduction time.Duration) av.CommandIntent {
 	nav.clearFixAltitudes()
 	intent, ok := nav.prepareAltitudeAssignment(alt, afterSpeed)
@@ -1271,6 +1296,71 @@ func (nav *Nav) CancelApproachClearance() av.CommandIntent {
 	return av.ApproachIntent{Type: av.ApproachCancel}
 }
 
+// Breakout handles a breakout instruction from the final monitor
+// controller during simultaneous approaches: the pilot turns immediately
+// to the assigned heading, climbs or descends to the given altitude (or
+// maintains the current one if alt is nil), and is no longer cleared for
+// the approach.
+func (nav *Nav) Breakout(hdg math.MagneticHeading, turn av.TurnDirection, alt *float32) av.CommandIntent {
+	if hdg <= 0 || hdg > 360 {
+		return av.MakeUnableIntent("unable. {hdg} isn't a valid heading", hdg)
+	}
+	if turn != av.TurnLeft && turn != av.TurnRight {
+		return av.MakeUnableIntent("unable. say direction of turn")
+	}
+
+	a := math.Round(nav.FlightState.Altitude/100) * 100
+	if alt != nil {
+		if *alt > nav.Perf.Ceiling {
+			return av.MakeUnableIntent("unable. That altitude is above our ceiling.")
+		}
+		a = *alt
+	}
+
+	intent := av.BreakoutIntent{
+		Heading:   hdg,
+		Turn:      av.HeadingTurnToRight,
+		Altitude:  a,
+		Direction: av.AltitudeMaintain,
+	}
+	if turn == av.TurnLeft {
+		intent.Turn = av.HeadingTurnToLeft
+	}
+	if a > nav.FlightState.Altitude+50 {
+		intent.Direction = av.AltitudeClimb
+	} else if a < nav.FlightState.Altitude-50 {
+		intent.Direction = av.AltitudeDescend
+	}
+
+	nav.leaveApproach(hdg, turn, a)
+
+	return intent
+}
+
+// Blunder makes an aircraft that is established on a final approach
+// course deviate from it, turning to the given heading and leveling off
+// at its current altitude. It is used to simulate blunders into the NTZ
+// during simultaneous approaches.
+func (nav *Nav) Blunder(hdg math.MagneticHeading, turn av.TurnDirection) {
+	nav.leaveApproach(hdg, turn, nav.FlightState.Altitude)
+}
+
+// leaveApproach immediately puts the aircraft on the given heading and
+// altitude and cancels its approach clearance, though it keeps its
+// assigned approach so that it can be cleared again.
+func (nav *Nav) leaveApproach(hdg math.MagneticHeading, turn av.TurnDirection, alt float32) {
+	nav.DeferredNavHeading = nil
+	nav.Heading = NavHeading{Assigned: &hdg, Turn: &turn}
+	nav.Speed = NavSpeed{}
+	nav.clearFixAltitudes()
+	nav.setAssignedAltitude(alt)
+	nav.Approach = NavApproach{
+		Assigned:   nav.Approach.Assigned,
+		AssignedId: nav.Approach.AssignedId,
+		ATPAVolume: nav.Approach.ATPAVolume,
+	}
+}
+
 func (nav *Nav) ClimbViaSID(simTime Time) av.CommandIntent {
 	if wps := nav.AssignedWaypoints(); len(wps) == 0 || !wps[0].OnSID() {
 		return av.MakeUnableIntent("unable. We're not flying a departure procedure")
@@ -1357,5 +1447,5 @@ func (nav *Nav) AltitudeOurDiscretion() av.CommandIntent {
 }
 
 func (nav *Nav) InterceptedButNotCleared() bool {
-	return nav.Approach.InterceptState == OnApproachCourse && !nav.Approach.Cleared
+	return nav.Approach.InterceptState == OnApproachCourse && !nav.flyingApproach()
 }
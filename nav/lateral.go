@@ -5,7 +5,9 @@
 package nav
 
 import (
+	"errors"
 	"fmt"
+	gomath "math"
 	"slices"
 
 	av "github.com/mmp/vice/aviation"
@@ -110,11 +112,23 @@ func (nav *Nav) DepartOnCourse(alt float32, exit string, simTime Time) {
 	nav.EnqueueOnCourse(simTime)
 }
 
+// Check logs any problems that Validate finds with the Nav's state.
 func (nav *Nav) Check(lg *log.Logger) {
+	if err := nav.Validate(); err != nil {
+		lg.Errorf("%v", err)
+	}
+}
+
+// Validate checks the Nav's state for problems that indicate bugs in the
+// navigation code or in the route definitions it was initialized with:
+// waypoints without locations and non-finite position or altitude. Each
+// problem found is returned joined into a single error.
+func (nav *Nav) Validate() error {
+	var errs []error
 	check := func(waypoints []av.Waypoint, what string) {
 		for _, wp := range waypoints {
 			if wp.Location.IsZero() {
-				lg.Errorf("zero waypoint location for %s in %s", wp.Fix, what)
+				errs = append(errs, fmt.Errorf("zero waypoint location for %s in %s", wp.Fix, what))
 			}
 		}
 	}
@@ -125,6 +139,20 @@ func (nav *Nav) Check(lg *log.Logger) {
 			check(waypoints, fmt.Sprintf("approach %d waypoints", i))
 		}
 	}
+
+	fs := &nav.FlightState
+	if !finite(fs.Position[0]) || !finite(fs.Position[1]) {
+		errs = append(errs, fmt.Errorf("non-finite position %v", fs.Position))
+	}
+	if !finite(fs.Altitude) {
+		errs = append(errs, fmt.Errorf("non-finite altitude %v", fs.Altitude))
+	}
+
+	return errors.Join(errs...)
+}
+
+func finite(v float32) bool {
+	return !gomath.IsNaN(float64(v)) && !gomath.IsInf(float64(v), 0)
 }
 
 func (nav *Nav) Update(callsign string, model *wx.Model, fp *av.FlightPlan, arrivalMETAR *wx.METAR, simTime Time,
@@ -138,6 +166,7 @@ func (nav *Nav) Update(callsign string, model *wx.Model, fp *av.FlightPlan, arri
 func (nav *Nav) UpdateWithWeather(callsign string, wxs wx.Sample, arrivalMETAR *wx.METAR, fp *av.FlightPlan, simTime Time, bravo *av.AirspaceGrid) UpdateResult {
 	nav.PendingWaypointActionEvents = nil
 	nav.activatePendingAltitude(simTime)
+	nav.updateApproachManeuver(callsign, simTime)
 
 	// Log current state every tick
 	NavLog(callsign, simTime, NavLogState, "pos=%.4f,%.4f alt=%.0f hdg=%.0f ias=%.0f gs=%.0f bank=%.1f rate=%.0f",
@@ -433,13 +462,20 @@ func (nav *Nav) updateWaypoints(callsign string, wxs wx.Sample, fp *av.FlightPla
 			}
 		}
 
+		if fix := nav.Approach.LostCommsFix; fix != "" && wp.Fix == fix {
+			nav.Approach.LostCommsFix = ""
+			nav.Approach.LostComms = true
+			nav.Speed = NavSpeed{}
+			nav.flyProcedureTurnIfNecessary()
+		}
+
 		if wp.InterceptApproach() {
 			if fp != nil {
 				_ = nav.InterceptApproach(fp.ArrivalAirport, nil)
 			}
 		}
 
-		if nav.Approach.Cleared {
+		if nav.flyingApproach() {
 			// The aircraft has made it to the approach fix they
 			// were cleared to, so they can start to descend.
 			nav.clearAltitudeForApproach()
@@ -467,7 +503,7 @@ func (nav *Nav) updateWaypoints(callsign string, wxs wx.Sample, fp *av.FlightPla
 		}
 
 		if wp.AltitudeRestriction() != nil && !nav.InterceptedButNotCleared() &&
-			(!nav.Approach.Cleared || wp.AltitudeRestriction().Range[0] < nav.FlightState.Altitude) {
+			(!nav.flyingApproach() || wp.AltitudeRestriction().Range[0] < nav.FlightState.Altitude) {
 			// Don't climb if we're cleared approach and below the next
 			// fix's altitude. Copy the value since the pointer into the
 			// slice element could become stale if the slice is reallocated.
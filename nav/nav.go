@@ -251,6 +251,37 @@ type NavApproach struct {
 
 	VisualReferences     []*av.Approach // Reference approaches for non-charted visual approach
 	InterceptedReference *av.Approach   // Approach giving the committed-to route for a non-charted visual approach
+
+	Maneuver        av.ApproachManeuver // circle to land / sidestep after the approach
+	ManeuverRunway  string              // runway to land on for Maneuver
+	ManeuverStarted bool                // have we left the approach course for ManeuverRunway?
+
+	// Aircraft that have lost communications join their assigned approach
+	// at LostCommsFix on their own; LostComms is set once they do, after
+	// which they fly it as if they had been cleared for it, though they
+	// haven't been.
+	LostCommsFix string
+	LostComms    bool
+}
+
+// flyingApproach returns whether the aircraft is flying its assigned
+// approach, either because it was cleared for it or because it joined it
+// on its own after losing communications.
+func (nav *Nav) flyingApproach() bool {
+	return nav.Approach.Cleared || nav.Approach.LostComms
+}
+
+// LandingRunway returns the runway the aircraft will land on: the
+// approach's runway unless it has been cleared to circle or sidestep to
+// another one.
+func (na *NavApproach) LandingRunway() string {
+	if na.Maneuver != av.StraightInLanding && na.ManeuverRunway != "" {
+		return na.ManeuverRunway
+	}
+	if na.Assigned != nil {
+		return na.Assigned.Runway
+	}
+	return ""
 }
 
 // EffectivelyCleared reports whether the aircraft has been cleared for the
@@ -311,6 +342,9 @@ type NavFixAssignment struct {
 		Altitude    *float32
 	}
 	Hold *av.Hold
+	// MissedApproachHold is set if Hold is the published hold at the end
+	// of a missed approach rather than one the controller assigned.
+	MissedApproachHold bool
 }
 
 type NavAirwork struct {
@@ -339,10 +373,10 @@ const (
 )
 
 func MakeArrivalNav(callsign av.ADSBCallsign, arr *av.Arrival, fp av.FlightPlan, perf av.AircraftPerformance,
-	nmPerLongitude float32, magneticVariation float32, model *wx.Model, simTime Time, lg *log.Logger) *Nav {
+	nmPerLongitude float32, magneticVariation float32, model *wx.Model, simTime Time, r *rand.Rand, lg *log.Logger) *Nav {
 	randomizeAltitudeRange := fp.Rules == av.FlightRulesVFR
 	if nav := makeNav(callsign, fp, perf, arr.Waypoints, randomizeAltitudeRange, nmPerLongitude,
-		magneticVariation, lg); nav != nil {
+		magneticVariation, r, lg); nav != nil {
 		if !arr.SpeedRestriction.IsZero() {
 			sr := arr.SpeedRestriction
 			nav.Speed.Restriction = &sr
@@ -373,9 +407,9 @@ func MakeArrivalNav(callsign av.ADSBCallsign, arr *av.Arrival, fp av.FlightPlan,
 
 func MakeDepartureNav(callsign av.ADSBCallsign, fp av.FlightPlan, perf av.AircraftPerformance,
 	assignedAlt, clearedAlt int, wp []av.Waypoint, randomizeAltitudeRange bool,
-	nmPerLongitude float32, magneticVariation float32, model *wx.Model, simTime Time, lg *log.Logger) *Nav {
+	nmPerLongitude float32, magneticVariation float32, model *wx.Model, simTime Time, r *rand.Rand, lg *log.Logger) *Nav {
 	if nav := makeNav(callsign, fp, perf, wp, randomizeAltitudeRange, nmPerLongitude, magneticVariation,
-		lg); nav != nil {
+		r, lg); nav != nil {
 		if assignedAlt != 0 {
 			nav.setAssignedAltitude(float32(min(assignedAlt, fp.Altitude)))
 		} else {
@@ -390,10 +424,10 @@ func MakeDepartureNav(callsign av.ADSBCallsign, fp av.FlightPlan, perf av.Aircra
 }
 
 func MakeOverflightNav(callsign av.ADSBCallsign, of *av.Overflight, fp av.FlightPlan, perf av.AircraftPerformance,
-	nmPerLongitude float32, magneticVariation float32, model *wx.Model, simTime Time, lg *log.Logger) *Nav {
+	nmPerLongitude float32, magneticVariation float32, model *wx.Model, simTime Time, r *rand.Rand, lg *log.Logger) *Nav {
 	randomizeAltitudeRange := fp.Rules == av.FlightRulesVFR
 	if nav := makeNav(callsign, fp, perf, of.Waypoints, randomizeAltitudeRange, nmPerLongitude,
-		magneticVariation, lg); nav != nil {
+		magneticVariation, r, lg); nav != nil {
 		if !of.SpeedRestriction.IsZero() {
 			sr := of.SpeedRestriction
 			nav.Speed.Restriction = &sr
@@ -420,12 +454,14 @@ func MakeOverflightNav(callsign av.ADSBCallsign, of *av.Overflight, fp av.Flight
 
 func makeNav(callsign av.ADSBCallsign, fp av.FlightPlan, perf av.AircraftPerformance, wp []av.Waypoint,
 	randomizeAltitudeRange bool, nmPerLongitude float32, magneticVariation float32,
-	lg *log.Logger) *Nav {
+	r *rand.Rand, lg *log.Logger) *Nav {
 	nav := &Nav{
 		Perf:           perf,
 		FinalAltitude:  float32(fp.Altitude),
 		FixAssignments: make(map[string]NavFixAssignment),
-		Rand:           rand.Make(),
+		// Seed from the caller's generator so that a Sim with a known seed
+		// spawns aircraft with reproducible routes and behavior.
+		Rand: rand.MakeSeeded(r.Uint64()),
 	}
 
 	// Copy the provided waypoints so that any local modifications we make don't pollute the
@@ -436,6 +472,19 @@ func makeNav(callsign av.ADSBCallsign, fp av.FlightPlan, perf av.AircraftPerform
 	av.RandomizeRoute(nav.Waypoints, nav.Rand, randomizeAltitudeRange, nav.Perf, nmPerLongitude,
 		magneticVariation, fp.ArrivalAirport, lg)
 
+	if randomizeAltitudeRange {
+		// The route's altitude ranges are for VFR aircraft; make sure the
+		// altitudes chosen from them clear the terrain around each waypoint.
+		for i := range nav.Waypoints {
+			wp := &nav.Waypoints[i]
+			if ar := wp.AltitudeRestriction(); ar != nil {
+				if minAlt := MinimumVFRAltitude(wp.Location, VFRTerrainRadius, nmPerLongitude); ar.Range[0] < minAlt {
+					wp.SetAltitudeRestriction(av.MakeAtAltitudeRestriction(minAlt))
+				}
+			}
+		}
+	}
+
 	nav.RouteAltitudeActions = slices.ContainsFunc(nav.Waypoints,
 		func(wp av.Waypoint) bool { return wp.HasAltitudeActions() })
 
@@ -664,7 +713,7 @@ func (nav *Nav) EnqueueOnCourse(simTime Time) {
 }
 
 func (nav *Nav) OnApproach(checkAltitude bool) bool {
-	if !nav.Approach.Cleared {
+	if !nav.flyingApproach() {
 		return false
 	}
 
@@ -1194,3 +1243,92 @@ func (nav *Nav) DivertToAirport(airport string) {
 	nav.FlightState.ArrivalAirportLocation = ap.Location
 	nav.FlightState.ArrivalAirportElevation = float32(ap.Elevation)
 }
+
+// LostComms has the aircraft fly the FAR 91.185 lost communications
+// procedure. It resumes its route--the route in a pending direct
+// clearance, if there is one--and goes direct to the next fix if it was
+// being vectored. It maintains the higher of its assigned altitude and
+// minAltitude, which the caller gives as the highest of the expected
+// altitude and the minimum altitude for the route. If it has been told
+// to expect an approach, it joins the approach at the first fix on its
+// route that is on the approach--or, if none is, at the closest initial
+// fix--and then flies the approach, descending from there. An aircraft
+// already cleared for an approach continues flying it.
+func (nav *Nav) LostComms(minAltitude float32) {
+	if nav.Approach.Cleared {
+		return
+	}
+
+	if nav.hasDeferredRoute() {
+		nav.Waypoints = nav.DeferredNavHeading.Waypoints
+	}
+	nav.DeferredNavHeading = nil
+	nav.Heading = NavHeading{}
+	nav.Airwork = nil
+
+	alt := nav.FlightState.Altitude
+	if a := nav.Altitude.Assigned; a != nil {
+		alt = *a
+	} else if c := nav.Altitude.Cleared; c != nil {
+		alt = *c
+	}
+	alt = max(alt, minAltitude)
+	if nav.Perf.Ceiling > 0 {
+		alt = min(alt, nav.Perf.Ceiling)
+	}
+	nav.Altitude = NavAltitude{Assigned: &alt}
+
+	if ap := nav.Approach.Assigned; ap != nil && ap.Type != av.VisualApproach && ap.Type != av.ChartedVisualApproach {
+		nav.Waypoints, nav.Approach.LostCommsFix = nav.approachRoute(ap)
+	}
+}
+
+// JoinExpectedApproach routes the aircraft to the approach it has been
+// told to expect so that it can be cleared for it; it's used when the
+// aircraft's route doesn't otherwise lead to the approach, e.g. after it
+// has diverted.
+func (nav *Nav) JoinExpectedApproach() {
+	if ap := nav.Approach.Assigned; ap != nil && ap.Type != av.VisualApproach && ap.Type != av.ChartedVisualApproach {
+		nav.Waypoints, _ = nav.approachRoute(ap)
+	}
+}
+
+// approachRoute returns the aircraft's route joined to the given approach
+// and the fix where it joins it.
+func (nav *Nav) approachRoute(ap *av.Approach) ([]av.Waypoint, string) {
+	route := slices.Clone(nav.Waypoints)
+	if n := len(route); n > 0 && route[n-1].Fix == nav.FlightState.ArrivalAirport.Fix {
+		route = route[:n-1]
+	}
+
+	join := func(wps []av.Waypoint, appr av.WaypointArray) ([]av.Waypoint, string) {
+		wps = append(wps, appr...)
+		return append(wps, nav.FlightState.ArrivalAirport), appr[0].Fix
+	}
+
+	for i, wp := range route {
+		for _, appr := range ap.Waypoints {
+			if idx := slices.IndexFunc(appr, func(awp av.Waypoint) bool { return awp.Fix == wp.Fix }); idx != -1 {
+				return join(route[:i], appr[idx:])
+			}
+		}
+	}
+
+	// The route doesn't meet the approach; go to the closest initial fix
+	// after the end of it.
+	from := nav.FlightState.Position
+	if len(route) > 0 {
+		from = route[len(route)-1].Location
+	}
+	var best av.WaypointArray
+	for _, appr := range ap.Waypoints {
+		if len(appr) > 0 && (best == nil ||
+			math.NMDistance2LL(from, appr[0].Location) < math.NMDistance2LL(from, best[0].Location)) {
+			best = appr
+		}
+	}
+	if best == nil {
+		return nav.Waypoints, ""
+	}
+	return join(route, best)
+}
@@ -600,6 +600,11 @@ func (f *FlightTest) makeAirport() *av.Airport {
 				a.Waypoints[i][j].SetOnApproach(true)
 			}
 		}
+		if appr.MissedApproach != nil {
+			ma := *appr.MissedApproach
+			ma.Waypoints = av.WaypointArray(util.DuplicateSlice(ma.Waypoints)).InitializeLocations(dbLocator{}, nmPerLong, magVar, true, e)
+			a.MissedApproach = &ma
+		}
 
 		// Add runway threshold waypoint to each route.
 		if rwy, ok := av.LookupRunway(icao, a.Runway); ok {
@@ -858,3 +863,144 @@ func (g ApproachGeometry) FAFOffset(distNM, lateralNM float32) math.Point2LL {
 	perpRight := math.OffsetHeading(outbound, 90)
 	return math.Offset2LL(onCourse, perpRight, lateralNM, g.NmPerLongitude)
 }
+
+func TestValidate(t *testing.T) {
+	nav := &Nav{
+		FlightState: FlightState{Position: math.Point2LL{-73.8, 40.6}, Altitude: 5000},
+		Waypoints:   []av.Waypoint{{Fix: "ROBER", Location: math.Point2LL{-73.9, 40.5}}},
+	}
+	if err := nav.Validate(); err != nil {
+		t.Errorf("unexpected error for valid nav: %v", err)
+	}
+
+	nav.Waypoints = append(nav.Waypoints, av.Waypoint{Fix: "BOGUS"})
+	nav.FlightState.Altitude = float32(gomath.NaN())
+	err := nav.Validate()
+	if err == nil {
+		t.Fatal("expected an error for invalid nav")
+	}
+	if msg := err.Error(); !strings.Contains(msg, "BOGUS") || !strings.Contains(msg, "altitude") {
+		t.Errorf("error %q doesn't report both problems", msg)
+	}
+}
+
+func TestLostComms(t *testing.T) {
+	arrival := av.Waypoint{Fix: "KXXX", Location: math.Point2LL{-73, 40}}
+	fix := func(name string, lon float32) av.Waypoint {
+		return av.Waypoint{Fix: name, Location: math.Point2LL{lon, 40.2}}
+	}
+	fixes := func(wps []av.Waypoint) string {
+		var s []string
+		for _, wp := range wps {
+			s = append(s, wp.Fix)
+		}
+		return strings.Join(s, " ")
+	}
+	makeNav := func(route ...av.Waypoint) *Nav {
+		n := &Nav{
+			FlightState: FlightState{
+				Position:       math.Point2LL{-74, 40.2},
+				Altitude:       6000,
+				ArrivalAirport: arrival,
+				NmPerLongitude: 45,
+			},
+			Waypoints: append(route, arrival),
+		}
+		n.Perf.Ceiling = 25000
+		return n
+	}
+
+	// Vectored with an assigned altitude below the minimum: it resumes its
+	// route and climbs to the minimum.
+	n := makeNav(fix("AAA", -73.8), fix("BBB", -73.5))
+	hdg := math.MagneticHeading(90)
+	n.Heading.Assigned = &hdg
+	n.setAssignedAltitude(4000)
+	n.LostComms(5000)
+	if _, ok := n.AssignedHeading(); ok {
+		t.Errorf("expected the aircraft to stop flying the assigned heading")
+	}
+	if a := n.Altitude.Assigned; a == nil || *a != 5000 {
+		t.Errorf("expected assigned altitude 5000, got %v", a)
+	}
+	if got := fixes(n.Waypoints); got != "AAA BBB KXXX" {
+		t.Errorf("expected route AAA BBB KXXX, got %s", got)
+	}
+
+	// The assigned altitude wins if it's higher; without one, the current
+	// altitude does.
+	n = makeNav(fix("AAA", -73.8))
+	n.setAssignedAltitude(9000)
+	n.LostComms(5000)
+	if a := n.Altitude.Assigned; a == nil || *a != 9000 {
+		t.Errorf("expected assigned altitude 9000, got %v", a)
+	}
+	n = makeNav(fix("AAA", -73.8))
+	n.LostComms(3000)
+	if a := n.Altitude.Assigned; a == nil || *a != 6000 {
+		t.Errorf("expected assigned altitude 6000, got %v", a)
+	}
+
+	appr := &av.Approach{
+		Type: av.RNAVApproach,
+		Waypoints: []av.WaypointArray{
+			{fix("NORTH", -73.2), fix("FINAL", -73.1), fix("RW36", -73.05)},
+			{fix("BBB", -73.5), fix("FINAL", -73.1), fix("RW36", -73.05)},
+		},
+	}
+
+	// The route meets the approach at BBB; the aircraft joins the approach
+	// there on its own, without being cleared for it.
+	n = makeNav(fix("AAA", -73.8), fix("BBB", -73.5), fix("CCC", -73.3))
+	n.Approach.Assigned = appr
+	n.LostComms(5000)
+	if got := fixes(n.Waypoints); got != "AAA BBB FINAL RW36 KXXX" {
+		t.Errorf("expected route AAA BBB FINAL RW36 KXXX, got %s", got)
+	}
+	if n.Approach.LostCommsFix != "BBB" {
+		t.Errorf("expected to join the approach at BBB, got %q", n.Approach.LostCommsFix)
+	}
+	if slices.ContainsFunc(n.Waypoints, av.Waypoint.ClearApproach) {
+		t.Errorf("lost comms route shouldn't clear the aircraft for the approach")
+	}
+	if n.Approach.Cleared || n.Approach.LostComms || n.flyingApproach() {
+		t.Errorf("flying the approach before reaching BBB")
+	}
+
+	// Once it passes BBB, it flies the approach, still without having been
+	// cleared for it.
+	n.Waypoints = n.Waypoints[1:]
+	n.FlightState.Position = n.Waypoints[0].Location
+	n.FlightState.GS = 200
+	n.Waypoints[0].Flags |= av.WaypointFlagFlyOver
+	n.updateWaypoints("", wx.Sample{}, &av.FlightPlan{}, Time{})
+	if got := fixes(n.Waypoints); got != "FINAL RW36 KXXX" {
+		t.Errorf("expected route FINAL RW36 KXXX after BBB, got %s", got)
+	}
+	if !n.Approach.LostComms || n.Approach.Cleared || n.Approach.LostCommsFix != "" {
+		t.Errorf("expected to fly the approach uncleared after BBB: %+v", n.Approach)
+	}
+	if !n.OnApproach(false) {
+		t.Errorf("expected to be on the approach after BBB")
+	}
+
+	// Otherwise it goes to the closest initial approach fix.
+	n = makeNav(fix("AAA", -73.8), fix("DDD", -73.3))
+	n.Approach.Assigned = appr
+	n.LostComms(5000)
+	if got := fixes(n.Waypoints); got != "AAA DDD NORTH FINAL RW36 KXXX" {
+		t.Errorf("expected route AAA DDD NORTH FINAL RW36 KXXX, got %s", got)
+	}
+	if n.Approach.LostCommsFix != "NORTH" {
+		t.Errorf("expected to join the approach at NORTH, got %q", n.Approach.LostCommsFix)
+	}
+
+	// An aircraft already cleared for the approach keeps flying it.
+	n = makeNav(fix("BBB", -73.5), fix("FINAL", -73.1))
+	n.Approach.Assigned = appr
+	n.Approach.Cleared = true
+	n.LostComms(5000)
+	if n.Altitude.Assigned != nil || fixes(n.Waypoints) != "BBB FINAL KXXX" {
+		t.Errorf("changed the route or altitude of an aircraft cleared for the approach")
+	}
+}
@@ -305,7 +305,7 @@ func (nav *Nav) flyManeuvers(maneuvers *[]LateralManeuver, wxs wx.Sample, simTim
 
 func (nav *Nav) flyProcedureTurnIfNecessary() {
 	wp := nav.AssignedWaypoints()
-	if !nav.Approach.Cleared || len(wp) < 2 || wp[0].ProcedureTurn() == nil || nav.Approach.NoPT {
+	if !nav.flyingApproach() || len(wp) < 2 || wp[0].ProcedureTurn() == nil || nav.Approach.NoPT {
 		return
 	}
 
@@ -558,6 +558,12 @@ func StartAirwork(wp av.Waypoint, nav Nav) *NavAirwork {
 		Altitude:       nav.FlightState.Altitude,
 	}
 
+	// Stay clear of the terrain anywhere in the airwork area.
+	if minAlt := MinimumVFRAltitude(a.Center, a.Radius, nav.FlightState.NmPerLongitude); a.AltRange[0] < minAlt {
+		a.AltRange[0] = minAlt
+		a.AltRange[1] = max(a.AltRange[1], minAlt+1000)
+	}
+
 	a.Start360(nav)
 
 	return a
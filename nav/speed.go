@@ -120,7 +120,7 @@ func (nav *Nav) TargetSpeed(targetAltitude float32, fp *av.FlightPlan, wxs wx.Sa
 		return nav.Perf.Speed.Landing + 5, MaximumRate
 	}
 	if nav.Speed.MaintainMaximumForward {
-		if nav.Approach.Cleared {
+		if nav.flyingApproach() {
 			// (We expect this to usually be the case.) Ad-hoc speed based
 			// on V2, also assuming some flaps are out, so we don't just
 			// want to return 250 knots here...
@@ -221,7 +221,7 @@ func (nav *Nav) TargetSpeed(targetAltitude float32, fp *av.FlightPlan, wxs wx.Sa
 	}
 
 	// Something from a previous waypoint; ignore it if we're cleared for the approach.
-	if nav.Speed.Restriction != nil && !nav.Approach.Cleared {
+	if nav.Speed.Restriction != nil && !nav.flyingApproach() {
 		naturalIAS, _ := nav.targetAltitudeIAS()
 		return nav.restrictedSpeed(nav.Speed.Restriction, naturalIAS), MaximumRate
 	}
@@ -276,7 +276,7 @@ func (nav *Nav) TargetSpeed(targetAltitude float32, fp *av.FlightPlan, wxs wx.Sa
 		return ias, MaximumRate
 	}
 
-	if nav.Approach.Cleared || nav.Approach.MissedApproachIntercept || nav.Approach.ApproachClearanceCancelled {
+	if nav.flyingApproach() || nav.Approach.MissedApproachIntercept || nav.Approach.ApproachClearanceCancelled {
 		// Don't speed up if we're cleared (or recently were, before an overshoot recovery or
 		// controller cancellation)
 		return nav.FlightState.IAS, MaximumRate
@@ -406,7 +406,7 @@ func (nav *Nav) getUpcomingSpeedRestrictionWaypoint() (onSID bool, sr *av.SpeedR
 // distanceToEndOfApproach returns the remaining distance to the last
 // waypoint (usually runway threshold) of the currently assigned approach.
 func (nav *Nav) DistanceToEndOfApproach() (float32, error) {
-	if nav.Approach.Assigned == nil || !nav.Approach.Cleared {
+	if nav.Approach.Assigned == nil || !nav.flyingApproach() {
 		return 0, ErrNotClearedForApproach
 	}
 
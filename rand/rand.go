@@ -31,9 +31,15 @@ type Rand struct {
 }
 
 func Make() *Rand {
+	return MakeSeeded(uint64(time.Now().UnixNano()))
+}
+
+// MakeSeeded returns a Rand with the given seed; the sequence of values it
+// generates is fully determined by the seed.
+func MakeSeeded(seed uint64) *Rand {
 	r := &Rand{pcg: mrand.NewPCG(0, 0)}
 	r.r = mrand.New(r.pcg)
-	r.Seed(uint64(time.Now().UnixNano()))
+	r.Seed(seed)
 	return r
 }
 
@@ -70,6 +76,10 @@ func (r *Rand) DurationRange(low, high time.Duration) time.Duration {
 	return low + time.Duration(r.r.Int64N(int64(high-low)))
 }
 
+func (r *Rand) Uint64() uint64 {
+	return r.r.Uint64()
+}
+
 func (r *Rand) Uint32() uint32 {
 	return r.r.Uint32()
 }
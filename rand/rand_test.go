@@ -129,6 +129,20 @@ func advance(r *Rand, n int) []uint32 {
 	return out
 }
 
+func TestMakeSeeded(t *testing.T) {
+	a, b := MakeSeeded(6502), MakeSeeded(6502)
+	want, got := advance(a, 16), advance(b, 16)
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("same-seed output diverged at %d: got %#x want %#x", i, got[i], want[i])
+		}
+	}
+
+	if c := MakeSeeded(6809); advance(c, 1)[0] == want[0] {
+		t.Errorf("different seeds unexpectedly produced the same first value")
+	}
+}
+
 func TestRandJSONRoundtrip(t *testing.T) {
 	r := Make()
 	r.Seed(6502)
@@ -0,0 +1,223 @@
+// server/api.go
+// Copyright(c) 2022-2026 vice contributors, licensed under the GNU Public License, Version 3.
+// SPDX: GPL-3.0-only
+
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/rpc"
+	"net/rpc/jsonrpc"
+	"slices"
+	"strings"
+
+	"golang.org/x/net/websocket"
+)
+
+///////////////////////////////////////////////////////////////////////////
+// Public JSON API
+
+// The JSON API exposes the same RPCs as the msgpack RPC server (e.g.,
+// "SimManager.ConnectToSim", "Sim.RunAircraftCommands", "Sim.HandoffTrack",
+// "Sim.TakeOrReturnLaunchControl") for web dashboards and external tools
+// that don't link the Go client. It is served by the HTTP server:
+//
+//	POST /api/v1/rpc/{method}: the request body is the JSON-encoded RPC
+//	    argument and the response body is the JSON-encoded result, or
+//	    {"error": "..."} with a 4xx status if the call failed.
+//	GET /api/v1/ws: a WebSocket carrying JSON-RPC 1.0 requests and
+//	    responses, for clients that make many calls.
+//	GET /api/v1/stream?token={controller token}: a WebSocket on which each
+//	    state update (a SimStateUpdate) is sent as it is published. As with
+//	    Sim.GetStateUpdate, a controller token's updates and events are
+//	    delivered to just one consumer.
+//
+// Web pages may only use the API from other origins if they are listed in
+// ServerLaunchConfig.APIOrigins; requests from other browser origins are
+// refused, while non-browser clients, which don't send an Origin header,
+// are unaffected.
+const APIPathPrefix = "/api/v1/"
+
+// Requests larger than this are rejected.
+const maxAPIRequestBytes = 1024 * 1024
+
+type apiError struct {
+	Error string `json:"error"`
+}
+
+func (sm *SimManager) registerAPIHandlers(mux *http.ServeMux) {
+	server := rpc.NewServer()
+	if err := server.Register(sm); err != nil {
+		sm.lg.Errorf("unable to register SimManager for API: %v", err)
+		return
+	}
+	if err := server.RegisterName("Sim", &dispatcher{sm: sm}); err != nil {
+		sm.lg.Errorf("unable to register dispatcher for API: %v", err)
+		return
+	}
+
+	mux.HandleFunc("POST "+APIPathPrefix+"rpc/{method}", func(w http.ResponseWriter, r *http.Request) {
+		sm.serveAPICall(server, w, r)
+	})
+	mux.HandleFunc("OPTIONS "+APIPathPrefix+"rpc/{method}", sm.serveAPIPreflight)
+	mux.Handle("GET "+APIPathPrefix+"ws", websocket.Server{
+		Handshake: sm.checkAPIOrigin,
+		Handler: func(ws *websocket.Conn) {
+			sm.lg.Infof("%s: new API connection", ws.Request().RemoteAddr)
+			server.ServeCodec(sm.metrics.instrumentCodec(jsonrpc.NewServerCodec(ws)))
+		},
+	})
+	mux.Handle("GET "+APIPathPrefix+"stream", websocket.Server{
+		Handshake: sm.checkAPIOrigin,
+		Handler:   sm.serveStateStream,
+	})
+}
+
+// apiOriginAllowed returns whether a web page from the given origin may
+// make cross-origin API requests.
+func (sm *SimManager) apiOriginAllowed(origin string) bool {
+	return slices.Contains(sm.apiOrigins, origin)
+}
+
+// setAPICORSHeaders allows the request's origin to read the response if
+// it is one of the allowed origins.
+func (sm *SimManager) setAPICORSHeaders(w http.ResponseWriter, r *http.Request) {
+	w.Header().Add("Vary", "Origin")
+	if origin := r.Header.Get("Origin"); origin != "" && sm.apiOriginAllowed(origin) {
+		w.Header().Set("Access-Control-Allow-Origin", origin)
+	}
+}
+
+// serveAPIPreflight responds to CORS preflight requests for API calls.
+func (sm *SimManager) serveAPIPreflight(w http.ResponseWriter, r *http.Request) {
+	sm.setAPICORSHeaders(w, r)
+	if w.Header().Get("Access-Control-Allow-Origin") != "" {
+		w.Header().Set("Access-Control-Allow-Methods", "POST")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// checkAPIOrigin is the WebSocket handshake function for the API. Browsers
+// always send an Origin header with WebSocket requests but don't apply
+// the same-origin policy to them, so it's checked here to keep arbitrary
+// web pages from using a visitor's connection to the server.
+func (sm *SimManager) checkAPIOrigin(config *websocket.Config, r *http.Request) error {
+	origin, err := websocket.Origin(config, r)
+	if err != nil {
+		return err
+	}
+	config.Origin = origin
+	if origin == nil || origin.Host == r.Host || sm.apiOriginAllowed(origin.Scheme+"://"+origin.Host) {
+		return nil
+	}
+	sm.lg.Warnf("%s: rejected API WebSocket from origin %q", r.RemoteAddr, origin)
+	return ErrAPIOriginNotAllowed
+}
+
+// apiCallCodec is an rpc.ServerCodec for a single RPC made via an HTTP
+// request.
+type apiCallCodec struct {
+	method string
+	body   io.Reader
+	result any
+	err    string
+}
+
+func (c *apiCallCodec) ReadRequestHeader(r *rpc.Request) error {
+	r.ServiceMethod = c.method
+	return nil
+}
+
+func (c *apiCallCodec) ReadRequestBody(arg any) error {
+	if arg == nil {
+		return nil
+	}
+	if err := json.NewDecoder(c.body).Decode(arg); err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+	// An empty body leaves the argument at its zero value.
+	return nil
+}
+
+func (c *apiCallCodec) WriteResponse(r *rpc.Response, result any) error {
+	c.result, c.err = result, r.Error
+	return nil
+}
+
+func (c *apiCallCodec) Close() error {
+	return nil
+}
+
+func (sm *SimManager) serveAPICall(server *rpc.Server, w http.ResponseWriter, r *http.Request) {
+	sm.setAPICORSHeaders(w, r)
+	w.Header().Set("Content-Type", "application/json")
+
+	codec := &apiCallCodec{
+		method: r.PathValue("method"),
+		body:   http.MaxBytesReader(w, r.Body, maxAPIRequestBytes),
+	}
+	// ServeRequest calls the method synchronously, so the result is
+	// available when it returns.
+	_ = server.ServeRequest(sm.metrics.instrumentCodec(codec))
+
+	if codec.err != "" {
+		status := http.StatusBadRequest
+		if strings.HasPrefix(codec.err, "rpc: can't find") {
+			status = http.StatusNotFound
+		}
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(apiError{Error: codec.err})
+		return
+	}
+
+	b, err := json.Marshal(codec.result)
+	if err != nil {
+		sm.lg.Errorf("%s: unable to encode API result: %v", codec.method, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		_ = json.NewEncoder(w).Encode(apiError{Error: err.Error()})
+		return
+	}
+	_, _ = w.Write(b)
+}
+
+// serveStateStream sends the controller's state updates over the
+// WebSocket as they are published, until the client disconnects or the
+// controller signs off.
+func (sm *SimManager) serveStateStream(ws *websocket.Conn) {
+	defer ws.Close()
+
+	token := ws.Request().URL.Query().Get("token")
+	sm.lg.Infof("%s: new API state stream", ws.Request().RemoteAddr)
+
+	// Clients don't send anything on the stream; reading lets us notice
+	// when they go away.
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _ = io.Copy(io.Discard, ws)
+	}()
+
+	for {
+		update, err := sm.GetStateUpdate(token, 0 /* always send everything */)
+		if err == nil && update == nil {
+			err = ErrNoSimForControllerToken
+		}
+		if err != nil {
+			_ = websocket.JSON.Send(ws, apiError{Error: err.Error()})
+			return
+		}
+
+		select {
+		case <-done:
+			return
+		default:
+			if err := websocket.JSON.Send(ws, update); err != nil {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,172 @@
+// server/api_test.go
+// Copyright(c) 2022-2026 vice contributors, licensed under the GNU Public License, Version 3.
+// SPDX: GPL-3.0-only
+
+package server
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/rpc/jsonrpc"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/websocket"
+)
+
+func makeTestAPIServer(t *testing.T, origins ...string) *httptest.Server {
+	t.Helper()
+
+	sm := makeTestSimManager(t)
+	sm.apiOrigins = origins
+	mux := http.NewServeMux()
+	sm.registerAPIHandlers(mux)
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func postAPICall(t *testing.T, srv *httptest.Server, method, body, origin string) (*http.Response, string) {
+	t.Helper()
+
+	req, err := http.NewRequest(http.MethodPost, srv.URL+APIPathPrefix+"rpc/"+method, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if origin != "" {
+		req.Header.Set("Origin", origin)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	var sb strings.Builder
+	if _, err := io.Copy(&sb, resp.Body); err != nil {
+		t.Fatal(err)
+	}
+	return resp, sb.String()
+}
+
+func TestServeAPICall(t *testing.T) {
+	srv := makeTestAPIServer(t)
+
+	resp, body := postAPICall(t, srv, GetRunningSimsRPC, "0", "")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("%s: status %d: %s", GetRunningSimsRPC, resp.StatusCode, body)
+	}
+	var running map[string]*RunningSim
+	if err := json.Unmarshal([]byte(body), &running); err != nil {
+		t.Fatalf("%s: %v", body, err)
+	}
+	if len(running) != 0 {
+		t.Errorf("expected no running sims, got %d", len(running))
+	}
+
+	// Errors returned by the RPC are reported with a 400.
+	resp, body = postAPICall(t, srv, "SimManager.Connect", "1", "")
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("Connect with old version: status %d; expected %d", resp.StatusCode, http.StatusBadRequest)
+	}
+	var apiErr apiError
+	if err := json.Unmarshal([]byte(body), &apiErr); err != nil {
+		t.Fatalf("%s: %v", body, err)
+	}
+	if apiErr.Error != ErrRPCVersionMismatch.Error() {
+		t.Errorf("got error %q; expected %q", apiErr.Error, ErrRPCVersionMismatch)
+	}
+
+	// Unknown methods are a 404.
+	if resp, _ := postAPICall(t, srv, "SimManager.NoSuchMethod", "", ""); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("unknown method: status %d; expected %d", resp.StatusCode, http.StatusNotFound)
+	}
+
+	// Malformed arguments are rejected.
+	if resp, _ := postAPICall(t, srv, GetRunningSimsRPC, "{", ""); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("malformed request: status %d; expected %d", resp.StatusCode, http.StatusBadRequest)
+	}
+}
+
+func TestAPICORS(t *testing.T) {
+	const allowed = "https://dashboard.example.com"
+	srv := makeTestAPIServer(t, allowed)
+
+	// No wildcard: other origins aren't allowed to read responses.
+	for _, origin := range []string{"", "https://evil.example.com"} {
+		resp, _ := postAPICall(t, srv, GetRunningSimsRPC, "0", origin)
+		if acao := resp.Header.Get("Access-Control-Allow-Origin"); acao != "" {
+			t.Errorf("origin %q: unexpected Access-Control-Allow-Origin %q", origin, acao)
+		}
+	}
+
+	resp, _ := postAPICall(t, srv, GetRunningSimsRPC, "0", allowed)
+	if acao := resp.Header.Get("Access-Control-Allow-Origin"); acao != allowed {
+		t.Errorf("Access-Control-Allow-Origin %q; expected %q", acao, allowed)
+	}
+
+	// Preflight
+	req, err := http.NewRequest(http.MethodOptions, srv.URL+APIPathPrefix+"rpc/"+GetRunningSimsRPC, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Origin", allowed)
+	req.Header.Set("Access-Control-Request-Method", "POST")
+	presp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	presp.Body.Close()
+	if presp.Header.Get("Access-Control-Allow-Origin") != allowed ||
+		presp.Header.Get("Access-Control-Allow-Methods") != "POST" {
+		t.Errorf("unexpected preflight response headers %v", presp.Header)
+	}
+}
+
+func TestAPIWebSocket(t *testing.T) {
+	const allowed = "https://dashboard.example.com"
+	srv := makeTestAPIServer(t, allowed)
+	wsURL := "ws" + strings.TrimPrefix(srv.URL, "http") + APIPathPrefix
+
+	for _, origin := range []string{srv.URL, allowed} {
+		ws, err := websocket.Dial(wsURL+"ws", "", origin)
+		if err != nil {
+			t.Fatalf("origin %q: %v", origin, err)
+		}
+		client := jsonrpc.NewClient(ws)
+		var running map[string]*RunningSim
+		if err := client.Call(GetRunningSimsRPC, 0, &running); err != nil {
+			t.Errorf("origin %q: %v", origin, err)
+		} else if len(running) != 0 {
+			t.Errorf("origin %q: expected no running sims, got %d", origin, len(running))
+		}
+		if err := client.Call("SimManager.Connect", 1, &ConnectResult{}); err == nil ||
+			err.Error() != ErrRPCVersionMismatch.Error() {
+			t.Errorf("origin %q: got error %v; expected %v", origin, err, ErrRPCVersionMismatch)
+		}
+		client.Close()
+	}
+
+	// Pages from other origins can't open the WebSocket.
+	if ws, err := websocket.Dial(wsURL+"ws", "", "https://evil.example.com"); err == nil {
+		ws.Close()
+		t.Errorf("expected WebSocket from another origin to be rejected")
+	}
+
+	// The state stream reports an error and closes for an invalid token.
+	ws, err := websocket.Dial(wsURL+"stream?token=bogus", "", srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ws.Close()
+	var apiErr apiError
+	if err := websocket.JSON.Receive(ws, &apiErr); err != nil {
+		t.Fatal(err)
+	}
+	if apiErr.Error != ErrNoSimForControllerToken.Error() {
+		t.Errorf("stream error %q; expected %q", apiErr.Error, ErrNoSimForControllerToken)
+	}
+}
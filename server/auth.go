@@ -0,0 +1,252 @@
+// server/auth.go
+// Copyright(c) 2022-2026 vice contributors, licensed under the GNU Public License, Version 3.
+// SPDX: GPL-3.0-only
+
+package server
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+///////////////////////////////////////////////////////////////////////////
+// Roles
+
+// Role determines what a connected user is allowed to do. Each role can do
+// everything that the roles before it can.
+type Role int
+
+const (
+	// RoleObserver can watch a sim but not control any aircraft.
+	RoleObserver Role = iota
+	// RoleController can sign in to a TCW and control traffic.
+	RoleController
+	// RoleInstructor can additionally sign in to privileged TCWs and use
+	// the instructor console.
+	RoleInstructor
+	// RoleAdmin can additionally perform server-wide actions like
+	// broadcasting messages to all sims.
+	RoleAdmin
+)
+
+func (r Role) String() string {
+	switch r {
+	case RoleObserver:
+		return "observer"
+	case RoleController:
+		return "controller"
+	case RoleInstructor:
+		return "instructor"
+	case RoleAdmin:
+		return "admin"
+	default:
+		return fmt.Sprintf("Role(%d)", int(r))
+	}
+}
+
+func ParseRole(s string) (Role, error) {
+	for r := RoleObserver; r <= RoleAdmin; r++ {
+		if strings.EqualFold(s, r.String()) {
+			return r, nil
+		}
+	}
+	return RoleObserver, fmt.Errorf("%q: unknown role", s)
+}
+
+func (r *Role) UnmarshalText(text []byte) error {
+	var err error
+	*r, err = ParseRole(string(text))
+	return err
+}
+
+func (r Role) MarshalText() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+// requestedRole returns the role that a user creating or joining a sim
+// will have at their TCW.
+func requestedRole(privileged, observing bool) Role {
+	if observing {
+		return RoleObserver
+	} else if privileged {
+		return RoleInstructor
+	}
+	return RoleController
+}
+
+///////////////////////////////////////////////////////////////////////////
+// Authentication
+
+// Credentials are provided by users when they create or join a sim on a
+// server that requires authentication. Either a username and password or
+// an API token may be given.
+type Credentials struct {
+	Username string
+	Password string
+	Token    string
+}
+
+// Account describes an authenticated user.
+type Account struct {
+	Username string
+	Role     Role
+}
+
+// Authenticator is implemented by the SimManager's source of user
+// accounts.
+type Authenticator interface {
+	// Authenticate returns the account corresponding to the given
+	// credentials, or ErrAuthenticationFailed if they aren't valid.
+	Authenticate(c Credentials) (Account, error)
+}
+
+// UserFile is an Authenticator for a JSON file of user accounts of the
+// form:
+//
+//	{
+//	    "users": {
+//	        "alice": {
+//	            "password_hash": "$2a$10$...",
+//	            "role": "instructor",
+//	            "token_hashes": [ "9f86d081884c7d65..." ]
+//	        }
+//	    }
+//	}
+//
+// Passwords are stored as bcrypt hashes (see HashPassword) and API tokens
+// as hex-encoded SHA-256 hashes (see HashToken). Users without a "role"
+// are observers.
+type UserFile struct {
+	Users map[string]*UserFileEntry `json:"users"`
+}
+
+type UserFileEntry struct {
+	PasswordHash string   `json:"password_hash"`
+	Role         Role     `json:"role"`
+	TokenHashes  []string `json:"token_hashes"`
+}
+
+func LoadUserFile(fn string) (*UserFile, error) {
+	b, err := os.ReadFile(fn)
+	if err != nil {
+		return nil, err
+	}
+
+	var uf UserFile
+	if err := json.Unmarshal(b, &uf); err != nil {
+		return nil, fmt.Errorf("%s: %w", fn, err)
+	}
+	if len(uf.Users) == 0 {
+		return nil, fmt.Errorf("%s: no users specified", fn)
+	}
+	for name, u := range uf.Users {
+		if name == "" {
+			return nil, fmt.Errorf("%s: empty username", fn)
+		}
+		if u == nil || (u.PasswordHash == "" && len(u.TokenHashes) == 0) {
+			return nil, fmt.Errorf("%s: %s: must specify \"password_hash\" and/or \"token_hashes\"", fn, name)
+		}
+		if u.PasswordHash != "" {
+			if _, err := bcrypt.Cost([]byte(u.PasswordHash)); err != nil {
+				return nil, fmt.Errorf("%s: %s: invalid \"password_hash\": %w", fn, name, err)
+			}
+		}
+		for _, h := range u.TokenHashes {
+			if b, err := hex.DecodeString(h); err != nil || len(b) != sha256.Size {
+				return nil, fmt.Errorf("%s: %s: %q: invalid token hash", fn, name, h)
+			}
+		}
+	}
+
+	return &uf, nil
+}
+
+func (uf *UserFile) Authenticate(c Credentials) (Account, error) {
+	if c.Token != "" {
+		th := sha256.Sum256([]byte(c.Token))
+		for name, u := range uf.Users {
+			for _, h := range u.TokenHashes {
+				if hb, err := hex.DecodeString(h); err == nil && subtle.ConstantTimeCompare(hb, th[:]) == 1 {
+					return Account{Username: name, Role: u.Role}, nil
+				}
+			}
+		}
+		return Account{}, ErrAuthenticationFailed
+	}
+
+	u, ok := uf.Users[c.Username]
+	if !ok || u.PasswordHash == "" {
+		return Account{}, ErrAuthenticationFailed
+	}
+	if bcrypt.CompareHashAndPassword([]byte(u.PasswordHash), []byte(c.Password)) != nil {
+		return Account{}, ErrAuthenticationFailed
+	}
+	return Account{Username: c.Username, Role: u.Role}, nil
+}
+
+// HashPassword returns the hash of a password in the form stored in a
+// UserFile.
+func HashPassword(pw string) (string, error) {
+	h, err := bcrypt.GenerateFromPassword([]byte(pw), bcrypt.DefaultCost)
+	return string(h), err
+}
+
+// HashToken returns the hash of an API token in the form stored in a
+// UserFile.
+func HashToken(token string) string {
+	h := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(h[:])
+}
+
+///////////////////////////////////////////////////////////////////////////
+// SimManager
+
+// authenticate checks that a user creating or joining a sim may do so with
+// the given role. If the server doesn't require authentication, anyone may
+// have any role; otherwise the credentials must be valid and the account's
+// role must be at least the given one.
+func (sm *SimManager) authenticate(c Credentials, role Role) error {
+	_, err := sm.authenticateAccount(c, role)
+	return err
+}
+
+// authenticateAccount is like authenticate but also returns the
+// authenticated account, which is empty if the server doesn't require
+// authentication.
+func (sm *SimManager) authenticateAccount(c Credentials, role Role) (Account, error) {
+	if sm.auth == nil {
+		return Account{}, nil
+	}
+
+	acct, err := sm.auth.Authenticate(c)
+	if err != nil {
+		sm.lg.Warnf("authentication failed for user %q", c.Username)
+		return Account{}, err
+	}
+	if acct.Role < role {
+		sm.lg.Warnf("user %q with role %s requested role %s", acct.Username, acct.Role, role)
+		return Account{}, ErrInsufficientRole
+	}
+	sm.lg.Infof("authenticated user %q with role %s", acct.Username, acct.Role)
+	return acct, nil
+}
+
+// lookupController returns the controller for the given token, or an error
+// if there is none or if the controller's role is less than the given one.
+func (sd *dispatcher) lookupController(token string, role Role) (*controllerContext, error) {
+	c := sd.sm.LookupController(token)
+	if c == nil {
+		return nil, ErrNoSimForControllerToken
+	}
+	if c.role < role {
+		return nil, ErrInsufficientRole
+	}
+	return c, nil
+}
@@ -0,0 +1,146 @@
+// server/auth_test.go
+// Copyright(c) 2022-2026 vice contributors, licensed under the GNU Public License, Version 3.
+// SPDX: GPL-3.0-only
+
+package server
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeTestUserFile(t *testing.T, contents string) string {
+	t.Helper()
+
+	fn := filepath.Join(t.TempDir(), "users.json")
+	if err := os.WriteFile(fn, []byte(contents), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return fn
+}
+
+func TestLoadUserFile(t *testing.T) {
+	pwHash, err := HashPassword("hunter2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tokenHash := HashToken("s3cr3t")
+
+	fn := writeTestUserFile(t, `{ "users": {
+    "alice": { "password_hash": "`+pwHash+`", "role": "instructor" },
+    "bob": { "token_hashes": [ "`+tokenHash+`" ], "role": "controller" },
+    "carol": { "password_hash": "`+pwHash+`" }
+} }`)
+	uf, err := LoadUserFile(fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, role := range map[string]Role{"alice": RoleInstructor, "bob": RoleController, "carol": RoleObserver} {
+		if u, ok := uf.Users[name]; !ok {
+			t.Errorf("%s: missing user", name)
+		} else if u.Role != role {
+			t.Errorf("%s: role %s; expected %s", name, u.Role, role)
+		}
+	}
+
+	for _, tc := range []struct {
+		name, contents, errSubstr string
+	}{
+		{"invalid JSON", `{ "users": `, "unexpected end"},
+		{"no users", `{ "users": {} }`, "no users"},
+		{"empty username", `{ "users": { "": { "password_hash": "` + pwHash + `" } } }`, "empty username"},
+		{"no credentials", `{ "users": { "dave": { "role": "controller" } } }`, "must specify"},
+		{"bad password hash", `{ "users": { "dave": { "password_hash": "hunter2" } } }`, "invalid \"password_hash\""},
+		{"bad token hash", `{ "users": { "dave": { "token_hashes": [ "abcd" ] } } }`, "invalid token hash"},
+		{"unknown role", `{ "users": { "dave": { "password_hash": "` + pwHash + `", "role": "pilot" } } }`, "unknown role"},
+	} {
+		if _, err := LoadUserFile(writeTestUserFile(t, tc.contents)); err == nil {
+			t.Errorf("%s: expected error", tc.name)
+		} else if !strings.Contains(err.Error(), tc.errSubstr) {
+			t.Errorf("%s: error %q doesn't contain %q", tc.name, err, tc.errSubstr)
+		}
+	}
+
+	if _, err := LoadUserFile(filepath.Join(t.TempDir(), "missing.json")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("missing file: got error %v", err)
+	}
+}
+
+func TestUserFileAuthenticate(t *testing.T) {
+	pwHash, err := HashPassword("hunter2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	uf := &UserFile{Users: map[string]*UserFileEntry{
+		"alice": {PasswordHash: pwHash, Role: RoleInstructor},
+		"bob":   {TokenHashes: []string{HashToken("s3cr3t")}, Role: RoleController},
+	}}
+
+	for _, tc := range []struct {
+		name  string
+		creds Credentials
+		user  string
+		role  Role
+	}{
+		{"password", Credentials{Username: "alice", Password: "hunter2"}, "alice", RoleInstructor},
+		{"token", Credentials{Token: "s3cr3t"}, "bob", RoleController},
+		// A token identifies the user by itself.
+		{"token with username", Credentials{Username: "alice", Token: "s3cr3t"}, "bob", RoleController},
+	} {
+		acct, err := uf.Authenticate(tc.creds)
+		if err != nil {
+			t.Errorf("%s: %v", tc.name, err)
+		} else if acct.Username != tc.user || acct.Role != tc.role {
+			t.Errorf("%s: got %+v; expected %s with role %s", tc.name, acct, tc.user, tc.role)
+		}
+	}
+
+	for _, tc := range []struct {
+		name  string
+		creds Credentials
+	}{
+		{"no credentials", Credentials{}},
+		{"wrong password", Credentials{Username: "alice", Password: "hunter3"}},
+		{"unknown user", Credentials{Username: "mallory", Password: "hunter2"}},
+		{"user without password", Credentials{Username: "bob", Password: ""}},
+		{"wrong token", Credentials{Token: "guess"}},
+		{"token as password", Credentials{Username: "bob", Password: "s3cr3t"}},
+	} {
+		if acct, err := uf.Authenticate(tc.creds); !errors.Is(err, ErrAuthenticationFailed) {
+			t.Errorf("%s: got %+v, %v; expected ErrAuthenticationFailed", tc.name, acct, err)
+		}
+	}
+}
+
+func TestAuthenticateRole(t *testing.T) {
+	pwHash, err := HashPassword("hunter2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sm := makeTestSimManager(t)
+
+	// Without a user file, anyone can do anything.
+	if err := sm.authenticate(Credentials{}, RoleInstructor); err != nil {
+		t.Errorf("no authentication: %v", err)
+	}
+
+	sm.auth = &UserFile{Users: map[string]*UserFileEntry{
+		"alice": {PasswordHash: pwHash, Role: RoleController},
+	}}
+	alice := Credentials{Username: "alice", Password: "hunter2"}
+	if err := sm.authenticate(alice, RoleController); err != nil {
+		t.Errorf("controller: %v", err)
+	}
+	if err := sm.authenticate(alice, RoleObserver); err != nil {
+		t.Errorf("observer: %v", err)
+	}
+	if err := sm.authenticate(alice, RoleInstructor); !errors.Is(err, ErrInsufficientRole) {
+		t.Errorf("instructor: got %v; expected ErrInsufficientRole", err)
+	}
+	if err := sm.authenticate(Credentials{Username: "alice"}, RoleObserver); !errors.Is(err, ErrAuthenticationFailed) {
+		t.Errorf("no password: got %v; expected ErrAuthenticationFailed", err)
+	}
+}
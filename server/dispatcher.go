@@ -7,6 +7,7 @@ package server
 import (
 	"fmt"
 	"log/slog"
+	"slices"
 	"time"
 
 	av "github.com/mmp/vice/aviation"
@@ -22,7 +23,15 @@ type dispatcher struct {
 
 const GetStateUpdateRPC = "Sim.GetStateUpdate"
 
-func (sd *dispatcher) GetStateUpdate(token string, update *SimStateUpdate) error {
+type GetStateUpdateArgs struct {
+	ControllerToken string
+	// Generation is the GenerationIndex of the client's state. If it is
+	// the state from the previous update returned, the tracks and flight
+	// plans are sent as a delta relative to it; zero requests everything.
+	Generation int
+}
+
+func (sd *dispatcher) GetStateUpdate(args *GetStateUpdateArgs, update *SimStateUpdate) error {
 	// Most of the methods in this file are called from the RPC dispatcher,
 	// which spawns up goroutines as needed to handle requests, so if we
 	// want to catch and report panics, all of the methods need to start
@@ -30,7 +39,7 @@ func (sd *dispatcher) GetStateUpdate(token string, update *SimStateUpdate) error
 	defer sd.sm.lg.CatchAndReportCrash()
 
 	// GetStateUpdate may return nil if user signs off concurrently.
-	if u, err := sd.sm.GetStateUpdate(token); err != nil {
+	if u, err := sd.sm.GetStateUpdate(args.ControllerToken, args.Generation); err != nil {
 		return err
 	} else if u == nil {
 		return ErrNoSimForControllerToken
@@ -53,9 +62,9 @@ const TakeOrReturnLaunchControlRPC = "Sim.TakeOrReturnLaunchControl"
 func (sd *dispatcher) TakeOrReturnLaunchControl(token string, _ *struct{}) error {
 	defer sd.sm.lg.CatchAndReportCrash()
 
-	c := sd.sm.LookupController(token)
-	if c == nil {
-		return ErrNoSimForControllerToken
+	c, err := sd.lookupController(token, RoleController)
+	if err != nil {
+		return err
 	}
 	return c.sim.TakeOrReturnLaunchControl(c.tcw)
 }
@@ -70,9 +79,9 @@ const SetSimRateRPC = "Sim.SetSimRate"
 func (sd *dispatcher) SetSimRate(r *SetSimRateArgs, _ *struct{}) error {
 	defer sd.sm.lg.CatchAndReportCrash()
 
-	c := sd.sm.LookupController(r.ControllerToken)
-	if c == nil {
-		return ErrNoSimForControllerToken
+	c, err := sd.lookupController(r.ControllerToken, RoleController)
+	if err != nil {
+		return err
 	}
 	return c.sim.SetSimRate(c.tcw, r.Rate)
 }
@@ -87,9 +96,9 @@ const SetLaunchConfigRPC = "Sim.SetLaunchConfig"
 func (sd *dispatcher) SetLaunchConfig(lc *SetLaunchConfigArgs, _ *struct{}) error {
 	defer sd.sm.lg.CatchAndReportCrash()
 
-	c := sd.sm.LookupController(lc.ControllerToken)
-	if c == nil {
-		return ErrNoSimForControllerToken
+	c, err := sd.lookupController(lc.ControllerToken, RoleController)
+	if err != nil {
+		return err
 	}
 	return c.sim.SetLaunchConfig(c.tcw, lc.Config)
 }
@@ -99,9 +108,9 @@ const TogglePauseRPC = "Sim.TogglePause"
 func (sd *dispatcher) TogglePause(token string, _ *struct{}) error {
 	defer sd.sm.lg.CatchAndReportCrash()
 
-	c := sd.sm.LookupController(token)
-	if c == nil {
-		return ErrNoSimForControllerToken
+	c, err := sd.lookupController(token, RoleController)
+	if err != nil {
+		return err
 	}
 
 	c.sim.TogglePause()
@@ -115,9 +124,9 @@ const RequestFlightFollowingRPC = "Sim.RequestFlightFollowing"
 func (sd *dispatcher) RequestFlightFollowing(token string, _ *struct{}) error {
 	defer sd.sm.lg.CatchAndReportCrash()
 
-	c := sd.sm.LookupController(token)
-	if c == nil {
-		return ErrNoSimForControllerToken
+	c, err := sd.lookupController(token, RoleController)
+	if err != nil {
+		return err
 	}
 	return c.sim.RequestFlightFollowing()
 }
@@ -132,9 +141,9 @@ const AddMETARAirportRPC = "Sim.AddMETARAirport"
 func (sd *dispatcher) AddMETARAirport(args *AddMETARAirportArgs, _ *struct{}) error {
 	defer sd.sm.lg.CatchAndReportCrash()
 
-	c := sd.sm.LookupController(args.ControllerToken)
-	if c == nil {
-		return ErrNoSimForControllerToken
+	c, err := sd.lookupController(args.ControllerToken, RoleController)
+	if err != nil {
+		return err
 	}
 	return c.sim.AddMETARAirport(args.Airport)
 }
@@ -149,9 +158,9 @@ const TriggerEmergencyRPC = "Sim.TriggerEmergency"
 func (sd *dispatcher) TriggerEmergency(args *TriggerEmergencyArgs, _ *struct{}) error {
 	defer sd.sm.lg.CatchAndReportCrash()
 
-	c := sd.sm.LookupController(args.ControllerToken)
-	if c == nil {
-		return ErrNoSimForControllerToken
+	c, err := sd.lookupController(args.ControllerToken, RoleController)
+	if err != nil {
+		return err
 	}
 	c.sim.TriggerEmergency(args.EmergencyName)
 	return nil
@@ -162,9 +171,9 @@ const FastForwardRPC = "Sim.FastForward"
 func (sd *dispatcher) FastForward(token string, update *SimStateUpdate) error {
 	defer sd.sm.lg.CatchAndReportCrash()
 
-	c := sd.sm.LookupController(token)
-	if c == nil {
-		return ErrNoSimForControllerToken
+	c, err := sd.lookupController(token, RoleController)
+	if err != nil {
+		return err
 	}
 	c.sim.FastForward()
 	c.sim.GlobalMessage(c.tcw, fmt.Sprintf("%s (%s) has fast-forwarded the sim", c.tcw, c.initials))
@@ -172,6 +181,190 @@ func (sd *dispatcher) FastForward(token string, update *SimStateUpdate) error {
 	return nil
 }
 
+type RewindArgs struct {
+	ControllerToken string
+	Duration        time.Duration
+}
+
+const RewindRPC = "Sim.Rewind"
+
+// Rewind rolls the sim back to the latest checkpoint that is at least the
+// given duration in the past.
+func (sd *dispatcher) Rewind(args *RewindArgs, update *SimStateUpdate) error {
+	defer sd.sm.lg.CatchAndReportCrash()
+
+	c, err := sd.lookupController(args.ControllerToken, RoleInstructor)
+	if err != nil {
+		return err
+	}
+	t, err := c.sim.Rewind(c.tcw, args.Duration)
+	if err != nil {
+		return err
+	}
+
+	// The sim may have been rewound to before a configuration switch.
+	if sc := c.sim.Scenario(); sc != "" {
+		c.session.mu.Lock(c.session.lg)
+		c.session.scenario = sc
+		c.session.mu.Unlock(c.session.lg)
+	}
+
+	c.sim.GlobalMessage(c.tcw, fmt.Sprintf("%s (%s) has rewound the sim to %s", c.tcw, c.initials,
+		t.Time().Format("15:04:05")))
+	*update = c.GetStateUpdate()
+	return nil
+}
+
+type SpawnInstructorAircraftArgs struct {
+	ControllerToken string
+	Spawn           sim.InstructorSpawn
+}
+
+const SpawnInstructorAircraftRPC = "Sim.SpawnInstructorAircraft"
+
+// SpawnInstructorAircraft creates an aircraft at an arbitrary position
+// for an instructor.
+func (sd *dispatcher) SpawnInstructorAircraft(args *SpawnInstructorAircraftArgs, update *SimStateUpdate) error {
+	defer sd.sm.lg.CatchAndReportCrash()
+
+	c, err := sd.lookupController(args.ControllerToken, RoleInstructor)
+	if err != nil {
+		return err
+	}
+	if err := c.sim.SpawnInstructorAircraft(c.tcw, args.Spawn); err != nil {
+		return err
+	}
+	*update = c.GetStateUpdate()
+	return nil
+}
+
+type ForcePilotErrorArgs struct {
+	ControllerToken string
+	Callsign        av.ADSBCallsign
+	PilotError      sim.PilotError
+}
+
+const ForcePilotErrorRPC = "Sim.ForcePilotError"
+
+// ForcePilotError makes an aircraft's pilot err or lose radio contact.
+// Unlike most instructor actions it isn't announced, since the point is
+// for the controllers to notice it themselves.
+func (sd *dispatcher) ForcePilotError(args *ForcePilotErrorArgs, update *SimStateUpdate) error {
+	defer sd.sm.lg.CatchAndReportCrash()
+
+	c, err := sd.lookupController(args.ControllerToken, RoleInstructor)
+	if err != nil {
+		return err
+	}
+	if err := c.sim.ForcePilotError(c.tcw, args.Callsign, args.PilotError); err != nil {
+		return err
+	}
+	*update = c.GetStateUpdate()
+	return nil
+}
+
+type AdjustPerformanceArgs struct {
+	ControllerToken string
+	Callsign        av.ADSBCallsign
+	Adjustment      sim.PerformanceAdjustment
+}
+
+const AdjustPerformanceRPC = "Sim.AdjustPerformance"
+
+func (sd *dispatcher) AdjustPerformance(args *AdjustPerformanceArgs, update *SimStateUpdate) error {
+	defer sd.sm.lg.CatchAndReportCrash()
+
+	c, err := sd.lookupController(args.ControllerToken, RoleInstructor)
+	if err != nil {
+		return err
+	}
+	if err := c.sim.AdjustPerformance(c.tcw, args.Callsign, args.Adjustment); err != nil {
+		return err
+	}
+	*update = c.GetStateUpdate()
+	return nil
+}
+
+type SetRunwayClosedArgs struct {
+	ControllerToken string
+	Airport         string
+	Runway          string
+	Closed          bool
+}
+
+const SetRunwayClosedRPC = "Sim.SetRunwayClosed"
+
+func (sd *dispatcher) SetRunwayClosed(args *SetRunwayClosedArgs, update *SimStateUpdate) error {
+	defer sd.sm.lg.CatchAndReportCrash()
+
+	c, err := sd.lookupController(args.ControllerToken, RoleInstructor)
+	if err != nil {
+		return err
+	}
+	if err := c.sim.SetRunwayClosed(c.tcw, args.Airport, args.Runway, args.Closed); err != nil {
+		return err
+	}
+	c.sim.GlobalMessage(c.tcw, fmt.Sprintf("%s (%s) has %s runway %s at %s", c.tcw, c.initials,
+		util.Select(args.Closed, "closed", "reopened"), args.Runway, args.Airport))
+	*update = c.GetStateUpdate()
+	return nil
+}
+
+type SwitchScenarioArgs struct {
+	ControllerToken string
+	ScenarioName    string
+}
+
+const SwitchScenarioRPC = "Sim.SwitchScenario"
+
+// SwitchScenario switches the running sim to the runways and controller
+// assignments of another scenario in the same scenario group, e.g. for a
+// runway configuration change.
+func (sd *dispatcher) SwitchScenario(args *SwitchScenarioArgs, update *SimStateUpdate) error {
+	defer sd.sm.lg.CatchAndReportCrash()
+
+	c, err := sd.lookupController(args.ControllerToken, RoleInstructor)
+	if err != nil {
+		return err
+	}
+	sw, err := sd.sm.makeConfigurationSwitch(c.session, args.ScenarioName)
+	if err != nil {
+		return err
+	}
+	if err := c.sim.SwitchConfiguration(c.tcw, sw); err != nil {
+		return err
+	}
+
+	c.session.mu.Lock(c.session.lg)
+	c.session.scenario = args.ScenarioName
+	c.session.mu.Unlock(c.session.lg)
+
+	c.sim.GlobalMessage(c.tcw, fmt.Sprintf("%s (%s) has switched the sim to %s", c.tcw, c.initials,
+		args.ScenarioName))
+	*update = c.GetStateUpdate()
+	return nil
+}
+
+type ScenarioConfigurations struct {
+	Current   string
+	Available []string
+}
+
+const GetScenarioConfigurationsRPC = "Sim.GetScenarioConfigurations"
+
+// GetScenarioConfigurations returns the scenarios that the controller's
+// sim can be switched to with SwitchScenario.
+func (sd *dispatcher) GetScenarioConfigurations(token string, result *ScenarioConfigurations) error {
+	defer sd.sm.lg.CatchAndReportCrash()
+
+	c, err := sd.lookupController(token, RoleInstructor)
+	if err != nil {
+		return err
+	}
+	result.Current, result.Available = sd.sm.switchableScenarios(c.session)
+	return nil
+}
+
 type AssociateFlightPlanArgs struct {
 	ControllerToken     string
 	Callsign            av.ADSBCallsign
@@ -183,11 +376,11 @@ const AssociateFlightPlanRPC = "Sim.AssociateFlightPlan"
 func (sd *dispatcher) AssociateFlightPlan(it *AssociateFlightPlanArgs, update *SimStateUpdate) error {
 	defer sd.sm.lg.CatchAndReportCrash()
 
-	c := sd.sm.LookupController(it.ControllerToken)
-	if c == nil {
-		return ErrNoSimForControllerToken
+	c, err := sd.lookupController(it.ControllerToken, RoleController)
+	if err != nil {
+		return err
 	}
-	err := c.sim.AssociateFlightPlan(c.tcw, it.Callsign, it.FlightPlanSpecifier)
+	err = c.sim.AssociateFlightPlan(c.tcw, it.Callsign, it.FlightPlanSpecifier)
 	if err == nil {
 		*update = c.GetStateUpdate()
 	}
@@ -206,11 +399,11 @@ const ActivateFlightPlanRPC = "Sim.ActivateFlightPlan"
 func (sd *dispatcher) ActivateFlightPlan(af *ActivateFlightPlanArgs, update *SimStateUpdate) error {
 	defer sd.sm.lg.CatchAndReportCrash()
 
-	c := sd.sm.LookupController(af.ControllerToken)
-	if c == nil {
-		return ErrNoSimForControllerToken
+	c, err := sd.lookupController(af.ControllerToken, RoleController)
+	if err != nil {
+		return err
 	}
-	err := c.sim.ActivateFlightPlan(c.tcw, af.TrackCallsign, af.FpACID, &af.FlightPlanSpecifier)
+	err = c.sim.ActivateFlightPlan(c.tcw, af.TrackCallsign, af.FpACID, &af.FlightPlanSpecifier)
 	if err == nil {
 		*update = c.GetStateUpdate()
 	}
@@ -227,11 +420,11 @@ const CreateFlightPlanRPC = "Sim.CreateFlightPlan"
 func (sd *dispatcher) CreateFlightPlan(cfp *CreateFlightPlanArgs, update *SimStateUpdate) error {
 	defer sd.sm.lg.CatchAndReportCrash()
 
-	c := sd.sm.LookupController(cfp.ControllerToken)
-	if c == nil {
-		return ErrNoSimForControllerToken
+	c, err := sd.lookupController(cfp.ControllerToken, RoleController)
+	if err != nil {
+		return err
 	}
-	err := c.sim.CreateFlightPlan(c.tcw, cfp.FlightPlanSpecifier)
+	err = c.sim.CreateFlightPlan(c.tcw, cfp.FlightPlanSpecifier)
 	if err == nil {
 		*update = c.GetStateUpdate()
 	}
@@ -250,11 +443,11 @@ const CreateInterfacilityVFRRPC = "Sim.CreateInterfacilityVFR"
 func (sd *dispatcher) CreateInterfacilityVFR(args *CreateInterfacilityVFRArgs, update *SimStateUpdate) error {
 	defer sd.sm.lg.CatchAndReportCrash()
 
-	c := sd.sm.LookupController(args.ControllerToken)
-	if c == nil {
-		return ErrNoSimForControllerToken
+	c, err := sd.lookupController(args.ControllerToken, RoleController)
+	if err != nil {
+		return err
 	}
-	err := c.sim.CreateInterfacilityVFR(c.tcw, args.ACID, args.IsIntermediate, args.RequestedAlt)
+	err = c.sim.CreateInterfacilityVFR(c.tcw, args.ACID, args.IsIntermediate, args.RequestedAlt)
 	if err == nil {
 		*update = c.GetStateUpdate()
 	}
@@ -272,11 +465,11 @@ const ModifyFlightPlanRPC = "Sim.ModifyFlightPlan"
 func (sd *dispatcher) ModifyFlightPlan(mfp *ModifyFlightPlanArgs, update *SimStateUpdate) error {
 	defer sd.sm.lg.CatchAndReportCrash()
 
-	c := sd.sm.LookupController(mfp.ControllerToken)
-	if c == nil {
-		return ErrNoSimForControllerToken
+	c, err := sd.lookupController(mfp.ControllerToken, RoleController)
+	if err != nil {
+		return err
 	}
-	err := c.sim.ModifyFlightPlan(c.tcw, mfp.ACID, mfp.FlightPlanSpecifier)
+	err = c.sim.ModifyFlightPlan(c.tcw, mfp.ACID, mfp.FlightPlanSpecifier)
 	if err == nil {
 		*update = c.GetStateUpdate()
 	}
@@ -296,11 +489,11 @@ const UpdateATISGITextRPC = "Sim.UpdateATISGIText"
 func (sd *dispatcher) UpdateATISGIText(args *UpdateATISGITextArgs, update *SimStateUpdate) error {
 	defer sd.sm.lg.CatchAndReportCrash()
 
-	c := sd.sm.LookupController(args.ControllerToken)
-	if c == nil {
-		return ErrNoSimForControllerToken
+	c, err := sd.lookupController(args.ControllerToken, RoleController)
+	if err != nil {
+		return err
 	}
-	err := c.sim.UpdateATISGIText(c.tcw, args.Line, args.Auxiliary, args.ATIS, args.GIText)
+	err = c.sim.UpdateATISGIText(c.tcw, args.Line, args.Auxiliary, args.ATIS, args.GIText)
 	if err == nil {
 		*update = c.GetStateUpdate()
 	}
@@ -324,11 +517,11 @@ const DeleteFlightPlanRPC = "Sim.DeleteFlightPlan"
 func (sd *dispatcher) DeleteFlightPlan(dt *DeleteFlightPlanArgs, update *SimStateUpdate) error {
 	defer sd.sm.lg.CatchAndReportCrash()
 
-	c := sd.sm.LookupController(dt.ControllerToken)
-	if c == nil {
-		return ErrNoSimForControllerToken
+	c, err := sd.lookupController(dt.ControllerToken, RoleController)
+	if err != nil {
+		return err
 	}
-	err := c.sim.DeleteFlightPlan(c.tcw, dt.ACID)
+	err = c.sim.DeleteFlightPlan(c.tcw, dt.ACID)
 	if err == nil {
 		*update = c.GetStateUpdate()
 	}
@@ -347,11 +540,11 @@ const RepositionTrackRPC = "Sim.RepositionTrack"
 func (sd *dispatcher) RepositionTrack(rt *RepositionTrackArgs, update *SimStateUpdate) error {
 	defer sd.sm.lg.CatchAndReportCrash()
 
-	c := sd.sm.LookupController(rt.ControllerToken)
-	if c == nil {
-		return ErrNoSimForControllerToken
+	c, err := sd.lookupController(rt.ControllerToken, RoleController)
+	if err != nil {
+		return err
 	}
-	err := c.sim.RepositionTrack(c.tcw, rt.ACID, rt.Callsign, rt.Position)
+	err = c.sim.RepositionTrack(c.tcw, rt.ACID, rt.Callsign, rt.Position)
 	if err == nil {
 		*update = c.GetStateUpdate()
 	}
@@ -369,11 +562,11 @@ const HandoffTrackRPC = "Sim.HandoffTrack"
 func (sd *dispatcher) HandoffTrack(h *HandoffArgs, update *SimStateUpdate) error {
 	defer sd.sm.lg.CatchAndReportCrash()
 
-	c := sd.sm.LookupController(h.ControllerToken)
-	if c == nil {
-		return ErrNoSimForControllerToken
+	c, err := sd.lookupController(h.ControllerToken, RoleController)
+	if err != nil {
+		return err
 	}
-	err := c.sim.HandoffTrack(c.tcw, h.ACID, h.ToPosition)
+	err = c.sim.HandoffTrack(c.tcw, h.ACID, h.ToPosition)
 	if err == nil {
 		*update = c.GetStateUpdate()
 	}
@@ -385,11 +578,11 @@ const RedirectHandoffRPC = "Sim.RedirectHandoff"
 func (sd *dispatcher) RedirectHandoff(h *HandoffArgs, update *SimStateUpdate) error {
 	defer sd.sm.lg.CatchAndReportCrash()
 
-	c := sd.sm.LookupController(h.ControllerToken)
-	if c == nil {
-		return ErrNoSimForControllerToken
+	c, err := sd.lookupController(h.ControllerToken, RoleController)
+	if err != nil {
+		return err
 	}
-	err := c.sim.RedirectHandoff(c.tcw, h.ACID, h.ToPosition)
+	err = c.sim.RedirectHandoff(c.tcw, h.ACID, h.ToPosition)
 	if err == nil {
 		*update = c.GetStateUpdate()
 	}
@@ -401,11 +594,11 @@ const AcceptRedirectedHandoffRPC = "Sim.AcceptRedirectedHandoff"
 func (sd *dispatcher) AcceptRedirectedHandoff(po *AcceptHandoffArgs, update *SimStateUpdate) error {
 	defer sd.sm.lg.CatchAndReportCrash()
 
-	c := sd.sm.LookupController(po.ControllerToken)
-	if c == nil {
-		return ErrNoSimForControllerToken
+	c, err := sd.lookupController(po.ControllerToken, RoleController)
+	if err != nil {
+		return err
 	}
-	err := c.sim.AcceptRedirectedHandoff(c.tcw, po.ACID)
+	err = c.sim.AcceptRedirectedHandoff(c.tcw, po.ACID)
 	if err == nil {
 		*update = c.GetStateUpdate()
 	}
@@ -419,11 +612,11 @@ const AcceptHandoffRPC = "Sim.AcceptHandoff"
 func (sd *dispatcher) AcceptHandoff(ah *AcceptHandoffArgs, update *SimStateUpdate) error {
 	defer sd.sm.lg.CatchAndReportCrash()
 
-	c := sd.sm.LookupController(ah.ControllerToken)
-	if c == nil {
-		return ErrNoSimForControllerToken
+	c, err := sd.lookupController(ah.ControllerToken, RoleController)
+	if err != nil {
+		return err
 	}
-	err := c.sim.AcceptHandoff(c.tcw, ah.ACID)
+	err = c.sim.AcceptHandoff(c.tcw, ah.ACID)
 	if err == nil {
 		*update = c.GetStateUpdate()
 	}
@@ -437,11 +630,11 @@ const CancelHandoffRPC = "Sim.CancelHandoff"
 func (sd *dispatcher) CancelHandoff(ch *CancelHandoffArgs, update *SimStateUpdate) error {
 	defer sd.sm.lg.CatchAndReportCrash()
 
-	c := sd.sm.LookupController(ch.ControllerToken)
-	if c == nil {
-		return ErrNoSimForControllerToken
+	c, err := sd.lookupController(ch.ControllerToken, RoleController)
+	if err != nil {
+		return err
 	}
-	err := c.sim.CancelHandoff(c.tcw, ch.ACID)
+	err = c.sim.CancelHandoff(c.tcw, ch.ACID)
 	if err == nil {
 		*update = c.GetStateUpdate()
 	}
@@ -465,11 +658,11 @@ const ForceQLRPC = "Sim.ForceQL"
 func (sd *dispatcher) ForceQL(ql *ForceQLArgs, update *SimStateUpdate) error {
 	defer sd.sm.lg.CatchAndReportCrash()
 
-	c := sd.sm.LookupController(ql.ControllerToken)
-	if c == nil {
-		return ErrNoSimForControllerToken
+	c, err := sd.lookupController(ql.ControllerToken, RoleController)
+	if err != nil {
+		return err
 	}
-	err := c.sim.ForceQL(c.tcw, ql.ACID, ql.ToPosition)
+	err = c.sim.ForceQL(c.tcw, ql.ACID, ql.ToPosition)
 	if err == nil {
 		*update = c.GetStateUpdate()
 	}
@@ -486,9 +679,9 @@ const GlobalMessageRPC = "Sim.GlobalMessage"
 func (sd *dispatcher) GlobalMessage(gm *GlobalMessageArgs, _ *struct{}) error {
 	defer sd.sm.lg.CatchAndReportCrash()
 
-	c := sd.sm.LookupController(gm.ControllerToken)
-	if c == nil {
-		return ErrNoSimForControllerToken
+	c, err := sd.lookupController(gm.ControllerToken, RoleController)
+	if err != nil {
+		return err
 	}
 	c.sim.GlobalMessage(c.tcw, fmt.Sprintf("%s(%s): %s", c.initials, c.tcw, gm.Message))
 	return nil
@@ -499,11 +692,11 @@ const PointOutRPC = "Sim.PointOut"
 func (sd *dispatcher) PointOut(po *PointOutArgs, update *SimStateUpdate) error {
 	defer sd.sm.lg.CatchAndReportCrash()
 
-	c := sd.sm.LookupController(po.ControllerToken)
-	if c == nil {
-		return ErrNoSimForControllerToken
+	c, err := sd.lookupController(po.ControllerToken, RoleController)
+	if err != nil {
+		return err
 	}
-	err := c.sim.PointOut(c.tcw, po.ACID, po.ToPosition)
+	err = c.sim.PointOut(c.tcw, po.ACID, po.ToPosition)
 	if err == nil {
 		*update = c.GetStateUpdate()
 	}
@@ -515,11 +708,11 @@ const AcknowledgePointOutRPC = "Sim.AcknowledgePointOut"
 func (sd *dispatcher) AcknowledgePointOut(po *PointOutArgs, update *SimStateUpdate) error {
 	defer sd.sm.lg.CatchAndReportCrash()
 
-	c := sd.sm.LookupController(po.ControllerToken)
-	if c == nil {
-		return ErrNoSimForControllerToken
+	c, err := sd.lookupController(po.ControllerToken, RoleController)
+	if err != nil {
+		return err
 	}
-	err := c.sim.AcknowledgePointOut(c.tcw, po.ACID)
+	err = c.sim.AcknowledgePointOut(c.tcw, po.ACID)
 	if err == nil {
 		*update = c.GetStateUpdate()
 	}
@@ -531,11 +724,11 @@ const RecallPointOutRPC = "Sim.RecallPointOut"
 func (sd *dispatcher) RecallPointOut(po *PointOutArgs, update *SimStateUpdate) error {
 	defer sd.sm.lg.CatchAndReportCrash()
 
-	c := sd.sm.LookupController(po.ControllerToken)
-	if c == nil {
-		return ErrNoSimForControllerToken
+	c, err := sd.lookupController(po.ControllerToken, RoleController)
+	if err != nil {
+		return err
 	}
-	err := c.sim.RecallPointOut(c.tcw, po.ACID)
+	err = c.sim.RecallPointOut(c.tcw, po.ACID)
 	if err == nil {
 		*update = c.GetStateUpdate()
 	}
@@ -547,11 +740,11 @@ const RejectPointOutRPC = "Sim.RejectPointOut"
 func (sd *dispatcher) RejectPointOut(po *PointOutArgs, update *SimStateUpdate) error {
 	defer sd.sm.lg.CatchAndReportCrash()
 
-	c := sd.sm.LookupController(po.ControllerToken)
-	if c == nil {
-		return ErrNoSimForControllerToken
+	c, err := sd.lookupController(po.ControllerToken, RoleController)
+	if err != nil {
+		return err
 	}
-	err := c.sim.RejectPointOut(c.tcw, po.ACID)
+	err = c.sim.RejectPointOut(c.tcw, po.ACID)
 	if err == nil {
 		*update = c.GetStateUpdate()
 	}
@@ -565,11 +758,11 @@ const ReleaseDepartureRPC = "Sim.ReleaseDeparture"
 func (sd *dispatcher) ReleaseDeparture(hd *HeldDepartureArgs, update *SimStateUpdate) error {
 	defer sd.sm.lg.CatchAndReportCrash()
 
-	c := sd.sm.LookupController(hd.ControllerToken)
-	if c == nil {
-		return ErrNoSimForControllerToken
+	c, err := sd.lookupController(hd.ControllerToken, RoleController)
+	if err != nil {
+		return err
 	}
-	err := c.sim.ReleaseDeparture(c.tcw, hd.Callsign)
+	err = c.sim.ReleaseDeparture(c.tcw, hd.Callsign)
 	if err == nil {
 		*update = c.GetStateUpdate()
 	}
@@ -583,11 +776,11 @@ const DeleteAllAircraftRPC = "Sim.DeleteAllAircraft"
 func (sd *dispatcher) DeleteAllAircraft(da *DeleteAircraftArgs, update *SimStateUpdate) error {
 	defer sd.sm.lg.CatchAndReportCrash()
 
-	c := sd.sm.LookupController(da.ControllerToken)
-	if c == nil {
-		return ErrNoSimForControllerToken
+	c, err := sd.lookupController(da.ControllerToken, RoleController)
+	if err != nil {
+		return err
 	}
-	err := c.sim.DeleteAllAircraft(c.tcw)
+	err = c.sim.DeleteAllAircraft(c.tcw)
 	if err == nil {
 		*update = c.GetStateUpdate()
 	}
@@ -604,11 +797,11 @@ const DeleteAircraftRPC = "Sim.DeleteAircraft"
 func (sd *dispatcher) DeleteAircraft(da *DeleteAircraftListArgs, update *SimStateUpdate) error {
 	defer sd.sm.lg.CatchAndReportCrash()
 
-	c := sd.sm.LookupController(da.ControllerToken)
-	if c == nil {
-		return ErrNoSimForControllerToken
+	c, err := sd.lookupController(da.ControllerToken, RoleController)
+	if err != nil {
+		return err
 	}
-	err := c.sim.DeleteAircraftSlice(c.tcw, da.Aircraft)
+	err = c.sim.DeleteAircraftSlice(c.tcw, da.Aircraft)
 	*update = c.GetStateUpdate()
 	return err
 }
@@ -624,11 +817,11 @@ const SendRouteCoordinatesRPC = "Sim.SendRouteCoordinates"
 func (sd *dispatcher) SendRouteCoordinates(rca *SendRouteCoordinatesArgs, update *SimStateUpdate) error {
 	defer sd.sm.lg.CatchAndReportCrash()
 
-	c := sd.sm.LookupController(rca.ControllerToken)
-	if c == nil {
-		return ErrNoSimForControllerToken
+	c, err := sd.lookupController(rca.ControllerToken, RoleController)
+	if err != nil {
+		return err
 	}
-	err := c.sim.SendRouteCoordinates(c.tcw, rca.ACID, rca.Minutes)
+	err = c.sim.SendRouteCoordinates(c.tcw, rca.ACID, rca.Minutes)
 	*update = c.GetStateUpdate()
 	return err
 }
@@ -644,12 +837,12 @@ const FlightPlanDirectRPC = "Sim.FlightPlanDirect"
 func (sd *dispatcher) FlightPlanDirect(da *FlightPlanDirectArgs, update *SimStateUpdate) error {
 	defer sd.sm.lg.CatchAndReportCrash()
 
-	c := sd.sm.LookupController(da.ControllerToken)
-	if c == nil {
-		return ErrNoSimForControllerToken
+	c, err := sd.lookupController(da.ControllerToken, RoleController)
+	if err != nil {
+		return err
 	}
 	tcp := c.sim.State.PrimaryPositionForTCW(c.tcw)
-	err := c.sim.FlightPlanDirect(tcp, da.Fix, da.ACID)
+	err = c.sim.FlightPlanDirect(tcp, da.Fix, da.ACID)
 	*update = c.GetStateUpdate()
 	return err
 }
@@ -678,6 +871,7 @@ type AircraftCommandsResult struct {
 	ReadbackText      string          // Text for client to synthesize
 	ReadbackVoiceName string          // Voice name for synthesis (e.g., "am_adam")
 	ReadbackCallsign  av.ADSBCallsign // Callsign for the readback
+	NoResponse        bool            // The aircraft is NORDO and didn't respond
 }
 
 const RunAircraftCommandsRPC = "Sim.RunAircraftCommands"
@@ -685,9 +879,9 @@ const RunAircraftCommandsRPC = "Sim.RunAircraftCommands"
 func (sd *dispatcher) RunAircraftCommands(cmds *AircraftCommandsArgs, result *AircraftCommandsResult) error {
 	defer sd.sm.lg.CatchAndReportCrash()
 
-	c := sd.sm.LookupController(cmds.ControllerToken)
-	if c == nil {
-		return ErrNoSimForControllerToken
+	c, err := sd.lookupController(cmds.ControllerToken, RoleController)
+	if err != nil {
+		return err
 	}
 
 	callsign := cmds.Callsign
@@ -726,6 +920,7 @@ func (sd *dispatcher) RunAircraftCommands(cmds *AircraftCommandsArgs, result *Ai
 
 	execResult := c.sim.RunAircraftControlCommands(c.tcw, cmds.Callsign, cmds.Commands, cmds.AudioDuration)
 	result.RemainingInput = execResult.RemainingInput
+	result.NoResponse = execResult.NoResponse
 	if execResult.Error != nil {
 		result.ErrorMessage = execResult.Error.Error()
 	}
@@ -735,10 +930,12 @@ func (sd *dispatcher) RunAircraftCommands(cmds *AircraftCommandsArgs, result *Ai
 		result.ReadbackText = execResult.ReadbackSpokenText
 		result.ReadbackVoiceName = c.sim.VoiceAssigner.GetVoice(cs, c.sim.Rand)
 		result.ReadbackCallsign = cs
+		sd.sm.metrics.countTTSRequest("readback", result.ReadbackVoiceName == "")
 	}
 
 	// Log whisper STT commands (WhisperDuration is non-zero for voice commands)
 	if cmds.WhisperDuration > 0 {
+		sd.sm.metrics.countSTTCommand(result.ErrorMessage != "")
 		sd.sm.lg.Info("STT command",
 			slog.String("transcript", cmds.WhisperTranscript),
 			slog.Float64("whisper_duration_ms", float64(cmds.WhisperDuration.Microseconds())/1000.0),
@@ -764,9 +961,9 @@ const SetWaypointCommandsRPC = "Sim.SetWaypointCommands"
 func (sd *dispatcher) SetWaypointCommands(args *SetWaypointCommandsArgs, _ *struct{}) error {
 	defer sd.sm.lg.CatchAndReportCrash()
 
-	c := sd.sm.LookupController(args.ControllerToken)
-	if c == nil {
-		return ErrNoSimForControllerToken
+	c, err := sd.lookupController(args.ControllerToken, RoleController)
+	if err != nil {
+		return err
 	}
 	return c.sim.SetWaypointCommands(c.tcw, args.Commands)
 }
@@ -782,9 +979,9 @@ const LaunchAircraftRPC = "Sim.LaunchAircraft"
 func (sd *dispatcher) LaunchAircraft(ls *LaunchAircraftArgs, _ *struct{}) error {
 	defer sd.sm.lg.CatchAndReportCrash()
 
-	c := sd.sm.LookupController(ls.ControllerToken)
-	if c == nil {
-		return ErrNoSimForControllerToken
+	c, err := sd.lookupController(ls.ControllerToken, RoleController)
+	if err != nil {
+		return err
 	}
 	c.sim.LaunchAircraft(ls.Aircraft, av.RunwayID(ls.DepartureRunway))
 	return nil
@@ -803,12 +1000,11 @@ const CreateDepartureRPC = "Sim.CreateDeparture"
 func (sd *dispatcher) CreateDeparture(da *CreateDepartureArgs, depAc *sim.Aircraft) error {
 	defer sd.sm.lg.CatchAndReportCrash()
 
-	c := sd.sm.LookupController(da.ControllerToken)
-	if c == nil {
-		return ErrNoSimForControllerToken
+	c, err := sd.lookupController(da.ControllerToken, RoleController)
+	if err != nil {
+		return err
 	}
 	var ac *sim.Aircraft
-	var err error
 	if da.Rules == av.FlightRulesIFR {
 		ac, err = c.sim.CreateIFRDeparture(da.Airport, av.RunwayID(da.Runway), da.Category)
 	} else {
@@ -832,9 +1028,9 @@ const CreateArrivalRPC = "Sim.CreateArrival"
 func (sd *dispatcher) CreateArrival(aa *CreateArrivalArgs, arrAc *sim.Aircraft) error {
 	defer sd.sm.lg.CatchAndReportCrash()
 
-	c := sd.sm.LookupController(aa.ControllerToken)
-	if c == nil {
-		return ErrNoSimForControllerToken
+	c, err := sd.lookupController(aa.ControllerToken, RoleController)
+	if err != nil {
+		return err
 	}
 	ac, err := c.sim.CreateArrival(aa.Group, aa.Airport)
 	if err == nil {
@@ -853,9 +1049,9 @@ const CreateOverflightRPC = "Sim.CreateOverflight"
 func (sd *dispatcher) CreateOverflight(oa *CreateOverflightArgs, ofAc *sim.Aircraft) error {
 	defer sd.sm.lg.CatchAndReportCrash()
 
-	c := sd.sm.LookupController(oa.ControllerToken)
-	if c == nil {
-		return ErrNoSimForControllerToken
+	c, err := sd.lookupController(oa.ControllerToken, RoleController)
+	if err != nil {
+		return err
 	}
 	ac, err := c.sim.CreateOverflight(oa.Group)
 	if err == nil {
@@ -880,9 +1076,9 @@ const CreateRestrictionAreaRPC = "Sim.CreateRestrictionArea"
 func (sd *dispatcher) CreateRestrictionArea(ra *RestrictionAreaArgs, result *CreateRestrictionAreaResultArgs) error {
 	defer sd.sm.lg.CatchAndReportCrash()
 
-	c := sd.sm.LookupController(ra.ControllerToken)
-	if c == nil {
-		return ErrNoSimForControllerToken
+	c, err := sd.lookupController(ra.ControllerToken, RoleController)
+	if err != nil {
+		return err
 	}
 	i, err := c.sim.CreateRestrictionArea(ra.RestrictionArea)
 	if err != nil {
@@ -898,11 +1094,11 @@ const UpdateRestrictionAreaRPC = "Sim.UpdateRestrictionArea"
 func (sd *dispatcher) UpdateRestrictionArea(ra *RestrictionAreaArgs, update *SimStateUpdate) error {
 	defer sd.sm.lg.CatchAndReportCrash()
 
-	c := sd.sm.LookupController(ra.ControllerToken)
-	if c == nil {
-		return ErrNoSimForControllerToken
+	c, err := sd.lookupController(ra.ControllerToken, RoleController)
+	if err != nil {
+		return err
 	}
-	err := c.sim.UpdateRestrictionArea(ra.Index, ra.RestrictionArea)
+	err = c.sim.UpdateRestrictionArea(ra.Index, ra.RestrictionArea)
 	if err == nil {
 		*update = c.GetStateUpdate()
 	}
@@ -914,11 +1110,11 @@ const DeleteRestrictionAreaRPC = "Sim.DeleteRestrictionArea"
 func (sd *dispatcher) DeleteRestrictionArea(ra *RestrictionAreaArgs, update *SimStateUpdate) error {
 	defer sd.sm.lg.CatchAndReportCrash()
 
-	c := sd.sm.LookupController(ra.ControllerToken)
-	if c == nil {
-		return ErrNoSimForControllerToken
+	c, err := sd.lookupController(ra.ControllerToken, RoleController)
+	if err != nil {
+		return err
 	}
-	err := c.sim.DeleteRestrictionArea(ra.Index)
+	err = c.sim.DeleteRestrictionArea(ra.Index)
 	if err == nil {
 		*update = c.GetStateUpdate()
 	}
@@ -947,11 +1143,10 @@ const GetAircraftDisplayStateRPC = "Sim.GetAircraftDisplayState"
 func (sd *dispatcher) GetAircraftDisplayState(as *AircraftSpecifier, state *sim.AircraftDisplayState) error {
 	defer sd.sm.lg.CatchAndReportCrash()
 
-	c := sd.sm.LookupController(as.ControllerToken)
-	if c == nil {
-		return ErrNoSimForControllerToken
+	c, err := sd.lookupController(as.ControllerToken, RoleObserver)
+	if err != nil {
+		return err
 	}
-	var err error
 	*state, err = c.sim.GetAircraftDisplayState(as.Callsign)
 	return err
 }
@@ -968,11 +1163,11 @@ const ConsolidateTCPRPC = "Sim.ConsolidateTCP"
 func (sd *dispatcher) ConsolidateTCP(args *ConsolidateTCPArgs, update *SimStateUpdate) error {
 	defer sd.sm.lg.CatchAndReportCrash()
 
-	c := sd.sm.LookupController(args.ControllerToken)
-	if c == nil {
-		return ErrNoSimForControllerToken
+	c, err := sd.lookupController(args.ControllerToken, RoleController)
+	if err != nil {
+		return err
 	}
-	err := c.sim.ConsolidateTCP(args.ReceivingTCW, args.SendingTCP, args.Type)
+	err = c.sim.ConsolidateTCP(args.ReceivingTCW, args.SendingTCP, args.Type)
 	if err == nil {
 		*update = c.GetStateUpdate()
 	}
@@ -989,11 +1184,39 @@ const DeconsolidateTCPRPC = "Sim.DeconsolidateTCP"
 func (sd *dispatcher) DeconsolidateTCP(args *DeconsolidateTCPArgs, update *SimStateUpdate) error {
 	defer sd.sm.lg.CatchAndReportCrash()
 
-	c := sd.sm.LookupController(args.ControllerToken)
-	if c == nil {
-		return ErrNoSimForControllerToken
+	c, err := sd.lookupController(args.ControllerToken, RoleController)
+	if err != nil {
+		return err
 	}
-	err := c.sim.DeconsolidateTCP(c.tcw, args.TCP)
+	err = c.sim.DeconsolidateTCP(c.tcw, args.TCP)
+	if err == nil {
+		*update = c.GetStateUpdate()
+	}
+	return err
+}
+
+type SetAutoControlledArgs struct {
+	ControllerToken string
+	TCW             sim.TCW
+	AutoControlled  bool
+}
+
+const SetAutoControlledRPC = "Sim.SetAutoControlled"
+
+// SetAutoControlled hands an unstaffed TCW's traffic to the autocontroller
+// (or takes it back). Only instructors may do so, and TCWs that a human is
+// signed in to can't be autocontrolled.
+func (sd *dispatcher) SetAutoControlled(args *SetAutoControlledArgs, update *SimStateUpdate) error {
+	defer sd.sm.lg.CatchAndReportCrash()
+
+	c, err := sd.lookupController(args.ControllerToken, RoleInstructor)
+	if err != nil {
+		return err
+	}
+	if args.AutoControlled && slices.Contains(c.session.GetActiveTCWs(), args.TCW) {
+		return ErrTCWAlreadyOccupied
+	}
+	err = c.sim.SetAutoControlled(args.TCW, args.AutoControlled)
 	if err == nil {
 		*update = c.GetStateUpdate()
 	}
@@ -1016,12 +1239,11 @@ const ConfigureATPARPC = "Sim.ConfigureATPA"
 func (sd *dispatcher) ConfigureATPA(args *ATPAConfigArgs, result *ATPAConfigResult) error {
 	defer sd.sm.lg.CatchAndReportCrash()
 
-	c := sd.sm.LookupController(args.ControllerToken)
-	if c == nil {
-		return ErrNoSimForControllerToken
+	c, err := sd.lookupController(args.ControllerToken, RoleController)
+	if err != nil {
+		return err
 	}
 
-	var err error
 	result.Output, err = c.sim.ConfigureATPA(args.Op, args.VolumeId)
 	if err == nil {
 		result.SimStateUpdate = c.GetStateUpdate()
@@ -1045,12 +1267,11 @@ const ConfigureFDAMRPC = "Sim.ConfigureFDAM"
 func (sd *dispatcher) ConfigureFDAM(args *FDAMConfigArgs, result *FDAMConfigResult) error {
 	defer sd.sm.lg.CatchAndReportCrash()
 
-	c := sd.sm.LookupController(args.ControllerToken)
-	if c == nil {
-		return ErrNoSimForControllerToken
+	c, err := sd.lookupController(args.ControllerToken, RoleController)
+	if err != nil {
+		return err
 	}
 
-	var err error
 	result.Output, err = c.sim.ConfigureFDAM(args.Op, args.RegionId)
 	if err == nil {
 		result.SimStateUpdate = c.GetStateUpdate()
@@ -1074,13 +1295,16 @@ const RequestContactTransmissionRPC = "Sim.RequestContactTransmission"
 func (sd *dispatcher) RequestContactTransmission(args *RequestContactArgs, result *RequestContactResult) error {
 	defer sd.sm.lg.CatchAndReportCrash()
 
-	c := sd.sm.LookupController(args.ControllerToken)
-	if c == nil {
-		return ErrNoSimForControllerToken
+	c, err := sd.lookupController(args.ControllerToken, RoleController)
+	if err != nil {
+		return err
 	}
 
 	// Request a contact from the session - returns text and voice name for client-side synthesis
 	result.ContactText, result.ContactVoiceName, result.ContactCallsign, result.ContactType = c.session.RequestContact(c.tcw)
+	if result.ContactText != "" {
+		sd.sm.metrics.countTTSRequest("contact", result.ContactVoiceName == "")
+	}
 	return nil
 }
 
@@ -1095,9 +1319,9 @@ const PushFlightStripRPC = "Sim.PushFlightStrip"
 func (sd *dispatcher) PushFlightStrip(args *PushFlightStripArgs, _ *struct{}) error {
 	defer sd.sm.lg.CatchAndReportCrash()
 
-	c := sd.sm.LookupController(args.ControllerToken)
-	if c == nil {
-		return ErrNoSimForControllerToken
+	c, err := sd.lookupController(args.ControllerToken, RoleController)
+	if err != nil {
+		return err
 	}
 	return c.sim.PushFlightStrip(c.tcw, args.ACID, args.ToTCP)
 }
@@ -1113,9 +1337,28 @@ const AnnotateFlightStripRPC = "Sim.AnnotateFlightStrip"
 func (sd *dispatcher) AnnotateFlightStrip(args *AnnotateFlightStripArgs, _ *struct{}) error {
 	defer sd.sm.lg.CatchAndReportCrash()
 
-	c := sd.sm.LookupController(args.ControllerToken)
-	if c == nil {
-		return ErrNoSimForControllerToken
+	c, err := sd.lookupController(args.ControllerToken, RoleController)
+	if err != nil {
+		return err
 	}
 	return c.sim.AnnotateFlightStrip(c.tcw, args.ACID, args.Annotations)
 }
+
+type GetScorecardArgs struct {
+	ControllerToken string
+	TCW             sim.TCW // TCW to get the scorecard of (optional - if empty, the user's own)
+}
+
+const GetScorecardRPC = "Sim.GetScorecard"
+
+func (sd *dispatcher) GetScorecard(args *GetScorecardArgs, sc *sim.Scorecard) error {
+	defer sd.sm.lg.CatchAndReportCrash()
+
+	c, err := sd.lookupController(args.ControllerToken, RoleObserver)
+	if err != nil {
+		return err
+	}
+	tcw := util.Select(args.TCW != "", args.TCW, c.tcw)
+	*sc, err = c.sim.GetScorecard(c.tcw, tcw)
+	return err
+}
@@ -13,18 +13,37 @@ import (
 )
 
 var (
+	ErrAPIOriginNotAllowed       = errors.New("Origin not allowed to use the API")
+	ErrAlreadySignedUp           = errors.New("Already signed up for that sim")
+	ErrAuthenticationFailed      = errors.New("Invalid username, password, or token")
 	ErrControllerAlreadySignedIn = errors.New("Controller with that callsign already signed in")
 	ErrDuplicateSimName          = errors.New("A sim with that name already exists")
+	ErrFederationDisabled        = errors.New("Federation is not enabled on this server")
+	ErrFederationLinkExists      = errors.New("Sim is already linked to that facility")
+	ErrFederationPeerNotAllowed  = errors.New("Server is not an allowed federation peer")
+	ErrIncompatibleScenario      = errors.New("Scenario uses different control positions")
 	ErrInvalidCommandSyntax      = errors.New("Invalid command syntax")
+	ErrInsufficientRole          = errors.New("Not authorized for that action")
 	ErrInvalidControllerToken    = errors.New("Invalid controller token")
+	ErrInvalidFederationSecret   = errors.New("Invalid federation secret")
+	ErrInvalidFederationToken    = errors.New("Invalid federation token")
 	ErrInvalidPassword           = errors.New("Invalid password")
+	ErrInvalidScheduleTime       = errors.New("Scheduled start time must be in the future")
 	ErrInvalidSimConfiguration   = errors.New("Invalid SimConfiguration")
 	ErrNoNamedSim                = errors.New("No Sim with that name")
+	ErrNoClientKey               = errors.New("Client key must be given")
+	ErrNoInitials                = errors.New("Controller initials must be given")
 	ErrNoSimForControllerToken   = errors.New("No Sim running for controller token")
+	ErrNotScheduleOwner          = errors.New("Only whoever made it can cancel it")
+	ErrNotSignedUp               = errors.New("Not signed up for that sim")
+	ErrPasswordTooLong           = errors.New("Password is too long")
 	ErrRPCTimeout                = errors.New("RPC call timed out")
 	ErrRPCVersionMismatch        = errors.New("Client and server RPC versions don't match")
 	ErrServerDisconnected        = errors.New("Server disconnected")
 	ErrTCWAlreadyOccupied        = errors.New("TCW is already occupied")
+	ErrTCWReserved               = errors.New("TCW is reserved for another controller")
+	ErrTooManyScheduledSims      = errors.New("Too many sims have been scheduled")
+	ErrUnknownScenario           = errors.New("Unknown scenario")
 )
 
 var errorStringToError = map[string]error{
@@ -74,18 +93,25 @@ var errorStringToError = map[string]error{
 	sim.ErrIllegalBeaconCode.Error():               sim.ErrIllegalBeaconCode,
 	sim.ErrIllegalFunction.Error():                 sim.ErrIllegalFunction,
 	sim.ErrIllegalLine.Error():                     sim.ErrIllegalLine,
+	sim.ErrIllegalPosition.Error():                 sim.ErrIllegalPosition,
 	sim.ErrIllegalTrackLocalFP.Error():             sim.ErrIllegalTrackLocalFP,
 	sim.ErrIllegalScratchpad.Error():               sim.ErrIllegalScratchpad,
 	sim.ErrInvalidAbbreviatedFP.Error():            sim.ErrInvalidAbbreviatedFP,
 	sim.ErrInvalidDepartureController.Error():      sim.ErrInvalidDepartureController,
+	sim.ErrInvalidPerformance.Error():              sim.ErrInvalidPerformance,
 	sim.ErrInvalidRestrictionAreaIndex.Error():     sim.ErrInvalidRestrictionAreaIndex,
+	sim.ErrInvalidTransferredAircraft.Error():      sim.ErrInvalidTransferredAircraft,
 	sim.ErrInvalidVolumeId.Error():                 sim.ErrInvalidVolumeId,
 	sim.ErrNoACType.Error():                        sim.ErrNoACType,
+	sim.ErrNoAssignedAltitude.Error():              sim.ErrNoAssignedAltitude,
+	sim.ErrNoCheckpoint.Error():                    sim.ErrNoCheckpoint,
 	sim.ErrNoMatchingFlight.Error():                sim.ErrNoMatchingFlight,
 	sim.ErrNoMatchingFlightPlan.Error():            sim.ErrNoMatchingFlightPlan,
 	sim.ErrNoScratchpad.Error():                    sim.ErrNoScratchpad,
 	sim.ErrNoVFRAircraftForFlightFollowing.Error(): sim.ErrNoVFRAircraftForFlightFollowing,
+	sim.ErrNotFederatedFacility.Error():            sim.ErrNotFederatedFacility,
 	sim.ErrNotLaunchController.Error():             sim.ErrNotLaunchController,
+	sim.ErrNotPrivilegedTCW.Error():                sim.ErrNotPrivilegedTCW,
 	sim.ErrTCPAlreadyConsolidated.Error():          sim.ErrTCPAlreadyConsolidated,
 	sim.ErrTCPNotConsolidated.Error():              sim.ErrTCPNotConsolidated,
 	sim.ErrTCWIsConsolidated.Error():               sim.ErrTCWIsConsolidated,
@@ -104,18 +130,36 @@ var errorStringToError = map[string]error{
 	sim.ErrVolumeDisabled.Error():                  sim.ErrVolumeDisabled,
 	sim.ErrVolumeNot25nm.Error():                   sim.ErrVolumeNot25nm,
 
+	ErrAlreadySignedUp.Error():           ErrAlreadySignedUp,
+	ErrAuthenticationFailed.Error():      ErrAuthenticationFailed,
 	ErrControllerAlreadySignedIn.Error(): ErrControllerAlreadySignedIn,
 	ErrDuplicateSimName.Error():          ErrDuplicateSimName,
+	ErrFederationDisabled.Error():        ErrFederationDisabled,
+	ErrFederationLinkExists.Error():      ErrFederationLinkExists,
+	ErrFederationPeerNotAllowed.Error():  ErrFederationPeerNotAllowed,
+	ErrIncompatibleScenario.Error():      ErrIncompatibleScenario,
 	ErrInvalidCommandSyntax.Error():      ErrInvalidCommandSyntax,
+	ErrInsufficientRole.Error():          ErrInsufficientRole,
 	ErrInvalidControllerToken.Error():    ErrInvalidControllerToken,
+	ErrInvalidFederationSecret.Error():   ErrInvalidFederationSecret,
+	ErrInvalidFederationToken.Error():    ErrInvalidFederationToken,
 	ErrInvalidPassword.Error():           ErrInvalidPassword,
+	ErrInvalidScheduleTime.Error():       ErrInvalidScheduleTime,
 	ErrInvalidSimConfiguration.Error():   ErrInvalidSimConfiguration,
 	ErrNoNamedSim.Error():                ErrNoNamedSim,
+	ErrNoClientKey.Error():               ErrNoClientKey,
+	ErrNoInitials.Error():                ErrNoInitials,
 	ErrNoSimForControllerToken.Error():   ErrNoSimForControllerToken,
+	ErrNotScheduleOwner.Error():          ErrNotScheduleOwner,
+	ErrNotSignedUp.Error():               ErrNotSignedUp,
+	ErrPasswordTooLong.Error():           ErrPasswordTooLong,
 	ErrRPCTimeout.Error():                ErrRPCTimeout,
 	ErrRPCVersionMismatch.Error():        ErrRPCVersionMismatch,
 	ErrServerDisconnected.Error():        ErrServerDisconnected,
 	ErrTCWAlreadyOccupied.Error():        ErrTCWAlreadyOccupied,
+	ErrTCWReserved.Error():               ErrTCWReserved,
+	ErrTooManyScheduledSims.Error():      ErrTooManyScheduledSims,
+	ErrUnknownScenario.Error():           ErrUnknownScenario,
 }
 
 func TryDecodeError(e error) error {
@@ -0,0 +1,468 @@
+// server/federation.go
+// Copyright(c) 2022-2026 vice contributors, licensed under the GNU Public License, Version 3.
+// SPDX: GPL-3.0-only
+
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/rpc"
+	"slices"
+	"time"
+
+	"github.com/mmp/vice/sim"
+	"github.com/mmp/vice/util"
+)
+
+// Sims running on different servers can be federated so that adjacent
+// facilities (e.g., a TRACON and its overlying ARTCC) can be controlled
+// from separate servers. An instructor in one sim links it to a sim on
+// another server with the LinkFederatedSim RPC; that server then
+// periodically calls the other's ExchangeNASMessages RPC, sending the
+// NASMessages its sim has queued for the other facility and receiving
+// the ones queued for it. See sim/federation.go for the messages
+// themselves. Each side acknowledges the messages it has received in its
+// next request or response; until then, they are resent with every
+// exchange and the receiving sim ignores the ones it has already
+// processed.
+//
+// Federation is off unless the server is launched with it enabled, and
+// sims may only link to servers in the server's list of federation
+// peers. An instructor in the sim being linked to must first allow the
+// link by setting a secret for the linking facility with the
+// SetFederationSecret RPC; the instructor making the link must give the
+// same secret.
+//
+// If no messages have been exchanged with a facility for
+// federationTimeout (e.g., because the other server went away), the
+// facility is unfederated and its controllers revert to being virtual
+// controllers.
+
+const federationExchangeInterval = time.Second
+const federationTimeout = time.Minute
+
+// LinkFederatedPeerRequest is sent by a server to the server running the
+// sim that it is federating with.
+type LinkFederatedPeerRequest struct {
+	Version  int
+	SimName  string
+	Password string
+	Account  Credentials // only used if the server requires authentication
+	// Facility is the facility of the sim that is linking.
+	Facility string
+	// Secret must match the one set for Facility in the sim being
+	// linked to.
+	Secret string
+}
+
+type LinkFederatedPeerResult struct {
+	// Token is used to identify the link in subsequent
+	// ExchangeNASMessages calls.
+	Token string
+	// Facility is the facility of the sim that was linked to.
+	Facility string
+}
+
+const LinkFederatedPeerRPC = "SimManager.LinkFederatedPeer"
+
+func (sm *SimManager) LinkFederatedPeer(req *LinkFederatedPeerRequest, result *LinkFederatedPeerResult) error {
+	defer sm.lg.CatchAndReportCrash()
+
+	if req.Version != ViceRPCVersion {
+		return ErrRPCVersionMismatch
+	} else if !sm.federation {
+		return ErrFederationDisabled
+	}
+
+	sm.mu.Lock(sm.lg)
+	session, ok := sm.sessionsByName[req.SimName]
+	sm.mu.Unlock(sm.lg)
+	if !ok {
+		return ErrNoNamedSim
+	}
+	// As in ConnectToSim, bcrypt is slow enough that the credentials are
+	// checked without holding sm.mu.
+	if !session.checkPassword(req.Password) {
+		return ErrInvalidPassword
+	}
+	if err := sm.authenticate(req.Account, RoleInstructor); err != nil {
+		return err
+	}
+
+	session.mu.Lock(session.lg)
+	secret, ok := session.federationSecrets[req.Facility]
+	if ok && subtle.ConstantTimeCompare([]byte(secret), []byte(req.Secret)) == 1 {
+		session.federation[req.Facility] = time.Now()
+	} else {
+		ok = false
+	}
+	session.mu.Unlock(session.lg)
+	if !ok {
+		sm.lg.Warnf("%s: invalid federation secret for %s", req.SimName, req.Facility)
+		return ErrInvalidFederationSecret
+	}
+
+	if err := session.sim.FederateFacility(req.Facility); err != nil {
+		session.mu.Lock(session.lg)
+		delete(session.federation, req.Facility)
+		session.mu.Unlock(session.lg)
+		return err
+	}
+
+	token := sm.addFederationPeer(session, req.Facility)
+
+	session.lg.Infof("%s: federated peer linked", req.Facility)
+
+	*result = LinkFederatedPeerResult{
+		Token:    token,
+		Facility: session.sim.Facility(),
+	}
+	return nil
+}
+
+type federationPeer struct {
+	session  *simSession
+	facility string
+}
+
+// addFederationPeer returns a new token for the link between the session
+// and the given facility's sim. The other server links again after it
+// loses its connection, so any previous token for the link is revoked.
+func (sm *SimManager) addFederationPeer(session *simSession, facility string) string {
+	token := makeFederationToken()
+
+	sm.mu.Lock(sm.lg)
+	defer sm.mu.Unlock(sm.lg)
+
+	for t, peer := range sm.federationPeers {
+		if peer.session == session && peer.facility == facility {
+			delete(sm.federationPeers, t)
+		}
+	}
+	sm.federationPeers[token] = federationPeer{session: session, facility: facility}
+	return token
+}
+
+func makeFederationToken() string {
+	var buf [16]byte
+	if _, err := rand.Read(buf[:]); err != nil {
+		panic(err)
+	}
+	return base64.StdEncoding.EncodeToString(buf[:])
+}
+
+type NASExchangeRequest struct {
+	Token    string
+	Messages []sim.NASMessage
+	Ack      uint64 // Seq of the last message received from the other sim
+}
+
+type NASExchangeResult struct {
+	Messages []sim.NASMessage
+	Ack      uint64
+}
+
+const ExchangeNASMessagesRPC = "SimManager.ExchangeNASMessages"
+
+func (sm *SimManager) ExchangeNASMessages(req *NASExchangeRequest, result *NASExchangeResult) error {
+	defer sm.lg.CatchAndReportCrash()
+
+	sm.mu.Lock(sm.lg)
+	peer, ok := sm.federationPeers[req.Token]
+	sm.mu.Unlock(sm.lg)
+	if !ok {
+		return ErrInvalidFederationToken
+	}
+
+	session := peer.session
+	session.mu.Lock(session.lg)
+	_, ok = session.federation[peer.facility]
+	if ok {
+		session.federation[peer.facility] = time.Now()
+	}
+	session.mu.Unlock(session.lg)
+
+	if !ok {
+		// The link timed out; the other server will need to link again.
+		sm.mu.Lock(sm.lg)
+		delete(sm.federationPeers, req.Token)
+		sm.mu.Unlock(sm.lg)
+		return ErrInvalidFederationToken
+	}
+
+	if err := session.sim.ReceiveNASMessages(peer.facility, req.Messages); err != nil {
+		return err
+	}
+	session.sim.AcknowledgeNASMessages(peer.facility, req.Ack)
+	result.Messages, result.Ack = session.sim.PendingNASMessages(peer.facility)
+
+	return nil
+}
+
+///////////////////////////////////////////////////////////////////////////
+// Outbound links
+
+type LinkFederatedSimArgs struct {
+	ControllerToken string
+	Address         string // host:port of the other server
+	SimName         string
+	Password        string
+	Account         Credentials // used if the other server requires authentication
+	Facility        string      // facility of the other sim, e.g. "ZNY"
+	Secret          string      // set for this sim's facility in the other sim
+}
+
+const LinkFederatedSimRPC = "Sim.LinkFederatedSim"
+
+// LinkFederatedSim links the controller's sim to a sim for a neighboring
+// facility that is running on another server.
+func (sd *dispatcher) LinkFederatedSim(args *LinkFederatedSimArgs, _ *struct{}) error {
+	defer sd.sm.lg.CatchAndReportCrash()
+
+	c, err := sd.lookupController(args.ControllerToken, RoleInstructor)
+	if err != nil {
+		return err
+	}
+	if err := sd.sm.linkFederatedSim(c.session, args); err != nil {
+		return err
+	}
+	c.sim.GlobalMessage(c.tcw, fmt.Sprintf("%s (%s) has linked the sim to %s at %s", c.tcw, c.initials,
+		args.Facility, args.Address))
+	return nil
+}
+
+type SetFederationSecretArgs struct {
+	ControllerToken string
+	Facility        string // facility of the sim that will link, e.g. "N90"
+	Secret          string // if empty, the facility may no longer link
+}
+
+const SetFederationSecretRPC = "Sim.SetFederationSecret"
+
+// SetFederationSecret allows a sim for the given facility on another
+// server to link to the controller's sim if it gives the secret.
+func (sd *dispatcher) SetFederationSecret(args *SetFederationSecretArgs, _ *struct{}) error {
+	defer sd.sm.lg.CatchAndReportCrash()
+
+	c, err := sd.lookupController(args.ControllerToken, RoleInstructor)
+	if err != nil {
+		return err
+	} else if !sd.sm.federation {
+		return ErrFederationDisabled
+	}
+
+	session := c.session
+	session.mu.Lock(session.lg)
+	if args.Secret == "" {
+		delete(session.federationSecrets, args.Facility)
+	} else {
+		session.federationSecrets[args.Facility] = args.Secret
+	}
+	session.mu.Unlock(session.lg)
+
+	return nil
+}
+
+type federationLink struct {
+	session *simSession
+	args    LinkFederatedSimArgs
+	client  *rpc.Client
+	token   string
+}
+
+func (sm *SimManager) linkFederatedSim(session *simSession, args *LinkFederatedSimArgs) error {
+	if !sm.federation {
+		return ErrFederationDisabled
+	} else if !slices.Contains(sm.federationAddresses, args.Address) {
+		sm.lg.Warnf("%s: attempt to link to a server that isn't a federation peer", args.Address)
+		return ErrFederationPeerNotAllowed
+	}
+
+	session.mu.Lock(session.lg)
+	if _, ok := session.federation[args.Facility]; ok {
+		session.mu.Unlock(session.lg)
+		return ErrFederationLinkExists
+	}
+	session.federation[args.Facility] = time.Now()
+	session.mu.Unlock(session.lg)
+
+	link := &federationLink{session: session, args: *args}
+	link.args.ControllerToken = ""
+
+	err := session.sim.FederateFacility(args.Facility)
+	if err == nil {
+		// Do the first exchange synchronously so that problems with the
+		// address, password, etc., are reported to the instructor.
+		err = link.exchange()
+	}
+	if err != nil {
+		session.mu.Lock(session.lg)
+		delete(session.federation, args.Facility)
+		session.mu.Unlock(session.lg)
+		_ = session.sim.UnfederateFacility(args.Facility)
+		link.close()
+		return err
+	}
+
+	go sm.runFederationLink(link)
+
+	return nil
+}
+
+func (sm *SimManager) runFederationLink(link *federationLink) {
+	defer sm.lg.CatchAndReportCrash()
+	defer link.close()
+
+	session := link.session
+	fac := link.args.Facility
+	for {
+		time.Sleep(federationExchangeInterval)
+
+		sm.mu.Lock(sm.lg)
+		running := session.name == "" || sm.sessionsByName[session.name] == session
+		sm.mu.Unlock(sm.lg)
+
+		session.mu.Lock(session.lg)
+		_, linked := session.federation[fac]
+		session.mu.Unlock(session.lg)
+
+		if !running || !linked {
+			session.lg.Infof("%s: federation link to %s ended", fac, link.args.Address)
+			return
+		}
+
+		if err := link.exchange(); err != nil {
+			session.lg.Warnf("%s: %s: %v", fac, link.args.Address, err)
+			// Reconnect and link again the next time around.
+			link.close()
+		}
+	}
+}
+
+// exchange sends the messages the sim has queued for the federated
+// facility and processes the ones the other sim has queued for us.
+func (link *federationLink) exchange() error {
+	s := link.session.sim
+	fac := link.args.Facility
+
+	if link.client == nil {
+		if err := link.connect(); err != nil {
+			return err
+		}
+	}
+
+	// If the call fails, the messages stay queued and are sent again
+	// next time.
+	msgs, ack := s.PendingNASMessages(fac)
+	var result NASExchangeResult
+	if err := link.call(ExchangeNASMessagesRPC, &NASExchangeRequest{Token: link.token, Messages: msgs, Ack: ack}, &result); err != nil {
+		return err
+	}
+	s.AcknowledgeNASMessages(fac, result.Ack)
+
+	link.session.mu.Lock(link.session.lg)
+	if _, ok := link.session.federation[fac]; ok {
+		link.session.federation[fac] = time.Now()
+	}
+	link.session.mu.Unlock(link.session.lg)
+
+	return s.ReceiveNASMessages(fac, result.Messages)
+}
+
+func (link *federationLink) connect() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", link.args.Address)
+	if err != nil {
+		return err
+	}
+	cc, err := util.MakeCompressedConn(conn)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	codec := util.MakeMessagepackClientCodec(cc)
+	codec = util.MakeLoggingClientCodec(link.args.Address, codec, link.session.lg)
+	link.client = rpc.NewClientWithCodec(codec)
+
+	var result LinkFederatedPeerResult
+	if err := link.call(LinkFederatedPeerRPC, &LinkFederatedPeerRequest{
+		Version:  ViceRPCVersion,
+		SimName:  link.args.SimName,
+		Password: link.args.Password,
+		Account:  link.args.Account,
+		Facility: link.session.sim.Facility(),
+		Secret:   link.args.Secret,
+	}, &result); err != nil {
+		link.close()
+		return err
+	}
+	if result.Facility != link.args.Facility {
+		link.close()
+		return fmt.Errorf("%s: sim %q is for %s, not %s", link.args.Address, link.args.SimName,
+			result.Facility, link.args.Facility)
+	}
+	link.token = result.Token
+
+	return nil
+}
+
+func (link *federationLink) call(method string, args, reply any) error {
+	call := link.client.Go(method, args, reply, nil)
+	select {
+	case <-call.Done:
+		return TryDecodeError(call.Error)
+	case <-time.After(15 * time.Second):
+		return ErrRPCTimeout
+	}
+}
+
+func (link *federationLink) close() {
+	if link.client != nil {
+		link.client.Close()
+		link.client = nil
+	}
+	link.token = ""
+}
+
+// expireFederation unfederates facilities that messages haven't been
+// exchanged with recently. It's called periodically from the sim's
+// update loop.
+func (sm *SimManager) expireFederation(session *simSession) {
+	facs := session.sim.FederatedFacilities()
+	if len(facs) == 0 {
+		return
+	}
+
+	session.mu.Lock(session.lg)
+	var expired []string
+	for _, fac := range facs {
+		if t, ok := session.federation[fac]; !ok || time.Since(t) > federationTimeout {
+			delete(session.federation, fac)
+			expired = append(expired, fac)
+		}
+	}
+	session.mu.Unlock(session.lg)
+
+	for _, fac := range expired {
+		session.lg.Warnf("%s: federation link timed out", fac)
+		_ = session.sim.UnfederateFacility(fac)
+	}
+
+	if len(expired) > 0 {
+		sm.mu.Lock(sm.lg)
+		for token, peer := range sm.federationPeers {
+			if peer.session == session && slices.Contains(expired, peer.facility) {
+				delete(sm.federationPeers, token)
+			}
+		}
+		sm.mu.Unlock(sm.lg)
+	}
+}
@@ -0,0 +1,111 @@
+// server/federation_test.go
+// Copyright(c) 2022-2026 vice contributors, licensed under the GNU Public License, Version 3.
+// SPDX: GPL-3.0-only
+
+package server
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestLinkFederatedPeer(t *testing.T) {
+	sm := makeTestSimManager(t)
+	session := makeSimSession("test", "", "", nil, nil, sm.lg)
+	sm.sessionsByName["test"] = session
+
+	link := func(secret string) error {
+		var result LinkFederatedPeerResult
+		return sm.LinkFederatedPeer(&LinkFederatedPeerRequest{
+			Version:  ViceRPCVersion,
+			SimName:  "test",
+			Facility: "ZNY",
+			Secret:   secret,
+		}, &result)
+	}
+
+	// Federation is off by default.
+	if err := link("secret"); !errors.Is(err, ErrFederationDisabled) {
+		t.Errorf("got %v, expected ErrFederationDisabled", err)
+	}
+
+	sm.federation = true
+	// No secret has been set for ZNY.
+	if err := link(""); !errors.Is(err, ErrInvalidFederationSecret) {
+		t.Errorf("got %v, expected ErrInvalidFederationSecret", err)
+	}
+
+	session.federationSecrets["ZNY"] = "secret"
+	for _, secret := range []string{"", "Secret", "secret2"} {
+		if err := link(secret); !errors.Is(err, ErrInvalidFederationSecret) {
+			t.Errorf("secret %q: got %v, expected ErrInvalidFederationSecret", secret, err)
+		}
+	}
+	// The secret for another facility doesn't allow ZNY to link.
+	delete(session.federationSecrets, "ZNY")
+	session.federationSecrets["ZBW"] = "secret"
+	if err := link("secret"); !errors.Is(err, ErrInvalidFederationSecret) {
+		t.Errorf("got %v, expected ErrInvalidFederationSecret", err)
+	}
+
+	if len(session.federation) != 0 || len(sm.federationPeers) != 0 {
+		t.Errorf("rejected links should not be recorded: %v %v", session.federation, sm.federationPeers)
+	}
+
+	if err := sm.LinkFederatedPeer(&LinkFederatedPeerRequest{Version: ViceRPCVersion, SimName: "other"},
+		&LinkFederatedPeerResult{}); !errors.Is(err, ErrNoNamedSim) {
+		t.Errorf("got %v, expected ErrNoNamedSim", err)
+	}
+}
+
+func TestLinkFederatedSimAllowlist(t *testing.T) {
+	sm := makeTestSimManager(t)
+	session := makeSimSession("test", "", "", nil, nil, sm.lg)
+
+	args := &LinkFederatedSimArgs{Address: "evil.example.com:8000", SimName: "test", Facility: "ZNY"}
+	if err := sm.linkFederatedSim(session, args); !errors.Is(err, ErrFederationDisabled) {
+		t.Errorf("got %v, expected ErrFederationDisabled", err)
+	}
+
+	sm.federation = true
+	sm.federationAddresses = []string{"vice.example.com:8000"}
+	for _, addr := range []string{"evil.example.com:8000", "vice.example.com:8001", "127.0.0.1:22", ""} {
+		args.Address = addr
+		if err := sm.linkFederatedSim(session, args); !errors.Is(err, ErrFederationPeerNotAllowed) {
+			t.Errorf("%q: got %v, expected ErrFederationPeerNotAllowed", addr, err)
+		}
+	}
+	if len(session.federation) != 0 {
+		t.Errorf("rejected links should not be recorded: %v", session.federation)
+	}
+}
+
+func TestFederationPeerTokens(t *testing.T) {
+	sm := makeTestSimManager(t)
+	session := makeSimSession("test", "", "", nil, nil, sm.lg)
+
+	first := sm.addFederationPeer(session, "ZNY")
+	other := sm.addFederationPeer(session, "ZBW")
+	second := sm.addFederationPeer(session, "ZNY")
+
+	if first == second {
+		t.Fatalf("expected a new token when relinking")
+	}
+	if _, ok := sm.federationPeers[first]; ok {
+		t.Errorf("previous token for ZNY should have been revoked")
+	}
+	if peer, ok := sm.federationPeers[second]; !ok || peer.facility != "ZNY" {
+		t.Errorf("missing the latest token for ZNY")
+	}
+	if peer, ok := sm.federationPeers[other]; !ok || peer.facility != "ZBW" {
+		t.Errorf("token for ZBW should be unaffected")
+	}
+	if len(sm.federationPeers) != 2 {
+		t.Errorf("%d tokens, expected 2", len(sm.federationPeers))
+	}
+
+	var result NASExchangeResult
+	if err := sm.ExchangeNASMessages(&NASExchangeRequest{Token: first}, &result); !errors.Is(err, ErrInvalidFederationToken) {
+		t.Errorf("got %v, expected ErrInvalidFederationToken for a revoked token", err)
+	}
+}
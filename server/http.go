@@ -47,12 +47,16 @@ func (sm *SimManager) launchHTTPServer() {
 		sm.lg.Infof("%s: served stats request", r.URL.String())
 	})
 
+	mux.HandleFunc("/metrics", sm.metricsHandler)
+
 	mux.HandleFunc("/debug/pprof/", pprof.Index)
 	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
 	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
 	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
 	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
 
+	sm.registerAPIHandlers(mux)
+
 	var listener net.Listener
 	var err error
 	var port int
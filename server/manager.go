@@ -14,6 +14,7 @@ import (
 	"path/filepath"
 	"slices"
 	"strings"
+	"sync"
 	"time"
 
 	av "github.com/mmp/vice/aviation"
@@ -63,6 +64,12 @@ type SimManager struct {
 	sessionsByName  map[string]*simSession
 	sessionsByToken map[string]*simSession
 
+	// Sims that will be started in the future; see schedule.go.
+	scheduledSims map[string]*scheduledSim
+	// scheduledSimFilesMu serializes writing and removing their files in
+	// the state directory; it must be acquired before mu.
+	scheduledSimFilesMu sync.Mutex
+
 	// Helpers and such
 	wxProvider     *wx.Provider
 	providersReady chan struct{}
@@ -74,6 +81,27 @@ type SimManager struct {
 	startTime time.Time
 	httpPort  int
 	local     bool
+	recordDir string // if set, sims are recorded to files in this directory
+	stateDir  string // if set, sims are persisted to this directory; see persist.go
+
+	// auth, if non-nil, authenticates users creating and joining sims;
+	// see auth.go.
+	auth Authenticator
+
+	// Origins of web pages that may make cross-origin API requests; see
+	// api.go.
+	apiOrigins []string
+
+	metrics *serverMetrics // see metrics.go
+
+	// Whether sims here may be linked with sims on other servers and the
+	// addresses of the servers they may link to; see federation.go.
+	federation          bool
+	federationAddresses []string
+
+	// Sims on other servers that have linked to sims here, indexed by the
+	// token returned by LinkFederatedPeer; see federation.go.
+	federationPeers map[string]federationPeer
 }
 
 // Client-side info about the available scenarios.
@@ -136,20 +164,31 @@ func (sm *SimManager) loadBrief(facility string) (string, error) {
 
 func NewSimManager(scenarioGroups map[string]map[string]*scenarioGroup, scenarioCatalogs map[string]map[string]*ScenarioCatalog,
 	mapSpecs map[string]*av.MapLibrarySpec, briefs *briefRegistry,
-	serverAddress string, isLocal bool, lg *log.Logger) *SimManager {
+	serverAddress string, isLocal bool, recordDir string, stateDir string, auth Authenticator, apiOrigins []string,
+	federation bool, federationAddresses []string, lg *log.Logger) *SimManager {
 	sm := &SimManager{
 		scenarioGroups:   scenarioGroups,
 		scenarioCatalogs: scenarioCatalogs,
 		sessionsByName:   make(map[string]*simSession),
 		sessionsByToken:  make(map[string]*simSession),
+		scheduledSims:    make(map[string]*scheduledSim),
 		mapSpecs:         mapSpecs,
 		briefs:           briefs,
 		// LoadScenarioGroups already validated emergencies.json; re-parse to hand the list to sim sessions.
 		emergencies:    loadEmergencies(nil),
 		startTime:      time.Now(),
 		local:          isLocal,
+		recordDir:      recordDir,
+		stateDir:       util.Select(isLocal, "", stateDir),
+		auth:           auth,
+		apiOrigins:     apiOrigins,
+		metrics:        makeServerMetrics(),
 		providersReady: make(chan struct{}),
 		lg:             lg,
+
+		federation:          federation && !isLocal,
+		federationAddresses: federationAddresses,
+		federationPeers:     make(map[string]federationPeer),
 	}
 
 	// Initialize WX provider asynchronously so the server can start
@@ -162,6 +201,14 @@ func NewSimManager(scenarioGroups map[string]map[string]*scenarioGroup, scenario
 
 	sm.launchHTTPServer()
 
+	if sm.stateDir != "" {
+		sm.restoreScheduledSims()
+		go sm.restoreSessions()
+	}
+	if !isLocal {
+		go sm.runScheduler()
+	}
+
 	return sm
 }
 
@@ -193,6 +240,9 @@ type NewSimRequest struct {
 
 	Initials   string // Controller initials (e.g., "XX")
 	Privileged bool
+
+	// Account is only used if the server requires authentication.
+	Account Credentials
 }
 
 func MakeNewSimRequest() NewSimRequest {
@@ -221,6 +271,7 @@ type SimState struct {
 	VideoMapLibraryHashes               map[string][]byte
 
 	UserIsPrivileged bool // Whether this user has elevated privileges (can control any aircraft)
+	UserIsObserver   bool // Whether this user is only observing the TCW; see JoinSimRequest
 
 	FlightStripACIDs []sim.ACID
 }
@@ -236,11 +287,22 @@ const NewSimRPC = "SimManager.NewSim"
 func (sm *SimManager) NewSim(req *NewSimRequest, result *NewSimResult) error {
 	lg := sm.lg.With(slog.String("sim_name", req.NewSimName))
 
+	role := requestedRole(req.Privileged, false)
+	if err := sm.authenticate(req.Account, role); err != nil {
+		return err
+	}
+
+	hash, err := hashSimPassword(req.Password)
+	if err != nil {
+		lg.Warnf("unable to hash password: %v", err)
+		return err
+	}
+
 	if nsc := sm.makeSimConfiguration(req, lg); nsc != nil {
 		s := sim.NewSim(*nsc, lg)
-		session := makeSimSession(req.NewSimName, req.GroupName, req.ScenarioName, req.Password, s, sm.lg)
+		session := makeSimSession(req.NewSimName, req.GroupName, req.ScenarioName, hash, s, sm.lg)
 		pos := s.ScenarioRootPosition()
-		return sm.Add(session, result, pos, req.Initials, req.Privileged, true)
+		return sm.Add(session, result, pos, req.Initials, role, req.Privileged, true)
 	} else {
 		return ErrInvalidSimConfiguration
 	}
@@ -305,11 +367,13 @@ func (sm *SimManager) makeSimConfiguration(req *NewSimRequest, lg *log.Logger) *
 		VirtualControllers:          sc.VirtualControllers,
 		ControllerConfiguration:     &sc.ControllerConfiguration,
 		ConfigurationId:             sc.ConfigurationString,
+		Scenario:                    req.ScenarioName,
 		WXProvider:                  wxp,
 		Emergencies:                 sm.emergencies,
 		StartTime:                   req.StartTime,
 		HandoffIDs:                  sg.FacilityConfig.HandoffIDs,
 		FixPairs:                    sg.FacilityConfig.FixPairs,
+		Timeline:                    sc.Timeline,
 	}
 
 	// Look up historical TFRs for this facility and time.
@@ -332,6 +396,66 @@ func (sm *SimManager) makeSimConfiguration(req *NewSimRequest, lg *log.Logger) *
 	return &nsc
 }
 
+// makeConfigurationSwitch returns the runway configuration of another
+// scenario from the session's scenario group so that the running sim can
+// be switched to it.
+func (sm *SimManager) makeConfigurationSwitch(session *simSession, scenarioName string) (sim.ConfigurationSwitch, error) {
+	sg, cur := sm.sessionScenarioGroup(session)
+	if sg == nil {
+		return sim.ConfigurationSwitch{}, ErrUnknownScenario
+	}
+	sc, ok := sg.Scenarios[scenarioName]
+	if !ok || sc.ConfigurationString == "" {
+		return sim.ConfigurationSwitch{}, ErrUnknownScenario
+	}
+	if !scenariosCompatible(sg.Scenarios[cur], sc) {
+		return sim.ConfigurationSwitch{}, ErrIncompatibleScenario
+	}
+
+	return sim.ConfigurationSwitch{
+		ConfigurationId:      sc.ConfigurationString,
+		Scenario:             scenarioName,
+		DepartureRunways:     sc.DepartureRunways,
+		ArrivalRunways:       sc.ArrivalRunways,
+		InboundAssignments:   sc.ControllerConfiguration.InboundAssignments,
+		DepartureAssignments: sc.ControllerConfiguration.DepartureAssignments,
+		GoAroundAssignments:  sc.ControllerConfiguration.GoAroundAssignments,
+		LaunchConfig:         CreateLaunchConfig(sc, sg),
+	}, nil
+}
+
+// switchableScenarios returns the scenario the session is running and the
+// other scenarios from its scenario group that it can be switched to.
+func (sm *SimManager) switchableScenarios(session *simSession) (string, []string) {
+	sg, cur := sm.sessionScenarioGroup(session)
+	if sg == nil {
+		return cur, nil
+	}
+	var names []string
+	for name, sc := range util.SortedMap(sg.Scenarios) {
+		if name != cur && sc.ConfigurationString != "" && scenariosCompatible(sg.Scenarios[cur], sc) {
+			names = append(names, name)
+		}
+	}
+	return cur, names
+}
+
+func (sm *SimManager) sessionScenarioGroup(session *simSession) (*scenarioGroup, string) {
+	session.mu.Lock(session.lg)
+	groupName, cur := session.scenarioGroup, session.scenario
+	session.mu.Unlock(session.lg)
+
+	return sm.scenarioGroups[session.sim.Facility()][groupName], cur
+}
+
+// scenariosCompatible reports whether a running sim can be switched from
+// one scenario to the other. The controllers stay signed in to their
+// positions, so both must use the same ones.
+func scenariosCompatible(from, to *scenario) bool {
+	return from == nil || slices.Equal(from.ControllerConfiguration.DefaultConsolidation.AllPositions(),
+		to.ControllerConfiguration.DefaultConsolidation.AllPositions())
+}
+
 type JoinSimRequest struct {
 	SimName         string
 	TCW             sim.TCW   // Which TCW to sign into
@@ -340,28 +464,55 @@ type JoinSimRequest struct {
 	Password        string
 	Privileged      bool
 	JoiningAsRelief bool
+	// Observing joins the sim to watch the TCW's scope and hear its radio
+	// traffic without signing in to it or being able to issue commands.
+	Observing bool
+	Account   Credentials // only used if the server requires authentication
 }
 
 const ConnectToSimRPC = "SimManager.ConnectToSim"
 
 func (sm *SimManager) ConnectToSim(req *JoinSimRequest, result *NewSimResult) error {
 	sm.mu.Lock(sm.lg)
-	defer sm.mu.Unlock(sm.lg)
-
 	session, ok := sm.sessionsByName[req.SimName]
+	sm.mu.Unlock(sm.lg)
 	if !ok {
 		return ErrNoNamedSim
 	}
 
-	if session.password != "" && req.Password != session.password {
+	// Checking bcrypt hashes is slow, so it's done without holding sm.mu
+	// so that other RPCs aren't held up.
+	if !session.checkPassword(req.Password) {
 		return ErrInvalidPassword
 	}
 
+	role := requestedRole(req.Privileged, req.Observing)
+	if err := sm.authenticate(req.Account, role); err != nil {
+		return err
+	}
+
+	sm.mu.Lock(sm.lg)
+	defer sm.mu.Unlock(sm.lg)
+
+	// Make sure the sim didn't end while we weren't holding the lock.
+	if sm.sessionsByName[req.SimName] != session {
+		return ErrNoNamedSim
+	}
+
 	tcw := req.TCW
 
 	var token string
 	var eventSub *sim.EventsSubscription
-	if req.JoiningAsRelief {
+	if req.Observing {
+		// Observers don't sign in and don't occupy the TCW; they just
+		// receive its state updates and events.
+		if _, ok := session.sim.GetCurrentConsolidation()[tcw]; !ok {
+			return av.ErrNoController
+		}
+		token = sm.makeControllerToken()
+		eventSub = session.sim.Subscribe()
+		session.lg.Infof("%s (%s): observing", tcw, req.Initials)
+	} else if req.JoiningAsRelief {
 		// Relief mode: don't call sim.SignOn (position already signed in)
 		// Just generate a token for this user
 		token = sm.makeControllerToken()
@@ -373,6 +524,11 @@ func (sm *SimManager) ConnectToSim(req *JoinSimRequest, result *NewSimResult) er
 		if err := sm.checkTCWAvailable(session, tcw); err != nil {
 			return err
 		}
+		if !req.Privileged {
+			if err := session.checkReservations(tcw, req.SelectedTCPs, req.Initials); err != nil {
+				return err
+			}
+		}
 
 		// Normal sign-in: call sim.SignOn
 		var err error
@@ -382,7 +538,7 @@ func (sm *SimManager) ConnectToSim(req *JoinSimRequest, result *NewSimResult) er
 		}
 	}
 
-	session.AddHumanController(token, tcw, req.Initials, eventSub)
+	session.AddHumanController(token, tcw, req.Initials, role, eventSub)
 	sm.sessionsByToken[token] = session
 
 	*result = *sm.buildNewSimResult(session, tcw, token)
@@ -409,7 +565,7 @@ func (sm *SimManager) checkTCWAvailable(ss *simSession, tcw sim.TCW) error {
 	defer ss.mu.Unlock(ss.lg)
 
 	for _, conn := range ss.connectionsByToken {
-		if conn.tcw == tcw {
+		if conn.tcw == tcw && !conn.observing() {
 			return ErrTCWAlreadyOccupied
 		}
 	}
@@ -445,6 +601,7 @@ func (sm *SimManager) buildNewSimResult(session *simSession, tcw sim.TCW, token
 			ControllerVideoMapFile:              vmFile,
 			VideoMapLibraryHashes:               hashes,
 			UserIsPrivileged:                    session.sim.TCWIsPrivileged(tcw),
+			UserIsObserver:                      session.isObserver(token),
 		},
 		ControllerToken: token,
 	}
@@ -462,11 +619,11 @@ func (sm *SimManager) AddLocal(req *AddLocalRequest, result *NewSimResult) error
 	if !sm.local {
 		sm.lg.Errorf("Called AddLocal with sm.local == false")
 	}
-	return sm.Add(session, result, req.Sim.ScenarioRootPosition(), req.Initials, false, false)
+	return sm.Add(session, result, req.Sim.ScenarioRootPosition(), req.Initials, RoleController, false, false)
 }
 
-func (sm *SimManager) Add(session *simSession, result *NewSimResult, initialTCP sim.ControlPosition, initials string, instructor bool,
-	prespawn bool) error {
+func (sm *SimManager) Add(session *simSession, result *NewSimResult, initialTCP sim.ControlPosition, initials string, role Role,
+	instructor bool, prespawn bool) error {
 	wxp := sm.getWXProvider()
 	session.sim.Activate(session.lg, wxp)
 
@@ -493,17 +650,13 @@ func (sm *SimManager) Add(session *simSession, result *NewSimResult, initialTCP
 		return err
 	}
 
-	session.AddHumanController(token, tcw, initials, eventSub)
+	session.AddHumanController(token, tcw, initials, role, eventSub)
 	sm.sessionsByToken[token] = session
 
 	sm.mu.Unlock(sm.lg)
 
 	// Run prespawn after the root controller is signed in.
-	if prespawn {
-		session.sim.Prespawn()
-	}
-
-	go sm.runSimUpdateLoop(session)
+	sm.runSession(session, prespawn)
 
 	// buildNewSimResult only reads init-immutable sm fields and goes
 	// through Sim accessors that take their own lock — no sm.mu needed.
@@ -517,6 +670,9 @@ func (sm *SimManager) Add(session *simSession, result *NewSimResult, initialTCP
 func (sm *SimManager) runSimUpdateLoop(session *simSession) {
 	defer sm.lg.CatchAndReportCrash()
 
+	persist := sm.stateDir != "" && session.name != ""
+	lastPersist := time.Now()
+
 	// Terminate idle Sims after 4 hours, but not local Sims.
 	const simIdleLimit = 4 * time.Hour
 	for sm.local || session.sim.IdleTime() < simIdleLimit {
@@ -524,7 +680,19 @@ func (sm *SimManager) runSimUpdateLoop(session *simSession) {
 			session.CullIdleControllers(sm)
 		}
 
+		start := time.Now()
 		session.sim.Update()
+		d := time.Since(start)
+		session.lastUpdateDuration.Store(int64(d))
+		sm.metrics.observeSimUpdate(d)
+
+		sm.expireFederation(session)
+		session.UpdateLobby()
+
+		if persist && time.Since(lastPersist) > persistInterval {
+			sm.persistSession(session)
+			lastPersist = time.Now()
+		}
 
 		time.Sleep(100 * time.Millisecond)
 	}
@@ -532,6 +700,9 @@ func (sm *SimManager) runSimUpdateLoop(session *simSession) {
 	sm.lg.Infof("%s: terminating sim after %s idle", session.name, session.sim.IdleTime())
 
 	session.sim.Destroy()
+	if persist {
+		sm.removePersistedSession(session)
+	}
 
 	sm.mu.Lock(sm.lg)
 	// Clean up all controllers for this sim
@@ -540,10 +711,34 @@ func (sm *SimManager) runSimUpdateLoop(session *simSession) {
 			delete(sm.sessionsByToken, token)
 		}
 	}
+	for token, peer := range sm.federationPeers {
+		if peer.session == session {
+			delete(sm.federationPeers, token)
+		}
+	}
 	delete(sm.sessionsByName, session.name)
 	sm.mu.Unlock(sm.lg)
 }
 
+// startRecording starts recording the session's sim to a new file in the
+// record directory. Failures are logged but are otherwise not fatal.
+func (sm *SimManager) startRecording(session *simSession) {
+	name := util.Select(session.name != "", session.name, "local")
+	fn := filepath.Join(sm.recordDir, name+"-"+time.Now().Format("20060102-150405")+".vicerec")
+
+	f, err := os.Create(fn)
+	if err != nil {
+		sm.lg.Errorf("%s: unable to create recording file: %v", fn, err)
+		return
+	}
+	if err := session.sim.StartRecording(f, rand.Make().Uint64()); err != nil {
+		sm.lg.Errorf("%s: unable to start recording: %v", fn, err)
+		f.Close()
+		return
+	}
+	sm.lg.Infof("%s: recording sim to %s", session.name, fn)
+}
+
 ///////////////////////////////////////////////////////////////////////////
 // Session Management - Sign On/Off
 
@@ -567,8 +762,9 @@ func (sm *SimManager) signOff(token string) error {
 		return ErrNoSimForControllerToken
 	}
 
-	// If this was the last user at the TCW, post messages and clear privileges
-	if result.UsersAtTCW == 0 {
+	// If this was the last user at the TCW, post messages and clear
+	// privileges. Observers come and go quietly.
+	if !result.Observer && result.UsersAtTCW == 0 {
 		// Get positions for the uncovered message
 		uncoveredPositions := session.sim.GetPositionsForTCW(result.TCW)
 
@@ -602,6 +798,22 @@ func (sm *SimManager) signOff(token string) error {
 	return nil
 }
 
+// runSession starts running a session that has been added to
+// sessionsByName.
+func (sm *SimManager) runSession(session *simSession, prespawn bool) {
+	if prespawn {
+		session.sim.Prespawn()
+	}
+
+	// Start recording after prespawn so that the recording's initial
+	// state already has the prespawned aircraft.
+	if sm.recordDir != "" {
+		sm.startRecording(session)
+	}
+
+	go sm.runSimUpdateLoop(session)
+}
+
 // assume SimManager lock is held
 func (sm *SimManager) signOn(ss *simSession, req *JoinSimRequest) (string, *sim.EventsSubscription, error) {
 	_, eventSub, err := ss.sim.SignOn(req.TCW, req.SelectedTCPs)
@@ -634,6 +846,7 @@ type ConnectResult struct {
 	ScenarioCatalogs      map[string]map[string]*ScenarioCatalog
 	RunningSims           map[string]*RunningSim
 	AvailableWXByFacility map[string][]util.TimeInterval
+	RequiresAccount       bool // whether users must authenticate to create or join sims
 }
 
 const ConnectRPC = "SimManager.Connect"
@@ -656,6 +869,7 @@ func (sm *SimManager) Connect(version int, result *ConnectResult) error {
 	defer sm.mu.Unlock(sm.lg)
 
 	result.ScenarioCatalogs = sm.scenarioCatalogs
+	result.RequiresAccount = sm.auth != nil
 
 	return nil
 }
@@ -673,6 +887,9 @@ type RunningSim struct {
 	RequirePassword              bool
 	ScenarioDefaultConsolidation map[sim.TCP][]sim.TCP
 	CurrentConsolidation         map[sim.TCW]TCPConsolidation
+	// Reservations gives the initials of the controllers that TCWs are
+	// reserved for in scheduled sims that are waiting for them to sign in.
+	Reservations map[sim.TCW]string
 }
 
 const GetRunningSimsRPC = "SimManager.GetRunningSims"
@@ -686,10 +903,11 @@ func (sm *SimManager) GetRunningSims(_ int, result *map[string]*RunningSim) erro
 		running[name] = &RunningSim{
 			GroupName:                    ss.scenarioGroup,
 			ScenarioName:                 ss.scenario,
-			RequirePassword:              ss.password != "",
+			RequirePassword:              ss.requiresPassword(),
 			ScenarioDefaultConsolidation: ss.sim.ScenarioDefaultConsolidation,
 			CurrentConsolidation:         ss.GetCurrentConsolidation(),
 		}
+		running[name].Reservations, _ = ss.lobbyState()
 	}
 
 	*result = running
@@ -702,6 +920,7 @@ type controllerContext struct {
 	token    string
 	tcw      sim.TCW
 	initials string
+	role     Role
 	sim      *sim.Sim
 	eventSub *sim.EventsSubscription
 	session  *simSession
@@ -721,7 +940,7 @@ func (sm *SimManager) lookupController(token string) *controllerContext {
 	return nil
 }
 
-func (sm *SimManager) GetStateUpdate(token string) (*SimStateUpdate, error) {
+func (sm *SimManager) GetStateUpdate(token string, generation int) (*SimStateUpdate, error) {
 	sm.mu.Lock(sm.lg)
 	session, ok := sm.sessionsByToken[token]
 	if !ok {
@@ -730,29 +949,49 @@ func (sm *SimManager) GetStateUpdate(token string) (*SimStateUpdate, error) {
 	}
 	sm.mu.Unlock(sm.lg)
 
-	return session.GetStateUpdate(token)
+	return session.GetStateUpdate(token, generation)
 }
 
 // SimStateUpdate wraps sim.StateUpdate and adds server-specific fields.
 type SimStateUpdate struct {
 	sim.StateUpdate
 
+	// Delta, if non-nil, holds the changes to the tracks and unassociated
+	// flight plans since the update at Delta.BaseGeneration; the
+	// StateUpdate's Tracks and UnassociatedFlightPlans are then unset.
+	Delta *sim.StateDelta
+
 	ActiveTCWs []sim.TCW
 	Events     []sim.Event
 }
 
 // Apply applies the update to the state, including server-specific fields.
-// The caller is responsible for delivering su.Events to consumers.
-func (su *SimStateUpdate) Apply(state *SimState) {
+// The caller is responsible for delivering su.Events to consumers. It
+// returns true if the update's sim state replaced the state's; it is not
+// applied if it is stale or if it is a delta relative to some other
+// generation than the state's.
+func (su *SimStateUpdate) Apply(state *SimState) bool {
+	state.ActiveTCWs = su.ActiveTCWs
+	state.FlightStripACIDs = su.FlightStripACIDs
+
 	// Make sure the generation index is above the current index so that if
 	// updates are returned out of order we ignore stale ones.
-	if state.GenerationIndex < su.GenerationIndex {
+	if state.GenerationIndex >= su.GenerationIndex {
+		return false
+	}
+
+	if su.Delta == nil {
 		state.DynamicState = su.DynamicState
 		state.DerivedState = su.DerivedState
+	} else if su.Delta.BaseGeneration == state.GenerationIndex {
+		tracks, fps := su.Delta.Apply(state.Tracks, state.UnassociatedFlightPlans)
+		state.DynamicState = su.DynamicState
+		state.DerivedState = su.DerivedState
+		state.Tracks, state.UnassociatedFlightPlans = tracks, fps
+	} else {
+		return false
 	}
-
-	state.ActiveTCWs = su.ActiveTCWs
-	state.FlightStripACIDs = su.FlightStripACIDs
+	return true
 }
 
 // GetStateUpdate fills in a server.SimStateUpdate with both sim state and human controllers.
@@ -850,21 +1089,35 @@ func (sm *SimManager) ReloadScenarioBrief(args ReloadScenarioBriefArgs, result *
 // Admin
 
 type BroadcastMessage struct {
+	Username string // only used if the server requires authentication
 	Password string
 	Message  string
 }
 
 const BroadcastRPC = "SimManager.Broadcast"
 
+// Broadcast sends a message to all running sims. If the server requires
+// authentication, the user must be an admin; otherwise the password must
+// match the contents of the file "password".
 func (sm *SimManager) Broadcast(m *BroadcastMessage, _ *struct{}) error {
-	pw, err := os.ReadFile("password")
-	if err != nil {
-		return err
-	}
+	if sm.auth != nil {
+		acct, err := sm.auth.Authenticate(Credentials{Username: m.Username, Password: m.Password})
+		if err != nil {
+			return err
+		}
+		if acct.Role < RoleAdmin {
+			return ErrInsufficientRole
+		}
+	} else {
+		pw, err := os.ReadFile("password")
+		if err != nil {
+			return err
+		}
 
-	password := strings.TrimRight(string(pw), "\n\r")
-	if password != m.Password {
-		return ErrInvalidPassword
+		password := strings.TrimRight(string(pw), "\n\r")
+		if password != m.Password {
+			return ErrInvalidPassword
+		}
 	}
 
 	sm.mu.Lock(sm.lg)
@@ -0,0 +1,28 @@
+// server/manager_test.go
+// Copyright(c) 2022-2026 vice contributors, licensed under the GNU Public License, Version 3.
+// SPDX: GPL-3.0-only
+
+package server
+
+import (
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/mmp/vice/log"
+)
+
+// makeTestSimManager returns a SimManager with no scenarios or running
+// sims that doesn't start the HTTP server or any background goroutines.
+func makeTestSimManager(t *testing.T) *SimManager {
+	t.Helper()
+
+	return &SimManager{
+		sessionsByName:  make(map[string]*simSession),
+		sessionsByToken: make(map[string]*simSession),
+		scheduledSims:   make(map[string]*scheduledSim),
+		metrics:         makeServerMetrics(),
+		lg:              &log.Logger{Logger: slog.New(slog.NewTextHandler(io.Discard, nil))},
+		federationPeers: make(map[string]federationPeer),
+	}
+}
@@ -0,0 +1,388 @@
+// server/metrics.go
+// Copyright(c) 2022-2026 vice contributors, licensed under the GNU Public License, Version 3.
+// SPDX: GPL-3.0-only
+
+package server
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"net/rpc"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mmp/vice/util"
+)
+
+///////////////////////////////////////////////////////////////////////////
+// Metrics
+
+// The HTTP server's /metrics endpoint exports the server's metrics in the
+// Prometheus text exposition format so that they can be scraped and
+// alerted on. Per-sim metrics are labeled with the sim's name and are
+// generated from the running sims when the endpoint is fetched, so they go
+// away when the sim does.
+
+// Upper bounds of the buckets, in seconds, for histograms of durations.
+var durationBuckets = []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+type histogram struct {
+	counts []uint64 // per-bucket, not cumulative; the last is for +Inf
+	sum    float64
+	count  uint64
+}
+
+func (h *histogram) observe(d time.Duration) {
+	if h.counts == nil {
+		h.counts = make([]uint64, len(durationBuckets)+1)
+	}
+	s := d.Seconds()
+	i := 0
+	for i < len(durationBuckets) && s > durationBuckets[i] {
+		i++
+	}
+	h.counts[i]++
+	h.sum += s
+	h.count++
+}
+
+type rpcMetrics struct {
+	duration histogram
+	errors   uint64
+}
+
+// serverMetrics holds the metrics that are accumulated as the server runs;
+// the rest are computed when they are requested.
+type serverMetrics struct {
+	mu sync.Mutex
+
+	rpcs            map[string]*rpcMetrics // service method ->
+	simUpdate       histogram
+	ttsRequests     map[string]uint64 // request type ->
+	ttsFailures     map[string]uint64
+	sttCommands     uint64
+	sttCommandFails uint64
+}
+
+// metricsSnapshot is a copy of serverMetrics' counters taken under its
+// mutex so that they can be written out without holding it.
+type metricsSnapshot struct {
+	rpcs            map[string]rpcMetrics
+	simUpdate       histogram
+	ttsRequests     map[string]uint64
+	ttsFailures     map[string]uint64
+	sttCommands     uint64
+	sttCommandFails uint64
+}
+
+func makeServerMetrics() *serverMetrics {
+	return &serverMetrics{
+		rpcs:        make(map[string]*rpcMetrics),
+		ttsRequests: make(map[string]uint64),
+		ttsFailures: make(map[string]uint64),
+	}
+}
+
+func (m *serverMetrics) observeRPC(method string, d time.Duration, failed bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	r, ok := m.rpcs[method]
+	if !ok {
+		r = &rpcMetrics{}
+		m.rpcs[method] = r
+	}
+	r.duration.observe(d)
+	if failed {
+		r.errors++
+	}
+}
+
+func (m *serverMetrics) observeSimUpdate(d time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.simUpdate.observe(d)
+}
+
+// countTTSRequest records a request for text to be synthesized by the
+// client; typ is "readback" or "contact".
+func (m *serverMetrics) countTTSRequest(typ string, failed bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.ttsRequests[typ]++
+	if failed {
+		m.ttsFailures[typ]++
+	}
+}
+
+// countSTTCommand records a command that was transcribed by the client's
+// speech-to-text and whether running it failed.
+func (m *serverMetrics) countSTTCommand(failed bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.sttCommands++
+	if failed {
+		m.sttCommandFails++
+	}
+}
+
+func (h histogram) clone() histogram {
+	h.counts = slices.Clone(h.counts)
+	return h
+}
+
+func (m *serverMetrics) snapshot() metricsSnapshot {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	snap := metricsSnapshot{
+		rpcs:            make(map[string]rpcMetrics, len(m.rpcs)),
+		simUpdate:       m.simUpdate.clone(),
+		ttsRequests:     maps.Clone(m.ttsRequests),
+		ttsFailures:     maps.Clone(m.ttsFailures),
+		sttCommands:     m.sttCommands,
+		sttCommandFails: m.sttCommandFails,
+	}
+	for method, r := range m.rpcs {
+		snap.rpcs[method] = rpcMetrics{duration: r.duration.clone(), errors: r.errors}
+	}
+	return snap
+}
+
+///////////////////////////////////////////////////////////////////////////
+// RPC instrumentation
+
+// metricsServerCodec wraps an rpc.ServerCodec to record the latency of
+// each RPC, from when its request is read to when its response is written.
+type metricsServerCodec struct {
+	rpc.ServerCodec
+	metrics *serverMetrics
+
+	mu      sync.Mutex
+	pending map[uint64]time.Time // request sequence number -> start time
+}
+
+func (m *serverMetrics) instrumentCodec(c rpc.ServerCodec) rpc.ServerCodec {
+	return &metricsServerCodec{
+		ServerCodec: c,
+		metrics:     m,
+		pending:     make(map[uint64]time.Time),
+	}
+}
+
+func (c *metricsServerCodec) ReadRequestHeader(r *rpc.Request) error {
+	err := c.ServerCodec.ReadRequestHeader(r)
+	if err == nil {
+		c.mu.Lock()
+		c.pending[r.Seq] = time.Now()
+		c.mu.Unlock()
+	}
+	return err
+}
+
+func (c *metricsServerCodec) WriteResponse(r *rpc.Response, body any) error {
+	c.mu.Lock()
+	start, ok := c.pending[r.Seq]
+	delete(c.pending, r.Seq)
+	c.mu.Unlock()
+
+	// Errors from net/rpc itself, e.g. for unknown methods, aren't
+	// recorded so that arbitrary method names don't become labels.
+	if ok && !strings.HasPrefix(r.Error, "rpc: ") {
+		c.metrics.observeRPC(r.ServiceMethod, time.Since(start), r.Error != "")
+	}
+	return c.ServerCodec.WriteResponse(r, body)
+}
+
+///////////////////////////////////////////////////////////////////////////
+// Exposition
+
+type metricsWriter struct {
+	w io.Writer
+}
+
+var labelValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func formatLabels(labels []string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteByte('{')
+	for i := 0; i+1 < len(labels); i += 2 {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(labels[i] + `="` + labelValueEscaper.Replace(labels[i+1]) + `"`)
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+func formatValue(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+func (mw metricsWriter) header(name, typ, help string) {
+	fmt.Fprintf(mw.w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+}
+
+// sample writes a sample of the metric; labels are given as alternating
+// names and values.
+func (mw metricsWriter) sample(name string, v float64, labels ...string) {
+	fmt.Fprintf(mw.w, "%s%s %s\n", name, formatLabels(labels), formatValue(v))
+}
+
+func (mw metricsWriter) histogram(name string, h *histogram, labels ...string) {
+	var cumulative uint64
+	for i, ub := range durationBuckets {
+		if h.counts != nil {
+			cumulative += h.counts[i]
+		}
+		mw.sample(name+"_bucket", float64(cumulative), slices.Concat(labels, []string{"le", formatValue(ub)})...)
+	}
+	mw.sample(name+"_bucket", float64(h.count), slices.Concat(labels, []string{"le", "+Inf"})...)
+	mw.sample(name+"_sum", h.sum, labels...)
+	mw.sample(name+"_count", float64(h.count), labels...)
+}
+
+type simMetrics struct {
+	name                 string
+	controllers          int
+	observers            int
+	ifr, vfr             int
+	idle, lastUpdate     time.Duration
+	pendingReconnections int
+}
+
+func (sm *SimManager) getSimMetrics() []simMetrics {
+	sm.mu.Lock(sm.lg)
+	defer sm.mu.Unlock(sm.lg)
+
+	var sims []simMetrics
+	for name, ss := range util.SortedMap(sm.sessionsByName) {
+		if name == "" {
+			// Local sims aren't interesting.
+			continue
+		}
+		ifr, vfr := ss.sim.GetTrafficCounts()
+		controllers, observers, reconnections := ss.connectionCounts()
+		sims = append(sims, simMetrics{
+			name:                 name,
+			controllers:          controllers,
+			observers:            observers,
+			ifr:                  ifr,
+			vfr:                  vfr,
+			idle:                 ss.sim.IdleTime(),
+			lastUpdate:           time.Duration(ss.lastUpdateDuration.Load()),
+			pendingReconnections: reconnections,
+		})
+	}
+	return sims
+}
+
+func (sm *SimManager) metricsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	mw := metricsWriter{w: w}
+
+	mw.header("vice_uptime_seconds", "gauge", "Time since the server started.")
+	mw.sample("vice_uptime_seconds", time.Since(sm.startTime).Seconds())
+
+	var ms runtime.MemStats
+	runtime.ReadMemStats(&ms)
+	mw.header("vice_goroutines", "gauge", "Number of running goroutines.")
+	mw.sample("vice_goroutines", float64(runtime.NumGoroutine()))
+	mw.header("vice_memory_alloc_bytes", "gauge", "Bytes of allocated heap objects.")
+	mw.sample("vice_memory_alloc_bytes", float64(ms.Alloc))
+	mw.header("vice_memory_sys_bytes", "gauge", "Bytes of memory obtained from the OS.")
+	mw.sample("vice_memory_sys_bytes", float64(ms.Sys))
+	mw.header("vice_gc_cycles_total", "counter", "Number of completed GC cycles.")
+	mw.sample("vice_gc_cycles_total", float64(ms.NumGC))
+
+	rx, tx := util.GetLoggedRPCBandwidth()
+	mw.header("vice_rpc_received_bytes_total", "counter", "Bytes received by the RPC server.")
+	mw.sample("vice_rpc_received_bytes_total", float64(rx))
+	mw.header("vice_rpc_sent_bytes_total", "counter", "Bytes sent by the RPC server.")
+	mw.sample("vice_rpc_sent_bytes_total", float64(tx))
+
+	sims := sm.getSimMetrics()
+	mw.header("vice_sims_active", "gauge", "Number of running multi-controller sims.")
+	mw.sample("vice_sims_active", float64(len(sims)))
+
+	mw.header("vice_sim_controllers", "gauge", "Number of controllers signed in to the sim.")
+	for _, s := range sims {
+		mw.sample("vice_sim_controllers", float64(s.controllers), "sim", s.name)
+	}
+	mw.header("vice_sim_observers", "gauge", "Number of observers connected to the sim.")
+	for _, s := range sims {
+		mw.sample("vice_sim_observers", float64(s.observers), "sim", s.name)
+	}
+	mw.header("vice_sim_pending_reconnections", "gauge", "Number of controllers from before a server restart that haven't reconnected.")
+	for _, s := range sims {
+		mw.sample("vice_sim_pending_reconnections", float64(s.pendingReconnections), "sim", s.name)
+	}
+	mw.header("vice_sim_aircraft", "gauge", "Number of aircraft in the sim.")
+	for _, s := range sims {
+		mw.sample("vice_sim_aircraft", float64(s.ifr), "sim", s.name, "rules", "ifr")
+		mw.sample("vice_sim_aircraft", float64(s.vfr), "sim", s.name, "rules", "vfr")
+	}
+	mw.header("vice_sim_idle_seconds", "gauge", "Time since a controller last interacted with the sim.")
+	for _, s := range sims {
+		mw.sample("vice_sim_idle_seconds", s.idle.Seconds(), "sim", s.name)
+	}
+	mw.header("vice_sim_last_update_seconds", "gauge", "Duration of the sim's most recent update step.")
+	for _, s := range sims {
+		mw.sample("vice_sim_last_update_seconds", s.lastUpdate.Seconds(), "sim", s.name)
+	}
+
+	// Work from a copy so that the metrics' mutex isn't held while writing
+	// to a client that may be slow to read the response.
+	m := sm.metrics.snapshot()
+
+	mw.header("vice_sim_update_duration_seconds", "histogram", "Duration of sim update steps across all sims.")
+	mw.histogram("vice_sim_update_duration_seconds", &m.simUpdate)
+
+	mw.header("vice_rpc_duration_seconds", "histogram", "Latency of RPCs, including long-polls for state updates.")
+	for method, r := range util.SortedMap(m.rpcs) {
+		mw.histogram("vice_rpc_duration_seconds", &r.duration, "method", method)
+	}
+	mw.header("vice_rpc_errors_total", "counter", "Number of RPCs that returned an error.")
+	for method, r := range util.SortedMap(m.rpcs) {
+		mw.sample("vice_rpc_errors_total", float64(r.errors), "method", method)
+	}
+
+	mw.header("vice_tts_requests_total", "counter", "Number of transmissions sent to clients for speech synthesis.")
+	for typ, n := range util.SortedMap(m.ttsRequests) {
+		mw.sample("vice_tts_requests_total", float64(n), "type", typ)
+	}
+	mw.header("vice_tts_failures_total", "counter", "Number of transmissions that couldn't be prepared for speech synthesis.")
+	for typ, n := range util.SortedMap(m.ttsFailures) {
+		mw.sample("vice_tts_failures_total", float64(n), "type", typ)
+	}
+	mw.header("vice_stt_commands_total", "counter", "Number of controller commands given by voice.")
+	mw.sample("vice_stt_commands_total", float64(m.sttCommands))
+	mw.header("vice_stt_command_failures_total", "counter", "Number of controller commands given by voice that failed.")
+	mw.sample("vice_stt_command_failures_total", float64(m.sttCommandFails))
+
+	select {
+	case <-sm.providersReady:
+		if sm.wxProvider != nil {
+			hits, misses := sm.wxProvider.AtmosGridCacheStats()
+			mw.header("vice_wx_atmos_cache_hits_total", "counter", "Number of atmospheric grid requests serviced from the cache.")
+			mw.sample("vice_wx_atmos_cache_hits_total", float64(hits))
+			mw.header("vice_wx_atmos_cache_misses_total", "counter", "Number of atmospheric grid requests that weren't in the cache.")
+			mw.sample("vice_wx_atmos_cache_misses_total", float64(misses))
+		}
+	default:
+		// Still initializing
+	}
+}
@@ -0,0 +1,289 @@
+// server/persist.go
+// Copyright(c) 2022-2026 vice contributors, licensed under the GNU Public License, Version 3.
+// SPDX: GPL-3.0-only
+
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/mmp/vice/sim"
+)
+
+///////////////////////////////////////////////////////////////////////////
+// Session persistence
+
+// When the server is given a state directory, each running multi-controller
+// sim is periodically written there along with its session so that it can
+// be restored if the server restarts. Restored sims are paused until their
+// controllers reconnect using their previous controller tokens.
+
+const persistInterval = time.Minute
+
+type persistedConnection struct {
+	TCW      sim.TCW
+	Initials string
+	Role     Role
+}
+
+type persistedSession struct {
+	Version       int // ViceSerializeVersion when written
+	Name          string
+	ScenarioGroup string
+	Scenario      string
+	PasswordHash  []byte
+	Connections   map[string]persistedConnection // controller token -> connection
+	// Reservations and LobbyDeadline are set for scheduled sims that are
+	// still waiting for reserved controllers; see schedule.go.
+	Reservations  map[sim.TCW]string
+	LobbyDeadline time.Time
+	Sim           json.RawMessage
+}
+
+func (sm *SimManager) sessionStatePath(name string) string {
+	return filepath.Join(sm.stateDir, url.PathEscape(name)+".json")
+}
+
+// persistedConnections returns the session's current connections along
+// with those from before a restart or that were dropped that haven't
+// reconnected yet.
+func (ss *simSession) persistedConnections() map[string]persistedConnection {
+	ss.mu.Lock(ss.lg)
+	defer ss.mu.Unlock(ss.lg)
+
+	conns := maps.Clone(ss.restoredConnections)
+	if conns == nil {
+		conns = make(map[string]persistedConnection)
+	}
+	for token, dc := range ss.droppedConnections {
+		conns[token] = dc.persistedConnection
+	}
+	for token, conn := range ss.connectionsByToken {
+		conns[token] = persistedConnection{TCW: conn.tcw, Initials: conn.initials, Role: conn.role}
+	}
+	return conns
+}
+
+// persistSession writes the session to the state directory.
+func (sm *SimManager) persistSession(session *simSession) {
+	simState, err := session.sim.MarshalState()
+	if err != nil {
+		session.lg.Errorf("unable to encode sim for persistence: %v", err)
+		return
+	}
+
+	reservations, lobbyDeadline := session.lobbyState()
+
+	b, err := json.Marshal(persistedSession{
+		Version:       ViceSerializeVersion,
+		Name:          session.name,
+		ScenarioGroup: session.scenarioGroup,
+		Scenario:      session.scenario,
+		PasswordHash:  session.passwordHash,
+		Connections:   session.persistedConnections(),
+		Reservations:  reservations,
+		LobbyDeadline: lobbyDeadline,
+		Sim:           simState,
+	})
+	if err != nil {
+		session.lg.Errorf("unable to encode session for persistence: %v", err)
+		return
+	}
+
+	if err := writeFileAtomically(sm.sessionStatePath(session.name), b); err != nil {
+		session.lg.Errorf("unable to write session state file: %v", err)
+	}
+}
+
+// writeFileAtomically writes the file via a temporary file that is then
+// renamed so that a crash while writing it doesn't lose the previous one.
+func writeFileAtomically(fn string, b []byte) error {
+	f, err := os.CreateTemp(filepath.Dir(fn), "state-*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return fmt.Errorf("%s: %w", f.Name(), err)
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return fmt.Errorf("%s: %w", f.Name(), err)
+	}
+	if err := os.Rename(f.Name(), fn); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	return nil
+}
+
+// removePersistedSession removes the session's state file, e.g. after the
+// sim has been terminated.
+func (sm *SimManager) removePersistedSession(session *simSession) {
+	if err := os.Remove(sm.sessionStatePath(session.name)); err != nil && !os.IsNotExist(err) {
+		session.lg.Warnf("unable to remove session state file: %v", err)
+	}
+}
+
+// setAsideStateFile renames a state file that was written by an
+// incompatible version of the server so that it isn't loaded again but is
+// still available if someone wants to see what was lost.
+func (sm *SimManager) setAsideStateFile(fn string, version int) {
+	if err := os.Rename(fn, fmt.Sprintf("%s.v%d", fn, version)); err != nil {
+		sm.lg.Warnf("%s: %v", fn, err)
+	}
+}
+
+// restoreSessions restores the sims in the state directory. Sims that were
+// saved by an incompatible version of the server can't be restored; they
+// are logged and set aside.
+func (sm *SimManager) restoreSessions() {
+	defer sm.lg.CatchAndReportCrash()
+
+	if err := os.MkdirAll(sm.stateDir, 0o755); err != nil {
+		sm.lg.Errorf("%s: unable to create state directory: %v", sm.stateDir, err)
+		return
+	}
+	entries, err := os.ReadDir(sm.stateDir)
+	if err != nil {
+		sm.lg.Errorf("%s: %v", sm.stateDir, err)
+		return
+	}
+
+	for _, entry := range entries {
+		fn := filepath.Join(sm.stateDir, entry.Name())
+		if entry.IsDir() || !strings.HasSuffix(fn, ".json") {
+			continue
+		}
+
+		b, err := os.ReadFile(fn)
+		if err != nil {
+			sm.lg.Errorf("%s: %v", fn, err)
+			continue
+		}
+		var ps persistedSession
+		if err := json.Unmarshal(b, &ps); err != nil {
+			sm.lg.Errorf("%s: %v", fn, err)
+			continue
+		}
+		if ps.Version != ViceSerializeVersion {
+			sm.lg.Errorf("%s: unable to restore sim %q with %d controllers: saved by server version %d, current is %d",
+				fn, ps.Name, len(ps.Connections), ps.Version, ViceSerializeVersion)
+			sm.setAsideStateFile(fn, ps.Version)
+			continue
+		}
+
+		s := &sim.Sim{}
+		if err := json.Unmarshal(ps.Sim, s); err != nil {
+			sm.lg.Errorf("%s: %v", fn, err)
+			continue
+		}
+
+		session := makeSimSession(ps.Name, ps.ScenarioGroup, ps.Scenario, ps.PasswordHash, s, sm.lg)
+		session.restoredConnections = ps.Connections
+		session.reservations = ps.Reservations
+		session.lobbyDeadline = ps.LobbyDeadline
+
+		s.Activate(session.lg, sm.getWXProvider())
+		// Nobody is connected yet.
+		s.SetPausedByServer(true)
+
+		sm.mu.Lock(sm.lg)
+		if _, ok := sm.sessionsByName[ps.Name]; ok {
+			sm.mu.Unlock(sm.lg)
+			sm.lg.Warnf("%s: a sim named %q is already running", fn, ps.Name)
+			continue
+		}
+		sm.sessionsByName[ps.Name] = session
+		sm.mu.Unlock(sm.lg)
+
+		sm.lg.Infof("%s: restored sim with %d connections to resume", ps.Name, len(ps.Connections))
+
+		if sm.recordDir != "" {
+			sm.startRecording(session)
+		}
+		go sm.runSimUpdateLoop(session)
+	}
+}
+
+const ReconnectRPC = "SimManager.Reconnect"
+
+// Reconnect signs a controller back in to their sim using the controller
+// token they had before they were disconnected, including from before the
+// server was restarted. If the token is still signed in, it just returns
+// the current state. Controllers whose connection was dropped resume
+// their event subscription, so the next state update includes the
+// events that they missed.
+func (sm *SimManager) Reconnect(token string, result *NewSimResult) error {
+	sm.mu.Lock(sm.lg)
+	defer sm.mu.Unlock(sm.lg)
+
+	if session, ok := sm.sessionsByToken[token]; ok {
+		session.mu.Lock(session.lg)
+		conn, ok := session.connectionsByToken[token]
+		session.mu.Unlock(session.lg)
+		if !ok {
+			return ErrNoSimForControllerToken
+		}
+		*result = *sm.buildNewSimResult(session, conn.tcw, token)
+		return nil
+	}
+
+	for _, session := range sm.sessionsByName {
+		session.mu.Lock(session.lg)
+		pc, restored := session.restoredConnections[token]
+		dc, dropped := session.droppedConnections[token]
+		session.mu.Unlock(session.lg)
+		if dropped {
+			pc = dc.persistedConnection
+		} else if !restored {
+			continue
+		}
+
+		var eventSub *sim.EventsSubscription
+		if pc.Role == RoleObserver {
+			eventSub = session.sim.Subscribe()
+		} else if err := sm.checkTCWAvailable(session, pc.TCW); err != nil {
+			// Someone else is already back at the TCW; join as relief.
+			eventSub = session.sim.Subscribe()
+		} else if _, eventSub, err = session.sim.SignOn(pc.TCW, nil); err != nil {
+			return err
+		}
+
+		if dropped {
+			// Pick up where the old subscription left off.
+			eventSub.Unsubscribe()
+			eventSub = dc.eventSub
+			if dc.privileged {
+				session.sim.SetPrivilegedTCW(pc.TCW, true)
+			}
+		}
+
+		session.mu.Lock(session.lg)
+		delete(session.restoredConnections, token)
+		delete(session.droppedConnections, token)
+		session.mu.Unlock(session.lg)
+
+		if pc.Role != RoleObserver {
+			session.sim.PostEvent(sim.Event{
+				Type:        sim.StatusMessageEvent,
+				WrittenText: string(pc.TCW) + " (" + pc.Initials + ") has reconnected.",
+			})
+		}
+		session.AddHumanController(token, pc.TCW, pc.Initials, pc.Role, eventSub)
+		sm.sessionsByToken[token] = session
+
+		*result = *sm.buildNewSimResult(session, pc.TCW, token)
+		return nil
+	}
+
+	return ErrNoSimForControllerToken
+}
@@ -5,6 +5,7 @@
 package server
 
 import (
+	"cmp"
 	"encoding/json"
 	"fmt"
 	"io/fs"
@@ -96,6 +97,11 @@ type scenario struct {
 	DefaultMapGroup string        `json:"default_map_group"`
 	VFRRateScale    *float32      `json:"vfr_rate_scale"`
 	VFFRequestRate  *int32        `json:"flight_following_request_rate,omitempty"`
+
+	// Timeline lists scripted spawns at fixed times; if TimelineOnly is
+	// set, random arrivals, departures, and overflights start out disabled.
+	Timeline     []sim.TimelineSpawn `json:"timeline,omitempty"`
+	TimelineOnly bool                `json:"timeline_only,omitempty"`
 }
 
 func (s *scenario) PostDeserialize(sg *scenarioGroup, e *util.ErrorLogger, mapSpec *av.MapLibrarySpec) {
@@ -619,6 +625,85 @@ func (s *scenario) PostDeserialize(sg *scenarioGroup, e *util.ErrorLogger, mapSp
 		ten := int32(10)
 		s.VFFRequestRate = &ten
 	}
+
+	s.validateTimeline(sg, e)
+}
+
+// validateTimeline checks the scripted spawns in the scenario's
+// "timeline" and sorts them by time.
+func (s *scenario) validateTimeline(sg *scenarioGroup, e *util.ErrorLogger) {
+	if s.TimelineOnly && len(s.Timeline) == 0 {
+		e.ErrorString(`"timeline_only" specified but no "timeline" given`)
+	}
+
+	callsigns := make(map[string]bool)
+	for i := range s.Timeline {
+		tl := &s.Timeline[i]
+		e.Push(fmt.Sprintf("timeline %.1f minutes", tl.AtMinutes))
+
+		if tl.AtMinutes < 0 {
+			e.ErrorString(`"at_minutes" cannot be negative`)
+		}
+
+		if tl.Callsign != "" {
+			tl.Callsign = strings.ToUpper(tl.Callsign)
+			if callsigns[tl.Callsign] {
+				e.ErrorString("callsign %q is used more than once", tl.Callsign)
+			}
+			callsigns[tl.Callsign] = true
+		}
+		if tl.AircraftType != "" {
+			tl.AircraftType = strings.ToUpper(tl.AircraftType)
+			if _, ok := av.DB.AircraftPerformance[tl.AircraftType]; !ok {
+				e.ErrorString(`"aircraft_type" %q not found in aircraft performance database`, tl.AircraftType)
+			}
+		}
+
+		switch tl.Type {
+		case sim.TimelineArrival:
+			if flow, ok := sg.InboundFlows[tl.InboundFlow]; !ok {
+				e.ErrorString(`"inbound_flow" %q not found`, tl.InboundFlow)
+			} else if !slices.ContainsFunc(flow.Arrivals, func(ar av.Arrival) bool {
+				_, ok := ar.Airlines[tl.Airport]
+				return ok && tl.MatchesArrival(ar)
+			}) {
+				e.ErrorString("no arrivals to %q in %q match %q", tl.Airport, tl.InboundFlow, tl.Route)
+			} else if !slices.ContainsFunc(s.ArrivalRunways, func(r sim.ArrivalRunway) bool { return r.Airport == tl.Airport }) {
+				e.ErrorString(`no runways listed in "arrival_runways" for %s`, tl.Airport)
+			}
+
+		case sim.TimelineOverflight:
+			if flow, ok := sg.InboundFlows[tl.InboundFlow]; !ok {
+				e.ErrorString(`"inbound_flow" %q not found`, tl.InboundFlow)
+			} else if !slices.ContainsFunc(flow.Overflights, tl.MatchesOverflight) {
+				e.ErrorString("no overflights in %q match %q", tl.InboundFlow, tl.Route)
+			}
+
+		case sim.TimelineDeparture:
+			if tl.Altitude != 0 || tl.Speed != 0 {
+				e.ErrorString(`"altitude" and "speed" cannot be specified for departures`)
+			}
+			if !slices.ContainsFunc(s.DepartureRunways, func(r sim.DepartureRunway) bool {
+				return r.Airport == tl.Airport && r.Runway == tl.Runway
+			}) {
+				e.ErrorString(`%s runway %s not found in "departure_runways"`, tl.Airport, tl.Runway)
+			} else if ap, ok := sg.Airports[tl.Airport]; ok && !slices.ContainsFunc(ap.Departures, func(dep av.Departure) bool {
+				_, ok := ap.DepartureRoutes[tl.Runway][dep.Exit]
+				return ok && tl.MatchesDeparture(dep)
+			}) {
+				e.ErrorString("no departures from runway %s match %q", tl.Runway, tl.Route)
+			}
+
+		default:
+			e.ErrorString(`"type" must be %q, %q, or %q`, sim.TimelineArrival, sim.TimelineDeparture, sim.TimelineOverflight)
+		}
+
+		e.Pop()
+	}
+
+	slices.SortStableFunc(s.Timeline, func(a, b sim.TimelineSpawn) int {
+		return cmp.Compare(a.AtMinutes, b.AtMinutes)
+	})
 }
 
 ///////////////////////////////////////////////////////////////////////////
@@ -716,6 +801,11 @@ func (sg *scenarioGroup) resolveControllerRefs() {
 		if ap.DepartureController != "" {
 			ap.DepartureController = resolve(ap.DepartureController)
 		}
+		for i := range ap.NTZs {
+			if ap.NTZs[i].Monitor != "" {
+				ap.NTZs[i].Monitor = resolve(ap.NTZs[i].Monitor)
+			}
+		}
 		for _, exitRoutes := range ap.DepartureRoutes {
 			for _, route := range exitRoutes {
 				if route.HandoffController != "" {
@@ -1723,6 +1813,7 @@ func initializeSimConfigurations(sg *scenarioGroup, catalogs map[string]map[stri
 			func(cc *sim.STARSController) bool { return len(cc.FlightFollowingAirspace) > 0 })
 		lc := sim.MakeLaunchConfig(scenario.DepartureRunways, *scenario.VFRRateScale, *scenario.VFFRequestRate,
 			vfrAirports, scenario.InboundFlowDefaultRates, haveVFRReportingRegions)
+		scenario.applyTimelineOnly(&lc)
 
 		spec := &ScenarioSpec{
 			ControllerConfiguration: &scenario.ControllerConfiguration,
@@ -2471,7 +2562,19 @@ func LoadScenarioGroups(extraScenarioFilename string, extraVideoMapFilename stri
 	}
 	lg.Infof("Missing V2 in performance database: %s", strings.Join(missing, ", "))
 
-	loadEmergencies(e)
+	emergencies := loadEmergencies(e)
+	for _, tracon := range scenarioGroups {
+		for _, sg := range tracon {
+			for name, sc := range sg.Scenarios {
+				for _, tl := range sc.Timeline {
+					if tl.Emergency != "" && !slices.ContainsFunc(emergencies,
+						func(em sim.Emergency) bool { return em.Name == tl.Emergency }) {
+						e.ErrorString("Scenario %s: timeline emergency %q not found in emergencies.json", name, tl.Emergency)
+					}
+				}
+			}
+		}
+	}
 
 	lg.Infof("LoadScenarioGroups total: %s", time.Since(start))
 	return scenarioGroups, catalogs, mapSpecs, briefs, extraScenarioErrors
@@ -2531,7 +2634,7 @@ func CreateLaunchConfig(scenario *scenario, scenarioGroup *scenarioGroup) sim.La
 		func(cfg *sim.STARSController) bool { return cfg.FlightFollowingAirspace != nil })
 
 	// Create proper LaunchConfig
-	return sim.MakeLaunchConfig(
+	lc := sim.MakeLaunchConfig(
 		scenario.DepartureRunways,
 		*scenario.VFRRateScale,
 		*scenario.VFFRequestRate,
@@ -2539,6 +2642,19 @@ func CreateLaunchConfig(scenario *scenario, scenarioGroup *scenarioGroup) sim.La
 		scenario.InboundFlowDefaultRates,
 		haveVFRReportingRegions,
 	)
+	scenario.applyTimelineOnly(&lc)
+	return lc
+}
+
+// applyTimelineOnly disables random arrivals, departures, and overflights
+// for scenarios that only want their timeline's spawns; they can still be
+// enabled by the launch controller.
+func (s *scenario) applyTimelineOnly(lc *sim.LaunchConfig) {
+	if s.TimelineOnly {
+		lc.ArrivalMode = sim.LaunchManual
+		lc.DepartureMode = sim.LaunchManual
+		lc.OverflightMode = sim.LaunchManual
+	}
 }
 
 // CreateNewSimConfiguration creates a NewSimConfiguration from scenario components
@@ -2583,6 +2699,7 @@ func CreateNewSimConfiguration(catalog *ScenarioCatalog, scenarioGroup *scenario
 		VirtualControllers:      scenario.VirtualControllers,
 		HandoffIDs:              scenarioGroup.FacilityConfig.HandoffIDs,
 		FixPairs:                scenarioGroup.FacilityConfig.FixPairs,
+		Timeline:                scenario.Timeline,
 	}
 
 	// Resolve fix pair assignments from the selected configuration
@@ -2713,8 +2830,8 @@ func loadEmergencies(e *util.ErrorLogger) []sim.Emergency {
 			e.ErrorString(`no emergency "stages" defined`)
 		}
 		for i, stage := range em.Stages {
-			// transmission is required unless request_return is true
-			if stage.Transmission == "" && !stage.RequestReturn {
+			// transmission is required unless request_return or lost_comms is true
+			if stage.Transmission == "" && !stage.RequestReturn && !stage.LostComms {
 				e.ErrorString(`stage %d missing required field "transmission"`, i)
 			}
 			// duration_minutes is required for all stages except the last one
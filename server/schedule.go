@@ -0,0 +1,721 @@
+// server/schedule.go
+// Copyright(c) 2022-2026 vice contributors, licensed under the GNU Public License, Version 3.
+// SPDX: GPL-3.0-only
+
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"maps"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	av "github.com/mmp/vice/aviation"
+	"github.com/mmp/vice/sim"
+	"github.com/mmp/vice/util"
+)
+
+///////////////////////////////////////////////////////////////////////////
+// Scheduled sims
+
+// Sims can be scheduled to start at a future time, e.g. for a weekly
+// event. Positions in a scheduled sim can be reserved for specific
+// controllers, either by the instructor who schedules it or by
+// controllers signing up for it; once all positions are taken, further
+// sign-ups go on a waitlist and are given positions as others cancel.
+//
+// Only whoever scheduled a sim or signed up for a position can cancel it.
+// On servers that require authentication, that's identified by their
+// account; otherwise clients send a random key that they keep, which
+// stands in for the account. Each owner may only have a few sims
+// scheduled at once.
+//
+// When its start time arrives, a scheduled sim is started like any other
+// but it stays paused until all of the controllers with reservations
+// have signed in, or until reservationGracePeriod has passed, after which
+// any remaining reservations are released. Until then, reserved TCWs can
+// only be taken by the controllers they are reserved for (or by
+// instructors).
+
+const schedulerInterval = 5 * time.Second
+const reservationGracePeriod = 15 * time.Minute
+
+// Limits on the number of scheduled sims, overall and for each owner.
+const maxScheduledSims = 50
+const maxScheduledSimsPerOwner = 3
+
+// ScheduledSim describes a scheduled sim for clients.
+type ScheduledSim struct {
+	Facility        string
+	GroupName       string
+	ScenarioName    string
+	StartAt         time.Time
+	RequirePassword bool
+	// Reservations maps the sim's positions to the initials of the
+	// controllers that have reserved them; positions that are still open
+	// map to "".
+	Reservations map[sim.TCP]string
+	// Waitlist holds the initials of controllers waiting for a position,
+	// in the order they signed up.
+	Waitlist []string
+}
+
+// IsSignedUp returns whether the controller with the given initials has
+// a reservation for the sim or is on its waitlist.
+func (ss *ScheduledSim) IsSignedUp(initials string) bool {
+	return util.SeqContains(maps.Values(ss.Reservations), initials) || slices.Contains(ss.Waitlist, initials)
+}
+
+// scheduledSim is the server's representation of a scheduled sim; it is
+// also what's written to the state directory.
+type scheduledSim struct {
+	Version      int           // ViceSerializeVersion when written
+	Request      NewSimRequest // with the password cleared
+	PasswordHash []byte
+	StartAt      time.Time
+	Reservations map[sim.TCP]string
+	Waitlist     []string
+	// Owner is who scheduled the sim and SignUpOwners maps the initials
+	// of controllers who have signed up to who signed them up; see
+	// scheduleOwner.
+	Owner        string
+	SignUpOwners map[string]string
+}
+
+// scheduleOwner returns the owner of a scheduled sim or sign-up made with
+// the given account, or with the given client key if the server doesn't
+// require authentication.
+func scheduleOwner(acct Account, clientKey string) (string, error) {
+	if acct.Username != "" {
+		return "user:" + acct.Username, nil
+	} else if clientKey == "" {
+		return "", ErrNoClientKey
+	}
+	return "key:" + HashToken(clientKey), nil
+}
+
+func (ss *scheduledSim) clientView() *ScheduledSim {
+	return &ScheduledSim{
+		Facility:        ss.Request.Facility,
+		GroupName:       ss.Request.GroupName,
+		ScenarioName:    ss.Request.ScenarioName,
+		StartAt:         ss.StartAt,
+		RequirePassword: len(ss.PasswordHash) > 0,
+		Reservations:    maps.Clone(ss.Reservations),
+		Waitlist:        slices.Clone(ss.Waitlist),
+	}
+}
+
+// signUp gives the controller the requested position, if it's open, or
+// the first open position if none was requested. If there are no open
+// positions, the controller is added to the waitlist and "" is returned.
+func (ss *scheduledSim) signUp(initials, owner string, tcp sim.TCP) (sim.TCP, error) {
+	if tcp != "" {
+		reserved, ok := ss.Reservations[tcp]
+		if !ok {
+			return "", av.ErrNoController
+		} else if reserved != "" {
+			return "", ErrTCWReserved
+		}
+		ss.Reservations[tcp] = initials
+		ss.SignUpOwners[initials] = owner
+		return tcp, nil
+	}
+
+	ss.SignUpOwners[initials] = owner
+	for tcp, reserved := range util.SortedMap(ss.Reservations) {
+		if reserved == "" {
+			ss.Reservations[tcp] = initials
+			return tcp, nil
+		}
+	}
+	ss.Waitlist = append(ss.Waitlist, initials)
+	return "", nil
+}
+
+// mayCancelSignUp returns whether the owner may cancel the controller's
+// sign-up: they must have made it or have scheduled the sim.
+func (ss *scheduledSim) mayCancelSignUp(initials, owner string) bool {
+	return owner == ss.Owner || owner == ss.SignUpOwners[initials]
+}
+
+// cancelSignUp removes the controller's reservation or removes them from
+// the waitlist. A position that is given up goes to the first controller
+// on the waitlist.
+func (ss *scheduledSim) cancelSignUp(initials string) error {
+	for tcp, reserved := range ss.Reservations {
+		if reserved == initials {
+			ss.Reservations[tcp] = ""
+			if len(ss.Waitlist) > 0 {
+				ss.Reservations[tcp] = ss.Waitlist[0]
+				ss.Waitlist = ss.Waitlist[1:]
+			}
+			delete(ss.SignUpOwners, initials)
+			return nil
+		}
+	}
+	if idx := slices.Index(ss.Waitlist, initials); idx != -1 {
+		ss.Waitlist = slices.Delete(ss.Waitlist, idx, idx+1)
+		delete(ss.SignUpOwners, initials)
+		return nil
+	}
+	return ErrNotSignedUp
+}
+
+type ScheduleSimRequest struct {
+	NewSimRequest
+	StartAt time.Time
+	// Reservations gives the positions that controllers can sign up
+	// for, along with the initials of the controllers they are reserved
+	// for; open positions map to "".
+	Reservations map[sim.TCP]string
+	// ClientKey identifies the client if the server doesn't require
+	// authentication; see scheduleOwner.
+	ClientKey string
+}
+
+const ScheduleSimRPC = "SimManager.ScheduleSim"
+
+func (sm *SimManager) ScheduleSim(req *ScheduleSimRequest, _ *struct{}) error {
+	if sm.local {
+		return ErrInvalidSimConfiguration
+	}
+	acct, err := sm.authenticateAccount(req.Account, RoleInstructor)
+	if err != nil {
+		return err
+	}
+	owner, err := scheduleOwner(acct, req.ClientKey)
+	if err != nil {
+		return err
+	}
+	if req.NewSimName == "" {
+		return ErrInvalidSimConfiguration
+	}
+	if !req.StartAt.After(time.Now()) {
+		return ErrInvalidScheduleTime
+	}
+
+	sg, ok := sm.scenarioGroups[req.Facility][req.GroupName]
+	if !ok {
+		return ErrInvalidSimConfiguration
+	}
+	sc, ok := sg.Scenarios[req.ScenarioName]
+	if !ok || req.ScenarioSpec == nil {
+		return ErrInvalidSimConfiguration
+	}
+	positions := sc.ControllerConfiguration.DefaultConsolidation.AllPositions()
+	for tcp := range req.Reservations {
+		if !slices.Contains(positions, tcp) {
+			return av.ErrNoController
+		}
+	}
+
+	ss := &scheduledSim{
+		Request:      req.NewSimRequest,
+		StartAt:      req.StartAt,
+		Reservations: maps.Clone(req.Reservations),
+		Owner:        owner,
+		SignUpOwners: make(map[string]string),
+	}
+	if ss.Reservations == nil {
+		ss.Reservations = make(map[sim.TCP]string)
+	}
+	for _, initials := range ss.Reservations {
+		if initials != "" {
+			ss.SignUpOwners[initials] = owner
+		}
+	}
+	if ss.PasswordHash, err = hashSimPassword(req.Password); err != nil {
+		return err
+	}
+	ss.Request.Password = ""
+	ss.Request.Account = Credentials{}
+
+	if err := func() error {
+		sm.mu.Lock(sm.lg)
+		defer sm.mu.Unlock(sm.lg)
+
+		if _, ok := sm.sessionsByName[req.NewSimName]; ok {
+			return ErrDuplicateSimName
+		}
+		if _, ok := sm.scheduledSims[req.NewSimName]; ok {
+			return ErrDuplicateSimName
+		}
+		n := 0
+		for _, s := range sm.scheduledSims {
+			if s.Owner == owner {
+				n++
+			}
+		}
+		if n >= maxScheduledSimsPerOwner || len(sm.scheduledSims) >= maxScheduledSims {
+			return ErrTooManyScheduledSims
+		}
+		sm.scheduledSims[req.NewSimName] = ss
+		return nil
+	}(); err != nil {
+		return err
+	}
+	sm.persistScheduledSim(req.NewSimName)
+
+	sm.lg.Info("scheduled sim", slog.String("sim_name", req.NewSimName), slog.Time("start", req.StartAt),
+		slog.String("scenario", req.ScenarioName))
+
+	return nil
+}
+
+const GetScheduledSimsRPC = "SimManager.GetScheduledSims"
+
+func (sm *SimManager) GetScheduledSims(_ int, result *map[string]*ScheduledSim) error {
+	sm.mu.Lock(sm.lg)
+	defer sm.mu.Unlock(sm.lg)
+
+	scheduled := make(map[string]*ScheduledSim)
+	for name, ss := range sm.scheduledSims {
+		scheduled[name] = ss.clientView()
+	}
+
+	*result = scheduled
+	return nil
+}
+
+type SimSignUpRequest struct {
+	SimName  string
+	Initials string
+	TCP      sim.TCP // optional; if empty, the first open position is reserved
+	Password string
+	Account  Credentials // only used if the server requires authentication
+	// ClientKey identifies the client if the server doesn't require
+	// authentication; see scheduleOwner.
+	ClientKey string
+}
+
+// checkScheduledSimPassword returns the named scheduled sim if the
+// password for it is correct. As in ConnectToSim, bcrypt is slow enough
+// that the password is checked without holding sm.mu; callers must make
+// sure that the sim is still scheduled once they have locked it again.
+func (sm *SimManager) checkScheduledSimPassword(name, password string) (*scheduledSim, error) {
+	sm.mu.Lock(sm.lg)
+	ss, ok := sm.scheduledSims[name]
+	var hash []byte
+	if ok {
+		hash = ss.PasswordHash
+	}
+	sm.mu.Unlock(sm.lg)
+
+	if !ok {
+		return nil, ErrNoNamedSim
+	} else if !checkPasswordHash(hash, password) {
+		return nil, ErrInvalidPassword
+	}
+	return ss, nil
+}
+
+const SignUpForSimRPC = "SimManager.SignUpForSim"
+
+// SignUpForSim reserves a position in a scheduled sim for a controller.
+// It returns the position that was reserved, or "" if the controller was
+// added to the waitlist.
+func (sm *SimManager) SignUpForSim(req *SimSignUpRequest, result *sim.TCP) error {
+	acct, err := sm.authenticateAccount(req.Account, RoleController)
+	if err != nil {
+		return err
+	}
+	owner, err := scheduleOwner(acct, req.ClientKey)
+	if err != nil {
+		return err
+	}
+	if req.Initials == "" {
+		return ErrNoInitials
+	}
+
+	ss, err := sm.checkScheduledSimPassword(req.SimName, req.Password)
+	if err != nil {
+		return err
+	}
+
+	tcp, err := func() (sim.TCP, error) {
+		sm.mu.Lock(sm.lg)
+		defer sm.mu.Unlock(sm.lg)
+
+		// Make sure the sim wasn't cancelled or started while we weren't
+		// holding the lock.
+		if sm.scheduledSims[req.SimName] != ss {
+			return "", ErrNoNamedSim
+		}
+		if ss.clientView().IsSignedUp(req.Initials) {
+			return "", ErrAlreadySignedUp
+		}
+		return ss.signUp(req.Initials, owner, req.TCP)
+	}()
+	if err != nil {
+		return err
+	}
+	sm.persistScheduledSim(req.SimName)
+
+	*result = tcp
+	return nil
+}
+
+const CancelSimSignUpRPC = "SimManager.CancelSimSignUp"
+
+// CancelSimSignUp cancels a controller's reservation or removes them from
+// the waitlist; only whoever signed them up or scheduled the sim may do
+// so.
+func (sm *SimManager) CancelSimSignUp(req *SimSignUpRequest, _ *struct{}) error {
+	acct, err := sm.authenticateAccount(req.Account, RoleController)
+	if err != nil {
+		return err
+	}
+	owner, err := scheduleOwner(acct, req.ClientKey)
+	if err != nil {
+		return err
+	}
+
+	ss, err := sm.checkScheduledSimPassword(req.SimName, req.Password)
+	if err != nil {
+		return err
+	}
+
+	if err := func() error {
+		sm.mu.Lock(sm.lg)
+		defer sm.mu.Unlock(sm.lg)
+
+		if sm.scheduledSims[req.SimName] != ss {
+			return ErrNoNamedSim
+		}
+		if !ss.clientView().IsSignedUp(req.Initials) {
+			return ErrNotSignedUp
+		} else if !ss.mayCancelSignUp(req.Initials, owner) && acct.Role < RoleAdmin {
+			return ErrNotScheduleOwner
+		}
+		return ss.cancelSignUp(req.Initials)
+	}(); err != nil {
+		return err
+	}
+	sm.persistScheduledSim(req.SimName)
+
+	return nil
+}
+
+type CancelScheduledSimRequest struct {
+	SimName  string
+	Password string
+	Account  Credentials // only used if the server requires authentication
+	// ClientKey identifies the client if the server doesn't require
+	// authentication; see scheduleOwner.
+	ClientKey string
+}
+
+const CancelScheduledSimRPC = "SimManager.CancelScheduledSim"
+
+// CancelScheduledSim cancels a scheduled sim; only whoever scheduled it
+// (or an admin) may do so.
+func (sm *SimManager) CancelScheduledSim(req *CancelScheduledSimRequest, _ *struct{}) error {
+	acct, err := sm.authenticateAccount(req.Account, RoleInstructor)
+	if err != nil {
+		return err
+	}
+	owner, err := scheduleOwner(acct, req.ClientKey)
+	if err != nil {
+		return err
+	}
+
+	ss, err := sm.checkScheduledSimPassword(req.SimName, req.Password)
+	if err != nil {
+		return err
+	}
+
+	if err := func() error {
+		sm.mu.Lock(sm.lg)
+		defer sm.mu.Unlock(sm.lg)
+
+		if sm.scheduledSims[req.SimName] != ss {
+			return ErrNoNamedSim
+		} else if owner != ss.Owner && acct.Role < RoleAdmin {
+			return ErrNotScheduleOwner
+		}
+		delete(sm.scheduledSims, req.SimName)
+		return nil
+	}(); err != nil {
+		return err
+	}
+	sm.removePersistedScheduledSim(req.SimName)
+
+	sm.lg.Info("cancelled scheduled sim", slog.String("sim_name", req.SimName))
+
+	return nil
+}
+
+///////////////////////////////////////////////////////////////////////////
+// Starting scheduled sims
+
+// runScheduler periodically starts the scheduled sims whose start time
+// has arrived.
+func (sm *SimManager) runScheduler() {
+	defer sm.lg.CatchAndReportCrash()
+
+	for {
+		time.Sleep(schedulerInterval)
+
+		sm.mu.Lock(sm.lg)
+		due := make(map[string]*scheduledSim)
+		for name, ss := range sm.scheduledSims {
+			if !ss.StartAt.After(time.Now()) {
+				due[name] = ss
+				delete(sm.scheduledSims, name)
+			}
+		}
+		sm.mu.Unlock(sm.lg)
+
+		for name, ss := range util.SortedMap(due) {
+			sm.startDueScheduledSim(name, ss)
+		}
+	}
+}
+
+// startDueScheduledSim starts a scheduled sim whose start time has
+// arrived. Its file in the state directory is only removed once the sim
+// has started and been persisted as a running sim, so that it isn't lost
+// if the server stops in between; if the sim can't be started, the file
+// is kept so that it's tried again when the server restarts.
+func (sm *SimManager) startDueScheduledSim(name string, ss *scheduledSim) {
+	session, err := sm.startScheduledSim(name, ss)
+	if err != nil {
+		sm.lg.Errorf("%s: unable to start scheduled sim: %v", name, err)
+		return
+	}
+
+	if sm.stateDir != "" {
+		sm.persistSession(session)
+	}
+	sm.removePersistedScheduledSim(name)
+}
+
+func (sm *SimManager) startScheduledSim(name string, ss *scheduledSim) (*simSession, error) {
+	lg := sm.lg.With(slog.String("sim_name", name))
+
+	nsc := sm.makeSimConfiguration(&ss.Request, lg)
+	if nsc == nil {
+		return nil, ErrInvalidSimConfiguration
+	}
+	s := sim.NewSim(*nsc, lg)
+
+	session := makeSimSession(name, ss.Request.GroupName, ss.Request.ScenarioName, ss.PasswordHash, s, sm.lg)
+	session.reservations = make(map[sim.TCW]string)
+	for tcp, initials := range ss.Reservations {
+		if initials != "" {
+			session.reservations[sim.TCW(tcp)] = initials
+		}
+	}
+	session.lobbyDeadline = time.Now().Add(reservationGracePeriod)
+
+	s.Activate(session.lg, sm.getWXProvider())
+	// Nobody is connected yet.
+	s.SetPausedByServer(true)
+
+	sm.mu.Lock(sm.lg)
+	if _, ok := sm.sessionsByName[name]; ok {
+		sm.mu.Unlock(sm.lg)
+		return nil, ErrDuplicateSimName
+	}
+	sm.sessionsByName[name] = session
+	sm.mu.Unlock(sm.lg)
+
+	session.lg.Infof("started scheduled sim with %d reservations", len(session.reservations))
+
+	sm.runSession(session, true)
+
+	return session, nil
+}
+
+///////////////////////////////////////////////////////////////////////////
+// Reservations in running sims
+
+// checkReservations returns ErrTCWReserved if the TCW or any of the TCPs
+// are reserved for a controller other than the one with the given
+// initials.
+func (ss *simSession) checkReservations(tcw sim.TCW, tcps []sim.TCP, initials string) error {
+	ss.mu.Lock(ss.lg)
+	defer ss.mu.Unlock(ss.lg)
+
+	reserved := func(tcw sim.TCW) bool {
+		r, ok := ss.reservations[tcw]
+		return ok && r != initials
+	}
+	if reserved(tcw) || slices.ContainsFunc(tcps, func(tcp sim.TCP) bool { return reserved(sim.TCW(tcp)) }) {
+		return ErrTCWReserved
+	}
+	return nil
+}
+
+// updateLobby returns whether a scheduled sim should still be held
+// because not all of the controllers with reservations have signed in.
+// Once they have or the grace period has passed, the reservations are
+// released. Must be called with ss.mu held.
+func (ss *simSession) updateLobby() bool {
+	if ss.lobbyDeadline.IsZero() {
+		return false
+	}
+
+	var missing []string
+	for tcw, initials := range util.SortedMap(ss.reservations) {
+		if !util.SeqContainsFunc(maps.Values(ss.connectionsByToken), func(conn *connectionState) bool {
+			return conn.tcw == tcw && conn.initials == initials && !conn.observing()
+		}) {
+			missing = append(missing, fmt.Sprintf("%s (%s)", tcw, initials))
+		}
+	}
+
+	var msg string
+	if len(missing) == 0 {
+		msg = "All reserved controllers have signed on; starting the sim."
+	} else if time.Now().After(ss.lobbyDeadline) {
+		msg = "Releasing reservations for " + strings.Join(missing, ", ") + "."
+	} else {
+		return true
+	}
+
+	ss.lg.Infof("lobby closed: %s", msg)
+	ss.sim.PostEvent(sim.Event{
+		Type:        sim.StatusMessageEvent,
+		WrittenText: msg,
+	})
+	ss.reservations = nil
+	ss.lobbyDeadline = time.Time{}
+
+	return false
+}
+
+// UpdateLobby is called periodically so that reservations are released
+// after the grace period even if nobody signs in or out.
+func (ss *simSession) UpdateLobby() {
+	ss.mu.Lock(ss.lg)
+	defer ss.mu.Unlock(ss.lg)
+
+	if !ss.lobbyDeadline.IsZero() {
+		ss.updateSimPauseState()
+	}
+}
+
+// lobbyState returns the session's reservations and the time at which
+// they will be released.
+func (ss *simSession) lobbyState() (map[sim.TCW]string, time.Time) {
+	ss.mu.Lock(ss.lg)
+	defer ss.mu.Unlock(ss.lg)
+
+	return maps.Clone(ss.reservations), ss.lobbyDeadline
+}
+
+///////////////////////////////////////////////////////////////////////////
+// Persistence
+
+func (sm *SimManager) scheduledSimPath(name string) string {
+	return filepath.Join(sm.stateDir, "scheduled", url.PathEscape(name)+".json")
+}
+
+// persistScheduledSim writes the scheduled sim to the state directory, if
+// there is one. It must be called without sm.mu held so that other RPCs
+// aren't held up while the file is written. The sim's current state is
+// written, so if it is changed again in the meantime, the later write
+// still ends up with the latest state; nothing is written if the sim has
+// been cancelled or started.
+func (sm *SimManager) persistScheduledSim(name string) {
+	if sm.stateDir == "" {
+		return
+	}
+
+	sm.scheduledSimFilesMu.Lock()
+	defer sm.scheduledSimFilesMu.Unlock()
+
+	sm.mu.Lock(sm.lg)
+	ss, ok := sm.scheduledSims[name]
+	var b []byte
+	var err error
+	if ok {
+		ss.Version = ViceSerializeVersion
+		b, err = json.Marshal(ss)
+	}
+	sm.mu.Unlock(sm.lg)
+
+	if !ok {
+		return
+	} else if err != nil {
+		sm.lg.Errorf("%s: unable to encode scheduled sim: %v", name, err)
+		return
+	}
+	if err := writeFileAtomically(sm.scheduledSimPath(name), b); err != nil {
+		sm.lg.Errorf("%s: unable to write scheduled sim: %v", name, err)
+	}
+}
+
+// removePersistedScheduledSim must be called without sm.mu held.
+func (sm *SimManager) removePersistedScheduledSim(name string) {
+	if sm.stateDir == "" {
+		return
+	}
+
+	sm.scheduledSimFilesMu.Lock()
+	defer sm.scheduledSimFilesMu.Unlock()
+
+	if err := os.Remove(sm.scheduledSimPath(name)); err != nil && !os.IsNotExist(err) {
+		sm.lg.Warnf("%s: unable to remove scheduled sim file: %v", name, err)
+	}
+}
+
+// restoreScheduledSims loads the scheduled sims in the state directory.
+func (sm *SimManager) restoreScheduledSims() {
+	dir := filepath.Join(sm.stateDir, "scheduled")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		sm.lg.Errorf("%s: unable to create directory: %v", dir, err)
+		return
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		sm.lg.Errorf("%s: %v", dir, err)
+		return
+	}
+
+	sm.mu.Lock(sm.lg)
+	defer sm.mu.Unlock(sm.lg)
+
+	for _, entry := range entries {
+		fn := filepath.Join(dir, entry.Name())
+		if entry.IsDir() || !strings.HasSuffix(fn, ".json") {
+			continue
+		}
+
+		b, err := os.ReadFile(fn)
+		if err != nil {
+			sm.lg.Errorf("%s: %v", fn, err)
+			continue
+		}
+		var ss scheduledSim
+		if err := json.Unmarshal(b, &ss); err != nil {
+			sm.lg.Errorf("%s: %v", fn, err)
+			continue
+		}
+		if ss.Version != ViceSerializeVersion {
+			sm.lg.Errorf("%s: unable to restore sim %q scheduled for %s: saved by server version %d, current is %d",
+				fn, ss.Request.NewSimName, ss.StartAt, ss.Version, ViceSerializeVersion)
+			sm.setAsideStateFile(fn, ss.Version)
+			continue
+		}
+		if ss.Reservations == nil {
+			ss.Reservations = make(map[sim.TCP]string)
+		}
+		if ss.SignUpOwners == nil {
+			ss.SignUpOwners = make(map[string]string)
+		}
+		sm.scheduledSims[ss.Request.NewSimName] = &ss
+	}
+
+	if len(sm.scheduledSims) > 0 {
+		sm.lg.Infof("restored %d scheduled sims", len(sm.scheduledSims))
+	}
+}
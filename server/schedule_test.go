@@ -0,0 +1,268 @@
+// server/schedule_test.go
+// Copyright(c) 2022-2026 vice contributors, licensed under the GNU Public License, Version 3.
+// SPDX: GPL-3.0-only
+
+package server
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mmp/vice/sim"
+)
+
+// makeScheduleTestSimManager returns a SimManager with a single N90
+// scenario with positions 1A and 1B.
+func makeScheduleTestSimManager(t *testing.T) *SimManager {
+	t.Helper()
+
+	sm := makeTestSimManager(t)
+	sm.scenarioGroups = map[string]map[string]*scenarioGroup{
+		"N90": {"N90": {Scenarios: map[string]*scenario{
+			"default": {ControllerConfiguration: sim.ControllerConfiguration{
+				DefaultConsolidation: sim.PositionConsolidation{"1A": {"1B"}},
+			}},
+		}}},
+	}
+	return sm
+}
+
+func makeScheduleSimRequest(name, clientKey string) *ScheduleSimRequest {
+	return &ScheduleSimRequest{
+		NewSimRequest: NewSimRequest{
+			Facility:     "N90",
+			NewSimName:   name,
+			GroupName:    "N90",
+			ScenarioName: "default",
+			ScenarioSpec: &ScenarioSpec{},
+		},
+		StartAt:      time.Now().Add(time.Hour),
+		Reservations: map[sim.TCP]string{"1A": "", "1B": ""},
+		ClientKey:    clientKey,
+	}
+}
+
+func TestScheduleSimOwner(t *testing.T) {
+	sm := makeScheduleTestSimManager(t)
+
+	if err := sm.ScheduleSim(makeScheduleSimRequest("test", ""), nil); !errors.Is(err, ErrNoClientKey) {
+		t.Errorf("got %v, expected ErrNoClientKey", err)
+	}
+	if err := sm.ScheduleSim(makeScheduleSimRequest("test", "alice"), nil); err != nil {
+		t.Fatalf("ScheduleSim: %v", err)
+	}
+
+	cancel := func(key string) error {
+		return sm.CancelScheduledSim(&CancelScheduledSimRequest{SimName: "test", ClientKey: key}, nil)
+	}
+	if err := cancel("bob"); !errors.Is(err, ErrNotScheduleOwner) {
+		t.Errorf("got %v, expected ErrNotScheduleOwner", err)
+	}
+	if err := cancel(""); !errors.Is(err, ErrNoClientKey) {
+		t.Errorf("got %v, expected ErrNoClientKey", err)
+	}
+	if _, ok := sm.scheduledSims["test"]; !ok {
+		t.Fatalf("sim was cancelled by someone other than its owner")
+	}
+	if err := cancel("alice"); err != nil {
+		t.Errorf("owner unable to cancel: %v", err)
+	}
+	if _, ok := sm.scheduledSims["test"]; ok {
+		t.Errorf("sim wasn't cancelled")
+	}
+}
+
+func TestScheduleSimAccountOwner(t *testing.T) {
+	sm := makeScheduleTestSimManager(t)
+	pwHash, err := HashPassword("hunter2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sm.auth = &UserFile{Users: map[string]*UserFileEntry{
+		"alice": {PasswordHash: pwHash, Role: RoleInstructor},
+		"bob":   {PasswordHash: pwHash, Role: RoleInstructor},
+		"root":  {PasswordHash: pwHash, Role: RoleAdmin},
+	}}
+	creds := func(user string) Credentials { return Credentials{Username: user, Password: "hunter2"} }
+
+	for _, name := range []string{"one", "two"} {
+		req := makeScheduleSimRequest(name, "")
+		req.Account = creds("alice")
+		if err := sm.ScheduleSim(req, nil); err != nil {
+			t.Fatalf("ScheduleSim: %v", err)
+		}
+	}
+
+	// With accounts, the client key doesn't make someone the owner.
+	cancel := func(name, user, key string) error {
+		return sm.CancelScheduledSim(&CancelScheduledSimRequest{SimName: name, Account: creds(user), ClientKey: key}, nil)
+	}
+	if err := cancel("one", "bob", ""); !errors.Is(err, ErrNotScheduleOwner) {
+		t.Errorf("got %v, expected ErrNotScheduleOwner", err)
+	}
+	if err := cancel("one", "alice", ""); err != nil {
+		t.Errorf("owner unable to cancel: %v", err)
+	}
+	// Admins can cancel anything.
+	if err := cancel("two", "root", ""); err != nil {
+		t.Errorf("admin unable to cancel: %v", err)
+	}
+	if len(sm.scheduledSims) != 0 {
+		t.Errorf("expected all sims to be cancelled: %v", sm.scheduledSims)
+	}
+}
+
+func TestScheduleSimLimit(t *testing.T) {
+	sm := makeScheduleTestSimManager(t)
+
+	for i := range maxScheduledSimsPerOwner {
+		if err := sm.ScheduleSim(makeScheduleSimRequest(fmt.Sprintf("alice%d", i), "alice"), nil); err != nil {
+			t.Fatalf("ScheduleSim: %v", err)
+		}
+	}
+	if err := sm.ScheduleSim(makeScheduleSimRequest("alice", "alice"), nil); !errors.Is(err, ErrTooManyScheduledSims) {
+		t.Errorf("got %v, expected ErrTooManyScheduledSims", err)
+	}
+	// Others can still schedule sims...
+	if err := sm.ScheduleSim(makeScheduleSimRequest("bob", "bob"), nil); err != nil {
+		t.Errorf("ScheduleSim: %v", err)
+	}
+
+	// ...up to the overall limit.
+	for i := len(sm.scheduledSims); i < maxScheduledSims; i++ {
+		sm.scheduledSims[fmt.Sprintf("other%d", i)] = &scheduledSim{Owner: fmt.Sprintf("key:%d", i)}
+	}
+	if err := sm.ScheduleSim(makeScheduleSimRequest("carol", "carol"), nil); !errors.Is(err, ErrTooManyScheduledSims) {
+		t.Errorf("got %v, expected ErrTooManyScheduledSims", err)
+	}
+}
+
+func TestCancelSimSignUpOwner(t *testing.T) {
+	sm := makeScheduleTestSimManager(t)
+	if err := sm.ScheduleSim(makeScheduleSimRequest("test", "instructor"), nil); err != nil {
+		t.Fatalf("ScheduleSim: %v", err)
+	}
+
+	signUp := func(initials, key string) {
+		t.Helper()
+		var tcp sim.TCP
+		if err := sm.SignUpForSim(&SimSignUpRequest{SimName: "test", Initials: initials, ClientKey: key}, &tcp); err != nil {
+			t.Fatalf("SignUpForSim: %v", err)
+		}
+	}
+	cancel := func(initials, key string) error {
+		return sm.CancelSimSignUp(&SimSignUpRequest{SimName: "test", Initials: initials, ClientKey: key}, nil)
+	}
+
+	signUp("AA", "alice")
+	signUp("BB", "bob")
+	signUp("CC", "carol") // waitlisted
+
+	if err := cancel("AA", "bob"); !errors.Is(err, ErrNotScheduleOwner) {
+		t.Errorf("got %v, expected ErrNotScheduleOwner", err)
+	}
+	if err := cancel("CC", "bob"); !errors.Is(err, ErrNotScheduleOwner) {
+		t.Errorf("got %v, expected ErrNotScheduleOwner for a waitlisted controller", err)
+	}
+	if err := cancel("DD", "bob"); !errors.Is(err, ErrNotSignedUp) {
+		t.Errorf("got %v, expected ErrNotSignedUp", err)
+	}
+	if ss := sm.scheduledSims["test"]; ss.Reservations["1A"] != "AA" {
+		t.Fatalf("AA's reservation was cancelled by another client: %v", ss.Reservations)
+	}
+
+	if err := cancel("AA", "alice"); err != nil {
+		t.Errorf("unable to cancel own sign-up: %v", err)
+	}
+	// Whoever scheduled the sim can cancel anyone's sign-up.
+	if err := cancel("BB", "instructor"); err != nil {
+		t.Errorf("unable to cancel sign-up for own sim: %v", err)
+	}
+
+	ss := sm.scheduledSims["test"]
+	if ss.Reservations["1A"] != "CC" || ss.Reservations["1B"] != "" || len(ss.Waitlist) != 0 {
+		t.Errorf("got reservations %v waitlist %v; expected CC to get 1A", ss.Reservations, ss.Waitlist)
+	}
+	if _, ok := ss.SignUpOwners["AA"]; ok {
+		t.Errorf("cancelled sign-ups should be forgotten")
+	}
+	// CC moved off the waitlist but it's still carol's sign-up.
+	if err := cancel("CC", "carol"); err != nil {
+		t.Errorf("unable to cancel own sign-up: %v", err)
+	}
+}
+
+func TestScheduledSimKeptIfStartFails(t *testing.T) {
+	sm := makeScheduleTestSimManager(t)
+	sm.stateDir = t.TempDir()
+	if err := os.MkdirAll(filepath.Join(sm.stateDir, "scheduled"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	// The scenario no longer exists, so the sim can't be started.
+	ss := &scheduledSim{
+		Request: NewSimRequest{Facility: "N90", NewSimName: "test", GroupName: "N90", ScenarioName: "removed"},
+		StartAt: time.Now(),
+	}
+	sm.scheduledSims["test"] = ss
+	sm.persistScheduledSim("test")
+	// As runScheduler does, take it out of the schedule before starting it.
+	delete(sm.scheduledSims, "test")
+
+	sm.startDueScheduledSim("test", ss)
+
+	if _, ok := sm.sessionsByName["test"]; ok {
+		t.Fatalf("sim shouldn't have been started")
+	}
+	if _, err := os.Stat(sm.scheduledSimPath("test")); err != nil {
+		t.Errorf("scheduled sim file should be kept if it can't be started: %v", err)
+	}
+}
+
+func TestScheduledSimFiles(t *testing.T) {
+	sm := makeScheduleTestSimManager(t)
+	sm.stateDir = t.TempDir()
+	if err := os.MkdirAll(filepath.Join(sm.stateDir, "scheduled"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	req := makeScheduleSimRequest("test", "alice")
+	req.Password = "hunter2"
+	if err := sm.ScheduleSim(req, nil); err != nil {
+		t.Fatalf("ScheduleSim: %v", err)
+	}
+	var tcp sim.TCP
+	if err := sm.SignUpForSim(&SimSignUpRequest{SimName: "test", Initials: "AA", Password: "hunter3", ClientKey: "bob"},
+		&tcp); !errors.Is(err, ErrInvalidPassword) {
+		t.Errorf("got %v, expected ErrInvalidPassword", err)
+	}
+	if err := sm.SignUpForSim(&SimSignUpRequest{SimName: "test", Initials: "AA", Password: "hunter2", ClientKey: "bob"},
+		&tcp); err != nil {
+		t.Fatalf("SignUpForSim: %v", err)
+	}
+
+	restored := makeScheduleTestSimManager(t)
+	restored.stateDir = sm.stateDir
+	restored.restoreScheduledSims()
+	if ss, ok := restored.scheduledSims["test"]; !ok || ss.Reservations[tcp] != "AA" {
+		t.Fatalf("sign-up wasn't persisted: %+v", ss)
+	}
+
+	cancel := &CancelScheduledSimRequest{SimName: "test", Password: "hunter2", ClientKey: "alice"}
+	if err := sm.CancelScheduledSim(cancel, nil); err != nil {
+		t.Fatalf("CancelScheduledSim: %v", err)
+	}
+	// A write for a change made before the sim was cancelled that runs
+	// after it was doesn't bring it back.
+	sm.persistScheduledSim("test")
+	if _, err := os.Stat(sm.scheduledSimPath("test")); !os.IsNotExist(err) {
+		t.Errorf("cancelled sim's file wasn't removed: %v", err)
+	}
+	if err := sm.CancelScheduledSim(cancel, nil); !errors.Is(err, ErrNoNamedSim) {
+		t.Errorf("got %v, expected ErrNoNamedSim", err)
+	}
+}
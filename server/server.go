@@ -84,7 +84,32 @@ import (
 // 74: ERAM MCA, RA, TimeView prefs added
 // 75: ERAM MCA/RA/TimeView positions moved to exported per-view Position fields
 // 76: backfill ERAM BeaconCodeView and CheckList prefs (both were added without bumping the version, so 74/75 saves may have them zero-valued)
-const ViceSerializeVersion = 76
+// 77: per-TCW scorecards and GetScorecard RPC
+// 78: autocontrolled TCWs and SetAutoControlled RPC
+// 79: sim checkpoints and Rewind RPC
+// 80: scenario timelines
+// 81: instructor RPCs for traffic injection and pilot overrides
+// 82: holding stacks with EFC times; ERAM HOLD LIST view prefs
+// 83: TBFM arrival metering timelines; STARS SSA TBFM filter
+// 84: delta-compressed state updates; GetStateUpdate takes GetStateUpdateArgs
+// 85: user accounts and roles; Credentials in NewSimRequest/JoinSimRequest
+// 86: observer connections (JoinSimRequest.Observing, SimState.UserIsObserver)
+// 87: cross-server federation (NAS messages, Sim.LinkFederatedSim)
+// 88: scheduled sims (ScheduleSim/SignUpForSim RPCs, RunningSim.Reservations)
+// 89: runway configuration switches (SwitchScenario/GetScenarioConfigurations RPCs)
+// 90: published missed approaches (Approach.MissedApproach, Aircraft.GoAroundOnMissedApproach)
+// 91: circle-to-land and sidestep clearances (NavApproach.Maneuver, ManeuverRunway, ManeuverStarted)
+// 92: NTZ monitoring (Airport.NTZs, Aircraft.NTZBlunderDistance, LaunchConfig.NTZBlunderRate)
+// 93: fuel modeling (Aircraft.Fuel, FuelBurn, FuelState, FuelStateTime; EmergencyState.Fuel)
+// 94: federation transfer acknowledgements and link secrets (FederatedFacility.PendingTransfers)
+// 95: scheduled sim owners (scheduledSim.Owner, SignUpOwners)
+// 96: scenario of the sim's current configuration (CommonState.Scenario)
+// 97: missed approach holds (NavFixAssignment.MissedApproachHold)
+// 98: landing runway thresholds in tracks (Track.ArrivalRunwayThreshold, ArrivalRunwayElevation)
+// 99: fuel emergency toggle (LaunchConfig.FuelEmergencies)
+// 100: lost communications approaches (NavApproach.LostCommsFix, LostComms)
+// 101: federation message acknowledgements (NASMessage.Seq, FederatedFacility.NextSeq, LastReceivedSeq)
+const ViceSerializeVersion = 101
 
 const ViceServerAddress = "vice.pharr.org"
 const ViceServerPort = 8000 - 50 + ViceRPCVersion
@@ -98,6 +123,26 @@ type ServerLaunchConfig struct {
 	ExtraScenarioBrief string
 	ServerAddress      string // address to use for remote TTS provider
 	IsLocal            bool
+	// RecordDir, if non-empty, gives a directory where recordings of all
+	// sims run by the server are written; see sim.Sim.StartRecording.
+	RecordDir string
+	// StateDir, if non-empty, gives a directory where running sims are
+	// periodically saved so that they can be restored if the server
+	// restarts.
+	StateDir string
+	// UsersFile, if non-empty, gives a JSON file of user accounts; if
+	// provided, users must authenticate to create or join sims. See
+	// UserFile for its format.
+	UsersFile string
+	// APIOrigins gives the origins (e.g., "https://dashboard.example.com")
+	// of web pages that may make cross-origin requests to the JSON API.
+	// By default, only same-origin and non-browser clients may use it.
+	APIOrigins []string
+	// Federation allows instructors to link sims on this server with sims
+	// on other servers; see federation.go. FederationPeers gives the
+	// addresses (host:port) of the servers that sims here may link to.
+	Federation      bool
+	FederationPeers []string
 	// ExitAfterLoad causes LaunchServer to return as soon as scenarios
 	// have been loaded and validated, without entering the accept loop.
 	// Used by CI smoketests to exercise scenario loading (which is where
@@ -159,10 +204,22 @@ func makeServer(config ServerLaunchConfig, lg *log.Logger) (int, func(), util.Er
 		return 0, nil, errorLogger, ""
 	}
 
+	var auth Authenticator
+	if config.UsersFile != "" {
+		uf, err := LoadUserFile(config.UsersFile)
+		if err != nil {
+			errorLogger.Error(err)
+			return 0, nil, errorLogger, ""
+		}
+		lg.Infof("%s: loaded %d user accounts", config.UsersFile, len(uf.Users))
+		auth = uf
+	}
+
 	serverFunc := func() {
 		server := rpc.NewServer()
 
-		sm := NewSimManager(scenarioGroups, scenarioCatalogs, mapSpecs, briefs, config.ServerAddress, config.IsLocal, lg)
+		sm := NewSimManager(scenarioGroups, scenarioCatalogs, mapSpecs, briefs, config.ServerAddress, config.IsLocal, config.RecordDir,
+			config.StateDir, auth, config.APIOrigins, config.Federation, config.FederationPeers, lg)
 		if err := server.Register(sm); err != nil {
 			lg.Errorf("unable to register SimManager: %v", err)
 			os.Exit(1)
@@ -184,6 +241,7 @@ func makeServer(config ServerLaunchConfig, lg *log.Logger) (int, func(), util.Er
 			} else {
 				codec := util.MakeMessagepackServerCodec(cc, lg)
 				codec = util.MakeLoggingServerCodec(conn.RemoteAddr().String(), codec, lg)
+				codec = sm.metrics.instrumentCodec(codec)
 				go server.ServeCodec(codec)
 			}
 		}
@@ -5,16 +5,20 @@
 package server
 
 import (
+	"errors"
 	"fmt"
 	"log/slog"
 	"maps"
 	"slices"
+	"sync/atomic"
 	"time"
 
 	av "github.com/mmp/vice/aviation"
 	"github.com/mmp/vice/log"
 	"github.com/mmp/vice/sim"
 	"github.com/mmp/vice/util"
+
+	"golang.org/x/crypto/bcrypt"
 )
 
 ///////////////////////////////////////////////////////////////////////////
@@ -25,30 +29,88 @@ type simSession struct {
 	scenarioGroup      string
 	scenario           string
 	sim                *sim.Sim
-	password           string
+	passwordHash       []byte // bcrypt hash; empty if no password is required
 	connectionsByToken map[string]*connectionState
 
+	// Connections from before the server restarted that haven't yet
+	// reconnected; see SimManager.Reconnect.
+	restoredConnections map[string]persistedConnection
+	// Connections that were signed off because their client stopped
+	// responding; they can be resumed for ReconnectGracePeriod.
+	droppedConnections map[string]*droppedConnection
+
+	lastUpdateDuration atomic.Int64 // nanoseconds taken by the most recent sim.Update
+
+	// For sims that were scheduled in advance, reservations maps TCWs to
+	// the initials of the controllers they are reserved for. The sim is
+	// held paused until all of them have signed in or lobbyDeadline
+	// passes; see schedule.go.
+	reservations  map[sim.TCW]string
+	lobbyDeadline time.Time
+
+	// federation records when NASMessages were last exchanged with each
+	// facility the sim is federated with and federationSecrets holds the
+	// secrets that sims for other facilities must give to link to this
+	// one; see federation.go.
+	federation        map[string]time.Time
+	federationSecrets map[string]string
+
 	lg *log.Logger
 	mu util.LoggingMutex
 }
 
-func makeSimSession(name, scenarioGroup, scenario, password string, s *sim.Sim, lg *log.Logger) *simSession {
+// makeSimSession returns a session for the given sim; passwordHash should
+// be empty if no password is required or otherwise the result of
+// hashSimPassword.
+func makeSimSession(name, scenarioGroup, scenario string, passwordHash []byte, s *sim.Sim, lg *log.Logger) *simSession {
 	if name != "" {
 		lg = lg.With(slog.String("sim_name", name))
 	}
+
 	return &simSession{
 		name:               name,
 		scenarioGroup:      scenarioGroup,
 		scenario:           scenario,
 		sim:                s,
-		password:           password,
+		passwordHash:       passwordHash,
 		lg:                 lg,
 		connectionsByToken: make(map[string]*connectionState),
+		droppedConnections: make(map[string]*droppedConnection),
+		federation:         make(map[string]time.Time),
+		federationSecrets:  make(map[string]string),
 	}
 }
 
+func (ss *simSession) requiresPassword() bool {
+	return len(ss.passwordHash) > 0
+}
+
+func (ss *simSession) checkPassword(password string) bool {
+	return checkPasswordHash(ss.passwordHash, password)
+}
+
+// hashSimPassword returns the bcrypt hash of a sim's password, or nil if
+// the password is empty. Passwords that can't be hashed are rejected so
+// that the sim isn't left without one.
+func hashSimPassword(password string) ([]byte, error) {
+	if password == "" {
+		return nil, nil
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if errors.Is(err, bcrypt.ErrPasswordTooLong) {
+		return nil, ErrPasswordTooLong
+	}
+	return hash, err
+}
+
+// checkPasswordHash returns whether the password matches the given bcrypt
+// hash; any password matches an empty hash.
+func checkPasswordHash(hash []byte, password string) bool {
+	return len(hash) == 0 || bcrypt.CompareHashAndPassword(hash, []byte(password)) == nil
+}
+
 func makeLocalSimSession(s *sim.Sim, lg *log.Logger) *simSession {
-	return makeSimSession("", "", "", "", s, lg)
+	return makeSimSession("", "", "", nil, s, lg)
 }
 
 // connectionState holds state for a single human's connection to a sim at a TCW.
@@ -56,6 +118,7 @@ type connectionState struct {
 	token               string
 	tcw                 sim.TCW
 	initials            string
+	role                Role
 	lastUpdateCall      time.Time
 	warnedNoUpdateCalls bool
 	stateUpdateEventSub *sim.EventsSubscription
@@ -64,12 +127,37 @@ type connectionState struct {
 	// delivered to this client via GetStateUpdate. The long-poll waits for
 	// the sim's pubGen to advance past this value.
 	lastSentGen uint64
+	// lastSentUpdate is the full state update that was most recently
+	// delivered; subsequent updates are sent as deltas relative to it.
+	lastSentUpdate *sim.StateUpdate
+}
+
+// observing returns whether the connection is an observer that watches
+// its TCW without occupying it.
+func (c *connectionState) observing() bool {
+	return c.role == RoleObserver
 }
 
+// droppedConnection is a connection that was signed off because its
+// client stopped responding, e.g. due to a flaky network. Its event
+// subscription is kept so that if the client reconnects with the same
+// token within ReconnectGracePeriod, it receives the radio transmissions,
+// handoff offers, and other events that it missed.
+type droppedConnection struct {
+	persistedConnection
+	privileged bool
+	eventSub   *sim.EventsSubscription
+	droppedAt  time.Time
+}
+
+// ReconnectGracePeriod is how long a dropped connection is held for its
+// client to reconnect.
+const ReconnectGracePeriod = 5 * time.Minute
+
 ///////////////////////////////////////////////////////////////////////////
 // Controller Lifecycle
 
-func (ss *simSession) AddHumanController(token string, tcw sim.TCW, initials string,
+func (ss *simSession) AddHumanController(token string, tcw sim.TCW, initials string, role Role,
 	sub *sim.EventsSubscription) {
 	ss.mu.Lock(ss.lg)
 	defer ss.mu.Unlock(ss.lg)
@@ -78,6 +166,7 @@ func (ss *simSession) AddHumanController(token string, tcw sim.TCW, initials str
 		token:               token,
 		tcw:                 tcw,
 		initials:            initials,
+		role:                role,
 		lastUpdateCall:      time.Now(),
 		stateUpdateEventSub: sub,
 	}
@@ -89,7 +178,8 @@ func (ss *simSession) AddHumanController(token string, tcw sim.TCW, initials str
 type signOffResult struct {
 	TCW        sim.TCW
 	Initials   string
-	UsersAtTCW int
+	Observer   bool
+	UsersAtTCW int // not including observers
 }
 
 func (ss *simSession) SignOff(token string) (signOffResult, bool) {
@@ -104,6 +194,7 @@ func (ss *simSession) SignOff(token string) (signOffResult, bool) {
 	result := signOffResult{
 		TCW:      conn.tcw,
 		Initials: conn.initials,
+		Observer: conn.observing(),
 	}
 
 	// Unsubscribe from events before deleting
@@ -115,7 +206,7 @@ func (ss *simSession) SignOff(token string) (signOffResult, bool) {
 
 	// Count remaining users at this TCW
 	for _, c := range ss.connectionsByToken {
-		if c.tcw == result.TCW {
+		if c.tcw == result.TCW && !c.observing() {
 			result.UsersAtTCW++
 		}
 	}
@@ -129,23 +220,46 @@ func (ss *simSession) SignOff(token string) (signOffResult, bool) {
 func (ss *simSession) CullIdleControllers(sm *SimManager) {
 	ss.mu.Lock(ss.lg)
 
+	for token, dc := range ss.droppedConnections {
+		if time.Since(dc.droppedAt) > ReconnectGracePeriod {
+			ss.lg.Infof("%s (%s): giving up on reconnection", dc.TCW, dc.Initials)
+			dc.eventSub.Unsubscribe()
+			delete(ss.droppedConnections, token)
+		}
+	}
+
 	var tokensToSignOff []string
 	for token, conn := range ss.connectionsByToken {
 		if time.Since(conn.lastUpdateCall) > StateUpdateWarn {
 			if !conn.warnedNoUpdateCalls {
 				conn.warnedNoUpdateCalls = true
 				ss.lg.Warnf("%s: no messages for %s", conn.tcw, StateUpdateWarn)
-				ss.sim.PostEvent(sim.Event{
-					Type: sim.StatusMessageEvent,
-					WrittenText: fmt.Sprintf("%s (%s) has not been heard from for %s. Connection lost?",
-						string(conn.tcw), conn.initials, StateUpdateWarn),
-				})
+				if !conn.observing() {
+					ss.sim.PostEvent(sim.Event{
+						Type: sim.StatusMessageEvent,
+						WrittenText: fmt.Sprintf("%s (%s) has not been heard from for %s. Connection lost?",
+							string(conn.tcw), conn.initials, StateUpdateWarn),
+					})
+				}
 			}
 
 			if time.Since(conn.lastUpdateCall) > StateUpdateKick {
 				ss.lg.Warnf("%s (%s): signing off idle controller", conn.tcw, conn.initials)
 				// Collect tokens to sign off after releasing the lock
 				tokensToSignOff = append(tokensToSignOff, token)
+
+				// Hold on to the event subscription in case the client
+				// reconnects; clearing it here keeps SignOff from
+				// unsubscribing it.
+				if conn.stateUpdateEventSub != nil {
+					ss.droppedConnections[token] = &droppedConnection{
+						persistedConnection: persistedConnection{TCW: conn.tcw, Initials: conn.initials, Role: conn.role},
+						privileged:          !conn.observing() && ss.sim.TCWIsPrivileged(conn.tcw),
+						eventSub:            conn.stateUpdateEventSub,
+						droppedAt:           time.Now(),
+					}
+					conn.stateUpdateEventSub = nil
+				}
 			}
 		}
 	}
@@ -161,11 +275,12 @@ func (ss *simSession) CullIdleControllers(sm *SimManager) {
 }
 
 // updateSimPauseState pauses the sim if no humans are connected, unpauses if at least one.
-// Must be called with ss.mu held.
+// Observers don't count. Scheduled sims are also kept paused while they are waiting
+// for reserved controllers. Must be called with ss.mu held.
 func (ss *simSession) updateSimPauseState() {
 	hasHumans := util.SeqContainsFunc(maps.Values(ss.connectionsByToken),
-		func(conn *connectionState) bool { return conn.tcw != "" })
-	ss.sim.SetPausedByServer(!hasHumans)
+		func(conn *connectionState) bool { return conn.tcw != "" && !conn.observing() })
+	ss.sim.SetPausedByServer(!hasHumans || ss.updateLobby())
 }
 
 ///////////////////////////////////////////////////////////////////////////
@@ -188,8 +303,9 @@ const (
 // GetStateUpdate is the main entry point for periodic state updates from a controller. It is a
 // long-poll: when the caller's lastSentGen is already behind the sim's publication generation, it
 // returns the current snapshot immediately; otherwise it parks until the sim publishes new state,
-// the heartbeat timer fires, or the sim is destroyed.
-func (ss *simSession) GetStateUpdate(token string) (*SimStateUpdate, error) {
+// the heartbeat timer fires, or the sim is destroyed. If generation matches the GenerationIndex of
+// the previous update returned, the tracks and flight plans are returned as a delta relative to it.
+func (ss *simSession) GetStateUpdate(token string, generation int) (*SimStateUpdate, error) {
 	ss.mu.Lock(ss.lg)
 	conn, ok := ss.connectionsByToken[token]
 	if !ok {
@@ -202,10 +318,12 @@ func (ss *simSession) GetStateUpdate(token string) (*SimStateUpdate, error) {
 	if conn.warnedNoUpdateCalls {
 		conn.warnedNoUpdateCalls = false
 		ss.lg.Warnf("%s(%s): connection re-established", conn.tcw, conn.initials)
-		ss.sim.PostEvent(sim.Event{
-			Type:        sim.StatusMessageEvent,
-			WrittenText: fmt.Sprintf("%s (%s) is back online.", string(conn.tcw), conn.initials),
-		})
+		if !conn.observing() {
+			ss.sim.PostEvent(sim.Event{
+				Type:        sim.StatusMessageEvent,
+				WrittenText: fmt.Sprintf("%s (%s) is back online.", string(conn.tcw), conn.initials),
+			})
+		}
 	}
 
 	tcw := conn.tcw
@@ -224,17 +342,27 @@ func (ss *simSession) GetStateUpdate(token string) (*SimStateUpdate, error) {
 	// "Attempted to get with unregistered subscription" errors.
 	ss.mu.Lock(ss.lg)
 	var events []sim.Event
+	var base *sim.StateUpdate
 	if conn, ok = ss.connectionsByToken[token]; ok {
 		conn.lastSentGen = gen
 		events = eventSub.Get()
+		base = conn.lastSentUpdate
+		conn.lastSentUpdate = &update
 	}
 	ss.mu.Unlock(ss.lg)
 
-	return &SimStateUpdate{
+	su := &SimStateUpdate{
 		StateUpdate: update,
 		ActiveTCWs:  ss.GetActiveTCWs(),
 		Events:      ss.sim.PrepareRadioTransmissionsForTCW(tcw, events),
-	}, nil
+	}
+	if base != nil && generation != 0 && base.GenerationIndex == generation {
+		if delta, ok := sim.MakeStateDelta(base, &update); ok {
+			su.Delta = &delta
+			su.Tracks, su.UnassociatedFlightPlans = nil, nil
+		}
+	}
+	return su, nil
 }
 
 // MakeControllerContext returns a ControllerContext for the given token, or nil if not found.
@@ -250,12 +378,39 @@ func (ss *simSession) MakeControllerContext(token string) *controllerContext {
 		token:    token,
 		tcw:      conn.tcw,
 		initials: conn.initials,
+		role:     conn.role,
 		sim:      ss.sim,
 		eventSub: conn.stateUpdateEventSub,
 		session:  ss,
 	}
 }
 
+// connectionCounts returns the number of controllers and observers
+// connected to the session and the number of connections, from before a
+// server restart or that were dropped, that haven't reconnected.
+func (ss *simSession) connectionCounts() (controllers, observers, restored int) {
+	ss.mu.Lock(ss.lg)
+	defer ss.mu.Unlock(ss.lg)
+
+	for _, conn := range ss.connectionsByToken {
+		if conn.observing() {
+			observers++
+		} else {
+			controllers++
+		}
+	}
+	return controllers, observers, len(ss.restoredConnections) + len(ss.droppedConnections)
+}
+
+// isObserver returns whether the given token's connection is an observer.
+func (ss *simSession) isObserver(token string) bool {
+	ss.mu.Lock(ss.lg)
+	defer ss.mu.Unlock(ss.lg)
+
+	conn, ok := ss.connectionsByToken[token]
+	return ok && conn.observing()
+}
+
 ///////////////////////////////////////////////////////////////////////////
 // Position/TCW State Queries (for GetRunningSims)
 
@@ -265,7 +420,9 @@ func (ss *simSession) GetCurrentConsolidation() map[sim.TCW]TCPConsolidation {
 
 	tcwInitials := make(map[sim.TCW][]string)
 	for _, conn := range ss.connectionsByToken {
-		tcwInitials[conn.tcw] = append(tcwInitials[conn.tcw], conn.initials)
+		if !conn.observing() {
+			tcwInitials[conn.tcw] = append(tcwInitials[conn.tcw], conn.initials)
+		}
 	}
 
 	// Get consolidation from sim and add initials
@@ -281,11 +438,11 @@ func (ss *simSession) GetCurrentConsolidation() map[sim.TCW]TCPConsolidation {
 }
 
 // getActiveTCWs returns the set of TCWs that have at least one human signed in.
-// Must be called with ss.mu held.
+// Observers aren't signed in. Must be called with ss.mu held.
 func (ss *simSession) getActiveTCWs() []sim.TCW {
 	var tcws []string
 	for _, conn := range ss.connectionsByToken {
-		if conn.tcw != "" {
+		if conn.tcw != "" && !conn.observing() {
 			tcws = append(tcws, string(conn.tcw))
 		}
 	}
@@ -0,0 +1,309 @@
+// server/session_test.go
+// Copyright(c) 2022-2026 vice contributors, licensed under the GNU Public License, Version 3.
+// SPDX: GPL-3.0-only
+
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	av "github.com/mmp/vice/aviation"
+	"github.com/mmp/vice/sim"
+)
+
+func TestSimPassword(t *testing.T) {
+	// No password: anyone can join.
+	hash, err := hashSimPassword("")
+	if err != nil || hash != nil {
+		t.Fatalf("empty password: got hash %v, error %v", hash, err)
+	}
+	ss := makeSimSession("test", "", "", hash, nil, makeTestSimManager(t).lg)
+	if ss.requiresPassword() || !ss.checkPassword("anything") {
+		t.Errorf("session without a password should accept any password")
+	}
+
+	hash, err = hashSimPassword("hunter2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ss = makeSimSession("test", "", "", hash, nil, makeTestSimManager(t).lg)
+	if !ss.requiresPassword() {
+		t.Errorf("expected session to require a password")
+	}
+	if !ss.checkPassword("hunter2") {
+		t.Errorf("correct password rejected")
+	}
+	for _, pw := range []string{"", "hunter3", "HUNTER2"} {
+		if ss.checkPassword(pw) {
+			t.Errorf("incorrect password %q accepted", pw)
+		}
+	}
+
+	// bcrypt can't hash passwords longer than 72 bytes; they must be
+	// rejected rather than leaving the sim without a password.
+	if hash, err := hashSimPassword(strings.Repeat("x", 73)); !errors.Is(err, ErrPasswordTooLong) || hash != nil {
+		t.Errorf("long password: got hash %v, error %v; expected ErrPasswordTooLong", hash, err)
+	}
+}
+
+func TestNewSimRejectsUnhashablePassword(t *testing.T) {
+	sm := makeTestSimManager(t)
+
+	var result NewSimResult
+	err := sm.NewSim(&NewSimRequest{
+		Facility:     "N90",
+		NewSimName:   "test",
+		GroupName:    "N90",
+		ScenarioName: "default",
+		Password:     strings.Repeat("x", 100),
+		Initials:     "XX",
+	}, &result)
+	if !errors.Is(err, ErrPasswordTooLong) {
+		t.Errorf("got error %v; expected ErrPasswordTooLong", err)
+	}
+	if len(sm.sessionsByName) != 0 {
+		t.Errorf("sim was created despite the invalid password")
+	}
+}
+
+func TestPersistedPasswordHash(t *testing.T) {
+	hash, err := hashSimPassword("hunter2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Running sims
+	b, err := json.Marshal(persistedSession{Version: ViceSerializeVersion, Name: "test", PasswordHash: hash})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ps persistedSession
+	if err := json.Unmarshal(b, &ps); err != nil {
+		t.Fatal(err)
+	}
+	ss := makeSimSession(ps.Name, "", "", ps.PasswordHash, nil, makeTestSimManager(t).lg)
+	if !ss.checkPassword("hunter2") || ss.checkPassword("hunter3") {
+		t.Errorf("restored session doesn't check its password")
+	}
+
+	// Scheduled sims
+	sm := makeTestSimManager(t)
+	sm.stateDir = t.TempDir()
+	if err := os.MkdirAll(filepath.Join(sm.stateDir, "scheduled"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	sm.scheduledSims["test"] = &scheduledSim{
+		Request:      NewSimRequest{NewSimName: "test"},
+		PasswordHash: hash,
+		StartAt:      time.Now().Add(time.Hour),
+	}
+	sm.persistScheduledSim("test")
+
+	restored := makeTestSimManager(t)
+	restored.stateDir = sm.stateDir
+	restored.restoreScheduledSims()
+	rs, ok := restored.scheduledSims["test"]
+	if !ok {
+		t.Fatalf("scheduled sim not restored")
+	}
+	if !checkPasswordHash(rs.PasswordHash, "hunter2") || checkPasswordHash(rs.PasswordHash, "hunter3") {
+		t.Errorf("restored scheduled sim doesn't check its password")
+	}
+}
+
+func TestRestoreIncompatibleVersion(t *testing.T) {
+	sm := makeTestSimManager(t)
+	sm.stateDir = t.TempDir()
+
+	b, err := json.Marshal(persistedSession{Version: ViceSerializeVersion - 1, Name: "old"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fn := sm.sessionStatePath("old")
+	if err := os.WriteFile(fn, b, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	sm.restoreSessions()
+
+	if len(sm.sessionsByName) != 0 {
+		t.Errorf("restored a sim saved by an incompatible version")
+	}
+	// The file is set aside rather than being skipped again next time.
+	if _, err := os.Stat(fn); !os.IsNotExist(err) {
+		t.Errorf("%s: expected file to be renamed", fn)
+	}
+	if _, err := os.Stat(fmt.Sprintf("%s.v%d", fn, ViceSerializeVersion-1)); err != nil {
+		t.Errorf("%v", err)
+	}
+}
+
+// makeTestSessionWithSim returns a session with a sim that has the
+// positions 1A and 1B and registers it with the SimManager.
+func makeTestSessionWithSim(t *testing.T, sm *SimManager) *simSession {
+	t.Helper()
+
+	if av.DB == nil {
+		av.DB = &av.StaticDatabase{Airports: map[string]av.FAAAirport{}}
+		t.Cleanup(func() { av.DB = nil })
+	}
+
+	s := sim.NewSim(sim.NewSimConfiguration{
+		Facility:         "N90",
+		ControlPositions: map[sim.TCP]*av.Controller{"1A": {Position: "1A"}, "1B": {Position: "1B"}},
+		ControllerConfiguration: &sim.ControllerConfiguration{
+			DefaultConsolidation: sim.PositionConsolidation{"1A": {"1B"}},
+		},
+		StartTime: time.Now(),
+	}, sm.lg)
+
+	session := makeSimSession("test", "", "", nil, s, sm.lg)
+	sm.sessionsByName["test"] = session
+	sm.briefs = newBriefRegistry()
+	return session
+}
+
+// signOnTestController signs a controller on to the given TCW and marks
+// it as not having been heard from since the given time.
+func signOnTestController(t *testing.T, sm *SimManager, session *simSession, token string, tcw sim.TCW,
+	lastUpdate time.Time) *sim.EventsSubscription {
+	t.Helper()
+
+	_, sub, err := session.sim.SignOn(tcw, nil)
+	if err != nil {
+		t.Fatalf("SignOn %s: %v", tcw, err)
+	}
+	session.AddHumanController(token, tcw, "XX", RoleController, sub)
+	session.connectionsByToken[token].lastUpdateCall = lastUpdate
+	sm.sessionsByToken[token] = session
+	return sub
+}
+
+func TestDroppedConnectionReconnect(t *testing.T) {
+	sm := makeTestSimManager(t)
+	session := makeTestSessionWithSim(t, sm)
+
+	sub := signOnTestController(t, sm, session, "token", "1A", time.Now().Add(-2*StateUpdateKick))
+	session.sim.SetPrivilegedTCW("1A", true)
+
+	session.CullIdleControllers(sm)
+
+	if _, ok := session.connectionsByToken["token"]; ok {
+		t.Fatalf("idle controller still signed on")
+	}
+	if _, ok := sm.sessionsByToken["token"]; ok {
+		t.Errorf("idle controller's token still maps to the session")
+	}
+	dc, ok := session.droppedConnections["token"]
+	if !ok {
+		t.Fatalf("idle controller's connection not held for reconnection")
+	}
+	if dc.TCW != "1A" || dc.Initials != "XX" || dc.Role != RoleController || !dc.privileged || dc.eventSub != sub {
+		t.Errorf("unexpected dropped connection %+v", dc)
+	}
+	if _, ok := session.persistedConnections()["token"]; !ok {
+		t.Errorf("dropped connection should be persisted")
+	}
+
+	// Events posted while the controller is gone are delivered after they
+	// reconnect.
+	session.sim.PostEvent(sim.Event{Type: sim.StatusMessageEvent, WrittenText: "while you were out"})
+
+	var result NewSimResult
+	if err := sm.Reconnect("token", &result); err != nil {
+		t.Fatalf("Reconnect: %v", err)
+	}
+	if len(session.droppedConnections) != 0 {
+		t.Errorf("dropped connection not removed after reconnecting")
+	}
+	conn, ok := session.connectionsByToken["token"]
+	if !ok || conn.tcw != "1A" || conn.stateUpdateEventSub != sub {
+		t.Fatalf("reconnected controller not signed on to 1A with its old subscription: %+v", conn)
+	}
+	if sm.sessionsByToken["token"] != session {
+		t.Errorf("reconnected token doesn't map to the session")
+	}
+	if !session.sim.TCWIsPrivileged("1A") {
+		t.Errorf("privileges not restored after reconnecting")
+	}
+	if !slices.ContainsFunc(sub.Get(), func(e sim.Event) bool { return e.WrittenText == "while you were out" }) {
+		t.Errorf("event posted while disconnected not delivered")
+	}
+}
+
+func TestDroppedConnectionExpires(t *testing.T) {
+	sm := makeTestSimManager(t)
+	session := makeTestSessionWithSim(t, sm)
+
+	// Recently heard from: not dropped.
+	signOnTestController(t, sm, session, "active", "1B", time.Now())
+	signOnTestController(t, sm, session, "token", "1A", time.Now().Add(-2*StateUpdateKick))
+	session.CullIdleControllers(sm)
+
+	if _, ok := session.connectionsByToken["active"]; !ok {
+		t.Errorf("active controller was signed off")
+	}
+	if _, ok := session.droppedConnections["active"]; ok {
+		t.Errorf("active controller's connection was dropped")
+	}
+
+	dc, ok := session.droppedConnections["token"]
+	if !ok {
+		t.Fatalf("idle controller's connection not held for reconnection")
+	}
+
+	// Within the grace period, it's kept.
+	dc.droppedAt = time.Now().Add(-ReconnectGracePeriod / 2)
+	session.CullIdleControllers(sm)
+	if _, ok := session.droppedConnections["token"]; !ok {
+		t.Fatalf("dropped connection discarded within the grace period")
+	}
+
+	dc.droppedAt = time.Now().Add(-2 * ReconnectGracePeriod)
+	session.CullIdleControllers(sm)
+	if _, ok := session.droppedConnections["token"]; ok {
+		t.Errorf("dropped connection kept after the grace period")
+	}
+
+	var result NewSimResult
+	if err := sm.Reconnect("token", &result); !errors.Is(err, ErrNoSimForControllerToken) {
+		t.Errorf("got %v, expected ErrNoSimForControllerToken", err)
+	}
+}
+
+func TestDroppedConnectionTCWTaken(t *testing.T) {
+	sm := makeTestSimManager(t)
+	session := makeTestSessionWithSim(t, sm)
+
+	signOnTestController(t, sm, session, "token", "1A", time.Now().Add(-2*StateUpdateKick))
+	session.CullIdleControllers(sm)
+	if _, ok := session.droppedConnections["token"]; !ok {
+		t.Fatalf("idle controller's connection not held for reconnection")
+	}
+
+	// Someone else signs on to 1A in the meantime; the original
+	// controller rejoins as relief.
+	signOnTestController(t, sm, session, "other", "1A", time.Now())
+
+	var result NewSimResult
+	if err := sm.Reconnect("token", &result); err != nil {
+		t.Fatalf("Reconnect: %v", err)
+	}
+	n := 0
+	for _, conn := range session.connectionsByToken {
+		if conn.tcw == "1A" {
+			n++
+		}
+	}
+	if n != 2 {
+		t.Errorf("expected 2 controllers at 1A, got %d", n)
+	}
+}
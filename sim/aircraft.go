@@ -89,6 +89,10 @@ type Aircraft struct {
 	MissingFlightPlan bool
 
 	GoAroundDistance *float32
+	// If non-nil, the aircraft blunders toward the adjacent final when
+	// it's within this distance of the end of an approach to a runway
+	// with an NTZ.
+	NTZBlunderDistance *float32
 
 	// Set when tower sends aircraft around for spacing; affects the contact message.
 	SentAroundForSpacing bool
@@ -96,6 +100,8 @@ type Aircraft struct {
 	SpacingGoAroundDeclined bool
 	// Set when going around on runway heading (vs a specific assigned heading).
 	GoAroundOnRunwayHeading bool
+	// Set when going around on the approach's published missed approach.
+	GoAroundOnMissedApproach bool
 	// Set when the aircraft has gone around; prevents the arrival drop
 	// filter from dropping its flight plan.
 	WentAround bool
@@ -115,6 +121,22 @@ type Aircraft struct {
 
 	EmergencyState *EmergencyState
 
+	// Fuel remaining in pounds and the nominal fuel burn in pounds per
+	// hour; fuel isn't modeled if FuelBurn is zero. See fuel.go.
+	Fuel          float32
+	FuelBurn      float32
+	FuelState     FuelState
+	FuelStateTime Time // when FuelState last changed
+
+	// ForcePilotMixUp is set for scripted pilot errors; the pilot mixes up
+	// the next instruction they are given regardless of the pilot error
+	// interval.
+	ForcePilotMixUp bool
+
+	// NORDO is set when an instructor has made the aircraft lose radio
+	// contact; it doesn't respond to or initiate transmissions.
+	NORDO bool
+
 	LastRadioTransmission Time
 
 	// LastAddressingForm tracks how the controller last addressed this aircraft.
@@ -353,7 +375,7 @@ func (ac *Aircraft) Update(model *wx.Model, simTime Time, arrivalMETAR *wx.METAR
 func (ac *Aircraft) PilotMixUp() av.CommandIntent {
 	return av.MixUpIntent{
 		Callsign:    ac.ADSBCallsign,
-		IsEmergency: ac.EmergencyState != nil,
+		IsEmergency: ac.IsEmergency(),
 	}
 }
 
@@ -539,6 +561,10 @@ func (ac *Aircraft) CancelApproachClearance() av.CommandIntent {
 	return ac.Nav.CancelApproachClearance()
 }
 
+func (ac *Aircraft) Breakout(heading int, turn av.TurnDirection, alt *float32) av.CommandIntent {
+	return ac.Nav.Breakout(math.MagneticHeading(heading), turn, alt)
+}
+
 func (ac *Aircraft) ClimbViaSID(simTime Time) av.CommandIntent {
 	return ac.Nav.ClimbViaSID(simTime.NavTime())
 }
@@ -587,7 +613,7 @@ func (ac *Aircraft) InterceptApproach(lg *log.Logger) av.CommandIntent {
 }
 
 func (ac *Aircraft) InitializeArrival(ap *av.Airport, arr *av.Arrival, nmPerLongitude float32, magneticVariation float32,
-	model *wx.Model, simTime Time, lg *log.Logger) error {
+	model *wx.Model, simTime Time, r *rand.Rand, lg *log.Logger) error {
 	ac.STAR = arr.STAR
 	ac.STARRunwayWaypoints = arr.RunwayWaypoints[ac.FlightPlan.ArrivalAirport]
 
@@ -597,7 +623,6 @@ func (ac *Aircraft) InitializeArrival(ap *av.Airport, arr *av.Arrival, nmPerLong
 		return ErrUnknownAircraftType
 	}
 
-	r := rand.Make()
 	if len(arr.CruiseAltitudes) > 0 {
 		ac.FlightPlan.Altitude = rand.SampleSlice(r, arr.CruiseAltitudes)
 	} else {
@@ -612,7 +637,7 @@ func (ac *Aircraft) InitializeArrival(ap *av.Airport, arr *av.Arrival, nmPerLong
 	ac.TypeOfFlight = av.FlightTypeArrival
 
 	nav := nav.MakeArrivalNav(ac.ADSBCallsign, arr, ac.FlightPlan, perf, nmPerLongitude, magneticVariation, model,
-		simTime.NavTime(), lg)
+		simTime.NavTime(), r, lg)
 	if nav == nil {
 		return fmt.Errorf("error initializing Nav")
 	}
@@ -631,7 +656,7 @@ func (ac *Aircraft) InitializeArrival(ap *av.Airport, arr *av.Arrival, nmPerLong
 
 func (ac *Aircraft) InitializeDeparture(ap *av.Airport, departureAirport string, dep *av.Departure,
 	runway string, exitRoute av.ExitRoute, nmPerLongitude float32, magneticVariation float32,
-	model *wx.Model, simTime Time, lg *log.Logger) error {
+	model *wx.Model, simTime Time, r *rand.Rand, lg *log.Logger) error {
 	wp := util.DuplicateSlice(exitRoute.Waypoints)
 	wp = append(wp, dep.RouteWaypoints...)
 	wp = util.FilterSliceInPlace(wp, func(wp av.Waypoint) bool { return !wp.Location.IsZero() })
@@ -652,7 +677,6 @@ func (ac *Aircraft) InitializeDeparture(ap *av.Airport, departureAirport string,
 	ac.FlightPlan.Exit = dep.Exit
 	ac.FlightPlan.DepartureRunway = runway
 
-	r := rand.Make()
 	idx := rand.SampleFiltered(r, dep.Altitudes, func(alt int) bool { return alt <= int(perf.Ceiling) })
 	if idx == -1 {
 		ac.FlightPlan.Altitude =
@@ -666,7 +690,7 @@ func (ac *Aircraft) InitializeDeparture(ap *av.Airport, departureAirport string,
 	randomizeAltitudeRange := ac.FlightPlan.Rules == av.FlightRulesVFR
 	nav := nav.MakeDepartureNav(ac.ADSBCallsign, ac.FlightPlan, perf, exitRoute.AssignedAltitude,
 		exitRoute.ClearedAltitude, wp, randomizeAltitudeRange,
-		nmPerLongitude, magneticVariation, model, simTime.NavTime(), lg)
+		nmPerLongitude, magneticVariation, model, simTime.NavTime(), r, lg)
 	if nav == nil {
 		return fmt.Errorf("error initializing Nav")
 	}
@@ -679,7 +703,7 @@ func (ac *Aircraft) InitializeDeparture(ap *av.Airport, departureAirport string,
 
 func (ac *Aircraft) InitializeVFRDeparture(ap *av.Airport, wps av.WaypointArray,
 	randomizeAltitudeRange bool, nmPerLongitude float32, magneticVariation float32, model *wx.Model,
-	simTime Time, lg *log.Logger) error {
+	simTime Time, r *rand.Rand, lg *log.Logger) error {
 	wp := util.DuplicateSlice(wps)
 
 	perf, ok := av.DB.AircraftPerformance[ac.FlightPlan.AircraftType]
@@ -692,7 +716,7 @@ func (ac *Aircraft) InitializeVFRDeparture(ap *av.Airport, wps av.WaypointArray,
 
 	nav := nav.MakeDepartureNav(ac.ADSBCallsign, ac.FlightPlan, perf, 0, /* assigned alt */
 		ac.FlightPlan.Altitude /* cleared alt */, wp,
-		randomizeAltitudeRange, nmPerLongitude, magneticVariation, model, simTime.NavTime(), lg)
+		randomizeAltitudeRange, nmPerLongitude, magneticVariation, model, simTime.NavTime(), r, lg)
 	if nav == nil {
 		return fmt.Errorf("error initializing Nav")
 	}
@@ -703,14 +727,13 @@ func (ac *Aircraft) InitializeVFRDeparture(ap *av.Airport, wps av.WaypointArray,
 }
 
 func (ac *Aircraft) InitializeOverflight(of *av.Overflight, nmPerLongitude float32,
-	magneticVariation float32, model *wx.Model, simTime Time, lg *log.Logger) error {
+	magneticVariation float32, model *wx.Model, simTime Time, r *rand.Rand, lg *log.Logger) error {
 	perf, ok := av.DB.AircraftPerformance[ac.FlightPlan.AircraftType]
 	if !ok {
 		lg.Errorf("%s: unable to get performance model", ac.FlightPlan.AircraftType)
 		return ErrUnknownAircraftType
 	}
 
-	r := rand.Make()
 	if len(of.CruiseAltitudes) > 0 {
 		ac.FlightPlan.Altitude = rand.SampleSlice(r, of.CruiseAltitudes)
 	} else {
@@ -721,7 +744,7 @@ func (ac *Aircraft) InitializeOverflight(of *av.Overflight, nmPerLongitude float
 	ac.TypeOfFlight = av.FlightTypeOverflight
 
 	nav := nav.MakeOverflightNav(ac.ADSBCallsign, of, ac.FlightPlan, perf, nmPerLongitude,
-		magneticVariation, model, simTime.NavTime(), lg)
+		magneticVariation, model, simTime.NavTime(), r, lg)
 	if nav == nil {
 		return fmt.Errorf("error initializing Nav")
 	}
@@ -958,9 +981,19 @@ func (ac *Aircraft) DisassociateFlightPlan() *NASFlightPlan {
 	return fp
 }
 
+// IsEmergency returns true if the aircraft has declared an emergency.
+// Minimum fuel is handled as a fuel emergency's first stage but doesn't
+// make the aircraft an emergency aircraft.
+func (ac *Aircraft) IsEmergency() bool {
+	return ac.EmergencyState != nil && (!ac.EmergencyState.Fuel || ac.FuelState != FuelStateMinimum)
+}
+
 func (ac *Aircraft) DivertToAirport(ap string) {
 	ac.FlightPlan.ArrivalAirport = ap
 	ac.TypeOfFlight = av.FlightTypeArrival
+	if ac.NASFlightPlan != nil {
+		ac.NASFlightPlan.ArrivalAirport = ap
+	}
 
 	ac.Nav.DivertToAirport(ap)
 }
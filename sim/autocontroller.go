@@ -0,0 +1,496 @@
+// sim/autocontroller.go
+// Copyright(c) 2022-2026 vice contributors, licensed under the GNU Public License, Version 3.
+// SPDX: GPL-3.0-only
+
+package sim
+
+import (
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+
+	av "github.com/mmp/vice/aviation"
+	"github.com/mmp/vice/math"
+	"github.com/mmp/vice/util"
+)
+
+// The autocontroller is a rule-based controller that works the traffic
+// for TCWs that no human is signed in to. Unlike virtual controllers,
+// which only hand aircraft off and let them fly their procedures, it
+// actively controls them: arrivals are vectored to intercept the final
+// approach course and cleared for the approach, with speed control and
+// extended vectors used to sequence them; departures are released and
+// cleared to climb via their SID; and tracks are handed off and switched
+// to the next controller's frequency. Its instructions go through the same
+// command paths as a human controller's.
+
+const (
+	// How often the autocontroller looks at its traffic, in seconds.
+	autoControlInterval = 5
+	// Arrivals are vectored once they are within this distance (nm) of
+	// the runway threshold or have reached the end of their route.
+	autoVectorDistance = 25
+	// The intercept point is this far (nm) outside the final approach fix.
+	autoInterceptGate = 3
+	// Arrivals within this distance (nm) of the extended centerline are
+	// turned to intercept it.
+	autoInterceptWidth = 3.5
+	// Maximum intercept angle, in degrees.
+	autoInterceptAngle = 30
+	// Buffer (nm) added to the required separation on final.
+	autoSpacingBuffer = 1.5
+	// Descent gradient (ft/nm) used for assigning altitudes during
+	// vectoring.
+	autoDescentGradient = 300
+)
+
+// autoController holds the autocontroller's bookkeeping. It isn't
+// serialized; if it is lost, the only consequence is that a few
+// instructions may be repeated.
+type autoController struct {
+	// The one-time instructions (e.g., "DVS") that have been issued to
+	// each aircraft.
+	issued map[av.ADSBCallsign]map[string]bool
+}
+
+type autoCommand struct {
+	tcw      TCW
+	callsign av.ADSBCallsign
+	commands []string
+}
+
+// SetAutoControlled enables or disables the autocontroller at the given
+// TCW. A human signing on to the TCW also disables it.
+func (s *Sim) SetAutoControlled(tcw TCW, auto bool) error {
+	s.mu.Lock(s.lg)
+	defer s.mu.Unlock(s.lg)
+
+	s.recordInput(RecordedInput{TCW: tcw, Type: RecordedAutoControl, AutoControlled: auto})
+
+	cons, ok := s.State.CurrentConsolidation[tcw]
+	if !ok {
+		return ErrTCWNotFound
+	}
+	if cons.AutoControlled == auto {
+		return nil
+	}
+	cons.AutoControlled = auto
+
+	s.eventStream.Post(Event{
+		Type: StatusMessageEvent,
+		WrittenText: util.Select(auto, fmt.Sprintf("%s is being worked by the autocontroller.", tcw),
+			fmt.Sprintf("%s is no longer being worked by the autocontroller.", tcw)),
+	})
+	s.publish()
+
+	return nil
+}
+
+func (s *Sim) updateAutoControllers() {
+	if s.prespawn || s.State.SimTime.Time().Unix()%autoControlInterval != 0 {
+		return
+	}
+
+	var tcws []TCW
+	for tcw, cons := range util.SortedMap(s.State.CurrentConsolidation) {
+		if cons.AutoControlled && cons.PrimaryTCP != "" {
+			tcws = append(tcws, tcw)
+		}
+	}
+	if len(tcws) == 0 {
+		return
+	}
+
+	if s.autoControl.issued == nil {
+		s.autoControl.issued = make(map[av.ADSBCallsign]map[string]bool)
+	}
+	for callsign := range s.autoControl.issued {
+		if _, ok := s.Aircraft[callsign]; !ok {
+			delete(s.autoControl.issued, callsign)
+		}
+	}
+
+	leaders := s.autoArrivalLeaders()
+
+	var cmds []autoCommand
+	for _, tcw := range tcws {
+		cmds = append(cmds, s.autoControlTCW(tcw, leaders)...)
+	}
+	if len(cmds) == 0 {
+		return
+	}
+
+	// As with waypoint commands, the aircraft commands are run via
+	// runAircraftControlCommands, which acquires the mutex itself.
+	s.mu.Unlock(s.lg)
+	var failed []autoCommand
+	for _, cmd := range cmds {
+		str := strings.Join(cmd.commands, " ")
+		if result := s.runAircraftControlCommands(cmd.tcw, cmd.callsign, str, 0); result.Error != nil {
+			s.lg.Info("autocontroller command failed", slog.String("tcw", string(cmd.tcw)),
+				slog.String("callsign", string(cmd.callsign)), slog.String("commands", str),
+				slog.Any("error", result.Error))
+			failed = append(failed, cmd)
+		}
+	}
+	s.mu.Lock(s.lg)
+
+	// Allow one-time instructions that failed to be retried.
+	for _, cmd := range failed {
+		for _, c := range cmd.commands {
+			delete(s.autoControl.issued[cmd.callsign], c)
+		}
+	}
+}
+
+// autoControlTCW handles the traffic for a single autocontrolled TCW,
+// returning the aircraft commands to be issued.
+func (s *Sim) autoControlTCW(tcw TCW, leaders map[av.ADSBCallsign]*Aircraft) []autoCommand {
+	var cmds []autoCommand
+	for callsign, ac := range util.SortedMap(s.Aircraft) {
+		if ac.HoldForRelease && !ac.Released {
+			if fp := s.STARSComputer.lookupFlightPlanByACID(ACID(callsign)); fp != nil &&
+				s.State.TCWControlsPosition(tcw, fp.InboundHandoffController) {
+				if err := s.releaseDeparture(tcw, callsign); err != nil {
+					s.lg.Info("autocontroller release failed", slog.String("callsign", string(callsign)),
+						slog.Any("error", err))
+				}
+			}
+			continue
+		}
+
+		fp := ac.NASFlightPlan
+		if fp == nil {
+			continue
+		}
+
+		if fp.HandoffController != "" && s.State.TCWControlsPosition(tcw, fp.HandoffController) {
+			if err := s.acceptHandoff(tcw, fp.ACID); err != nil {
+				s.lg.Info("autocontroller handoff accept failed", slog.String("acid", string(fp.ACID)),
+					slog.Any("error", err))
+			}
+			continue
+		}
+
+		if !s.State.TCWControlsPosition(tcw, ac.ControllerFrequency) {
+			// Not talking to us (yet).
+			continue
+		}
+
+		var commands []string
+		if !s.State.TCWControlsPosition(tcw, fp.TrackingController) {
+			// The track has been handed off; send the pilot along to the
+			// new controller.
+			if fp.TrackingController != "" && fp.HandoffController == "" {
+				commands = []string{"FC"}
+			}
+		} else if ac.IsDeparture() {
+			if ac.SID != "" {
+				commands = s.autoIssueOnce(ac, nil, "CVS")
+			}
+		} else if ac.IsArrival() {
+			commands = s.autoControlArrival(ac, leaders[callsign])
+		}
+
+		if len(commands) > 0 {
+			cmds = append(cmds, autoCommand{tcw: tcw, callsign: callsign, commands: commands})
+		}
+	}
+	return cmds
+}
+
+// autoIssueOnce adds the given commands to cmds unless they have already
+// been issued to the aircraft.
+func (s *Sim) autoIssueOnce(ac *Aircraft, cmds []string, commands ...string) []string {
+	issued := s.autoControl.issued[ac.ADSBCallsign]
+	if issued == nil {
+		issued = make(map[string]bool)
+		s.autoControl.issued[ac.ADSBCallsign] = issued
+	}
+	for _, cmd := range commands {
+		if !issued[cmd] {
+			issued[cmd] = true
+			cmds = append(cmds, cmd)
+		}
+	}
+	return cmds
+}
+
+// autoArrivalLeaders returns, for each arrival that has been assigned an
+// approach, the aircraft ahead of it on the approach to the same runway.
+func (s *Sim) autoArrivalLeaders() map[av.ADSBCallsign]*Aircraft {
+	type arrival struct {
+		ac   *Aircraft
+		dist float32
+	}
+	byRunway := make(map[string][]arrival)
+	for _, ac := range util.SortedMap(s.Aircraft) {
+		appr := ac.Nav.Approach.Assigned
+		if appr == nil || !ac.IsAirborne() {
+			continue
+		}
+		key := ac.FlightPlan.ArrivalAirport + "/" + appr.Runway
+		byRunway[key] = append(byRunway[key], arrival{
+			ac:   ac,
+			dist: math.NMDistance2LLFast(ac.Position(), appr.Threshold, s.State.NmPerLongitude),
+		})
+	}
+
+	leaders := make(map[av.ADSBCallsign]*Aircraft)
+	for _, arrivals := range byRunway {
+		slices.SortStableFunc(arrivals, func(a, b arrival) int {
+			if a.dist < b.dist {
+				return -1
+			} else if a.dist > b.dist {
+				return 1
+			}
+			return 0
+		})
+		for i := 1; i < len(arrivals); i++ {
+			leaders[arrivals[i].ac.ADSBCallsign] = arrivals[i-1].ac
+		}
+	}
+	return leaders
+}
+
+// autoFinal describes the geometry of an approach's final approach
+// course in nm coordinates.
+type autoFinal struct {
+	threshold   [2]float32
+	outbound    [2]float32 // unit vector from the threshold out along the extended centerline
+	fafDistance float32
+	fafAltitude float32
+	course      math.MagneticHeading // inbound
+}
+
+func (s *Sim) autoFinalApproach(airport string, appr *av.Approach) (autoFinal, bool) {
+	nmPerLongitude := s.State.NmPerLongitude
+	f := autoFinal{threshold: math.LL2NM(appr.Threshold, nmPerLongitude)}
+
+	for _, wps := range appr.Waypoints {
+		for _, wp := range wps {
+			if !wp.FAF() || wp.Location.IsZero() {
+				continue
+			}
+			v := math.Sub2f(math.LL2NM(wp.Location, nmPerLongitude), f.threshold)
+			f.fafDistance = math.Length2f(v)
+			f.outbound = math.Normalize2f(v)
+			if ar := wp.AltitudeRestriction(); ar != nil {
+				f.fafAltitude = util.Select(ar.Range[0] > 0, ar.Range[0], ar.Range[1])
+			}
+			break
+		}
+		if f.fafDistance > 0 {
+			break
+		}
+	}
+	if f.fafDistance == 0 {
+		// No FAF; use the runway centerline and assume a 5nm final.
+		if appr.OppositeThreshold.IsZero() {
+			return f, false
+		}
+		f.outbound = math.Normalize2f(math.Sub2f(f.threshold, math.LL2NM(appr.OppositeThreshold, nmPerLongitude)))
+		f.fafDistance = 5
+	}
+	if f.fafAltitude == 0 {
+		f.fafAltitude = float32(av.DB.Airports[airport].Elevation) + 1500
+	}
+
+	f.course = math.TrueToMagnetic(math.VectorHeading(math.Scale2f(f.outbound, -1)), s.State.MagneticVariation)
+	return f, true
+}
+
+// autoControlArrival returns the commands for an arrival that the
+// autocontroller is working.
+func (s *Sim) autoControlArrival(ac *Aircraft, leader *Aircraft) []string {
+	var cmds []string
+	if ac.STAR != "" {
+		cmds = s.autoIssueOnce(ac, cmds, "DVS")
+	}
+
+	appr := ac.Nav.Approach.Assigned
+	if appr == nil {
+		if id := s.autoPickApproach(ac); id != "" {
+			cmds = s.autoIssueOnce(ac, cmds, "E"+id)
+		}
+		return cmds
+	}
+
+	final, ok := s.autoFinalApproach(ac.FlightPlan.ArrivalAirport, appr)
+	if !ok {
+		return cmds
+	}
+
+	p := math.Sub2f(math.LL2NM(ac.Position(), s.State.NmPerLongitude), final.threshold)
+	along := math.Dot(p, final.outbound)
+	lateral := final.outbound[0]*p[1] - final.outbound[1]*p[0]
+	dist := math.Length2f(p)
+
+	if ac.Nav.Approach.Cleared {
+		if dist < final.fafDistance+1 && !ac.GotContactTower {
+			cmds = s.autoIssueOnce(ac, cmds, "TO")
+		}
+		return cmds
+	}
+
+	// Extend the intercept point if we're too close to the aircraft ahead.
+	gate := final.fafDistance + autoInterceptGate
+	required := float32(0)
+	if leader != nil {
+		required = s.approachSeparation(leader, ac) + autoSpacingBuffer
+		leaderDist := math.NMDistance2LLFast(leader.Position(), appr.Threshold, s.State.NmPerLongitude)
+		gate += max(0, required-(dist-leaderDist))
+	}
+
+	_, vectoring := ac.Nav.AssignedHeading()
+	if vectoring || dist < autoVectorDistance || len(ac.Nav.Waypoints) == 0 {
+		cmds = append(cmds, s.autoVectorArrival(ac, final, p, along, lateral, gate)...)
+	}
+	if spd := s.autoArrivalSpeed(ac, leader, dist, gate, required, appr); spd != "" {
+		cmds = append(cmds, spd)
+	}
+	return cmds
+}
+
+// autoVectorArrival returns the altitude, heading, and approach clearance
+// commands for an arrival that is being vectored to final; p is its
+// position relative to the threshold, along and lateral its position
+// relative to the extended centerline, and gate the distance from the
+// threshold at which it should intercept.
+func (s *Sim) autoVectorArrival(ac *Aircraft, final autoFinal, p [2]float32, along, lateral, gate float32) []string {
+	var cmds []string
+
+	side := util.Select(lateral < 0, float32(-1), float32(1))
+	normal := [2]float32{-final.outbound[1], final.outbound[0]}
+	at := func(along, lateral float32) [2]float32 {
+		return math.Add2f(math.Scale2f(final.outbound, along), math.Scale2f(normal, lateral))
+	}
+
+	// Descend along a gradient toward the intercept altitude, staying
+	// above the MVA.
+	toGate := math.Distance2f(p, at(gate, 0))
+	alt := final.fafAltitude + max(0, autoDescentGradient*(toGate-5))
+	alt = math.Ceil(alt/1000) * 1000
+	alt = max(alt, final.fafAltitude)
+	if mva := float32(s.getMVAGrid().GetMVA(ac.Position())); alt < mva {
+		alt = math.Ceil(mva/100) * 100
+	}
+	current := ac.Altitude()
+	if a := ac.Nav.Altitude.Assigned; a != nil {
+		current = *a
+	}
+	if alt < current-100 {
+		cmds = append(cmds, "A"+strconv.Itoa(int(alt)/100))
+	}
+
+	var hdg math.MagneticHeading
+	clear := false
+	heading := ac.Nav.FlightState.Heading
+	if along >= gate-1 && math.Abs(lateral) <= autoInterceptWidth && math.HeadingDifference(heading, final.course) < 90 {
+		// In position to intercept: aim for a point a few miles ahead on
+		// the centerline, limiting the intercept angle.
+		target := at(max(along-4, final.fafDistance+1), 0)
+		h := s.autoHeadingTo(p, target)
+		delta := math.Clamp(math.HeadingSignedTurn(final.course, h), -autoInterceptAngle, autoInterceptAngle)
+		hdg = math.NormalizeHeading(final.course + math.MagneticHeading(delta))
+		clear = true
+	} else if along < gate+2 {
+		// Too close in; head out on a downwind leg.
+		hdg = s.autoHeadingTo(p, at(gate+5, side*max(math.Abs(lateral), 4)))
+	} else {
+		// Head for a base leg that ends just outside the intercept point.
+		hdg = s.autoHeadingTo(p, at(gate+2, side*2))
+	}
+
+	hdg = math.MagneticHeading(5 * math.Round(float32(hdg)/5))
+	if hdg == 0 {
+		hdg = 360
+	}
+	if cur, ok := ac.Nav.AssignedHeading(); !ok || math.HeadingDifference(cur, hdg) >= 10 || clear {
+		cmds = append(cmds, fmt.Sprintf("H%03d", int(hdg)))
+	}
+	if clear {
+		cmds = append(cmds, "C"+ac.Nav.Approach.AssignedId)
+	}
+
+	return cmds
+}
+
+// autoArrivalSpeed returns a speed assignment for an arrival if its
+// current one should change.
+func (s *Sim) autoArrivalSpeed(ac *Aircraft, leader *Aircraft, dist, gate, required float32, appr *av.Approach) string {
+	var spd float32
+	switch {
+	case dist > 20:
+		return ""
+	case dist > gate+3:
+		spd = 210
+	default:
+		spd = 180
+	}
+	if leader != nil {
+		leaderDist := math.NMDistance2LLFast(leader.Position(), appr.Threshold, s.State.NmPerLongitude)
+		if dist-leaderDist < required {
+			spd = 170
+		}
+	}
+	spd = max(spd, 10*math.Ceil((ac.Nav.Perf.Speed.Landing+10)/10))
+
+	if sr := ac.Nav.Speed.Assigned; sr != nil && sr.Range[0] == spd && sr.Range[1] == spd {
+		return ""
+	}
+	if sr := ac.Nav.Speed.Assigned; sr == nil && ac.IAS() <= spd {
+		// Already slower than we'd assign.
+		return ""
+	}
+	return "S" + strconv.Itoa(int(spd))
+}
+
+func (s *Sim) autoHeadingTo(p, target [2]float32) math.MagneticHeading {
+	return math.TrueToMagnetic(math.VectorHeading(math.Sub2f(target, p)), s.State.MagneticVariation)
+}
+
+// autoPickApproach returns the id of an approach to one of the active
+// arrival runways at the aircraft's arrival airport, preferring ILS
+// approaches and runways that its STAR has runway-specific waypoints for.
+// It returns an empty string if there is no such approach.
+func (s *Sim) autoPickApproach(ac *Aircraft) string {
+	ap, ok := s.State.Airports[ac.FlightPlan.ArrivalAirport]
+	if !ok {
+		return ""
+	}
+
+	best, bestScore := "", -1
+	for _, rwy := range s.State.ArrivalRunways {
+		if rwy.Airport != ac.FlightPlan.ArrivalAirport {
+			continue
+		}
+		for id, appr := range util.SortedMap(ap.Approaches) {
+			if appr.Runway != rwy.Runway.Base() {
+				continue
+			}
+			score := 0
+			if appr.Type == av.ILSApproach {
+				score++
+			}
+			if _, ok := ac.STARRunwayWaypoints[appr.Runway]; ok {
+				score += 2
+			}
+			if score > bestScore {
+				best, bestScore = id, score
+			}
+		}
+	}
+	return best
+}
+
+// PickArrivalApproach is autoPickApproach for callers outside of the sim,
+// such as vicebatch's scripted controller.
+func (s *Sim) PickArrivalApproach(ac *Aircraft) string {
+	s.mu.Lock(s.lg)
+	defer s.mu.Unlock(s.lg)
+
+	return s.autoPickApproach(ac)
+}
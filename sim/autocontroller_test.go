@@ -0,0 +1,118 @@
+// sim/autocontroller_test.go
+// Copyright(c) 2022-2026 vice contributors, licensed under the GNU Public License, Version 3.
+// SPDX: GPL-3.0-only
+
+package sim
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	av "github.com/mmp/vice/aviation"
+	"github.com/mmp/vice/math"
+)
+
+func TestSetAutoControlled(t *testing.T) {
+	s := makeTestSim(t)
+	s.State.CurrentConsolidation["2B"] = &TCPConsolidation{PrimaryTCP: "2B"}
+
+	if err := s.SetAutoControlled("NOBODY", true); !errors.Is(err, ErrTCWNotFound) {
+		t.Errorf("got %v, expected %v", err, ErrTCWNotFound)
+	}
+
+	if err := s.SetAutoControlled("2B", true); err != nil {
+		t.Fatalf("SetAutoControlled: %v", err)
+	}
+	if !s.State.CurrentConsolidation["2B"].AutoControlled {
+		t.Errorf("2B should be autocontrolled")
+	}
+
+	if s.State.CurrentConsolidation[E2ETCW()].AutoControlled {
+		t.Errorf("%s should not be autocontrolled", E2ETCW())
+	}
+
+	if err := s.SetAutoControlled("2B", false); err != nil {
+		t.Fatalf("SetAutoControlled: %v", err)
+	}
+	if s.State.CurrentConsolidation["2B"].AutoControlled {
+		t.Errorf("2B should no longer be autocontrolled")
+	}
+}
+
+// makeAutoTestApproach returns an ILS to runway 36 with the threshold at
+// the origin and the FAF 5nm south at 2000'.
+func makeAutoTestApproach() *av.Approach {
+	faf := av.Waypoint{Fix: "FAF", Location: math.Point2LL{0, -5.0 / 60}, AltRestriction: av.MakeAtAltitudeRestriction(2000)}
+	faf.Flags |= av.WaypointFlagFAF | av.WaypointFlagHasAltRestriction
+	return &av.Approach{
+		Id:                "I36",
+		Type:              av.ILSApproach,
+		Runway:            "36",
+		Threshold:         math.Point2LL{0, 0},
+		OppositeThreshold: math.Point2LL{0, 2.0 / 60},
+		Waypoints:         []av.WaypointArray{{faf, {Fix: "THR", Location: math.Point2LL{0, 0}}}},
+	}
+}
+
+func TestAutoFinalApproach(t *testing.T) {
+	s := makeTestSim(t)
+
+	f, ok := s.autoFinalApproach("KJFK", makeAutoTestApproach())
+	if !ok {
+		t.Fatalf("autoFinalApproach failed")
+	}
+	if f.fafDistance < 4.99 || f.fafDistance > 5.01 {
+		t.Errorf("FAF distance %.2f, expected 5", f.fafDistance)
+	}
+	if f.fafAltitude != 2000 {
+		t.Errorf("FAF altitude %.0f, expected 2000", f.fafAltitude)
+	}
+	if math.HeadingDifference(f.course, 360) > 0.5 {
+		t.Errorf("inbound course %.1f, expected 360", f.course)
+	}
+}
+
+func TestAutoControlArrival(t *testing.T) {
+	s := makeTestSim(t)
+	s.autoControl.issued = make(map[av.ADSBCallsign]map[string]bool)
+
+	appr := makeAutoTestApproach()
+	ac := addTestAircraft(s, "AAL1", math.Point2LL{0, -12.0 / 60}, 3000)
+	ac.Nav.Approach.Assigned = appr
+	ac.Nav.Approach.AssignedId = appr.Id
+	ac.Nav.FlightState.Heading = 10
+
+	// Established on the extended centerline outside the intercept point:
+	// turned to join and cleared.
+	cmds := s.autoControlArrival(ac, nil)
+	if !slices.Contains(cmds, "CI36") {
+		t.Errorf("expected approach clearance, got %v", cmds)
+	}
+	if !slices.Contains(cmds, "H360") {
+		t.Errorf("expected H360, got %v", cmds)
+	}
+
+	// Abeam the runway: vectored outbound on a downwind rather than
+	// cleared.
+	ac.Nav.FlightState.Position = math.Point2LL{5.0 / 60, 0}
+	ac.Nav.FlightState.Heading = 270
+	cmds = s.autoControlArrival(ac, nil)
+	if slices.Contains(cmds, "CI36") {
+		t.Errorf("unexpected approach clearance abeam the runway: %v", cmds)
+	}
+	i := slices.IndexFunc(cmds, func(c string) bool { return len(c) == 4 && c[0] == 'H' })
+	if i == -1 {
+		t.Fatalf("expected a heading assignment, got %v", cmds)
+	}
+	if cmds[i] < "H135" || cmds[i] > "H225" {
+		t.Errorf("expected a southerly downwind heading, got %s", cmds[i])
+	}
+
+	// Once cleared and inside the FAF, the pilot is sent to tower.
+	ac.Nav.Approach.Cleared = true
+	ac.Nav.FlightState.Position = math.Point2LL{0, -4.0 / 60}
+	if cmds := s.autoControlArrival(ac, nil); !slices.Equal(cmds, []string{"TO"}) {
+		t.Errorf("expected TO, got %v", cmds)
+	}
+}
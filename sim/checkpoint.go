@@ -0,0 +1,166 @@
+// sim/checkpoint.go
+// Copyright(c) 2022-2026 vice contributors, licensed under the GNU Public License, Version 3.
+// SPDX: GPL-3.0-only
+
+package sim
+
+import (
+	"encoding/json"
+	"log/slog"
+	"reflect"
+	"time"
+)
+
+// The Sim periodically takes checkpoints of its full state so that an
+// instructor can rewind the session, e.g. to let a student try again
+// after a deal. Checkpoints are the same JSON encoding of the Sim that is
+// used for saved sims and recordings, so everything that survives a
+// save/restore survives a rewind. They are kept in memory in a ring that
+// covers the last maxCheckpoints*checkpointInterval of sim time.
+
+const (
+	checkpointInterval = 30 * time.Second
+	maxCheckpoints     = 20
+)
+
+type checkpoint struct {
+	simTime Time
+	state   []byte
+}
+
+// takeCheckpoint adds a checkpoint of the Sim's current state to the
+// ring if one is due. The caller must hold s.mu.
+func (s *Sim) takeCheckpoint() {
+	if s.prespawn || s.State.SimTime.Time().Unix()%int64(checkpointInterval.Seconds()) != 0 {
+		return
+	}
+
+	b, err := json.Marshal(s)
+	if err != nil {
+		s.lg.Errorf("%v: unable to checkpoint sim", err)
+		return
+	}
+
+	if len(s.checkpoints) == maxCheckpoints {
+		s.checkpoints = s.checkpoints[1:]
+	}
+	s.checkpoints = append(s.checkpoints, checkpoint{simTime: s.State.SimTime, state: b})
+}
+
+// Checkpoints returns the sim times of the available checkpoints, oldest
+// first.
+func (s *Sim) Checkpoints() []Time {
+	s.mu.Lock(s.lg)
+	defer s.mu.Unlock(s.lg)
+
+	var t []Time
+	for _, cp := range s.checkpoints {
+		t = append(t, cp.simTime)
+	}
+	return t
+}
+
+// Rewind restores the Sim to the most recent checkpoint that is at least
+// the given duration before the current sim time and returns that
+// checkpoint's sim time. Checkpoints after it are discarded. Only
+// privileged TCWs may rewind the sim.
+func (s *Sim) Rewind(tcw TCW, d time.Duration) (Time, error) {
+	s.mu.Lock(s.lg)
+	defer s.mu.Unlock(s.lg)
+
+	if !s.PrivilegedTCWs[tcw] {
+		return Time{}, ErrNotPrivilegedTCW
+	}
+
+	s.recordInput(RecordedInput{TCW: tcw, Type: RecordedRewind, Rewind: d})
+
+	target := s.State.SimTime.Add(-d)
+	idx := -1
+	for i, cp := range s.checkpoints {
+		if !cp.simTime.After(target) {
+			idx = i
+		}
+	}
+	if idx == -1 {
+		return Time{}, ErrNoCheckpoint
+	}
+
+	if err := s.restoreCheckpoint(s.checkpoints[idx]); err != nil {
+		return Time{}, err
+	}
+	s.checkpoints = s.checkpoints[:idx+1]
+
+	s.lg.Info("rewound sim", slog.String("tcw", string(tcw)), slog.Time("sim_time", s.State.SimTime.Time()))
+
+	return s.State.SimTime, nil
+}
+
+// restoreCheckpoint replaces the Sim's serialized state with the state in
+// the checkpoint. The caller must hold s.mu.
+func (s *Sim) restoreCheckpoint(cp checkpoint) error {
+	var restored Sim
+	if err := json.Unmarshal(cp.state, &restored); err != nil {
+		return err
+	}
+
+	// Which TCWs are staffed and privileged, whether the sim is paused,
+	// and which facilities it is linked with are facts about the session,
+	// not the sim state, so they are kept as they are now.
+	consolidation, paused := s.State.CurrentConsolidation, s.State.Paused
+	privileged := s.PrivilegedTCWs
+	federation, federated := s.Federation, s.State.FederatedFacilities
+
+	// Exported fields are the ones that are serialized and thus are in the
+	// checkpoint; unexported ones are runtime state (the mutex, event
+	// stream, publication channels, caches, ...) that stays as it is.
+	dst, src := reflect.ValueOf(s).Elem(), reflect.ValueOf(&restored).Elem()
+	for i := range dst.NumField() {
+		if dst.Type().Field(i).IsExported() {
+			dst.Field(i).Set(src.Field(i))
+		}
+	}
+
+	s.State.CurrentConsolidation, s.State.Paused = consolidation, paused
+	s.PrivilegedTCWs = privileged
+	s.restoreFederation(federation, federated)
+
+	// As in Activate.
+	restoreControllerFields(s.ControlPositions)
+	restoreControllerFields(s.State.Controllers)
+
+	// Reset the runtime state that is tied to the sim time or to aircraft
+	// that may no longer exist.
+	s.lastSimUpdate = s.State.SimTime
+	s.updateTimeSlop = 0
+	s.lastSimUpdateTime = time.Now()
+	s.lastControlCommandTime = time.Now()
+	s.scoring = scoringState{}
+	s.autoControl = autoController{}
+
+	s.publish()
+
+	return nil
+}
+
+// restoreFederation puts back the live federation links after a
+// checkpoint has been restored. The remote tracks are part of the
+// restored state, since their placeholder flight plans are, so they are
+// taken from the checkpoint; the placeholders for facilities that are no
+// longer linked are removed. The caller must hold s.mu.
+func (s *Sim) restoreFederation(federation map[string]*FederatedFacility, federated []string) {
+	for fac, fed := range s.Federation {
+		if _, ok := federation[fac]; !ok {
+			for acid := range fed.RemoteTracks {
+				s.dropRemoteTrack(fed, acid)
+			}
+		}
+	}
+	for fac, fed := range federation {
+		fed.RemoteTracks = make(map[ACID]bool)
+		if cfed, ok := s.Federation[fac]; ok {
+			fed.RemoteTracks = cfed.RemoteTracks
+		}
+	}
+
+	s.Federation, s.State.FederatedFacilities = federation, federated
+}
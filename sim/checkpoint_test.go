@@ -0,0 +1,98 @@
+// sim/checkpoint_test.go
+// Copyright(c) 2022-2026 vice contributors, licensed under the GNU Public License, Version 3.
+// SPDX: GPL-3.0-only
+
+package sim
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mmp/vice/math"
+)
+
+func TestRewind(t *testing.T) {
+	s := makeTestSim(t)
+	t0 := NewSimTime(time.Date(2025, time.November, 1, 12, 0, 0, 0, time.UTC))
+	s.State.SimTime = t0
+	s.State.ConfigurationId, s.State.Scenario = "22S", "JFK 22s"
+
+	p0 := math.Point2LL{0, 0}
+	ac := addTestAircraft(s, "AAL1", p0, 5000)
+	s.takeCheckpoint()
+	if len(s.checkpoints) != 1 {
+		t.Fatalf("expected a checkpoint, got %d", len(s.checkpoints))
+	}
+
+	// Not on a checkpoint boundary.
+	s.State.SimTime = t0.Add(10 * time.Second)
+	s.takeCheckpoint()
+	if len(s.checkpoints) != 1 {
+		t.Fatalf("unexpected checkpoint at %s", s.State.SimTime.Time())
+	}
+
+	s.State.SimTime = t0.Add(checkpointInterval)
+	ac.Nav.FlightState.Position = math.Point2LL{0.1, 0.1}
+	addTestAircraft(s, "AAL2", math.Point2LL{0.2, 0.2}, 6000)
+	s.takeCheckpoint()
+
+	s.State.SimTime = t0.Add(2 * checkpointInterval)
+	s.State.Paused = true
+	s.State.ConfigurationId, s.State.Scenario = "31S", "JFK 31s"
+
+	if _, err := s.Rewind("STUDENT", time.Minute); !errors.Is(err, ErrNotPrivilegedTCW) {
+		t.Errorf("got %v, expected %v", err, ErrNotPrivilegedTCW)
+	}
+	if _, err := s.Rewind(E2ETCW(), 10*time.Minute); !errors.Is(err, ErrNoCheckpoint) {
+		t.Errorf("got %v, expected %v", err, ErrNoCheckpoint)
+	}
+
+	// Rewinding 45s goes back to the first checkpoint.
+	rt, err := s.Rewind(E2ETCW(), 45*time.Second)
+	if err != nil {
+		t.Fatalf("Rewind: %v", err)
+	}
+	if !rt.Equal(t0) || !s.State.SimTime.Equal(t0) {
+		t.Errorf("rewound to %s, sim time %s; expected %s", rt.Time(), s.State.SimTime.Time(), t0.Time())
+	}
+	if _, ok := s.Aircraft["AAL2"]; ok {
+		t.Errorf("AAL2 should not exist after rewinding")
+	}
+	if ac, ok := s.Aircraft["AAL1"]; !ok {
+		t.Errorf("AAL1 missing after rewinding")
+	} else if ac.Position() != p0 {
+		t.Errorf("AAL1 at %v, expected %v", ac.Position(), p0)
+	}
+	if s.State.ConfigurationId != "22S" || s.State.Scenario != "JFK 22s" {
+		t.Errorf("configuration %q from %q after rewinding; expected 22S from \"JFK 22s\"",
+			s.State.ConfigurationId, s.State.Scenario)
+	}
+	if !s.State.Paused {
+		t.Errorf("pause state should be unchanged by rewinding")
+	}
+	if len(s.checkpoints) != 1 {
+		t.Errorf("expected later checkpoints to be discarded, have %d", len(s.checkpoints))
+	}
+}
+
+func TestRewindKeepsPrivileges(t *testing.T) {
+	s := makeTestSim(t)
+	t0 := NewSimTime(time.Date(2025, time.November, 1, 12, 0, 0, 0, time.UTC))
+	s.State.SimTime = t0
+	s.takeCheckpoint()
+
+	// Instructor status is granted after the checkpoint was taken.
+	s.State.SimTime = t0.Add(checkpointInterval / 2)
+	s.SetPrivilegedTCW("INSTRUCTOR", true)
+
+	if _, err := s.Rewind("INSTRUCTOR", 10*time.Second); err != nil {
+		t.Fatalf("Rewind: %v", err)
+	}
+	if !s.TCWIsPrivileged("INSTRUCTOR") {
+		t.Errorf("rewinding revoked privileges granted after the checkpoint")
+	}
+	if !s.TCWIsPrivileged(E2ETCW()) {
+		t.Errorf("rewinding revoked privileges granted before the checkpoint")
+	}
+}
@@ -25,6 +25,7 @@ type ControlCommandsResult struct {
 	Error              error
 	ReadbackSpokenText string          // Spoken text for TTS synthesis (if readback was generated)
 	ReadbackCallsign   av.ADSBCallsign // Aircraft callsign for the readback
+	NoResponse         bool            // The aircraft didn't hear the commands (it's NORDO)
 }
 
 // RunAircraftControlCommands executes a space-separated string of control commands for an aircraft.
@@ -35,6 +36,23 @@ type ControlCommandsResult struct {
 // the pilot-reaction delay applied by deferred-action Nav commands is reduced by
 // (audioDuration - callsignAudioOffset), floored at zero.
 func (s *Sim) RunAircraftControlCommands(tcw TCW, callsign av.ADSBCallsign, commandStr string, audioDuration time.Duration) ControlCommandsResult {
+	// Hold commandMu until the commands have run so that the sim can't
+	// step between when they're recorded and when they run.
+	s.commandMu.Lock(s.lg)
+	defer s.commandMu.Unlock(s.lg)
+
+	s.mu.Lock(s.lg)
+	s.recordInput(RecordedInput{TCW: tcw, Type: RecordedControlCommands, Callsign: callsign, Commands: commandStr,
+		AudioDuration: audioDuration})
+	s.mu.Unlock(s.lg)
+
+	return s.runAircraftControlCommands(tcw, callsign, commandStr, audioDuration)
+}
+
+// runAircraftControlCommands is the inner implementation of
+// RunAircraftControlCommands; it is called directly for commands that the
+// Sim issues itself (e.g., waypoint commands) so that they aren't recorded.
+func (s *Sim) runAircraftControlCommands(tcw TCW, callsign av.ADSBCallsign, commandStr string, audioDuration time.Duration) ControlCommandsResult {
 	// This function has many early returns and runs without holding s.mu; publish unconditionally
 	// at the end so any state updates make their way out quickly.
 	defer func() {
@@ -97,6 +115,12 @@ func (s *Sim) RunAircraftControlCommands(tcw TCW, callsign av.ADSBCallsign, comm
 		}
 	}
 
+	// NORDO aircraft don't hear the controller, so there's no readback
+	// and nothing happens.
+	if s.isNORDO(callsign) {
+		return ControlCommandsResult{NoResponse: true, ReadbackCallsign: callsign}
+	}
+
 	// Handle special STT commands that need direct TTS synthesis
 	// These short-circuit normal command processing
 	if len(commands) == 1 {
@@ -153,6 +177,14 @@ func (s *Sim) RunAircraftControlCommands(tcw TCW, callsign av.ADSBCallsign, comm
 	}
 }
 
+func (s *Sim) isNORDO(callsign av.ADSBCallsign) bool {
+	s.mu.Lock(s.lg)
+	defer s.mu.Unlock(s.lg)
+
+	ac, ok := s.Aircraft[callsign]
+	return ok && ac.NORDO
+}
+
 // rollbackLastCommand restores the nav state of the last aircraft that received a command.
 // This is used when the controller says "negative, that was for {other callsign}" to undo
 // commands given to the wrong aircraft due to STT callsign misinterpretation.
@@ -223,12 +255,12 @@ func (s *Sim) readbackCallsignSuffix(callsign av.ADSBCallsign, tcw TCW) *av.Radi
 			Callsign:     ac.ADSBCallsign,
 			AircraftType: ac.FlightPlan.AircraftType,
 			UseTypeForm:  true,
-			IsEmergency:  ac.EmergencyState != nil,
+			IsEmergency:  ac.IsEmergency(),
 		}
 	} else {
 		csArg = av.CallsignArg{
 			Callsign:    ac.ADSBCallsign,
-			IsEmergency: ac.EmergencyState != nil,
+			IsEmergency: ac.IsEmergency(),
 		}
 	}
 	return av.MakeReadbackTransmission("{callsign}"+heavySuper, csArg)
@@ -506,6 +538,31 @@ func (s *Sim) runOneControlCommand(tcw TCW, callsign av.ADSBCallsign, command st
 			}
 		}
 
+	case 'B':
+		// Breakout: BL270, BR090/C30, BL240/D20, BR360/A40
+		if len(command) < 3 || (command[1] != 'L' && command[1] != 'R') {
+			return nil, ErrInvalidCommandSyntax
+		}
+		turn := util.Select(command[1] == 'L', av.TurnLeft, av.TurnRight)
+		hdgStr, altStr, haveAlt := strings.Cut(command[2:], "/")
+		hdg, err := strconv.Atoi(hdgStr)
+		if err != nil {
+			return nil, ErrInvalidCommandSyntax
+		}
+		var alt *float32
+		if haveAlt {
+			if len(altStr) < 2 || !strings.ContainsRune("ACD", rune(altStr[0])) || !util.IsAllNumbers(altStr[1:]) {
+				return nil, ErrInvalidCommandSyntax
+			}
+			a, err := strconv.Atoi(altStr[1:])
+			if err != nil {
+				return nil, err
+			}
+			af := float32(100 * a)
+			alt = &af
+		}
+		return s.Breakout(tcw, callsign, hdg, turn, alt)
+
 	case 'C':
 		if command == "CAC" {
 			return s.CancelApproachClearance(tcw, callsign)
@@ -557,6 +614,9 @@ func (s *Sim) runOneControlCommand(tcw TCW, callsign av.ADSBCallsign, command st
 			return s.ClearedApproach(tcw, callsign, "", true) // clear "expect"ed approach
 		} else if appr, ok := strings.CutPrefix(command, "CSI"); ok && !util.IsAllNumbers(appr) {
 			return s.ClearedApproach(tcw, callsign, appr, true)
+		} else if strings.Contains(command, "/CIR") || strings.Contains(command, "/SS") {
+			// Circle to land or sidestep, e.g. CI4R/CIR31 or CI22L/SS22R.
+			return s.ClearedApproach(tcw, callsign, command[1:], false)
 		} else if components := strings.Split(command, "/"); len(components) > 1 {
 			fix := components[0][1:]
 			var ar *av.AltitudeRestriction
@@ -613,6 +673,8 @@ func (s *Sim) runOneControlCommand(tcw TCW, callsign av.ADSBCallsign, command st
 	case 'D':
 		if command == "DVS" {
 			return s.DescendViaSTAR(tcw, callsign)
+		} else if command == "DIH" {
+			return s.DescendInHold(tcw, callsign)
 		} else if components := strings.Split(command, "/"); len(components) > 1 && len(components[1]) > 1 {
 			fix := components[0][1:]
 
@@ -653,6 +715,9 @@ func (s *Sim) runOneControlCommand(tcw TCW, callsign av.ADSBCallsign, command st
 			}
 			// Fall through
 		}
+		if efc, ok := strings.CutPrefix(command, "EFC"); ok && (efc == "" || util.IsAllNumbers(efc)) {
+			return s.ExpectFurtherClearance(tcw, callsign, efc)
+		}
 		if id, ok := strings.CutPrefix(command, "EVA"); ok && len(id) > 0 { // Expect visual
 			return s.ExpectApproach(tcw, callsign, "_VIS"+id)
 		} else if fix, ok := strings.CutPrefix(command, "EXPDIR"); ok && len(fix) > 0 {
@@ -478,7 +478,7 @@ func (s *Sim) handleTrafficAdvisory(ac *Aircraft, oclock int, miles, callAlt int
 
 	traffic := func() *Aircraft {
 		var best *Aircraft
-		for cs, candidate := range s.Aircraft {
+		for cs, candidate := range util.SortedMap(s.Aircraft) {
 			if cs == ac.ADSBCallsign {
 				continue // Skip self
 			}
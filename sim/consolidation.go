@@ -38,6 +38,9 @@ type TCW string
 type TCPConsolidation struct {
 	PrimaryTCP    TCP
 	SecondaryTCPs []SecondaryTCP
+	// AutoControlled is set when the autocontroller is working the
+	// TCW's traffic; see SetAutoControlled.
+	AutoControlled bool
 }
 
 type ConsolidationType int
@@ -198,10 +201,13 @@ func (cc *ControllerConfiguration) Validate(controlPositions map[TCP]*av.Control
 func (s *Sim) SignOn(tcw TCW, tcps []TCP) (*UserState, *EventsSubscription, error) {
 	s.mu.Lock(s.lg)
 
-	if _, ok := s.State.CurrentConsolidation[tcw]; !ok {
+	cons, ok := s.State.CurrentConsolidation[tcw]
+	if !ok {
 		s.mu.Unlock(s.lg)
 		return nil, nil, av.ErrNoController
 	}
+	// A human is taking over from the autocontroller, if it was running.
+	cons.AutoControlled = false
 
 	s.publish()
 	s.mu.Unlock(s.lg)
@@ -272,6 +278,9 @@ func (s *Sim) ConsolidateTCP(receivingTCW TCW, sendingTCP TCP, consType Consolid
 	s.mu.Lock(s.lg)
 	defer s.mu.Unlock(s.lg)
 
+	s.recordInput(RecordedInput{TCW: receivingTCW, Type: RecordedConsolidateTCP, TCP: sendingTCP,
+		Consolidation: consType})
+
 	if _, ok := s.State.CurrentConsolidation[receivingTCW]; !ok {
 		return ErrTCWNotFound
 	}
@@ -386,6 +395,8 @@ func (s *Sim) DeconsolidateTCP(tcw TCW, tcp TCP) error {
 	s.mu.Lock(s.lg)
 	defer s.mu.Unlock(s.lg)
 
+	s.recordInput(RecordedInput{TCW: tcw, Type: RecordedDeconsolidateTCP, TCP: tcp})
+
 	// If no TCP specified, the user wants their own TCW's TCP back
 	if tcp == "" {
 		tcp = TCP(tcw)
@@ -453,6 +464,8 @@ func (s *Sim) SetPrivilegedTCW(tcw TCW, privileged bool) {
 	s.mu.Lock(s.lg)
 	defer s.mu.Unlock(s.lg)
 
+	s.recordInput(RecordedInput{TCW: tcw, Type: RecordedPrivilegedTCW, Privileged: privileged})
+
 	if privileged {
 		s.PrivilegedTCWs[tcw] = true
 	} else {
@@ -11,6 +11,7 @@ import (
 	"time"
 
 	av "github.com/mmp/vice/aviation"
+	"github.com/mmp/vice/util"
 )
 
 // callsignAudioOffset is the approximate time taken by the callsign at the
@@ -216,6 +217,7 @@ func (s *Sim) deleteAircraft(ac *Aircraft) {
 		fp = s.STARSComputer.takeFlightPlanByACID(ACID(ac.ADSBCallsign))
 	}
 	if fp != nil {
+		s.federationTrackDropped(fp)
 		delete(s.Handoffs, fp.ACID)
 		delete(s.PointOuts, fp.ACID)
 		s.deleteFlightPlan(fp)
@@ -244,7 +246,7 @@ func (s *Sim) DeleteAllAircraft(tcw TCW) error {
 		return av.ErrOtherControllerHasTrack
 	}
 
-	for _, ac := range s.Aircraft {
+	for _, ac := range util.SortedMap(s.Aircraft) {
 		// Only delete airborne aircraft; leave all of the ones at the
 		// gate, etc., so we don't have a bubble of no departures for a
 		// long time while the departure queues refill.
@@ -281,8 +283,16 @@ func (s *Sim) ReleaseDeparture(tcw TCW, callsign av.ADSBCallsign) error {
 	s.mu.Lock(s.lg)
 	defer s.mu.Unlock(s.lg)
 
+	s.recordInput(RecordedInput{TCW: tcw, Type: RecordedReleaseDeparture, Callsign: callsign})
+
 	s.lastControlCommandTime = time.Now()
 
+	return s.releaseDeparture(tcw, callsign)
+}
+
+// releaseDeparture is the inner implementation of ReleaseDeparture; the
+// caller must hold s.mu.
+func (s *Sim) releaseDeparture(tcw TCW, callsign av.ADSBCallsign) error {
 	ac, ok := s.Aircraft[callsign]
 	if !ok {
 		return av.ErrNoAircraftForCallsign
@@ -309,6 +319,19 @@ func (s *Sim) ShouldTriggerPilotMixUp(callsign av.ADSBCallsign) bool {
 	s.mu.Lock(s.lg)
 	defer s.mu.Unlock(s.lg)
 
+	ac, ok := s.Aircraft[callsign]
+	if ok && ac.NORDO {
+		return false
+	}
+
+	// Scripted mix-ups from the scenario timeline and the instructor
+	// always happen.
+	if ok && ac.ForcePilotMixUp {
+		ac.ForcePilotMixUp = false
+		s.LastPilotError = s.State.SimTime
+		return true
+	}
+
 	// If pilot errors are disabled (interval == 0), never trigger mix-ups
 	if s.PilotErrorInterval == 0 {
 		return false
@@ -320,7 +343,7 @@ func (s *Sim) ShouldTriggerPilotMixUp(callsign av.ADSBCallsign) bool {
 	}
 
 	// Check if we've recently communicated with this specific aircraft
-	if ac, ok := s.Aircraft[callsign]; ok {
+	if ok {
 		// Don't trigger mix-up if we just communicated with this pilot
 		if !ac.LastRadioTransmission.IsZero() && s.State.SimTime.Sub(ac.LastRadioTransmission) < 20*time.Second {
 			return false
@@ -340,6 +363,9 @@ func (s *Sim) PilotMixUp(tcw TCW, callsign av.ADSBCallsign) (string, error) {
 
 	intent, err := s.dispatchControlledAircraftCommand(tcw, callsign,
 		func(tcw TCW, ac *Aircraft) av.CommandIntent {
+			if ac.NORDO {
+				return nil
+			}
 			return ac.PilotMixUp()
 		})
 	if err == nil && intent != nil {
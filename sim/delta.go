@@ -0,0 +1,102 @@
+// sim/delta.go
+// Copyright(c) 2022-2026 vice contributors, licensed under the GNU Public License, Version 3.
+// SPDX: GPL-3.0-only
+
+package sim
+
+import (
+	"reflect"
+	"slices"
+
+	av "github.com/mmp/vice/aviation"
+)
+
+// StateDelta describes how the tracks and unassociated flight plans--by
+// far the bulk of a StateUpdate--changed since an earlier update, so that
+// they needn't all be sent to the client every time the sim publishes.
+type StateDelta struct {
+	BaseGeneration int // GenerationIndex of the update the delta is relative to
+
+	Tracks        map[av.ADSBCallsign]*Track // added or changed
+	RemovedTracks []av.ADSBCallsign
+
+	// FlightPlanACIDs gives the ACIDs of all of the unassociated flight
+	// plans, in order; FlightPlans holds just the ones that were added or
+	// changed.
+	FlightPlanACIDs []ACID
+	FlightPlans     map[ACID]*NASFlightPlan
+}
+
+// MakeStateDelta returns the delta that takes prev's tracks and
+// unassociated flight plans to cur's. It returns false if the change can't
+// be represented as a delta, in which case cur should be sent in full.
+func MakeStateDelta(prev, cur *StateUpdate) (StateDelta, bool) {
+	d := StateDelta{
+		BaseGeneration: prev.GenerationIndex,
+		Tracks:         make(map[av.ADSBCallsign]*Track),
+		FlightPlans:    make(map[ACID]*NASFlightPlan),
+	}
+
+	for callsign, trk := range cur.Tracks {
+		if ptrk, ok := prev.Tracks[callsign]; !ok || !reflect.DeepEqual(ptrk, trk) {
+			d.Tracks[callsign] = trk
+		}
+	}
+	for callsign := range prev.Tracks {
+		if _, ok := cur.Tracks[callsign]; !ok {
+			d.RemovedTracks = append(d.RemovedTracks, callsign)
+		}
+	}
+	slices.Sort(d.RemovedTracks)
+
+	prevFPs := make(map[ACID]*NASFlightPlan, len(prev.UnassociatedFlightPlans))
+	for _, fp := range prev.UnassociatedFlightPlans {
+		prevFPs[fp.ACID] = fp
+	}
+	seen := make(map[ACID]bool, len(cur.UnassociatedFlightPlans))
+	for _, fp := range cur.UnassociatedFlightPlans {
+		if seen[fp.ACID] {
+			// ACIDs must be unique for the delta to be unambiguous.
+			return StateDelta{}, false
+		}
+		seen[fp.ACID] = true
+
+		d.FlightPlanACIDs = append(d.FlightPlanACIDs, fp.ACID)
+		if pfp, ok := prevFPs[fp.ACID]; !ok || !reflect.DeepEqual(pfp, fp) {
+			d.FlightPlans[fp.ACID] = fp
+		}
+	}
+
+	return d, true
+}
+
+// Apply returns the tracks and unassociated flight plans that result from
+// applying the delta to the given ones, which must be from the update at
+// d.BaseGeneration. The provided map and slice are not modified.
+func (d *StateDelta) Apply(tracks map[av.ADSBCallsign]*Track, fps []*NASFlightPlan) (map[av.ADSBCallsign]*Track, []*NASFlightPlan) {
+	newTracks := make(map[av.ADSBCallsign]*Track, len(tracks)+len(d.Tracks))
+	for callsign, trk := range tracks {
+		newTracks[callsign] = trk
+	}
+	for _, callsign := range d.RemovedTracks {
+		delete(newTracks, callsign)
+	}
+	for callsign, trk := range d.Tracks {
+		newTracks[callsign] = trk
+	}
+
+	prevFPs := make(map[ACID]*NASFlightPlan, len(fps))
+	for _, fp := range fps {
+		prevFPs[fp.ACID] = fp
+	}
+	newFPs := make([]*NASFlightPlan, 0, len(d.FlightPlanACIDs))
+	for _, acid := range d.FlightPlanACIDs {
+		if fp, ok := d.FlightPlans[acid]; ok {
+			newFPs = append(newFPs, fp)
+		} else if fp, ok := prevFPs[acid]; ok {
+			newFPs = append(newFPs, fp)
+		}
+	}
+
+	return newTracks, newFPs
+}
@@ -0,0 +1,80 @@
+// sim/delta_test.go
+// Copyright(c) 2022-2026 vice contributors, licensed under the GNU Public License, Version 3.
+// SPDX: GPL-3.0-only
+
+package sim
+
+import (
+	"maps"
+	"reflect"
+	"slices"
+	"testing"
+
+	av "github.com/mmp/vice/aviation"
+)
+
+func TestStateDelta(t *testing.T) {
+	track := func(callsign av.ADSBCallsign, alt float32) *Track {
+		return &Track{RadarTrack: av.RadarTrack{ADSBCallsign: callsign, TransponderAltitude: alt}}
+	}
+	fp := func(acid ACID, exit string) *NASFlightPlan {
+		return &NASFlightPlan{ACID: acid, ExitFix: exit}
+	}
+
+	prev := StateUpdate{
+		DynamicState: DynamicState{GenerationIndex: 5},
+		DerivedState: DerivedState{
+			Tracks: map[av.ADSBCallsign]*Track{
+				"AAL1": track("AAL1", 5000),
+				"AAL2": track("AAL2", 6000),
+				"AAL3": track("AAL3", 7000),
+			},
+			UnassociatedFlightPlans: []*NASFlightPlan{fp("N1", "A"), fp("N2", "B"), fp("N3", "C")},
+		},
+	}
+	cur := StateUpdate{
+		DynamicState: DynamicState{GenerationIndex: 7},
+		DerivedState: DerivedState{
+			Tracks: map[av.ADSBCallsign]*Track{
+				"AAL1": track("AAL1", 5000),
+				"AAL2": track("AAL2", 5800),
+				"AAL4": track("AAL4", 3000),
+			},
+			UnassociatedFlightPlans: []*NASFlightPlan{fp("N3", "C"), fp("N1", "D"), fp("N4", "E")},
+		},
+	}
+
+	d, ok := MakeStateDelta(&prev, &cur)
+	if !ok {
+		t.Fatalf("unable to make delta")
+	}
+	if d.BaseGeneration != 5 {
+		t.Errorf("expected base generation 5, got %d", d.BaseGeneration)
+	}
+	if keys := slices.Sorted(maps.Keys(d.Tracks)); !slices.Equal(keys, []av.ADSBCallsign{"AAL2", "AAL4"}) {
+		t.Errorf("expected only AAL2 and AAL4 to be sent, got %v", keys)
+	}
+	if !slices.Equal(d.RemovedTracks, []av.ADSBCallsign{"AAL3"}) {
+		t.Errorf("expected AAL3 to be removed, got %v", d.RemovedTracks)
+	}
+	if len(d.FlightPlans) != 2 || d.FlightPlans["N1"] == nil || d.FlightPlans["N4"] == nil {
+		t.Errorf("expected only N1 and N4 flight plans to be sent, got %v", d.FlightPlans)
+	}
+
+	tracks, fps := d.Apply(prev.Tracks, prev.UnassociatedFlightPlans)
+	if !reflect.DeepEqual(tracks, cur.Tracks) {
+		t.Errorf("tracks mismatch after applying delta: %v", tracks)
+	}
+	if !reflect.DeepEqual(fps, cur.UnassociatedFlightPlans) {
+		t.Errorf("flight plans mismatch after applying delta: %v", fps)
+	}
+	if len(prev.Tracks) != 3 || prev.Tracks["AAL2"].TransponderAltitude != 6000 {
+		t.Errorf("Apply modified the base tracks")
+	}
+
+	// Duplicate ACIDs can't be represented.
+	cur.UnassociatedFlightPlans = append(cur.UnassociatedFlightPlans, fp("N4", "F"))
+	if _, ok := MakeStateDelta(&prev, &cur); ok {
+		t.Errorf("expected delta with duplicate ACIDs to fail")
+	}
+}
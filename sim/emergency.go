@@ -5,7 +5,6 @@
 package sim
 
 import (
-	"maps"
 	"slices"
 	"strings"
 	"time"
@@ -167,6 +166,7 @@ type EmergencyStage struct {
 	RequestEquipment    bool   `json:"request_equipment"`
 	RequestDelayVectors bool   `json:"request_delay_vectors"`
 	DeclareEmergency    bool   `json:"declare_emergency"`
+	LostComms           bool   `json:"lost_comms"`
 }
 
 // EmergencyState tracks the current state of an aircraft's emergency.
@@ -174,6 +174,9 @@ type EmergencyState struct {
 	Emergency      *Emergency
 	CurrentStage   int
 	NextUpdateTime Time
+	// Fuel is set for fuel emergencies, which advance to their next stage
+	// when the aircraft's fuel state changes rather than after a delay.
+	Fuel bool
 }
 
 func (s *Sim) triggerEmergency(idx int) bool {
@@ -185,7 +188,7 @@ func (s *Sim) triggerEmergency(idx int) bool {
 	em := &s.State.Emergencies[idx]
 
 	// Sample aircraft with weight 0 for virtual-controlled or existing emergencies
-	ac, ok := rand.SampleWeightedSeq(s.Rand, maps.Values(s.Aircraft), func(ac *Aircraft) float32 {
+	ac, ok := rand.SampleWeightedSeq(s.Rand, util.SortedMapValues(s.Aircraft), func(ac *Aircraft) float32 {
 		if ac.EmergencyState != nil {
 			return 0
 		}
@@ -298,42 +301,24 @@ func getSoulsOnBoard(ac *Aircraft, rng *rand.Rand) int {
 
 // getFuelRemaining returns realistic fuel remaining in pounds
 func getFuelRemaining(ac *Aircraft, rng *rand.Rand) int {
-	maxFuel := 10000 // fallback if we somehow don't find something better
-
-	// Check if we have specific data for this aircraft type from the database
-	perf := av.DB.AircraftPerformance[ac.FlightPlan.AircraftType]
-	if perf.Capacity.FuelPounds > 0 {
-		maxFuel = perf.Capacity.FuelPounds
-	} else if avg, ok := cwtFuelPounds[perf.Category.CWT]; ok {
-		maxFuel = avg
+	if ac.FuelBurn > 0 {
+		// We've been keeping track of it.
+		return int(ac.Fuel)
 	}
 
+	maxFuel, burnPerHour := fuelCapacityAndBurn(av.DB.AircraftPerformance[ac.FlightPlan.AircraftType])
+
 	if ac.IsArrival() {
 		// Arrivals should have roughly 2 hours of fuel remaining
-
-		// Guesstimate percentage fuel burned per hour of flight; assume it's proportional to
-		// aircraft size.
-		var percentBurnPerHour float32
-		switch perf.Category.CWT {
-		case "A", "B", "C":
-			percentBurnPerHour = .06
-		case "D", "E":
-			percentBurnPerHour = .12
-		case "F", "G", "H":
-			percentBurnPerHour = .15
-		case "I":
-			percentBurnPerHour = .25
-		}
-
-		twoHoursFuel := int(2 * percentBurnPerHour * float32(maxFuel))
+		twoHoursFuel := 2 * burnPerHour
 		// Add some variance ±10%
 		variance := rng.Float32Range(0.9, 1.1)
-		return int(float32(twoHoursFuel) * variance)
+		return int(twoHoursFuel * variance)
 	} else {
 		// Departures: estimate based on distance to destination
 		// For now, use 60-90% of max fuel as a reasonable range for departures
 		ratio := rng.Float32Range(0.6, 0.9)
-		return int(float32(maxFuel) * ratio)
+		return int(maxFuel * ratio)
 	}
 }
 
@@ -374,7 +359,9 @@ func (s *Sim) runEmergencyStage(ac *Aircraft) {
 		}
 	}
 
-	transmit(stage.Transmission)
+	if stage.Transmission != "" {
+		transmit(stage.Transmission)
+	}
 
 	// Sometimes (50% chance) proactively include souls on board and fuel remaining
 	if stage.DeclareEmergency && s.Rand.Float32() < 0.5 {
@@ -458,13 +445,21 @@ func (s *Sim) runEmergencyStage(ac *Aircraft) {
 		transmit("[we'd like equipment standing by|request ARFF waiting for us|roll the trucks for us|we're gonna need the trucks by the runway]")
 	}
 
-	// Queue the radio transmission (TTS will be synthesized when client requests it)
-	rt := av.MakeContactTransmission(strings.Join(transmission, ", "), args...)
-	s.enqueueEmergencyTransmission(ac.ADSBCallsign, TCP(ac.ControllerFrequency), rt)
+	// Queue the radio transmission (TTS will be synthesized when client
+	// requests it); there's nothing to say in a lost comms stage without
+	// a transmission and nothing is heard after comms are lost.
+	if len(transmission) > 0 && !ac.NORDO {
+		rt := av.MakeContactTransmission(strings.Join(transmission, ", "), args...)
+		s.enqueueEmergencyTransmission(ac.ADSBCallsign, TCP(ac.ControllerFrequency), rt)
+	}
+
+	if stage.LostComms {
+		s.loseComms(ac)
+	}
 
 	// Schedule next stage based on current stage's duration
 	es.CurrentStage++
-	if es.CurrentStage < len(es.Emergency.Stages) {
+	if es.CurrentStage < len(es.Emergency.Stages) && !es.Fuel {
 		dur := stage.DurationMinutes
 		delay := s.Rand.DurationRange(time.Duration(dur[0])*time.Minute, time.Duration(dur[1])*time.Minute)
 
@@ -472,3 +467,30 @@ func (s *Sim) runEmergencyStage(ac *Aircraft) {
 		s.enqueueEmergencyUpdate(ac.ADSBCallsign, es.NextUpdateTime)
 	}
 }
+
+// loseComms puts the aircraft in lost communications: it stops responding
+// to the controller, squawks 7600, and flies the FAR 91.185 procedure.
+// Departures expect to climb to their flight plan altitude; arrivals that
+// haven't been told to expect an approach pick one to an active arrival
+// runway.
+func (s *Sim) loseComms(ac *Aircraft) {
+	if ac.NORDO {
+		return
+	}
+	ac.NORDO = true
+	s.enqueueTransponderChange(ac.ADSBCallsign, 0o7600, ac.Mode)
+
+	var minAlt float32
+	if ac.IsDeparture() {
+		minAlt = float32(ac.FlightPlan.Altitude)
+	}
+	minAlt = max(minAlt, float32(s.getMVAGrid().GetMVA(ac.Position())))
+
+	if ac.IsArrival() && ac.Nav.Approach.Assigned == nil {
+		if id := s.autoPickApproach(ac); id != "" {
+			ac.ExpectApproach(id, s.State.Airports[ac.FlightPlan.ArrivalAirport])
+		}
+	}
+
+	ac.Nav.LostComms(minAlt)
+}
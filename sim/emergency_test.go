@@ -0,0 +1,70 @@
+// sim/emergency_test.go
+// Copyright(c) 2022-2026 vice contributors, licensed under the GNU Public License, Version 3.
+// SPDX: GPL-3.0-only
+
+package sim
+
+import (
+	"testing"
+
+	av "github.com/mmp/vice/aviation"
+	"github.com/mmp/vice/math"
+)
+
+func TestLostCommsEmergency(t *testing.T) {
+	s := makeTestSim(t)
+	s.mvaGrid = av.MakeMVAGrid([]av.MVA{{
+		MinimumLimit: 4000,
+		Bounds:       math.Extent2D{P0: [2]float32{-1, -1}, P1: [2]float32{1, 1}},
+		ExteriorRing: [][2]float32{{-1, -1}, {1, -1}, {1, 1}, {-1, 1}, {-1, -1}},
+	}})
+
+	ac := addTestAircraft(s, "AAL1", math.Point2LL{0, 0}, 3000)
+	ac.Mode = av.TransponderModeAltitude
+	hdg := math.MagneticHeading(270)
+	ac.Nav.Heading.Assigned = &hdg
+	ac.Nav.Waypoints = []av.Waypoint{{Fix: "AAA", Location: math.Point2LL{0.1, 0.1}}, ac.Nav.FlightState.ArrivalAirport}
+
+	ac.EmergencyState = &EmergencyState{Emergency: &Emergency{
+		Name: "Lost Communications",
+		Stages: []EmergencyStage{
+			{Transmission: "we may be having radio problems", DurationMinutes: [2]int{1, 1}},
+			{LostComms: true},
+		},
+	}}
+
+	pending := func() int {
+		n := 0
+		for _, pcs := range s.PendingContacts {
+			n += len(pcs)
+		}
+		return n
+	}
+
+	s.runEmergencyStage(ac)
+	if ac.NORDO || pending() != 1 {
+		t.Fatalf("expected a transmission before comms are lost: NORDO %v, %d pending", ac.NORDO, pending())
+	}
+
+	s.runEmergencyStage(ac)
+	if !ac.NORDO {
+		t.Fatalf("expected the aircraft to be NORDO")
+	}
+	if pending() != 1 {
+		t.Errorf("NORDO aircraft transmitted")
+	}
+	if n := len(s.FutureSquawkChanges); n != 1 || s.FutureSquawkChanges[0].Code != 0o7600 {
+		t.Errorf("expected a squawk change to 7600, got %+v", s.FutureSquawkChanges)
+	}
+	if _, ok := ac.Nav.AssignedHeading(); ok {
+		t.Errorf("expected the aircraft to resume its route")
+	}
+	if a := ac.Nav.Altitude.Assigned; a == nil || *a != 4000 {
+		t.Errorf("expected the aircraft to climb to the 4000 minimum altitude, got %v", a)
+	}
+
+	s.RunAircraftControlCommands(E2ETCW(), "AAL1", "H180", 0)
+	if _, ok := ac.Nav.AssignedHeading(); ok {
+		t.Errorf("NORDO aircraft followed an instruction")
+	}
+}
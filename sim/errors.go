@@ -12,6 +12,8 @@ var (
 	ErrAircraftAlreadyReleased         = errors.New("Aircraft already released")
 	ErrSimPublishStalled               = errors.New("Sim publish loop has stalled")
 	ErrATPADisabled                    = errors.New("ATPA is disabled system-wide")
+	ErrAlreadyRecording                = errors.New("Sim is already being recorded")
+	ErrBadRecordingVersion             = errors.New("Unsupported recording version")
 	ErrBeaconMismatch                  = errors.New("Beacon code mismatch")
 	ErrControllerAlreadySignedIn       = errors.New("Controller with that callsign already signed in")
 	ErrDuplicateACID                   = errors.New("Duplicate ACID")
@@ -30,15 +32,22 @@ var (
 	ErrIllegalScratchpad               = errors.New("Illegal scratchpad")
 	ErrInvalidAbbreviatedFP            = errors.New("Invalid abbreviated flight plan")
 	ErrInvalidDepartureController      = errors.New("Invalid departure controller")
+	ErrInvalidPerformance              = errors.New("Invalid performance adjustment")
 	ErrInvalidRestrictionAreaIndex     = errors.New("Invalid restriction area index")
+	ErrInvalidTransferredAircraft      = errors.New("Invalid transferred aircraft")
 	ErrInvalidVolumeId                 = errors.New("Invalid ATPA volume ID")
 	ErrNoACType                        = errors.New("No aircraft type")
+	ErrNoAssignedAltitude              = errors.New("Aircraft has no assigned altitude")
+	ErrNoCheckpoint                    = errors.New("No checkpoint available to rewind to")
 	ErrNoMatchingFlight                = errors.New("No matching flight")
 	ErrNoMatchingFlightPlan            = errors.New("No matching flight plan")
 	ErrNoScratchpad                    = errors.New("No scratchpad")
 	ErrNoRecentCommand                 = errors.New("No recent command to roll back")
 	ErrNoVFRAircraftForFlightFollowing = errors.New("No VFR aircraft available for flight following")
+	ErrNotFederatedFacility            = errors.New("Facility is not federated")
 	ErrNotLaunchController             = errors.New("Not signed in as the launch controller")
+	ErrNotPrivilegedTCW                = errors.New("Not signed in with instructor privileges")
+	ErrNotRecording                    = errors.New("Sim is not being recorded")
 	ErrTCPAlreadyConsolidated          = errors.New("TCP already consolidated - deconsolidate first")
 	ErrTCPNotConsolidated              = errors.New("TCP is not consolidated")
 	ErrTCWIsConsolidated               = errors.New("receiving TCW is a consolidated position")
@@ -52,6 +61,7 @@ var (
 	ErrUnknownAircraftType             = errors.New("Unknown aircraft type")
 	ErrUnknownController               = errors.New("Unknown controller")
 	ErrUnknownControllerFacility       = errors.New("Unknown controller facility")
+	ErrUnknownRecordedInput            = errors.New("Unknown recorded input type")
 	ErrVFRBelowMVA                     = errors.New("VFR aircraft below MVA")
 	ErrVFRSimTookTooLong               = errors.New("VFR simulation took too long")
 	ErrViolatedAirspace                = errors.New("Violated B/C airspace")
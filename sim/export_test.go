@@ -1,12 +1,15 @@
 package sim
 
 import (
+	"io"
+	"log/slog"
 	"os"
 	"testing"
 	"time"
 
 	av "github.com/mmp/vice/aviation"
 	"github.com/mmp/vice/log"
+	"github.com/mmp/vice/math"
 	"github.com/mmp/vice/nav"
 	vrand "github.com/mmp/vice/rand"
 	"github.com/mmp/vice/wx"
@@ -76,3 +79,28 @@ func MakeTestAircraft(callsign av.ADSBCallsign, runway string) *Aircraft {
 
 // E2ETCW returns the TCW used by NewTestSim.
 func E2ETCW() TCW { return TCW("TEST") }
+
+// makeTestSim returns a NewTestSim with an empty MVA grid and 60nm per
+// degree of longitude, so that test distances are easy to work out.
+func makeTestSim(t *testing.T) *Sim {
+	t.Helper()
+
+	lg := &log.Logger{Logger: slog.New(slog.NewTextHandler(io.Discard, nil))}
+	s := NewTestSim(lg)
+	s.State.NmPerLongitude = 60
+	s.mvaGrid = av.MakeMVAGrid(nil)
+	return s
+}
+
+// addTestAircraft adds an associated IFR arrival at the given position and
+// altitude to the sim.
+func addTestAircraft(s *Sim, callsign av.ADSBCallsign, p math.Point2LL, alt float32) *Aircraft {
+	ac := MakeTestAircraft(callsign, "22L")
+	ac.FlightPlan.Rules = av.FlightRulesIFR
+	ac.NASFlightPlan = &NASFlightPlan{ACID: ACID(callsign), OwningTCW: E2ETCW()}
+	ac.Nav.FlightState.Position = p
+	ac.Nav.FlightState.Altitude = alt
+	ac.Nav.FlightState.IAS = 210
+	s.Aircraft[callsign] = ac
+	return ac
+}
@@ -0,0 +1,870 @@
+// sim/federation.go
+// Copyright(c) 2022-2026 vice contributors, licensed under the GNU Public License, Version 3.
+// SPDX: GPL-3.0-only
+
+package sim
+
+import (
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"time"
+
+	av "github.com/mmp/vice/aviation"
+	"github.com/mmp/vice/math"
+	"github.com/mmp/vice/util"
+)
+
+// Federation allows adjacent facilities to run in separate Sims, possibly
+// on different servers. A Sim knows the other facility's control
+// positions as neighbor controllers (e.g., "N56" for ZNY's sector 56 in
+// N90); once that facility is federated, those positions are no longer
+// treated as virtual controllers. Instead, handoffs, point outs, and
+// tracks that cross the boundary are exchanged with the other Sim as
+// NASMessages: each Sim queues outbound messages in a per-facility
+// outbox that the server periodically drains and delivers to its peer.
+//
+// The aircraft itself is simulated by exactly one Sim at a time. While a
+// track is being handed off or pointed out to the other facility, the
+// receiving Sim shows it using a placeholder flight plan whose location
+// is updated from NASTrackUpdate messages. When the aircraft is switched
+// to a federated controller's frequency, it is sent to the receiving Sim,
+// which checks it over, adds it, and has it check in with the new
+// controller. The sending Sim keeps flying the aircraft until the
+// receiving Sim replies: it is removed once the transfer is accepted and
+// goes back to the controller that switched it if the transfer is
+// rejected or the facility is unfederated in the meantime.
+
+type NASMessageType int
+
+const (
+	NASHandoffInitiate NASMessageType = iota
+	NASHandoffAccept
+	NASHandoffCancel
+	NASPointOut
+	NASPointOutAcknowledge
+	NASPointOutReject
+	NASPointOutRecall
+	NASTrackUpdate
+	NASTrackTransfer
+	NASTrackDrop
+	NASTrackTransferAccept
+	NASTrackTransferReject
+)
+
+func (t NASMessageType) String() string {
+	switch t {
+	case NASHandoffInitiate:
+		return "HandoffInitiate"
+	case NASHandoffAccept:
+		return "HandoffAccept"
+	case NASHandoffCancel:
+		return "HandoffCancel"
+	case NASPointOut:
+		return "PointOut"
+	case NASPointOutAcknowledge:
+		return "PointOutAcknowledge"
+	case NASPointOutReject:
+		return "PointOutReject"
+	case NASPointOutRecall:
+		return "PointOutRecall"
+	case NASTrackUpdate:
+		return "TrackUpdate"
+	case NASTrackTransfer:
+		return "TrackTransfer"
+	case NASTrackDrop:
+		return "TrackDrop"
+	case NASTrackTransferAccept:
+		return "TrackTransferAccept"
+	case NASTrackTransferReject:
+		return "TrackTransferReject"
+	default:
+		return fmt.Sprintf("NASMessageType(%d)", int(t))
+	}
+}
+
+// NASPosition identifies a control position independently of how it is
+// named in any particular Sim: Position is the position as it is known in
+// its own facility (e.g., "56" rather than "N56").
+type NASPosition struct {
+	Facility string
+	Position string
+}
+
+// NASMessage is exchanged between federated Sims.
+type NASMessage struct {
+	Type NASMessageType
+	ACID ACID
+	// From and To are the sending and receiving controllers for handoffs
+	// and point outs (and their replies); for track transfers, From is
+	// the controller that switched the aircraft's frequency and To is the
+	// controller it is to contact.
+	From, To NASPosition
+	// Tracking is the controller that has the track; it is set for
+	// handoffs, point outs, and track transfers.
+	Tracking NASPosition
+
+	// FlightPlan is set for handoffs and point outs. Facility-specific
+	// fields (controllers, list index, CID, etc.) are cleared.
+	FlightPlan *NASFlightPlan `json:",omitempty"`
+	// Aircraft is set for track transfers.
+	Aircraft *Aircraft `json:",omitempty"`
+	Location math.Point2LL
+
+	// Seq is assigned when the message is queued; it increases with each
+	// message sent to a facility.
+	Seq uint64
+}
+
+// FederatedFacility holds the state for a facility that is being run in
+// another Sim.
+type FederatedFacility struct {
+	// Prefix is the neighbor prefix the facility's control positions have
+	// in this Sim.
+	Prefix string
+	// Outbox holds the messages for the facility that it hasn't yet
+	// acknowledged receiving; they are resent until it does.
+	Outbox  []NASMessage
+	NextSeq uint64
+	// LastReceivedSeq is the Seq of the last message from the facility
+	// that was processed; messages that are resent after it was received
+	// are ignored.
+	LastReceivedSeq uint64
+	// RemoteTracks records the ACIDs of placeholder flight plans in
+	// STARSComputer.FlightPlans for aircraft simulated by the other Sim.
+	RemoteTracks map[ACID]bool
+	// Transfers are aircraft that have been switched to one of the
+	// facility's controllers and will be sent to it at the next update.
+	Transfers []FederationTransfer
+	// PendingTransfers are aircraft that have been sent to the facility
+	// but that it hasn't yet accepted or rejected.
+	PendingTransfers []FederationTransfer
+}
+
+type FederationTransfer struct {
+	ADSBCallsign av.ADSBCallsign
+	ACID         ACID
+	From         ControlPosition
+	To           TCP
+}
+
+// FederateFacility starts exchanging NASMessages with the given
+// neighboring facility rather than treating its controllers as virtual
+// controllers. It is a no-op if the facility is already federated.
+func (s *Sim) FederateFacility(facility string) error {
+	s.mu.Lock(s.lg)
+	defer s.mu.Unlock(s.lg)
+
+	if _, ok := s.Federation[facility]; ok {
+		return nil
+	}
+
+	prefix := s.State.neighborPrefix(facility)
+	if prefix == "" || facility == s.State.Facility ||
+		!slices.ContainsFunc(slices.Collect(maps.Values(s.State.Controllers)),
+			func(ctrl *av.Controller) bool { return ctrl.FacilityIdentifier == prefix }) {
+		return ErrUnknownControllerFacility
+	}
+
+	if s.Federation == nil {
+		s.Federation = make(map[string]*FederatedFacility)
+	}
+	s.Federation[facility] = &FederatedFacility{
+		Prefix: prefix,
+		// Start from the current time rather than zero so that if this
+		// end of the link is reset, the other Sim doesn't take our new
+		// messages for ones it has already received.
+		NextSeq:      uint64(time.Now().UnixNano()),
+		RemoteTracks: make(map[ACID]bool),
+	}
+	s.State.FederatedFacilities = slices.Sorted(maps.Keys(s.Federation))
+
+	s.lg.Info("federated facility", slog.String("facility", facility), slog.String("prefix", prefix))
+	s.eventStream.Post(Event{
+		Type:        StatusMessageEvent,
+		WrittenText: facility + " is now being controlled from another server.",
+	})
+	s.publish()
+
+	return nil
+}
+
+// UnfederateFacility stops exchanging NASMessages with the given
+// facility; its controllers go back to being virtual controllers. Any
+// pending handoffs to them are cancelled, aircraft that were being
+// transferred to them stay here, and any tracks that were being shown
+// for the other Sim are removed.
+func (s *Sim) UnfederateFacility(facility string) error {
+	s.mu.Lock(s.lg)
+	defer s.mu.Unlock(s.lg)
+
+	fed, ok := s.Federation[facility]
+	if !ok {
+		return ErrNotFederatedFacility
+	}
+
+	for _, t := range slices.Concat(fed.Transfers, fed.PendingTransfers) {
+		s.returnTransferredAircraft(t)
+	}
+	for acid := range fed.RemoteTracks {
+		s.dropRemoteTrack(fed, acid)
+	}
+	for _, ac := range s.Aircraft {
+		if fp := ac.NASFlightPlan; fp != nil && s.federatedFacility(fp.HandoffController) == facility {
+			fp.HandoffController = ""
+		}
+	}
+	for acid := range s.PointOuts {
+		s.deletePointOuts(acid, func(po PointOut) bool {
+			return s.federatedFacility(po.ToController) == facility
+		})
+	}
+
+	delete(s.Federation, facility)
+	s.State.FederatedFacilities = slices.Sorted(maps.Keys(s.Federation))
+
+	s.lg.Info("unfederated facility", slog.String("facility", facility))
+	s.eventStream.Post(Event{
+		Type:        StatusMessageEvent,
+		WrittenText: "Lost connection to " + facility + ".",
+	})
+	s.publish()
+
+	return nil
+}
+
+// FederatedFacilities returns the facilities that the Sim is currently
+// federated with.
+func (s *Sim) FederatedFacilities() []string {
+	s.mu.Lock(s.lg)
+	defer s.mu.Unlock(s.lg)
+
+	return slices.Clone(s.State.FederatedFacilities)
+}
+
+// PendingNASMessages returns the messages queued for the given facility
+// that it hasn't acknowledged along with the Seq of the last message
+// received from it, which should be sent to it as an acknowledgement.
+func (s *Sim) PendingNASMessages(facility string) ([]NASMessage, uint64) {
+	s.mu.Lock(s.lg)
+	defer s.mu.Unlock(s.lg)
+
+	fed, ok := s.Federation[facility]
+	if !ok {
+		return nil, 0
+	}
+	return slices.Clone(fed.Outbox), fed.LastReceivedSeq
+}
+
+// AcknowledgeNASMessages removes the messages up to and including the one
+// with the given Seq from the facility's outbox; its Sim has received
+// them.
+func (s *Sim) AcknowledgeNASMessages(facility string, seq uint64) {
+	s.mu.Lock(s.lg)
+	defer s.mu.Unlock(s.lg)
+
+	if fed, ok := s.Federation[facility]; ok {
+		fed.Outbox = slices.DeleteFunc(fed.Outbox, func(msg NASMessage) bool { return msg.Seq <= seq })
+	}
+}
+
+// ReceiveNASMessages processes messages sent by the given facility's Sim.
+func (s *Sim) ReceiveNASMessages(facility string, msgs []NASMessage) error {
+	s.mu.Lock(s.lg)
+	defer s.mu.Unlock(s.lg)
+
+	fed, ok := s.Federation[facility]
+	if !ok {
+		return ErrNotFederatedFacility
+	}
+
+	for _, msg := range msgs {
+		if msg.Seq <= fed.LastReceivedSeq {
+			// It was resent because our acknowledgement was lost.
+			continue
+		}
+		fed.LastReceivedSeq = msg.Seq
+
+		if err := s.receiveNASMessage(facility, fed, msg); err != nil {
+			s.lg.Warn("unable to process NAS message", slog.String("facility", facility),
+				slog.String("type", msg.Type.String()), slog.String("acid", string(msg.ACID)),
+				slog.Any("error", err))
+		}
+	}
+
+	if len(msgs) > 0 {
+		s.publish()
+	}
+	return nil
+}
+
+func (s *Sim) receiveNASMessage(facility string, fed *FederatedFacility, msg NASMessage) error {
+	from, fromOk := s.fromNASPosition(msg.From)
+	to, toOk := s.fromNASPosition(msg.To)
+
+	switch msg.Type {
+	case NASHandoffInitiate:
+		if !fromOk || !toOk || !s.State.IsLocalController(to) {
+			return av.ErrNoController
+		}
+		fp, _, _ := s.getFlightPlanForACID(msg.ACID)
+		if fp == nil {
+			if fp = s.addRemoteTrack(fed, msg); fp == nil {
+				return ErrNoMatchingFlightPlan
+			}
+		}
+		fp.TrackingController = from
+		s.handoffTrack(fp, to)
+		if shouldCreateFlightStrip(fp) && !s.isVirtualController(to) {
+			s.initFlightStrip(fp, to)
+		}
+
+	case NASHandoffAccept:
+		fp, _, _ := s.getFlightPlanForACID(msg.ACID)
+		if fp == nil {
+			return ErrNoMatchingFlightPlan
+		} else if !fromOk || s.federatedFacility(fp.HandoffController) != facility {
+			return av.ErrNotBeingHandedOffToMe
+		}
+		s.eventStream.Post(Event{
+			Type:           AcceptedHandoffEvent,
+			ACID:           fp.ACID,
+			FromController: fp.TrackingController,
+			ToController:   from,
+		})
+		delete(s.Handoffs, fp.ACID)
+		fp.TrackingController = from
+		fp.HandoffController = ""
+		fp.OwningTCW = ""
+		fp.RedirectedHandoff = RedirectedHandoff{}
+
+	case NASHandoffCancel:
+		fp, _, _ := s.getFlightPlanForACID(msg.ACID)
+		if fp == nil {
+			return ErrNoMatchingFlightPlan
+		} else if toOk && fp.HandoffController == to {
+			delete(s.Handoffs, fp.ACID)
+			fp.HandoffController = ""
+			s.pruneRemoteTrack(fed, msg.ACID)
+		}
+
+	case NASPointOut:
+		if !fromOk || !toOk || !s.State.IsLocalController(to) {
+			return av.ErrNoController
+		}
+		fp, _, _ := s.getFlightPlanForACID(msg.ACID)
+		if fp == nil {
+			if fp = s.addRemoteTrack(fed, msg); fp == nil {
+				return ErrNoMatchingFlightPlan
+			}
+			if tracking, ok := s.fromNASPosition(msg.Tracking); ok {
+				fp.TrackingController = tracking
+			}
+		}
+		s.pointOut(msg.ACID, s.State.Controllers[from], s.State.Controllers[to])
+
+	case NASPointOutAcknowledge, NASPointOutReject:
+		fp, _, _ := s.getFlightPlanForACID(msg.ACID)
+		match := func(po PointOut) bool { return po.FromController == to && po.ToController == from }
+		if fp == nil || !fromOk || !toOk || !slices.ContainsFunc(s.PointOuts[msg.ACID], match) {
+			return av.ErrNotPointedOutByMe
+		}
+		// As with local acknowledgements, "to" and "from" are swapped in
+		// the event since they are w.r.t. the original point out.
+		s.eventStream.Post(Event{
+			Type:           util.Select(msg.Type == NASPointOutAcknowledge, AcknowledgedPointOutEvent, RejectedPointOutEvent),
+			FromController: from,
+			ToController:   to,
+			ACID:           msg.ACID,
+		})
+		if msg.Type == NASPointOutAcknowledge {
+			fp.AddPointOutHistory(from)
+		}
+		s.deletePointOuts(msg.ACID, match)
+
+	case NASPointOutRecall:
+		match := func(po PointOut) bool { return po.FromController == from && po.ToController == to }
+		if !fromOk || !toOk || !slices.ContainsFunc(s.PointOuts[msg.ACID], match) {
+			return av.ErrNotPointedOutToMe
+		}
+		s.eventStream.Post(Event{
+			Type:           RecalledPointOutEvent,
+			FromController: from,
+			ToController:   to,
+			ACID:           msg.ACID,
+		})
+		s.deletePointOuts(msg.ACID, match)
+		s.pruneRemoteTrack(fed, msg.ACID)
+
+	case NASTrackUpdate:
+		if fed.RemoteTracks[msg.ACID] {
+			if fp := s.STARSComputer.lookupFlightPlanByACID(msg.ACID); fp != nil {
+				fp.Location = msg.Location
+			}
+		}
+
+	case NASTrackTransfer:
+		err := s.receiveTrackTransfer(fed, msg, from, to, toOk)
+		s.sendNASMessage(fed, NASMessage{
+			Type: util.Select(err == nil, NASTrackTransferAccept, NASTrackTransferReject),
+			ACID: msg.ACID,
+			From: msg.To,
+			To:   msg.From,
+		})
+		return err
+
+	case NASTrackTransferAccept, NASTrackTransferReject:
+		idx := slices.IndexFunc(fed.PendingTransfers, func(t FederationTransfer) bool { return t.ACID == msg.ACID })
+		if idx == -1 {
+			return ErrNoMatchingFlightPlan
+		}
+		t := fed.PendingTransfers[idx]
+		fed.PendingTransfers = slices.Delete(fed.PendingTransfers, idx, idx+1)
+		if msg.Type == NASTrackTransferAccept {
+			s.completeTransfer(t)
+		} else {
+			s.lg.Warn("federated facility rejected aircraft", slog.String("facility", facility),
+				slog.String("acid", string(msg.ACID)))
+			s.returnTransferredAircraft(t)
+		}
+
+	case NASTrackDrop:
+		if fed.RemoteTracks[msg.ACID] {
+			s.dropRemoteTrack(fed, msg.ACID)
+		}
+
+	default:
+		return fmt.Errorf("%d: unknown NAS message type", msg.Type)
+	}
+
+	return nil
+}
+
+func (s *Sim) receiveTrackTransfer(fed *FederatedFacility, msg NASMessage, from, to TCP, toOk bool) error {
+	if msg.Aircraft == nil || msg.Aircraft.NASFlightPlan == nil {
+		return av.ErrNoFlightPlan
+	}
+	if !toOk || !s.State.IsLocalController(to) {
+		return av.ErrNoController
+	}
+	ac := *msg.Aircraft
+	if err := s.checkTransferredAircraft(&ac, msg.ACID); err != nil {
+		return err
+	}
+	if _, ok := s.Aircraft[ac.ADSBCallsign]; ok {
+		return ErrDuplicateACID
+	}
+
+	// Use the placeholder flight plan if we have one, since it has this
+	// Sim's controllers, list index, strip, etc.
+	var fp *NASFlightPlan
+	if fed.RemoteTracks[msg.ACID] {
+		fp = s.STARSComputer.takeFlightPlanByACID(msg.ACID)
+		delete(fed.RemoteTracks, msg.ACID)
+	}
+	if fp == nil {
+		fp = federatedFlightPlan(ac.NASFlightPlan)
+		fp.ListIndex = s.STARSComputer.getListIndex()
+		if tracking, ok := s.fromNASPosition(msg.Tracking); ok {
+			fp.TrackingController = tracking
+		}
+	}
+	fp.Location = math.Point2LL{}
+	fp.AssignedSquawk = ac.NASFlightPlan.AssignedSquawk
+
+	if s.ERAMComputer.SquawkCodePool.InInitialPool(fp.AssignedSquawk) {
+		// Account for the code so that it's not handed out again while
+		// the aircraft is here; it's returned when the flight plan is
+		// deleted.
+		if err := s.ERAMComputer.SquawkCodePool.Take(fp.AssignedSquawk); err != nil {
+			s.lg.Warn("transferred aircraft squawk code already assigned",
+				slog.String("acid", string(fp.ACID)), slog.String("squawk", fp.AssignedSquawk.String()))
+		}
+	}
+
+	ac.NASFlightPlan = fp
+	ac.ControllerFrequency = ""
+	s.addAircraftNoLock(ac)
+
+	s.lg.Info("received aircraft from federated facility", slog.String("adsb_callsign", string(ac.ADSBCallsign)),
+		slog.String("to", string(to)))
+
+	if pac, ok := s.Aircraft[ac.ADSBCallsign]; ok {
+		s.enqueueControllerContact(pac, to, from)
+	}
+	return nil
+}
+
+// federationTransferRangeSlop is how far outside of the Sim's range a
+// transferred aircraft may be, in nm.
+const federationTransferRangeSlop = 100
+
+// checkTransferredAircraft makes sure that an aircraft sent by a federated
+// facility's Sim is sensible before it's added to this one and resets the
+// parts of its state that should come from this Sim rather than the other
+// one.
+func (s *Sim) checkTransferredAircraft(ac *Aircraft, acid ACID) error {
+	if ac.ADSBCallsign == "" || ac.NASFlightPlan.ACID != acid {
+		return ErrInvalidTransferredAircraft
+	}
+
+	perf, ok := av.DB.AircraftPerformance[ac.FlightPlan.AircraftType]
+	if !ok {
+		return ErrUnknownAircraftType
+	}
+	ac.Nav.Perf = perf
+	ac.Nav.FlightState.NmPerLongitude = s.State.NmPerLongitude
+	ac.Nav.FlightState.MagneticVariation = s.State.MagneticVariation
+
+	if math.NMDistance2LL(ac.Position(), s.State.Center) > s.State.Range+federationTransferRangeSlop ||
+		ac.Altitude() < 0 || ac.Altitude() > max(perf.Ceiling, 60000) ||
+		ac.Nav.FlightState.IAS < 0 || (perf.Speed.MaxTAS > 0 && ac.Nav.FlightState.IAS > perf.Speed.MaxTAS) ||
+		ac.Fuel < 0 || ac.FuelBurn < 0 {
+		return ErrInvalidTransferredAircraft
+	}
+
+	// It's already in the air, so none of the departure launch state
+	// applies.
+	ac.HoldForRelease = false
+	ac.Released = false
+	ac.WaitingForLaunch = false
+
+	return nil
+}
+
+// addRemoteTrack creates a placeholder flight plan for an aircraft that
+// is simulated by a federated facility's Sim.
+func (s *Sim) addRemoteTrack(fed *FederatedFacility, msg NASMessage) *NASFlightPlan {
+	if msg.FlightPlan == nil {
+		return nil
+	}
+
+	fp := federatedFlightPlan(msg.FlightPlan)
+	fp.ACID = msg.ACID
+	fp.ListIndex = s.STARSComputer.getListIndex()
+	fp.Location = msg.Location
+	s.STARSComputer.FlightPlans = append(s.STARSComputer.FlightPlans, fp)
+	fed.RemoteTracks[msg.ACID] = true
+
+	return fp
+}
+
+// pruneRemoteTrack removes the placeholder flight plan for a remote track
+// once nothing in this Sim is interested in it any more.
+func (s *Sim) pruneRemoteTrack(fed *FederatedFacility, acid ACID) {
+	if !fed.RemoteTracks[acid] {
+		return
+	}
+	fp := s.STARSComputer.lookupFlightPlanByACID(acid)
+	if fp == nil || (!s.State.IsLocalController(fp.TrackingController) && fp.HandoffController == "" &&
+		len(s.PointOuts[acid]) == 0) {
+		s.dropRemoteTrack(fed, acid)
+	}
+}
+
+func (s *Sim) dropRemoteTrack(fed *FederatedFacility, acid ACID) {
+	delete(fed.RemoteTracks, acid)
+	delete(s.Handoffs, acid)
+	delete(s.PointOuts, acid)
+
+	if fp := s.STARSComputer.takeFlightPlanByACID(acid); fp != nil {
+		// The beacon code and CID belong to the other Sim, so only
+		// release the things that we allocated.
+		s.STARSComputer.returnListIndex(fp.ListIndex)
+		if fp.StripOwner != "" {
+			s.freeStripCID(fp.StripCID)
+		}
+	}
+}
+
+// federatedFlightPlan returns a copy of the flight plan with the fields
+// that are specific to the Sim that it came from cleared.
+func federatedFlightPlan(fp *NASFlightPlan) *NASFlightPlan {
+	c := *fp
+	c.CID = ""
+	c.TrackingController = ""
+	c.HandoffController = ""
+	c.LastLocalController = ""
+	c.OwningTCW = ""
+	c.InboundHandoffController = ""
+	c.PointOutHistory = nil
+	c.RedirectedHandoff = RedirectedHandoff{}
+	c.ListIndex = UnsetSTARSListIndex
+	c.StripCID = 0
+	c.StripOwner = ""
+	c.StripAnnotations = [9]string{}
+	c.Location = math.Point2LL{}
+	c.DeleteTime = Time{}
+	c.GlobalLeaderLineDirection = nil
+	return &c
+}
+
+///////////////////////////////////////////////////////////////////////////
+// Outbound messages
+
+// neighborPrefix returns the prefix that the given neighboring facility's
+// control positions have in this Sim, following the same rules that are
+// used when the neighbor controllers are loaded.
+func (ss *CommonState) neighborPrefix(facility string) string {
+	for _, hid := range ss.HandoffIDs {
+		if hid.ID == facility {
+			if hid.StarsID != "" {
+				return hid.StarsID
+			}
+			return hid.Prefix
+		}
+	}
+	return ""
+}
+
+// federatedFacility returns the facility that the given control position
+// belongs to if that facility is federated and "" otherwise.
+func (s *Sim) federatedFacility(pos ControlPosition) string {
+	if len(s.Federation) == 0 || pos == "" {
+		return ""
+	}
+	ctrl, ok := s.State.Controllers[pos]
+	if !ok || ctrl.FacilityIdentifier == "" {
+		return ""
+	}
+	for fac, fed := range s.Federation {
+		if fed.Prefix == ctrl.FacilityIdentifier {
+			return fac
+		}
+	}
+	return ""
+}
+
+func (s *Sim) toNASPosition(pos ControlPosition) NASPosition {
+	ctrl, ok := s.State.Controllers[pos]
+	if !ok {
+		return NASPosition{}
+	} else if ctrl.FacilityIdentifier == "" {
+		return NASPosition{Facility: s.State.Facility, Position: ctrl.Position}
+	} else if fac := s.federatedFacility(pos); fac != "" {
+		return NASPosition{Facility: fac, Position: ctrl.Position}
+	}
+	return NASPosition{}
+}
+
+func (s *Sim) fromNASPosition(p NASPosition) (TCP, bool) {
+	var pos TCP
+	if p.Facility == s.State.Facility {
+		pos = TCP(p.Position)
+	} else if fed, ok := s.Federation[p.Facility]; ok {
+		pos = TCP(fed.Prefix + p.Position)
+	} else {
+		return "", false
+	}
+
+	_, ok := s.State.Controllers[pos]
+	return pos, ok
+}
+
+func (s *Sim) sendNASMessage(fed *FederatedFacility, msg NASMessage) {
+	if msg.Type == NASTrackUpdate {
+		// Only the latest position matters. The new one goes at the end
+		// so that the outbox stays in Seq order.
+		fed.Outbox = slices.DeleteFunc(fed.Outbox, func(m NASMessage) bool {
+			return m.Type == NASTrackUpdate && m.ACID == msg.ACID
+		})
+	}
+	msg.Seq = fed.NextSeq
+	fed.NextSeq++
+	fed.Outbox = append(fed.Outbox, msg)
+}
+
+// sendFederatedHandoff is called when a track is offered to a federated
+// facility's controller.
+func (s *Sim) sendFederatedHandoff(fp *NASFlightPlan, toTCP TCP) {
+	fed := s.Federation[s.federatedFacility(toTCP)]
+	s.sendNASMessage(fed, NASMessage{
+		Type:       NASHandoffInitiate,
+		ACID:       fp.ACID,
+		From:       s.toNASPosition(fp.TrackingController),
+		To:         s.toNASPosition(toTCP),
+		Tracking:   s.toNASPosition(fp.TrackingController),
+		FlightPlan: federatedFlightPlan(fp),
+		Location:   s.trackLocation(fp),
+	})
+}
+
+func (s *Sim) sendFederatedPointOut(acid ACID, from, to ControlPosition) {
+	fp, _, _ := s.getFlightPlanForACID(acid)
+	if fp == nil {
+		return
+	}
+	fed := s.Federation[s.federatedFacility(to)]
+	s.sendNASMessage(fed, NASMessage{
+		Type:       NASPointOut,
+		ACID:       acid,
+		From:       s.toNASPosition(from),
+		To:         s.toNASPosition(to),
+		Tracking:   s.toNASPosition(fp.TrackingController),
+		FlightPlan: federatedFlightPlan(fp),
+		Location:   s.trackLocation(fp),
+	})
+}
+
+// sendFederatedReply sends a message in response to a handoff or point
+// out from a federated facility's controller, if from is one.
+func (s *Sim) sendFederatedReply(ty NASMessageType, acid ACID, from, to ControlPosition) {
+	fac := s.federatedFacility(to)
+	if fac == "" {
+		return
+	}
+	s.sendNASMessage(s.Federation[fac], NASMessage{
+		Type: ty,
+		ACID: acid,
+		From: s.toNASPosition(from),
+		To:   s.toNASPosition(to),
+	})
+}
+
+// pruneFederatedTrack removes the placeholder flight plan for the given
+// ACID if it is a remote track that is no longer of interest.
+func (s *Sim) pruneFederatedTrack(acid ACID) {
+	for _, fed := range s.Federation {
+		s.pruneRemoteTrack(fed, acid)
+	}
+}
+
+func (s *Sim) trackLocation(fp *NASFlightPlan) math.Point2LL {
+	if _, ac, _ := s.getFlightPlanForACID(fp.ACID); ac != nil {
+		return ac.Position()
+	}
+	return fp.Location
+}
+
+// federationInterest returns the federated facilities that need track
+// updates for the given flight plan.
+func (s *Sim) federationInterest(fp *NASFlightPlan) []string {
+	var facs []string
+	add := func(pos ControlPosition) {
+		if fac := s.federatedFacility(pos); fac != "" && !slices.Contains(facs, fac) {
+			facs = append(facs, fac)
+		}
+	}
+	add(fp.TrackingController)
+	add(fp.HandoffController)
+	for _, po := range s.PointOuts[fp.ACID] {
+		add(po.ToController)
+	}
+	return facs
+}
+
+// queueFederationTransfer is called when an aircraft is told to contact a
+// federated facility's controller.
+func (s *Sim) queueFederationTransfer(ac *Aircraft, tcp TCP, fromPos ControlPosition) {
+	fed := s.Federation[s.federatedFacility(tcp)]
+	match := func(t FederationTransfer) bool { return t.ADSBCallsign == ac.ADSBCallsign }
+	if !slices.ContainsFunc(fed.Transfers, match) && !slices.ContainsFunc(fed.PendingTransfers, match) {
+		fed.Transfers = append(fed.Transfers, FederationTransfer{ADSBCallsign: ac.ADSBCallsign, From: fromPos, To: tcp})
+	}
+}
+
+// federationTrackDropped is called when an aircraft is deleted.
+func (s *Sim) federationTrackDropped(fp *NASFlightPlan) {
+	for _, fac := range s.federationInterest(fp) {
+		s.sendNASMessage(s.Federation[fac], NASMessage{Type: NASTrackDrop, ACID: fp.ACID})
+	}
+}
+
+// updateFederation is called once a second to send pending aircraft
+// transfers and track updates to federated facilities.
+func (s *Sim) updateFederation() {
+	for _, fac := range slices.Sorted(maps.Keys(s.Federation)) {
+		fed := s.Federation[fac]
+		transfers := fed.Transfers
+		fed.Transfers = nil
+		for _, t := range transfers {
+			ac, ok := s.Aircraft[t.ADSBCallsign]
+			if !ok || !ac.IsAssociated() {
+				continue
+			}
+			s.transferAircraft(fed, ac, t)
+		}
+	}
+
+	for _, ac := range util.SortedMap(s.Aircraft) {
+		if !ac.IsAssociated() {
+			continue
+		}
+		for _, fac := range s.federationInterest(ac.NASFlightPlan) {
+			s.sendNASMessage(s.Federation[fac], NASMessage{
+				Type:     NASTrackUpdate,
+				ACID:     ac.NASFlightPlan.ACID,
+				Location: ac.Position(),
+			})
+		}
+	}
+}
+
+func (s *Sim) transferAircraft(fed *FederatedFacility, ac *Aircraft, t FederationTransfer) {
+	fp := ac.NASFlightPlan
+	msg := NASMessage{
+		Type:     NASTrackTransfer,
+		ACID:     fp.ACID,
+		From:     s.toNASPosition(t.From),
+		To:       s.toNASPosition(t.To),
+		Tracking: s.toNASPosition(fp.TrackingController),
+	}
+	acCopy := *ac
+	acCopy.NASFlightPlan = federatedFlightPlan(fp)
+	msg.Aircraft = &acCopy
+	s.sendNASMessage(fed, msg)
+
+	// Keep flying the aircraft here until the other Sim says whether it
+	// has taken it.
+	t.ACID = fp.ACID
+	fed.PendingTransfers = append(fed.PendingTransfers, t)
+
+	s.lg.Info("transferring aircraft to federated facility", slog.String("adsb_callsign", string(ac.ADSBCallsign)),
+		slog.String("to", string(t.To)))
+}
+
+// completeTransfer is called when a federated facility has accepted an
+// aircraft that was sent to it.
+func (s *Sim) completeTransfer(t FederationTransfer) {
+	ac, ok := s.Aircraft[t.ADSBCallsign]
+	if !ok || ac.NASFlightPlan == nil || ac.NASFlightPlan.ACID != t.ACID {
+		return
+	}
+
+	s.lg.Info("transferred aircraft to federated facility", slog.String("adsb_callsign", string(ac.ADSBCallsign)),
+		slog.String("to", string(t.To)))
+
+	// The other Sim now owns the aircraft; remove it here without
+	// telling the other Sim that the track was dropped.
+	fp := ac.NASFlightPlan
+	ac.DisassociateFlightPlan()
+	delete(s.Handoffs, fp.ACID)
+	delete(s.PointOuts, fp.ACID)
+	s.deleteFlightPlan(fp)
+	s.deleteAircraft(ac)
+}
+
+// returnTransferredAircraft is called when an aircraft that was switched
+// to a federated facility's controller isn't going to be simulated there
+// after all; the controller that switched it gets the track back and the
+// aircraft stays on their frequency.
+func (s *Sim) returnTransferredAircraft(t FederationTransfer) {
+	ac, ok := s.Aircraft[t.ADSBCallsign]
+	if !ok || ac.NASFlightPlan == nil {
+		return
+	}
+
+	fp := ac.NASFlightPlan
+	if s.State.IsLocalController(t.From) {
+		fp.TrackingController = t.From
+		fp.HandoffController = ""
+		delete(s.Handoffs, fp.ACID)
+	}
+	ac.ControllerFrequency = t.From
+
+	s.eventStream.Post(Event{
+		Type:        StatusMessageEvent,
+		WrittenText: fmt.Sprintf("%s could not be transferred to %s.", fp.ACID, t.To),
+	})
+}
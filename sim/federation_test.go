@@ -0,0 +1,477 @@
+// sim/federation_test.go
+// Copyright(c) 2022-2026 vice contributors, licensed under the GNU Public License, Version 3.
+// SPDX: GPL-3.0-only
+
+package sim
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	av "github.com/mmp/vice/aviation"
+	"github.com/mmp/vice/math"
+)
+
+// makeFederationTestSim returns a Sim for the given facility with a single
+// local position and a single position in the neighboring facility.
+func makeFederationTestSim(t *testing.T, facility, local, neighbor, prefix, remote string) *Sim {
+	t.Helper()
+
+	s := makeTestSim(t)
+	s.State.Facility = facility
+	s.State.HandoffIDs = []HandoffID{{ID: neighbor, Prefix: prefix}}
+	s.State.Controllers = map[ControlPosition]*av.Controller{
+		TCP(local):           {Position: local, Frequency: 125000},
+		TCP(prefix + remote): {Position: remote, FacilityIdentifier: prefix, Frequency: 132000},
+	}
+	s.ControlPositions = s.State.Controllers
+	s.ScenarioDefaultConsolidation = PositionConsolidation{TCP(local): nil}
+	s.State.CurrentConsolidation = map[TCW]*TCPConsolidation{TCW(local): {PrimaryTCP: TCP(local)}}
+	s.PrivilegedTCWs = map[TCW]bool{}
+	s.Handoffs = make(map[ACID]Handoff)
+	s.PointOuts = make(map[ACID][]PointOut)
+	s.STARSComputer = makeSTARSComputer(facility)
+	s.ERAMComputer = makeERAMComputer(facility, nil)
+	s.State.Center = math.Point2LL{-73.8, 40.6}
+	s.State.Range = 50
+	return s
+}
+
+func addFederationTestAircraft(t *testing.T, s *Sim, callsign av.ADSBCallsign, tcp TCP) *Aircraft {
+	t.Helper()
+
+	ac := addTestAircraft(s, callsign, math.Point2LL{-73.8, 40.6}, 8000)
+	ac.FlightPlan.AircraftType = "B738"
+	ac.ControllerFrequency = tcp
+	sq, err := s.ERAMComputer.CreateSquawk()
+	if err != nil {
+		t.Fatalf("CreateSquawk: %v", err)
+	}
+	ac.Squawk = sq
+	ac.NASFlightPlan.AssignedSquawk = sq
+	ac.NASFlightPlan.TrackingController = tcp
+	ac.NASFlightPlan.LastLocalController = tcp
+	ac.NASFlightPlan.OwningTCW = TCW(tcp)
+	ac.NASFlightPlan.Rules = av.FlightRulesIFR
+	ac.NASFlightPlan.ListIndex = s.STARSComputer.getListIndex()
+	return ac
+}
+
+// takeNASMessages returns the messages the Sim has queued for the
+// facility and acknowledges them as if they had been delivered.
+func takeNASMessages(s *Sim, facility string) []NASMessage {
+	msgs, _ := s.PendingNASMessages(facility)
+	if len(msgs) > 0 {
+		s.AcknowledgeNASMessages(facility, msgs[len(msgs)-1].Seq)
+	}
+	return msgs
+}
+
+// exchangeNASMessages delivers the messages queued in each Sim to the
+// other, as the server does.
+func exchangeNASMessages(t *testing.T, tracon, artcc *Sim) {
+	t.Helper()
+
+	if err := artcc.ReceiveNASMessages("N90", takeNASMessages(tracon, "ZNY")); err != nil {
+		t.Fatalf("ZNY: ReceiveNASMessages: %v", err)
+	}
+	if err := tracon.ReceiveNASMessages("ZNY", takeNASMessages(artcc, "N90")); err != nil {
+		t.Fatalf("N90: ReceiveNASMessages: %v", err)
+	}
+}
+
+func makeFederatedTestSims(t *testing.T) (tracon, artcc *Sim) {
+	t.Helper()
+
+	if _, ok := av.DB.AircraftPerformance["B738"]; !ok {
+		av.DB.AircraftPerformance["B738"] = av.AircraftPerformance{ICAO: "B738", Ceiling: 41000}
+		t.Cleanup(func() { delete(av.DB.AircraftPerformance, "B738") })
+	}
+
+	tracon = makeFederationTestSim(t, "N90", "1N", "ZNY", "N", "56")
+	artcc = makeFederationTestSim(t, "ZNY", "56", "N90", "2", "1N")
+
+	if err := tracon.FederateFacility("ZBW"); !errors.Is(err, ErrUnknownControllerFacility) {
+		t.Errorf("expected ErrUnknownControllerFacility, got %v", err)
+	}
+	if err := tracon.FederateFacility("ZNY"); err != nil {
+		t.Fatalf("FederateFacility: %v", err)
+	}
+	if err := artcc.FederateFacility("N90"); err != nil {
+		t.Fatalf("FederateFacility: %v", err)
+	}
+	if !slices.Equal(tracon.State.FederatedFacilities, []string{"ZNY"}) {
+		t.Errorf("got federated facilities %v, expected [ZNY]", tracon.State.FederatedFacilities)
+	}
+	return
+}
+
+func TestFederatedHandoffAndTransfer(t *testing.T) {
+	tracon, artcc := makeFederatedTestSims(t)
+	ac := addFederationTestAircraft(t, tracon, "AAL1", "1N")
+	squawk := ac.Squawk
+
+	if tracon.isVirtualController("N56") {
+		t.Errorf("federated controller should not be virtual")
+	}
+
+	if err := tracon.HandoffTrack("1N", "AAL1", "N56"); err != nil {
+		t.Fatalf("HandoffTrack: %v", err)
+	}
+	if _, ok := tracon.Handoffs["AAL1"]; ok {
+		t.Errorf("handoff to a federated controller should not be auto-accepted")
+	}
+	exchangeNASMessages(t, tracon, artcc)
+
+	fp, rac, active := artcc.getFlightPlanForACID("AAL1")
+	if fp == nil || rac != nil || !active {
+		t.Fatalf("expected a remote track in ZNY; got fp %v ac %v active %v", fp, rac, active)
+	}
+	if fp.TrackingController != "21N" || fp.HandoffController != "56" {
+		t.Errorf("got tracking %q handoff %q, expected 21N/56", fp.TrackingController, fp.HandoffController)
+	}
+	if fp.AssignedSquawk != squawk {
+		t.Errorf("got squawk %s, expected %s", fp.AssignedSquawk, squawk)
+	}
+
+	// Track updates move the remote track.
+	ac.Nav.FlightState.Position = math.Point2LL{-73.8, 40.7}
+	tracon.updateFederation()
+	exchangeNASMessages(t, tracon, artcc)
+	if fp.Location != ac.Position() {
+		t.Errorf("remote track at %v, expected %v", fp.Location, ac.Position())
+	}
+
+	if err := artcc.AcceptHandoff("56", "AAL1"); err != nil {
+		t.Fatalf("AcceptHandoff: %v", err)
+	}
+	exchangeNASMessages(t, tracon, artcc)
+	if tfp := ac.NASFlightPlan; tfp.TrackingController != "N56" || tfp.HandoffController != "" {
+		t.Errorf("got tracking %q handoff %q after accept, expected N56", tfp.TrackingController, tfp.HandoffController)
+	}
+
+	// Switching the aircraft to ZNY moves it to the other Sim.
+	if _, err := tracon.ContactTrackingController("1N", "AAL1"); err != nil {
+		t.Fatalf("ContactTrackingController: %v", err)
+	}
+	tracon.updateFederation()
+	exchangeNASMessages(t, tracon, artcc)
+
+	if _, ok := tracon.Aircraft["AAL1"]; ok {
+		t.Errorf("aircraft should have been removed from N90")
+	}
+	rac, ok := artcc.Aircraft["AAL1"]
+	if !ok {
+		t.Fatalf("aircraft should have been added to ZNY")
+	}
+	if rac.NASFlightPlan != fp || fp.TrackingController != "56" || !fp.Location.IsZero() {
+		t.Errorf("expected the ZNY flight plan tracked by 56; got %+v", rac.NASFlightPlan)
+	}
+	if rac.Squawk != squawk {
+		t.Errorf("got squawk %s, expected %s", rac.Squawk, squawk)
+	}
+	if artcc.STARSComputer.lookupFlightPlanByACID("AAL1") != nil {
+		t.Errorf("remote track placeholder should have been removed")
+	}
+	if !slices.ContainsFunc(artcc.FutureFrequencyChanges, func(f FutureFrequencyChange) bool {
+		return f.ADSBCallsign == "AAL1" && f.TCP == "56"
+	}) {
+		t.Errorf("aircraft should be switching to 56")
+	}
+}
+
+func TestFederatedHandoffCancel(t *testing.T) {
+	tracon, artcc := makeFederatedTestSims(t)
+	addFederationTestAircraft(t, tracon, "AAL1", "1N")
+
+	if err := tracon.HandoffTrack("1N", "AAL1", "N56"); err != nil {
+		t.Fatalf("HandoffTrack: %v", err)
+	}
+	exchangeNASMessages(t, tracon, artcc)
+	if err := tracon.CancelHandoff("1N", "AAL1"); err != nil {
+		t.Fatalf("CancelHandoff: %v", err)
+	}
+	exchangeNASMessages(t, tracon, artcc)
+
+	if fp, _, _ := artcc.getFlightPlanForACID("AAL1"); fp != nil {
+		t.Errorf("remote track should have been removed after cancel")
+	}
+	if n := len(artcc.STARSComputer.AvailableIndices); n != 99 {
+		t.Errorf("%d available list indices, expected 99", n)
+	}
+}
+
+func TestFederatedPointOut(t *testing.T) {
+	tracon, artcc := makeFederatedTestSims(t)
+	addFederationTestAircraft(t, tracon, "AAL1", "1N")
+
+	if err := tracon.PointOut("1N", "AAL1", "N56"); err != nil {
+		t.Fatalf("PointOut: %v", err)
+	}
+	exchangeNASMessages(t, tracon, artcc)
+
+	pos := artcc.PointOuts["AAL1"]
+	if len(pos) != 1 || pos[0].FromController != "21N" || pos[0].ToController != "56" {
+		t.Fatalf("got ZNY point outs %+v, expected 21N->56", pos)
+	}
+
+	// The federated controller, not the sim, acknowledges it.
+	tracon.State.SimTime = tracon.State.SimTime.Add(time.Minute)
+	tracon.lastSimUpdate = tracon.State.SimTime // skip the aircraft updates
+	tracon.updateState()
+	if len(tracon.PointOuts["AAL1"]) != 1 {
+		t.Fatalf("point out to a federated controller should not be auto-acknowledged")
+	}
+
+	if err := artcc.AcknowledgePointOut("56", "AAL1"); err != nil {
+		t.Fatalf("AcknowledgePointOut: %v", err)
+	}
+	exchangeNASMessages(t, tracon, artcc)
+
+	if len(tracon.PointOuts["AAL1"]) != 0 {
+		t.Errorf("point out should have been acknowledged: %+v", tracon.PointOuts["AAL1"])
+	}
+	if fp := tracon.Aircraft["AAL1"].NASFlightPlan; !slices.Equal(fp.PointOutHistory, []ControlPosition{"N56"}) {
+		t.Errorf("got point out history %v, expected [N56]", fp.PointOutHistory)
+	}
+	if fp, _, _ := artcc.getFlightPlanForACID("AAL1"); fp != nil {
+		t.Errorf("remote track should have been removed after the acknowledgement")
+	}
+}
+
+func TestFederatedTrackDropAndUnfederate(t *testing.T) {
+	tracon, artcc := makeFederatedTestSims(t)
+	ac := addFederationTestAircraft(t, tracon, "AAL1", "1N")
+	addFederationTestAircraft(t, tracon, "AAL2", "1N")
+
+	if err := tracon.HandoffTrack("1N", "AAL1", "N56"); err != nil {
+		t.Fatalf("HandoffTrack: %v", err)
+	}
+	if err := tracon.HandoffTrack("1N", "AAL2", "N56"); err != nil {
+		t.Fatalf("HandoffTrack: %v", err)
+	}
+	exchangeNASMessages(t, tracon, artcc)
+
+	tracon.deleteAircraft(ac)
+	exchangeNASMessages(t, tracon, artcc)
+	if fp, _, _ := artcc.getFlightPlanForACID("AAL1"); fp != nil {
+		t.Errorf("remote track should have been removed after the aircraft was deleted")
+	}
+
+	if err := artcc.UnfederateFacility("N90"); err != nil {
+		t.Fatalf("UnfederateFacility: %v", err)
+	}
+	if fp, _, _ := artcc.getFlightPlanForACID("AAL2"); fp != nil {
+		t.Errorf("remote track should have been removed after unfederating")
+	}
+	if err := artcc.ReceiveNASMessages("N90", nil); !errors.Is(err, ErrNotFederatedFacility) {
+		t.Errorf("expected ErrNotFederatedFacility, got %v", err)
+	}
+	if !artcc.isVirtualController("21N") {
+		t.Errorf("controller should be virtual after unfederating")
+	}
+}
+
+// handOffToZNY hands AAL1 off from N90 to ZNY and switches it to ZNY's
+// frequency so that it's queued for transfer.
+func handOffToZNY(t *testing.T, tracon, artcc *Sim) {
+	t.Helper()
+
+	if err := tracon.HandoffTrack("1N", "AAL1", "N56"); err != nil {
+		t.Fatalf("HandoffTrack: %v", err)
+	}
+	exchangeNASMessages(t, tracon, artcc)
+	if err := artcc.AcceptHandoff("56", "AAL1"); err != nil {
+		t.Fatalf("AcceptHandoff: %v", err)
+	}
+	exchangeNASMessages(t, tracon, artcc)
+	if _, err := tracon.ContactTrackingController("1N", "AAL1"); err != nil {
+		t.Fatalf("ContactTrackingController: %v", err)
+	}
+}
+
+func TestFederatedTransferPending(t *testing.T) {
+	tracon, artcc := makeFederatedTestSims(t)
+	addFederationTestAircraft(t, tracon, "AAL1", "1N")
+	handOffToZNY(t, tracon, artcc)
+
+	tracon.updateFederation()
+	if _, ok := tracon.Aircraft["AAL1"]; !ok {
+		t.Fatalf("aircraft should stay in N90 until ZNY accepts it")
+	}
+	if n := len(tracon.Federation["ZNY"].PendingTransfers); n != 1 {
+		t.Fatalf("%d pending transfers, expected 1", n)
+	}
+
+	// Switching it again while the transfer is pending doesn't send it twice.
+	tracon.queueFederationTransfer(tracon.Aircraft["AAL1"], "N56", "1N")
+	if n := len(tracon.Federation["ZNY"].Transfers); n != 0 {
+		t.Errorf("%d queued transfers for an aircraft that's already being transferred", n)
+	}
+
+	if err := artcc.ReceiveNASMessages("N90", takeNASMessages(tracon, "ZNY")); err != nil {
+		t.Fatalf("ReceiveNASMessages: %v", err)
+	}
+	if _, ok := tracon.Aircraft["AAL1"]; !ok {
+		t.Fatalf("aircraft should stay in N90 until the acceptance is received")
+	}
+	if err := tracon.ReceiveNASMessages("ZNY", takeNASMessages(artcc, "N90")); err != nil {
+		t.Fatalf("ReceiveNASMessages: %v", err)
+	}
+	if _, ok := tracon.Aircraft["AAL1"]; ok {
+		t.Errorf("aircraft should have been removed from N90 after ZNY accepted it")
+	}
+	if _, ok := artcc.Aircraft["AAL1"]; !ok {
+		t.Errorf("aircraft should have been added to ZNY")
+	}
+	if n := len(tracon.Federation["ZNY"].PendingTransfers); n != 0 {
+		t.Errorf("%d pending transfers after acceptance, expected 0", n)
+	}
+}
+
+func TestFederatedMessagesResent(t *testing.T) {
+	tracon, artcc := makeFederatedTestSims(t)
+	addFederationTestAircraft(t, tracon, "AAL1", "1N")
+	handOffToZNY(t, tracon, artcc)
+	tracon.updateFederation()
+
+	// ZNY's acknowledgement is lost, so N90 sends the transfer again.
+	msgs, _ := tracon.PendingNASMessages("ZNY")
+	if !slices.ContainsFunc(msgs, func(msg NASMessage) bool { return msg.Type == NASTrackTransfer }) {
+		t.Fatalf("no track transfer queued for ZNY: %+v", msgs)
+	}
+	for range 2 {
+		if err := artcc.ReceiveNASMessages("N90", msgs); err != nil {
+			t.Fatalf("ReceiveNASMessages: %v", err)
+		}
+	}
+	if resent, _ := tracon.PendingNASMessages("ZNY"); len(resent) != len(msgs) {
+		t.Errorf("%d messages pending, expected %d until they are acknowledged", len(resent), len(msgs))
+	}
+
+	replies, ack := artcc.PendingNASMessages("N90")
+	if ack != msgs[len(msgs)-1].Seq {
+		t.Errorf("got ack %d, expected %d", ack, msgs[len(msgs)-1].Seq)
+	}
+	var types []NASMessageType
+	for _, msg := range replies {
+		types = append(types, msg.Type)
+	}
+	if !slices.Equal(types, []NASMessageType{NASTrackTransferAccept}) {
+		t.Errorf("got replies %v, expected a single TrackTransferAccept", types)
+	}
+
+	tracon.AcknowledgeNASMessages("ZNY", ack)
+	if pending, _ := tracon.PendingNASMessages("ZNY"); len(pending) != 0 {
+		t.Errorf("%d messages pending after they were acknowledged", len(pending))
+	}
+	if err := tracon.ReceiveNASMessages("ZNY", replies); err != nil {
+		t.Fatalf("ReceiveNASMessages: %v", err)
+	}
+	if _, ok := tracon.Aircraft["AAL1"]; ok {
+		t.Errorf("aircraft should have been removed from N90 after ZNY accepted it")
+	}
+	if _, ok := artcc.Aircraft["AAL1"]; !ok {
+		t.Errorf("aircraft should have been added to ZNY")
+	}
+}
+
+func TestFederatedTransferRejected(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		modify func(ac *Aircraft)
+	}{
+		{"unknown type", func(ac *Aircraft) { ac.FlightPlan.AircraftType = "XXXX" }},
+		{"far away", func(ac *Aircraft) { ac.Nav.FlightState.Position = math.Point2LL{-100, 30} }},
+		{"altitude", func(ac *Aircraft) { ac.Nav.FlightState.Altitude = 250000 }},
+		{"callsign", func(ac *Aircraft) { ac.ADSBCallsign = "" }},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tracon, artcc := makeFederatedTestSims(t)
+			ac := addFederationTestAircraft(t, tracon, "AAL1", "1N")
+			handOffToZNY(t, tracon, artcc)
+			tracon.updateFederation()
+
+			// Corrupt the aircraft on its way to ZNY.
+			msgs := takeNASMessages(tracon, "ZNY")
+			for _, msg := range msgs {
+				if msg.Type == NASTrackTransfer {
+					tc.modify(msg.Aircraft)
+				}
+			}
+			if err := artcc.ReceiveNASMessages("N90", msgs); err != nil {
+				t.Fatalf("ReceiveNASMessages: %v", err)
+			}
+			if len(artcc.Aircraft) != 0 {
+				t.Errorf("invalid aircraft should not have been added to ZNY")
+			}
+			if err := tracon.ReceiveNASMessages("ZNY", takeNASMessages(artcc, "N90")); err != nil {
+				t.Fatalf("ReceiveNASMessages: %v", err)
+			}
+
+			if tracon.Aircraft["AAL1"] != ac {
+				t.Fatalf("aircraft should still be in N90 after ZNY rejected it")
+			}
+			if ac.NASFlightPlan.TrackingController != "1N" || ac.ControllerFrequency != "1N" {
+				t.Errorf("got tracking %q frequency %q, expected the aircraft returned to 1N",
+					ac.NASFlightPlan.TrackingController, ac.ControllerFrequency)
+			}
+			if n := len(tracon.Federation["ZNY"].PendingTransfers); n != 0 {
+				t.Errorf("%d pending transfers after rejection, expected 0", n)
+			}
+		})
+	}
+}
+
+func TestFederatedTransferUnfederate(t *testing.T) {
+	tracon, artcc := makeFederatedTestSims(t)
+	ac := addFederationTestAircraft(t, tracon, "AAL1", "1N")
+	handOffToZNY(t, tracon, artcc)
+	tracon.updateFederation()
+
+	if err := tracon.UnfederateFacility("ZNY"); err != nil {
+		t.Fatalf("UnfederateFacility: %v", err)
+	}
+	if tracon.Aircraft["AAL1"] != ac {
+		t.Fatalf("aircraft being transferred should stay in N90 after unfederating")
+	}
+	if ac.NASFlightPlan.TrackingController != "1N" || ac.ControllerFrequency != "1N" {
+		t.Errorf("got tracking %q frequency %q, expected the aircraft returned to 1N",
+			ac.NASFlightPlan.TrackingController, ac.ControllerFrequency)
+	}
+}
+
+func TestFederatedRewind(t *testing.T) {
+	tracon, artcc := makeFederatedTestSims(t)
+	tracon.State.SimTime = NewSimTime(time.Date(2025, time.November, 1, 12, 0, 0, 0, time.UTC))
+	tracon.takeCheckpoint()
+
+	addFederationTestAircraft(t, artcc, "AAL1", "56")
+	if err := artcc.HandoffTrack("56", "AAL1", "21N"); err != nil {
+		t.Fatalf("HandoffTrack: %v", err)
+	}
+	exchangeNASMessages(t, tracon, artcc)
+	if fp, _, _ := tracon.getFlightPlanForACID("AAL1"); fp == nil {
+		t.Fatalf("expected a remote track in N90")
+	}
+
+	// The link was made after the checkpoint, but rewinding doesn't undo it.
+	tracon.State.SimTime = tracon.State.SimTime.Add(checkpointInterval)
+	tracon.PrivilegedTCWs["1N"] = true
+	if _, err := tracon.Rewind("1N", checkpointInterval); err != nil {
+		t.Fatalf("Rewind: %v", err)
+	}
+	if fed, ok := tracon.Federation["ZNY"]; !ok {
+		t.Fatalf("federation link should survive rewinding")
+	} else if len(fed.RemoteTracks) != 0 {
+		t.Errorf("remote tracks %v after rewinding, expected none", fed.RemoteTracks)
+	}
+	if !slices.Equal(tracon.State.FederatedFacilities, []string{"ZNY"}) {
+		t.Errorf("got federated facilities %v, expected [ZNY]", tracon.State.FederatedFacilities)
+	}
+	if fp, _, _ := tracon.getFlightPlanForACID("AAL1"); fp != nil {
+		t.Errorf("remote track from after the checkpoint should be gone")
+	}
+}
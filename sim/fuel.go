@@ -0,0 +1,235 @@
+// sim/fuel.go
+// Copyright(c) 2022-2026 vice contributors, licensed under the GNU Public License, Version 3.
+// SPDX: GPL-3.0-only
+
+package sim
+
+import (
+	"log/slog"
+	"time"
+
+	av "github.com/mmp/vice/aviation"
+	"github.com/mmp/vice/math"
+	"github.com/mmp/vice/nav"
+	"github.com/mmp/vice/util"
+)
+
+// IFR aircraft carry a fuel load that is burned as they fly, faster when
+// climbing and at low altitudes. Arrivals are launched with enough fuel
+// to reach the airport with their reserves plus some extra to absorb
+// delays. If fuel emergencies are enabled in the LaunchConfig and holding
+// and vectoring eats into the reserves, they declare minimum fuel and then
+// emergency fuel via an EmergencyState. If they're still not cleared for
+// an approach a while after that, they divert to the closest other
+// airport in the scenario.
+
+type FuelState int
+
+const (
+	FuelStateNormal    FuelState = iota
+	FuelStateMinimum             // declared minimum fuel
+	FuelStateEmergency           // declared emergency fuel
+	FuelStateDiverted            // diverted to another airport
+)
+
+const (
+	// Arrivals declare minimum fuel when they'd land with less than
+	// fuelReserveMinutes of fuel and emergency fuel when they'd land
+	// with less than fuelFinalReserveMinutes.
+	fuelReserveMinutes      = 45
+	fuelFinalReserveMinutes = 30
+	// How long after declaring emergency fuel an aircraft waits for an
+	// approach clearance before it diverts.
+	fuelDivertDelay = 10 * time.Minute
+)
+
+// fuelEmergency's stages are run as the aircraft's fuel state escalates
+// rather than according to their durations.
+var fuelEmergency = Emergency{
+	Name: "Fuel",
+	Stages: []EmergencyStage{
+		{Transmission: "[we're|we are|we're now] minimum fuel[, we can accept little or no delay|, we can't take much more delay|]"},
+		{Transmission: "[we're|we are now|we're now] emergency fuel[, we need to land as soon as possible|, request priority handling|]",
+			DeclareEmergency: true},
+	},
+}
+
+// fuelCapacityAndBurn returns the fuel capacity in pounds and the
+// approximate cruise fuel burn in pounds per hour for the given aircraft
+// performance.
+func fuelCapacityAndBurn(perf av.AircraftPerformance) (capacity, burn float32) {
+	capacity = 10000 // fallback if we somehow don't find something better
+	if perf.Capacity.FuelPounds > 0 {
+		capacity = float32(perf.Capacity.FuelPounds)
+	} else if avg, ok := cwtFuelPounds[perf.Category.CWT]; ok {
+		capacity = float32(avg)
+	}
+
+	// Guesstimate percentage fuel burned per hour of flight; assume it's proportional to
+	// aircraft size.
+	percentBurnPerHour := float32(0.15)
+	switch perf.Category.CWT {
+	case "A", "B", "C":
+		percentBurnPerHour = .06
+	case "D", "E":
+		percentBurnPerHour = .12
+	case "F", "G", "H":
+		percentBurnPerHour = .15
+	case "I":
+		percentBurnPerHour = .25
+	}
+
+	return capacity, percentBurnPerHour * capacity
+}
+
+// initializeFuel sets the initial fuel load for a newly-launched IFR
+// aircraft.
+func (s *Sim) initializeFuel(ac *Aircraft) {
+	if ac.FlightPlan.Rules != av.FlightRulesIFR {
+		return
+	}
+
+	capacity, burn := fuelCapacityAndBurn(ac.Nav.Perf)
+	ac.FuelBurn = burn
+	if ac.IsArrival() {
+		// Enough to get to the airport and land with the reserve plus
+		// a random amount extra for delays.
+		minutes := ac.minutesToLanding() + fuelReserveMinutes + s.Rand.Float32Range(15, 75)
+		ac.Fuel = min(capacity, burn*minutes/60)
+	} else {
+		ac.Fuel = capacity * s.Rand.Float32Range(0.6, 0.9)
+	}
+}
+
+// minutesToLanding estimates how long it will take the aircraft to reach
+// its arrival airport and land.
+func (ac *Aircraft) minutesToLanding() float32 {
+	d := math.NMDistance2LL(ac.Position(), ac.ArrivalAirportLocation())
+	gs := max(ac.GS(), 180)
+	return 60*d/gs + 5 // allow a few minutes for the approach
+}
+
+// updateFuel burns a second's worth of fuel and, if fuel emergencies are
+// enabled, escalates the fuel state of arrivals working a human controller
+// if they're getting low.
+func (s *Sim) updateFuel(ac *Aircraft) {
+	if ac.FuelBurn == 0 || !ac.IsAirborne() {
+		return
+	}
+
+	burn := ac.FuelBurn / 3600
+	switch rate := ac.Nav.FlightState.AltitudeRate; {
+	case rate > 300:
+		burn *= 1.5
+	case rate < -300:
+		burn *= 0.5
+	case ac.Altitude() < 10000:
+		// Holding or being vectored down low.
+		burn *= 1.2
+	}
+	ac.Fuel = max(0, ac.Fuel-burn)
+
+	if s.prespawn || !s.State.LaunchConfig.FuelEmergencies || !ac.IsArrival() || ac.IsUnassociated() ||
+		ac.ControllerFrequency == "" || s.isVirtualController(ac.ControllerFrequency) {
+		return
+	}
+	if ac.EmergencyState != nil && !ac.EmergencyState.Fuel {
+		// Don't pile on if it's already dealing with something else.
+		return
+	}
+
+	// Minutes of fuel the aircraft would have left after landing.
+	remaining := 60*ac.Fuel/ac.FuelBurn - ac.minutesToLanding()
+
+	switch ac.FuelState {
+	case FuelStateNormal:
+		if remaining < fuelReserveMinutes {
+			ac.EmergencyState = &EmergencyState{Emergency: &fuelEmergency, Fuel: true}
+			s.setFuelState(ac, FuelStateMinimum)
+			s.runEmergencyStage(ac)
+		}
+
+	case FuelStateMinimum:
+		if remaining < fuelFinalReserveMinutes {
+			s.setFuelState(ac, FuelStateEmergency)
+			s.runEmergencyStage(ac)
+		}
+
+	case FuelStateEmergency:
+		if !ac.Nav.Approach.Cleared && s.State.SimTime.Sub(ac.FuelStateTime) > fuelDivertDelay {
+			s.divertForFuel(ac)
+		}
+	}
+}
+
+func (s *Sim) setFuelState(ac *Aircraft, fs FuelState) {
+	ac.FuelState = fs
+	ac.FuelStateTime = s.State.SimTime
+	s.lg.Info("fuel state changed", slog.String("callsign", string(ac.ADSBCallsign)),
+		slog.Int("state", int(fs)), slog.Float64("fuel", float64(ac.Fuel)))
+}
+
+// divertForFuel sends the aircraft to the closest airport in the scenario
+// other than its destination that has an approach it can expect there.
+func (s *Sim) divertForFuel(ac *Aircraft) {
+	var alternate string
+	closest := float32(0)
+	for icao, sap := range util.SortedMap(s.State.Airports) {
+		if icao == ac.FlightPlan.ArrivalAirport || len(sap.Approaches) == 0 {
+			continue
+		}
+		if ap, ok := av.DB.Airports[icao]; ok {
+			if d := math.NMDistance2LL(ac.Position(), ap.Location); alternate == "" || d < closest {
+				alternate, closest = icao, d
+			}
+		}
+	}
+	if alternate == "" {
+		// Nowhere else to go; it'll have to keep waiting.
+		return
+	}
+
+	ac.DivertToAirport(alternate)
+	s.setFuelState(ac, FuelStateDiverted)
+	// Forget about whatever it was doing for the original airport.
+	ac.Nav.Heading = nav.NavHeading{}
+	ac.Nav.DeferredNavHeading = nil
+	ac.Nav.Approach = nav.NavApproach{}
+	ac.STARRunwayWaypoints = nil
+
+	// Otherwise it would fly over the alternate and keep going; instead,
+	// it expects an approach there and flies to it so that it can be
+	// cleared. Without a radio, it flies the approach on its own.
+	ac.ExpectApproach(s.alternateApproach(ac), s.State.Airports[alternate])
+	if ac.NORDO {
+		ac.Nav.LostComms(float32(s.getMVAGrid().GetMVA(ac.Position())))
+	} else {
+		ac.Nav.JoinExpectedApproach()
+	}
+
+	if !ac.NORDO {
+		rt := av.MakeContactTransmission("[we need to|we're going to|we have to] divert to {airport}, [we can't wait any longer|we don't have the fuel to hold any longer]",
+			alternate)
+		s.enqueueEmergencyTransmission(ac.ADSBCallsign, TCP(ac.ControllerFrequency), rt)
+	}
+}
+
+// alternateApproach returns the approach a diverting aircraft should
+// expect at its new arrival airport: one to an active arrival runway if
+// there is one and otherwise one of the airport's approaches, preferring
+// an ILS.
+func (s *Sim) alternateApproach(ac *Aircraft) string {
+	if id := s.autoPickApproach(ac); id != "" {
+		return id
+	}
+
+	best := ""
+	for id, appr := range util.SortedMap(s.State.Airports[ac.FlightPlan.ArrivalAirport].Approaches) {
+		if appr.Type == av.ILSApproach {
+			return id
+		} else if best == "" {
+			best = id
+		}
+	}
+	return best
+}
@@ -0,0 +1,226 @@
+// sim/fuel_test.go
+// Copyright(c) 2022-2026 vice contributors, licensed under the GNU Public License, Version 3.
+// SPDX: GPL-3.0-only
+
+package sim
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	av "github.com/mmp/vice/aviation"
+	"github.com/mmp/vice/math"
+)
+
+func TestFuelBurn(t *testing.T) {
+	s := makeTestSim(t)
+	s.prespawn = true // just burn fuel
+
+	ac := addTestAircraft(s, "AAL1", math.Point2LL{0, 0.5}, 20000)
+	ac.FuelBurn = 3600 // a pound a second
+	ac.Fuel = 1000
+
+	s.updateFuel(ac)
+	if ac.Fuel != 999 {
+		t.Errorf("level cruise: fuel %f; expected 999", ac.Fuel)
+	}
+
+	ac.Nav.FlightState.AltitudeRate = 2000
+	s.updateFuel(ac)
+	if ac.Fuel != 997.5 {
+		t.Errorf("climbing: fuel %f; expected 997.5", ac.Fuel)
+	}
+
+	ac.Nav.FlightState.AltitudeRate = -1500
+	s.updateFuel(ac)
+	if ac.Fuel != 997 {
+		t.Errorf("descending: fuel %f; expected 997", ac.Fuel)
+	}
+
+	ac.Nav.FlightState.AltitudeRate = 0
+	ac.Nav.FlightState.Altitude = 5000
+	s.updateFuel(ac)
+	if math.Abs(ac.Fuel-995.8) > 0.01 {
+		t.Errorf("low altitude: fuel %f; expected 995.8", ac.Fuel)
+	}
+
+	// No fuel modeling
+	ac.FuelBurn = 0
+	s.updateFuel(ac)
+	if math.Abs(ac.Fuel-995.8) > 0.01 {
+		t.Errorf("fuel %f changed without a fuel burn", ac.Fuel)
+	}
+}
+
+func TestFuelEscalation(t *testing.T) {
+	setupTestRunways(t, "KJFK", nil)
+	setupTestRunways(t, "KLGA", nil)
+	lga := av.DB.Airports["KLGA"]
+	lga.Location = math.Point2LL{0, 0.2}
+	av.DB.Airports["KLGA"] = lga
+
+	// KTEB is closer but there's no approach to expect there.
+	setupTestRunways(t, "KTEB", nil)
+	teb := av.DB.Airports["KTEB"]
+	teb.Location = math.Point2LL{0, 0.15}
+	av.DB.Airports["KTEB"] = teb
+
+	s := makeTestSim(t)
+	s.State.Airports = map[string]*av.Airport{
+		"KJFK": {},
+		"KTEB": {},
+		"KLGA": {Approaches: map[string]*av.Approach{
+			"R22": {Type: av.RNAVApproach, Runway: "22"},
+			"I4": {Type: av.ILSApproach, Runway: "4", Waypoints: []av.WaypointArray{{
+				{Fix: "ZALPO", Location: math.Point2LL{0, 0.35}},
+				{Fix: "_RWY4", Location: math.Point2LL{0, 0.2}},
+			}}},
+		}},
+	}
+	s.State.LaunchConfig.FuelEmergencies = true
+
+	// Holding 10nm from KJFK, which is at (0,0).
+	ac := addTestAircraft(s, "AAL1", math.Point2LL{0, 10.0 / 60}, 5000)
+	ac.Nav.FlightState.GS = 220
+	ac.Nav.Approach.Cleared = false
+	ac.FuelBurn = 6000 // 100 pounds a minute
+	toLand := ac.minutesToLanding()
+
+	// Plenty of fuel
+	ac.Fuel = 100 * (toLand + 60)
+	s.updateFuel(ac)
+	if ac.FuelState != FuelStateNormal || ac.EmergencyState != nil {
+		t.Fatalf("declared with plenty of fuel: state %d", ac.FuelState)
+	}
+
+	// Dipping into the reserve: minimum fuel, which isn't an emergency.
+	ac.Fuel = 100 * (toLand + 40)
+	s.updateFuel(ac)
+	if ac.FuelState != FuelStateMinimum {
+		t.Fatalf("expected minimum fuel, got state %d", ac.FuelState)
+	}
+	if ac.EmergencyState == nil || !ac.EmergencyState.Fuel || ac.EmergencyState.CurrentStage != 1 {
+		t.Fatalf("expected fuel emergency state at stage 1, got %+v", ac.EmergencyState)
+	}
+	if ac.IsEmergency() {
+		t.Errorf("minimum fuel shouldn't be an emergency")
+	}
+	if len(s.FutureEmergencyUpdates) != 0 {
+		t.Errorf("fuel emergency stages shouldn't be scheduled")
+	}
+	if n := len(s.PendingContacts["125.0"]); n != 1 {
+		t.Errorf("expected a minimum fuel transmission, got %d", n)
+	}
+
+	// Below the final reserve: emergency fuel.
+	ac.Fuel = 100 * (toLand + 25)
+	s.updateFuel(ac)
+	if ac.FuelState != FuelStateEmergency || !ac.IsEmergency() {
+		t.Fatalf("expected emergency fuel, got state %d", ac.FuelState)
+	}
+
+	// Not diverting yet
+	s.State.SimTime = s.State.SimTime.Add(5 * time.Minute)
+	s.updateFuel(ac)
+	if ac.FuelState != FuelStateEmergency {
+		t.Fatalf("diverted too soon")
+	}
+
+	// Left holding for too long: divert to KLGA.
+	s.State.SimTime = s.State.SimTime.Add(6 * time.Minute)
+	s.updateFuel(ac)
+	if ac.FuelState != FuelStateDiverted {
+		t.Fatalf("expected to divert, got state %d", ac.FuelState)
+	}
+	if ac.FlightPlan.ArrivalAirport != "KLGA" {
+		t.Errorf("diverted to %q; expected KLGA", ac.FlightPlan.ArrivalAirport)
+	}
+	if ac.NASFlightPlan.ArrivalAirport != "KLGA" {
+		t.Errorf("NAS flight plan arrival airport %q; expected KLGA", ac.NASFlightPlan.ArrivalAirport)
+	}
+	// It expects the ILS at KLGA and flies to it rather than over the
+	// airport.
+	if ac.Nav.Approach.AssignedId != "I4" || ac.Nav.Approach.Cleared {
+		t.Errorf("expecting %q (cleared %v) after diverting; expected I4", ac.Nav.Approach.AssignedId,
+			ac.Nav.Approach.Cleared)
+	}
+	var fixes []string
+	for _, wp := range ac.Nav.Waypoints {
+		fixes = append(fixes, wp.Fix)
+	}
+	if !slices.Equal(fixes, []string{"ZALPO", "_RWY4", "KLGA"}) {
+		t.Errorf("route %v after diverting; expected it to join the approach", fixes)
+	}
+	if n := len(s.PendingContacts["125.0"]); n != 3 {
+		t.Errorf("expected 3 transmissions, got %d", n)
+	}
+}
+
+func TestFuelNoAlternate(t *testing.T) {
+	setupTestRunways(t, "KJFK", nil)
+	setupTestRunways(t, "KTEB", nil)
+
+	s := makeTestSim(t)
+	s.State.Airports = map[string]*av.Airport{"KJFK": {}, "KTEB": {}}
+
+	ac := addTestAircraft(s, "AAL1", math.Point2LL{0, 10.0 / 60}, 5000)
+	s.setFuelState(ac, FuelStateEmergency)
+	s.divertForFuel(ac)
+
+	// There's nowhere with an approach to divert to, so it keeps waiting.
+	if ac.FuelState != FuelStateEmergency || ac.FlightPlan.ArrivalAirport != "KJFK" {
+		t.Errorf("diverted to %q with state %d; expected to keep waiting for KJFK",
+			ac.FlightPlan.ArrivalAirport, ac.FuelState)
+	}
+}
+
+func TestFuelEscalationClearedForApproach(t *testing.T) {
+	setupTestRunways(t, "KJFK", nil)
+	setupTestRunways(t, "KLGA", nil)
+
+	s := makeTestSim(t)
+	s.State.Airports = map[string]*av.Airport{"KJFK": {}, "KLGA": {}}
+	s.State.LaunchConfig.FuelEmergencies = true
+
+	ac := addTestAircraft(s, "AAL1", math.Point2LL{0, 10.0 / 60}, 3000)
+	ac.Nav.Approach.Cleared = true
+	ac.FuelBurn = 6000
+	ac.Fuel = 100 * (ac.minutesToLanding() + 10)
+
+	s.updateFuel(ac)
+	s.updateFuel(ac)
+	s.State.SimTime = s.State.SimTime.Add(20 * time.Minute)
+	s.updateFuel(ac)
+
+	// Cleared for the approach, it keeps going to its destination.
+	if ac.FuelState != FuelStateEmergency || ac.FlightPlan.ArrivalAirport != "KJFK" {
+		t.Errorf("expected emergency fuel and to continue to KJFK, got state %d to %s",
+			ac.FuelState, ac.FlightPlan.ArrivalAirport)
+	}
+}
+
+func TestFuelEmergenciesDisabled(t *testing.T) {
+	setupTestRunways(t, "KJFK", nil)
+	setupTestRunways(t, "KLGA", nil)
+
+	s := makeTestSim(t)
+	s.State.Airports = map[string]*av.Airport{"KJFK": {}, "KLGA": {}}
+
+	ac := addTestAircraft(s, "AAL1", math.Point2LL{0, 10.0 / 60}, 5000)
+	ac.Nav.Approach.Cleared = false
+	ac.FuelBurn = 6000
+	ac.Fuel = 100 * (ac.minutesToLanding() + 10)
+
+	s.updateFuel(ac)
+	s.State.SimTime = s.State.SimTime.Add(20 * time.Minute)
+	s.updateFuel(ac)
+
+	// Fuel is still burned but it neither declares nor diverts.
+	if ac.Fuel >= 100*(ac.minutesToLanding()+10) {
+		t.Errorf("no fuel burned")
+	}
+	if ac.FuelState != FuelStateNormal || ac.EmergencyState != nil || ac.FlightPlan.ArrivalAirport != "KJFK" {
+		t.Errorf("fuel emergencies disabled: got state %d to %s", ac.FuelState, ac.FlightPlan.ArrivalAirport)
+	}
+}
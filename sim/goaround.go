@@ -13,6 +13,7 @@ import (
 
 	av "github.com/mmp/vice/aviation"
 	"github.com/mmp/vice/math"
+	"github.com/mmp/vice/util"
 )
 
 func (s *Sim) contactDeparture(ac *Aircraft, fp *NASFlightPlan) {
@@ -40,7 +41,7 @@ func (s *Sim) goAround(ac *Aircraft) {
 		return
 	}
 	airport := ac.FlightPlan.ArrivalAirport
-	runway := approach.Runway
+	runway := ac.Nav.Approach.LandingRunway()
 
 	proc := s.getGoAroundProcedureForAircraft(ac)
 	if proc.HandoffController == "" {
@@ -50,22 +51,39 @@ func (s *Sim) goAround(ac *Aircraft) {
 	ac.WentAround = true
 	ac.GotContactTower = false
 	ac.SpacingGoAroundDeclined = false
-	ac.GoAroundOnRunwayHeading = proc.IsRunwayHeading
+	// Fly the published missed approach if there is one; otherwise use
+	// the scenario's go around heading.
+	ma := approach.MissedApproach
+	ac.GoAroundOnMissedApproach = ma != nil
+	ac.GoAroundOnRunwayHeading = proc.IsRunwayHeading && ma == nil
 
 	altitude := float32(proc.Altitude)
+	heading := proc.Heading
+	if ma != nil {
+		if ma.Altitude != 0 {
+			altitude = float32(ma.Altitude)
+		}
+		heading = ma.Heading
+	}
 
 	// Waypoint at the opposite threshold recording who to contact when it's reached.
 	wp := av.Waypoint{
 		Location:       approach.OppositeThreshold,
 		Flags:          av.WaypointFlagFlyOver | av.WaypointFlagHasAltRestriction,
-		Heading:        int16(proc.Heading),
+		Heading:        int16(heading),
 		AltRestriction: av.MakeAtAltitudeRestriction(altitude),
 		Extra: &av.WaypointExtra{
 			GoAroundContactController: proc.HandoffController,
 		},
 	}
 
-	ac.Nav.GoAroundWithProcedure(altitude, wp)
+	if ma != nil {
+		missed := *ma
+		missed.Altitude = int(altitude)
+		ac.Nav.GoAroundWithMissedApproach(missed, wp)
+	} else {
+		ac.Nav.GoAroundWithProcedure(altitude, wp)
+	}
 
 	holdRunways := append([]string{runway}, proc.HoldDepartures...)
 	s.holdDeparturesForGoAround(airport, holdRunways, proc.HandoffController)
@@ -75,10 +93,7 @@ func (s *Sim) goAround(ac *Aircraft) {
 // Lookup priority: go_around_assignments for airport/runway, airport, then departure_assignments for airport.
 func (s *Sim) getGoAroundController(ac *Aircraft) TCP {
 	airport := ac.FlightPlan.ArrivalAirport
-	runway := ""
-	if ac.Nav.Approach.Assigned != nil {
-		runway = ac.Nav.Approach.Assigned.Runway
-	}
+	runway := ac.Nav.Approach.LandingRunway()
 
 	// Check go_around_assignments for specific runway
 	if runway != "" {
@@ -138,7 +153,7 @@ func (s *Sim) holdDeparturesForGoAround(airport string, holdRunways []string, go
 // aircraft's arrival airport/runway, if one exists in the scenario's arrival_runways.
 func (s *Sim) getGoAroundProcedureForAircraft(ac *Aircraft) *GoAroundProcedure {
 	airport := ac.FlightPlan.ArrivalAirport
-	runway := ac.Nav.Approach.Assigned.Runway
+	runway := ac.Nav.Approach.LandingRunway()
 
 	// Find matching arrival runway with a go-around procedure
 	for _, ar := range s.State.ArrivalRunways {
@@ -148,8 +163,15 @@ func (s *Sim) getGoAroundProcedureForAircraft(ac *Aircraft) *GoAroundProcedure {
 	}
 
 	approach := ac.Nav.Approach.Assigned
+	heading := int(math.TrueToMagnetic(approach.RunwayHeading(s.State.NmPerLongitude), s.State.MagneticVariation) + 0.5)
+	if runway != approach.Runway {
+		// Circling or sidestepping to another runway.
+		if rwy, ok := av.LookupRunway(airport, runway); ok {
+			heading = int(rwy.Heading + 0.5)
+		}
+	}
 	return &GoAroundProcedure{
-		Heading:           int(math.TrueToMagnetic(approach.RunwayHeading(s.State.NmPerLongitude), s.State.MagneticVariation) + 0.5),
+		Heading:           heading,
 		IsRunwayHeading:   true,
 		Altitude:          1000 * int((ac.Nav.FlightState.ArrivalAirportElevation+2500)/1000),
 		HandoffController: s.getGoAroundController(ac),
@@ -157,28 +179,30 @@ func (s *Sim) getGoAroundProcedureForAircraft(ac *Aircraft) *GoAroundProcedure {
 }
 
 // checkFinalApproachSpacing checks for spacing violations between IFR aircraft
-// on the same final approach and triggers go-arounds when separation is insufficient.
+// landing on the same runway and triggers go-arounds when separation is insufficient.
 func (s *Sim) checkFinalApproachSpacing() {
 	if !s.State.LaunchConfig.EnableTowerGoArounds {
 		return
 	}
 
-	type runwayKey struct{ airport, runway string }
-	aircraftByRunway := make(map[runwayKey][]*Aircraft)
+	// Keyed by "airport/runway" so that the runways are visited in a
+	// consistent order.
+	aircraftByRunway := make(map[string][]*Aircraft)
 
-	// Group IFR aircraft with assigned approaches by airport+runway
-	for _, ac := range s.Aircraft {
+	// Group IFR aircraft with assigned approaches by airport and the
+	// runway they'll land on
+	for _, ac := range util.SortedMap(s.Aircraft) {
 		// Only tower sends aircraft around; don't include ones that have already been sent around
 		// since presumably we'll have vertical separation soon if not already.
 		if ac.Nav.Approach.Assigned != nil && ac.GotContactTower && !ac.SentAroundForSpacing {
-			key := runwayKey{ac.FlightPlan.ArrivalAirport, ac.Nav.Approach.Assigned.Runway}
+			key := ac.FlightPlan.ArrivalAirport + "/" + ac.Nav.Approach.LandingRunway()
 			aircraftByRunway[key] = append(aircraftByRunway[key], ac)
 		}
 	}
 
-	for _, aircraft := range aircraftByRunway {
+	for _, aircraft := range util.SortedMap(aircraftByRunway) {
 		// Sort by distance to threshold (closest first)
-		threshold := aircraft[0].Nav.Approach.Assigned.Threshold
+		threshold := landingThreshold(aircraft[0])
 		slices.SortFunc(aircraft, func(a, b *Aircraft) int {
 			return cmp.Compare(math.NMDistance2LL(a.Position(), threshold),
 				math.NMDistance2LL(b.Position(), threshold))
@@ -188,13 +212,7 @@ func (s *Sim) checkFinalApproachSpacing() {
 		for i := 1; i < len(aircraft); i++ {
 			front, trailing := aircraft[i-1], aircraft[i]
 
-			// Get required separation
-			vol := trailing.ATPAVolume()
-			eligible25nm := vol != nil && vol.Enable25nmApproach &&
-				s.State.IsATPAVolume25nmEnabled(vol.Id) &&
-				trailing.OnExtendedCenterline(0.2) && front.OnExtendedCenterline(0.2)
-			reqSep := av.CWTApproachSeparation(front.CWT(), trailing.CWT(), eligible25nm)
-
+			reqSep := s.approachSeparation(front, trailing)
 			actualSep := math.NMDistance2LL(front.Position(), trailing.Position())
 
 			majorBust := actualSep < reqSep*0.8
@@ -204,6 +222,7 @@ func (s *Sim) checkFinalApproachSpacing() {
 			// >10% but <=20% violation: 50% chance (one-time roll); skip check if already declined
 			issueGoAround := majorBust || (minorBust && !trailing.SpacingGoAroundDeclined && s.Rand.Float32() < 0.5)
 			if issueGoAround {
+				s.scoreSpacingGoAround(front, trailing, actualSep, reqSep)
 				s.goAroundForSpacing(trailing)
 			} else if minorBust {
 				trailing.SpacingGoAroundDeclined = true
@@ -212,6 +231,30 @@ func (s *Sim) checkFinalApproachSpacing() {
 	}
 }
 
+// landingThreshold returns the threshold of the runway that the aircraft
+// will land on, which differs from its approach's if it is circling or
+// sidestepping.
+func landingThreshold(ac *Aircraft) math.Point2LL {
+	appr := ac.Nav.Approach.Assigned
+	if rwy := ac.Nav.Approach.LandingRunway(); rwy != appr.Runway {
+		if r, ok := av.LookupRunway(ac.FlightPlan.ArrivalAirport, rwy); ok {
+			return r.Threshold
+		}
+	}
+	return appr.Threshold
+}
+
+// approachSeparation returns the required in-trail separation in nm
+// between two aircraft on the same final approach, accounting for their
+// CWT categories and whether reduced 2.5nm separation is available.
+func (s *Sim) approachSeparation(front, trailing *Aircraft) float32 {
+	vol := trailing.ATPAVolume()
+	eligible25nm := vol != nil && vol.Enable25nmApproach &&
+		s.State.IsATPAVolume25nmEnabled(vol.Id) &&
+		trailing.OnExtendedCenterline(0.2) && front.OnExtendedCenterline(0.2)
+	return av.CWTApproachSeparation(front.CWT(), trailing.CWT(), eligible25nm)
+}
+
 // goAroundForSpacing initiates a tower-commanded go-around for spacing violations.
 func (s *Sim) goAroundForSpacing(ac *Aircraft) {
 	ac.SentAroundForSpacing = true
@@ -0,0 +1,134 @@
+// sim/goaround_test.go
+// Copyright(c) 2022-2026 vice contributors, licensed under the GNU Public License, Version 3.
+// SPDX: GPL-3.0-only
+
+package sim
+
+import (
+	"testing"
+
+	av "github.com/mmp/vice/aviation"
+	"github.com/mmp/vice/math"
+	"github.com/mmp/vice/nav"
+)
+
+func TestGoAroundFliesPublishedMissedApproach(t *testing.T) {
+	s := makeTestSim(t)
+	ac := addTestAircraft(s, "AAL1", math.Point2LL{0, 2.0 / 60}, 800)
+	ac.Nav.FixAssignments = make(map[string]nav.NavFixAssignment)
+
+	hold := av.Hold{Fix: "DPK", InboundCourse: 258, TurnDirection: av.TurnLeft, LegMinutes: 1, MinimumAltitude: 4000}
+	ac.Nav.Approach.Assigned.MissedApproach = &av.MissedApproach{
+		Altitude:  4000,
+		Waypoints: av.WaypointArray{{Fix: "DPK", Location: math.Point2LL{0.2, 0.2}}},
+		Hold:      &hold,
+	}
+
+	s.goAround(ac)
+
+	if !ac.GoAroundOnMissedApproach || ac.GoAroundOnRunwayHeading {
+		t.Errorf("expected go around on the missed approach, not runway heading")
+	}
+	if alt := ac.Nav.Altitude.Assigned; alt == nil || *alt != 4000 {
+		t.Errorf("expected assigned altitude 4000, got %v", alt)
+	}
+
+	wps := ac.Nav.Waypoints
+	if len(wps) != 3 || wps[1].Fix != "DPK" || wps[2].Fix != "KJFK" {
+		t.Fatalf("expected route to DPK then KJFK after the runway end, got %s", wps.Encode())
+	}
+	if wps[0].Heading != 0 {
+		t.Errorf("expected no heading at the runway end, got %d", wps[0].Heading)
+	}
+	if wps[0].GoAroundContactController() == "" {
+		t.Errorf("expected go around contact controller at the runway end")
+	}
+
+	if nfa, ok := ac.Nav.FixAssignments["DPK"]; !ok || nfa.Hold == nil || *nfa.Hold != hold {
+		t.Errorf("expected the published hold at DPK, got %+v", nfa)
+	}
+
+	if nfa := ac.Nav.FixAssignments["DPK"]; !nfa.MissedApproachHold {
+		t.Errorf("expected the hold at DPK to be marked as the missed approach's")
+	}
+
+	// A hold the controller assigned somewhere else.
+	camrn := av.Hold{Fix: "CAMRN", InboundCourse: 40, TurnDirection: av.TurnRight, LegMinutes: 1}
+	ac.Nav.FixAssignments["CAMRN"] = nav.NavFixAssignment{Hold: &camrn}
+
+	// Clearing the aircraft for an approach cancels the missed approach's
+	// hold but not the controller's.
+	ac.Nav.Approach.Assigned = &av.Approach{Type: av.ILSApproach, Runway: "22L"}
+	ac.Nav.Approach.AssignedId = "I22L"
+	ac.Nav.Approach.Cleared = true
+	ac.Nav.ClearedApproach("I22L", nil, s.State.SimTime.NavTime(), false)
+	if nfa := ac.Nav.FixAssignments["DPK"]; nfa.Hold != nil {
+		t.Errorf("expected approach clearance to cancel the missed approach hold")
+	}
+	if nfa := ac.Nav.FixAssignments["CAMRN"]; nfa.Hold == nil || *nfa.Hold != camrn {
+		t.Errorf("expected approach clearance to keep the controller-assigned hold at CAMRN, got %+v", nfa)
+	}
+}
+
+func TestGoAroundWithoutMissedApproach(t *testing.T) {
+	s := makeTestSim(t)
+	ac := addTestAircraft(s, "AAL1", math.Point2LL{0, 2.0 / 60}, 800)
+	ac.Nav.Approach.Assigned.OppositeThreshold = math.Point2LL{2.0 / 60, 0}
+
+	s.goAround(ac)
+
+	if ac.GoAroundOnMissedApproach || !ac.GoAroundOnRunwayHeading {
+		t.Errorf("expected go around on runway heading")
+	}
+	if wps := ac.Nav.Waypoints; len(wps) != 2 || wps[0].Heading != 90 {
+		t.Errorf("expected runway end with a heading then the airport, got %s", wps.Encode())
+	}
+}
+
+func TestGoAroundCirclingUsesLandingRunway(t *testing.T) {
+	setupTestRunways(t, "KJFK", []av.Runway{
+		{Id: "22L", Heading: 220, Threshold: math.Point2LL{0, 0}},
+		{Id: "31R", Heading: 310, Threshold: math.Point2LL{0.05, -0.05}},
+	})
+
+	s := makeTestSim(t)
+	s.GoAroundAssignments = map[string]TCP{"KJFK/22L": "2A", "KJFK/31R": "3A"}
+	s.State.LaunchConfig.EnableTowerGoArounds = true
+
+	circling := addTestAircraft(s, "AAL1", math.Point2LL{0.05, -0.05 - 2.0/60}, 1000)
+	circling.Nav.Approach.Maneuver = av.CircleToLand
+	circling.Nav.Approach.ManeuverRunway = "31R"
+
+	if tcp := s.getGoAroundController(circling); tcp != "3A" {
+		t.Errorf("go around controller %q; expected 3A for the landing runway", tcp)
+	}
+	if proc := s.getGoAroundProcedureForAircraft(circling); proc.Heading != 310 || proc.HandoffController != "3A" {
+		t.Errorf("go around procedure %+v; expected runway 31R's heading and controller", proc)
+	}
+
+	s.State.ArrivalRunways = []ArrivalRunway{{Airport: "KJFK", Runway: "31R", GoAround: &GoAroundProcedure{Heading: 270}}}
+	if proc := s.getGoAroundProcedureForAircraft(circling); proc.Heading != 270 {
+		t.Errorf("go around procedure %+v; expected 31R's go around procedure", proc)
+	}
+
+	// An aircraft on the straight-in approach to 22L right behind the
+	// circling one isn't landing on the same runway, so there's no
+	// spacing problem.
+	straightIn := addTestAircraft(s, "AAL2", math.Point2LL{0.05, -0.05 - 2.5/60}, 1200)
+	circling.GotContactTower, straightIn.GotContactTower = true, true
+	s.checkFinalApproachSpacing()
+	if straightIn.WentAround || circling.WentAround {
+		t.Errorf("aircraft landing on different runways were sent around")
+	}
+
+	// Another aircraft landing on 31R that's too close to the circling one.
+	s.State.ArrivalRunways = nil
+	behind := addTestAircraft(s, "AAL3", math.Point2LL{0.05, -0.05 - 2.5/60}, 1200)
+	behind.Nav.Approach.Assigned = &av.Approach{Type: av.ILSApproach, Runway: "31R",
+		Threshold: math.Point2LL{0.05, -0.05}}
+	behind.GotContactTower = true
+	s.checkFinalApproachSpacing()
+	if !behind.WentAround || circling.WentAround {
+		t.Errorf("expected the aircraft behind the circling one to go around")
+	}
+}
@@ -100,6 +100,8 @@ func (s *Sim) HandoffTrack(tcw TCW, acid ACID, toTCP TCP) error {
 	s.mu.Lock(s.lg)
 	defer s.mu.Unlock(s.lg)
 
+	s.recordInput(RecordedInput{TCW: tcw, Type: RecordedHandoff, ACID: acid, TCP: toTCP})
+
 	if err := s.dispatchTrackedFlightPlanCommand(tcw, acid,
 		func(tcw TCW, fp *NASFlightPlan, ac *Aircraft) error {
 			// Resolve the target TCP - it may be consolidated to another controller
@@ -151,6 +153,12 @@ func (s *Sim) handoffTrack(fp *NASFlightPlan, toTCP TCP) {
 		s.lg.Errorf("Unable to handoff %s: to controller %q (resolved: %q) not found", fp.ACID, toTCP, resolvedTCP)
 	}
 
+	if s.federatedFacility(resolvedTCP) != "" {
+		// It's up to the controller in the other Sim to accept it.
+		s.sendFederatedHandoff(fp, resolvedTCP)
+		return
+	}
+
 	// Add them to the auto-accept map even if the target controller is
 	// currently signed in; this way, if they sign off in the interim, we
 	// still end up accepting it automatically.
@@ -250,6 +258,14 @@ func (s *Sim) AcceptHandoff(tcw TCW, acid ACID) error {
 	s.mu.Lock(s.lg)
 	defer s.mu.Unlock(s.lg)
 
+	s.recordInput(RecordedInput{TCW: tcw, Type: RecordedAcceptHandoff, ACID: acid})
+
+	return s.acceptHandoff(tcw, acid)
+}
+
+// acceptHandoff is the inner implementation of AcceptHandoff; the caller
+// must hold s.mu.
+func (s *Sim) acceptHandoff(tcw TCW, acid ACID) error {
 	if _, err := s.dispatchFlightPlanCommand(tcw, acid,
 		func(tcw TCW, fp *NASFlightPlan, ac *Aircraft) error {
 			if fp.RedirectedHandoff.RedirectedTo != "" {
@@ -294,6 +310,8 @@ func (s *Sim) AcceptHandoff(tcw TCW, acid ACID) error {
 			// Clean up if a point out was accepted as a handoff
 			delete(s.PointOuts, acid)
 
+			s.sendFederatedReply(NASHandoffAccept, acid, newTrackingController, previousTrackingController)
+
 			if ac != nil {
 				haveTransferComms := slices.ContainsFunc(ac.Nav.Waypoints,
 					func(wp av.Waypoint) bool { return wp.HasTransferCommsAction() })
@@ -316,8 +334,11 @@ func (s *Sim) CancelHandoff(tcw TCW, acid ACID) error {
 	s.mu.Lock(s.lg)
 	defer s.mu.Unlock(s.lg)
 
+	s.recordInput(RecordedInput{TCW: tcw, Type: RecordedCancelHandoff, ACID: acid})
+
 	if err := s.dispatchTrackedFlightPlanCommand(tcw, acid, nil,
 		func(tcw TCW, fp *NASFlightPlan, ac *Aircraft) {
+			s.sendFederatedReply(NASHandoffCancel, acid, fp.TrackingController, fp.HandoffController)
 			delete(s.Handoffs, acid)
 			fp.HandoffController = ""
 			fp.RedirectedHandoff = RedirectedHandoff{}
@@ -332,6 +353,8 @@ func (s *Sim) RedirectHandoff(tcw TCW, acid ACID, controller TCP) error {
 	s.mu.Lock(s.lg)
 	defer s.mu.Unlock(s.lg)
 
+	s.recordInput(RecordedInput{TCW: tcw, Type: RecordedRedirectHandoff, ACID: acid, TCP: controller})
+
 	if _, err := s.dispatchFlightPlanCommand(tcw, acid,
 		func(tcw TCW, fp *NASFlightPlan, ac *Aircraft) error {
 			primaryTCP := s.State.PrimaryPositionForTCW(tcw)
@@ -384,6 +407,8 @@ func (s *Sim) AcceptRedirectedHandoff(tcw TCW, acid ACID) error {
 	s.mu.Lock(s.lg)
 	defer s.mu.Unlock(s.lg)
 
+	s.recordInput(RecordedInput{TCW: tcw, Type: RecordedAcceptRedirectedHandoff, ACID: acid})
+
 	if _, err := s.dispatchFlightPlanCommand(tcw, acid,
 		func(tcw TCW, fp *NASFlightPlan, ac *Aircraft) error {
 			// TODO(mtrokel): need checks here that we do have an inbound
@@ -486,6 +511,8 @@ func (s *Sim) PointOut(fromTCW TCW, acid ACID, toTCP TCP) error {
 	s.mu.Lock(s.lg)
 	defer s.mu.Unlock(s.lg)
 
+	s.recordInput(RecordedInput{TCW: fromTCW, Type: RecordedPointOut, ACID: acid, TCP: toTCP})
+
 	if err := s.dispatchTrackedFlightPlanCommand(fromTCW, acid,
 		func(tcw TCW, fp *NASFlightPlan, ac *Aircraft) error {
 			if octrl, ok := s.State.Controllers[toTCP]; !ok {
@@ -539,6 +566,10 @@ func (s *Sim) pointOut(acid ACID, from *av.Controller, to *av.Controller) {
 			AcceptTime:     s.State.SimTime.Add(s.Rand.DurationRange(4*time.Second, 14*time.Second)),
 		})
 	}
+
+	if s.federatedFacility(to.PositionId()) != "" {
+		s.sendFederatedPointOut(acid, from.PositionId(), to.PositionId())
+	}
 }
 
 // findInboundPointOut returns the first pending PointOut whose ToController is
@@ -563,6 +594,8 @@ func (s *Sim) AcknowledgePointOut(tcw TCW, acid ACID) error {
 	s.mu.Lock(s.lg)
 	defer s.mu.Unlock(s.lg)
 
+	s.recordInput(RecordedInput{TCW: tcw, Type: RecordedAcknowledgePointOut, ACID: acid})
+
 	acked := util.FilterSlice(s.PointOuts[acid], func(po PointOut) bool {
 		return s.State.TCWControlsPosition(tcw, po.ToController)
 	})
@@ -585,11 +618,13 @@ func (s *Sim) AcknowledgePointOut(tcw TCW, acid ACID) error {
 					ACID:           acid,
 				})
 				fp.AddPointOutHistory(po.ToController)
+				s.sendFederatedReply(NASPointOutAcknowledge, acid, po.ToController, po.FromController)
 			}
 
 			s.deletePointOuts(acid, func(po PointOut) bool {
 				return s.State.TCWControlsPosition(tcw, po.ToController)
 			})
+			s.pruneFederatedTrack(acid)
 
 			return nil
 		}); err != nil {
@@ -603,6 +638,8 @@ func (s *Sim) RecallPointOut(tcw TCW, acid ACID) error {
 	s.mu.Lock(s.lg)
 	defer s.mu.Unlock(s.lg)
 
+	s.recordInput(RecordedInput{TCW: tcw, Type: RecordedRecallPointOut, ACID: acid})
+
 	recalled := util.FilterSlice(s.PointOuts[acid], func(po PointOut) bool {
 		return s.State.TCWControlsPosition(tcw, po.FromController)
 	})
@@ -622,6 +659,7 @@ func (s *Sim) RecallPointOut(tcw TCW, acid ACID) error {
 					ToController:   po.ToController,
 					ACID:           acid,
 				})
+				s.sendFederatedReply(NASPointOutRecall, acid, po.FromController, po.ToController)
 			}
 
 			s.deletePointOuts(acid, func(po PointOut) bool {
@@ -638,6 +676,8 @@ func (s *Sim) RejectPointOut(tcw TCW, acid ACID) error {
 	s.mu.Lock(s.lg)
 	defer s.mu.Unlock(s.lg)
 
+	s.recordInput(RecordedInput{TCW: tcw, Type: RecordedRejectPointOut, ACID: acid})
+
 	rejected := util.FilterSlice(s.PointOuts[acid], func(po PointOut) bool {
 		return s.State.TCWControlsPosition(tcw, po.ToController)
 	})
@@ -659,11 +699,13 @@ func (s *Sim) RejectPointOut(tcw TCW, acid ACID) error {
 					ToController:   po.FromController,
 					ACID:           acid,
 				})
+				s.sendFederatedReply(NASPointOutReject, acid, po.ToController, po.FromController)
 			}
 
 			s.deletePointOuts(acid, func(po PointOut) bool {
 				return s.State.TCWControlsPosition(tcw, po.ToController)
 			})
+			s.pruneFederatedTrack(acid)
 
 			return nil
 		}); err != nil {
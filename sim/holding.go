@@ -0,0 +1,233 @@
+// sim/holding.go
+// Copyright(c) 2022-2026 vice contributors, licensed under the GNU Public License, Version 3.
+// SPDX: GPL-3.0-only
+
+package sim
+
+import (
+	"cmp"
+	"slices"
+	"strconv"
+	"time"
+
+	av "github.com/mmp/vice/aviation"
+	"github.com/mmp/vice/math"
+	"github.com/mmp/vice/util"
+)
+
+const (
+	// When the controller asks for the stack's next EFC time, it is at
+	// least efcLead from now and efcSpacing after the latest EFC already
+	// issued in the stack.
+	efcLead    = 10 * time.Minute
+	efcSpacing = 4 * time.Minute
+
+	// Vertical separation between aircraft in a holding stack.
+	holdingStackSeparation = 1000
+)
+
+// HoldingAircraft is an aircraft in a holding stack, either holding or
+// cleared to hold and on its way to the fix.
+type HoldingAircraft struct {
+	ADSBCallsign av.ADSBCallsign
+	Altitude     int  // assigned altitude in the hold
+	EFC          Time // expect further clearance time; zero if none has been issued
+	Entered      Time // when it joined the stack
+}
+
+// holdAssignment returns the hold that the aircraft is flying or has been
+// cleared to fly, or nil if there is none.
+func holdAssignment(ac *Aircraft) *av.Hold {
+	if fh := ac.Nav.Heading.Hold; fh != nil && !fh.Cancel {
+		return &fh.Hold
+	}
+	if dh := ac.Nav.DeferredNavHeading; dh != nil && dh.Hold != nil {
+		return &dh.Hold.Hold
+	}
+	for _, wp := range ac.Nav.Waypoints {
+		if fa, ok := ac.Nav.FixAssignments[wp.Fix]; ok && fa.Hold != nil {
+			return fa.Hold
+		}
+	}
+	return nil
+}
+
+// holdingAltitude returns the altitude the aircraft will hold at: its
+// assigned altitude if it has one and otherwise its current altitude.
+func holdingAltitude(ac *Aircraft) int {
+	if a := ac.Nav.Altitude.Assigned; a != nil {
+		return int(*a)
+	} else if c := ac.Nav.Altitude.Cleared; c != nil {
+		return int(*c)
+	}
+	return int(ac.Altitude()+50) / 100 * 100
+}
+
+// holdingEntry returns the aircraft's entry in the holding stack at fix,
+// adding it to the stack if it isn't already there. Caller must hold
+// s.mu.
+func (s *Sim) holdingEntry(ac *Aircraft, fix string) *HoldingAircraft {
+	if s.State.HoldingStacks == nil {
+		s.State.HoldingStacks = make(map[string][]HoldingAircraft)
+	}
+
+	stack := s.State.HoldingStacks[fix]
+	idx := slices.IndexFunc(stack, func(ha HoldingAircraft) bool { return ha.ADSBCallsign == ac.ADSBCallsign })
+	if idx == -1 {
+		stack = append(stack, HoldingAircraft{
+			ADSBCallsign: ac.ADSBCallsign,
+			Altitude:     holdingAltitude(ac),
+			Entered:      s.State.SimTime,
+		})
+		idx = len(stack) - 1
+		s.State.HoldingStacks[fix] = stack
+	}
+	return &stack[idx]
+}
+
+// updateHoldingStacks brings the holding stacks up to date with the
+// aircraft's holds and altitudes. Aircraft that have lost communications
+// leave the hold at their EFC time, per FAR 91.185.
+func (s *Sim) updateHoldingStacks() {
+	for _, ac := range util.SortedMap(s.Aircraft) {
+		if fh := ac.Nav.Heading.Hold; fh != nil && !fh.Cancel && ac.NORDO {
+			if e := s.holdingEntry(ac, fh.Hold.Fix); !e.EFC.IsZero() && !s.State.SimTime.Before(e.EFC) {
+				fh.Cancel = true
+			}
+		}
+	}
+
+	for fix, stack := range s.State.HoldingStacks {
+		stack = slices.DeleteFunc(stack, func(ha HoldingAircraft) bool {
+			ac, ok := s.Aircraft[ha.ADSBCallsign]
+			if !ok {
+				return true
+			}
+			h := holdAssignment(ac)
+			return h == nil || h.Fix != fix
+		})
+		if len(stack) == 0 {
+			delete(s.State.HoldingStacks, fix)
+			continue
+		}
+		s.State.HoldingStacks[fix] = stack
+	}
+
+	for _, ac := range util.SortedMap(s.Aircraft) {
+		if h := holdAssignment(ac); h != nil {
+			s.holdingEntry(ac, h.Fix).Altitude = holdingAltitude(ac)
+		}
+	}
+
+	for _, stack := range s.State.HoldingStacks {
+		slices.SortFunc(stack, func(a, b HoldingAircraft) int {
+			return cmp.Or(cmp.Compare(a.Altitude, b.Altitude), cmp.Compare(a.ADSBCallsign, b.ADSBCallsign))
+		})
+	}
+}
+
+// nextEFC returns the EFC time to issue to the next aircraft in the stack
+// at fix.
+func (s *Sim) nextEFC(fix string, callsign av.ADSBCallsign) Time {
+	efc := s.State.SimTime.Add(efcLead)
+	for _, ha := range s.State.HoldingStacks[fix] {
+		if ha.ADSBCallsign != callsign && !ha.EFC.IsZero() && ha.EFC.Add(efcSpacing).After(efc) {
+			efc = ha.EFC.Add(efcSpacing)
+		}
+	}
+	// Round up to the minute.
+	t := efc.Time()
+	if r := t.Truncate(time.Minute); !r.Equal(t) {
+		t = r.Add(time.Minute)
+	}
+	return NewSimTime(t)
+}
+
+// parseEFC converts an HHMM UTC time to the next time it occurs.
+func (s *Sim) parseEFC(hhmm string) (Time, bool) {
+	if len(hhmm) != 4 || !util.IsAllNumbers(hhmm) {
+		return Time{}, false
+	}
+	v, err := strconv.Atoi(hhmm)
+	if err != nil || v/100 > 23 || v%100 > 59 {
+		return Time{}, false
+	}
+
+	now := s.State.SimTime.Time().UTC()
+	t := time.Date(now.Year(), now.Month(), now.Day(), v/100, v%100, 0, 0, time.UTC)
+	if t.Before(now) {
+		t = t.Add(24 * time.Hour)
+	}
+	return NewSimTime(t), true
+}
+
+// ExpectFurtherClearance issues an EFC time to an aircraft that has been
+// cleared to hold. If hhmm is empty, the holding stack assigns the next
+// EFC time.
+func (s *Sim) ExpectFurtherClearance(tcw TCW, callsign av.ADSBCallsign, hhmm string) (av.CommandIntent, error) {
+	s.mu.Lock(s.lg)
+	defer s.mu.Unlock(s.lg)
+
+	var efc Time
+	if hhmm != "" {
+		var ok bool
+		if efc, ok = s.parseEFC(hhmm); !ok {
+			return nil, ErrInvalidCommandSyntax
+		}
+	}
+
+	return s.dispatchControlledAircraftCommand(tcw, callsign,
+		func(tcw TCW, ac *Aircraft) av.CommandIntent {
+			h := holdAssignment(ac)
+			if h == nil {
+				return av.MakeUnableIntent("unable. We haven't been told to hold")
+			}
+
+			e := s.holdingEntry(ac, h.Fix)
+			if efc.IsZero() {
+				e.EFC = s.nextEFC(h.Fix, ac.ADSBCallsign)
+			} else {
+				e.EFC = efc
+			}
+			t := e.EFC.UTC()
+			return av.ExpectFurtherClearanceIntent{Time: 100*t.Hour() + t.Minute()}
+		})
+}
+
+// DescendInHold descends a holding aircraft 1,000' to the next altitude
+// in the stack. The pilot is unable if that altitude is occupied or below
+// the minimum holding altitude.
+func (s *Sim) DescendInHold(tcw TCW, callsign av.ADSBCallsign) (av.CommandIntent, error) {
+	s.mu.Lock(s.lg)
+	defer s.mu.Unlock(s.lg)
+
+	return s.dispatchControlledAircraftCommand(tcw, callsign,
+		func(tcw TCW, ac *Aircraft) av.CommandIntent {
+			h := holdAssignment(ac)
+			if h == nil {
+				return av.MakeUnableIntent("unable. We haven't been told to hold")
+			}
+
+			e := s.holdingEntry(ac, h.Fix)
+			alt := e.Altitude - holdingStackSeparation
+
+			floor := h.MinimumAltitude
+			if p, ok := av.DB.LookupWaypoint(h.Fix); ok {
+				floor = max(floor, s.getMVAGrid().GetMVA(p))
+			}
+			if alt < floor {
+				return av.MakeUnableIntent("unable. We're at the bottom of the hold")
+			}
+			if slices.ContainsFunc(s.State.HoldingStacks[h.Fix], func(ha HoldingAircraft) bool {
+				return ha.ADSBCallsign != ac.ADSBCallsign && math.Abs(ha.Altitude-alt) < holdingStackSeparation
+			}) {
+				return av.MakeUnableIntent("unable. There's traffic holding at {alt}", alt)
+			}
+
+			intent := ac.AssignAltitude(alt, false, s.State.SimTime, 0)
+			if _, unable := intent.(av.UnableIntent); !unable {
+				e.Altitude = alt
+			}
+			return intent
+		})
+}
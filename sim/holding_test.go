@@ -0,0 +1,160 @@
+// sim/holding_test.go
+// Copyright(c) 2022-2026 vice contributors, licensed under the GNU Public License, Version 3.
+// SPDX: GPL-3.0-only
+
+package sim
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	av "github.com/mmp/vice/aviation"
+	"github.com/mmp/vice/math"
+	"github.com/mmp/vice/nav"
+	"github.com/mmp/vice/rand"
+)
+
+func addHoldingTestAircraft(s *Sim, callsign av.ADSBCallsign, alt float32) *Aircraft {
+	ac := addTestAircraft(s, callsign, math.Point2LL{0, 0}, alt)
+	ac.Nav.Perf.Ceiling = 40000
+	ac.Nav.Rand = rand.Make()
+	ac.Nav.Altitude.Assigned = &alt
+	ac.Nav.Heading.Hold = &nav.FlyHold{Hold: av.Hold{Fix: "MERIT", MinimumAltitude: 5000}}
+	return ac
+}
+
+func TestHoldingStackEFC(t *testing.T) {
+	s := makeTestSim(t)
+	s.State.SimTime = NewSimTime(time.Date(2026, 3, 1, 15, 23, 30, 0, time.UTC))
+	tcw := E2ETCW()
+
+	addHoldingTestAircraft(s, "AAL1", 7000)
+	addHoldingTestAircraft(s, "AAL2", 6000)
+	addTestAircraft(s, "AAL3", math.Point2LL{0, 0}, 5000)
+	s.updateHoldingStacks()
+
+	stack := s.State.HoldingStacks["MERIT"]
+	if len(stack) != 2 || stack[0].ADSBCallsign != "AAL2" || stack[1].ADSBCallsign != "AAL1" {
+		t.Fatalf("expected AAL2 below AAL1 in the MERIT stack, got %+v", stack)
+	}
+
+	efc := func(callsign av.ADSBCallsign, hhmm string) av.CommandIntent {
+		t.Helper()
+		intent, err := s.ExpectFurtherClearance(tcw, callsign, hhmm)
+		if err != nil {
+			t.Fatalf("%s: %v", callsign, err)
+		}
+		return intent
+	}
+
+	// The first EFC is 10 minutes out, rounded up to the minute, and later
+	// ones are spaced after it.
+	if intent := efc("AAL2", ""); intent != (av.ExpectFurtherClearanceIntent{Time: 1534}) {
+		t.Errorf("expected EFC 1534, got %+v", intent)
+	}
+	if intent := efc("AAL1", ""); intent != (av.ExpectFurtherClearanceIntent{Time: 1538}) {
+		t.Errorf("expected EFC 1538, got %+v", intent)
+	}
+
+	// An explicit time earlier in the day is for tomorrow.
+	if intent := efc("AAL1", "0215"); intent != (av.ExpectFurtherClearanceIntent{Time: 215}) {
+		t.Errorf("expected EFC 0215, got %+v", intent)
+	}
+	if e := s.State.HoldingStacks["MERIT"][1].EFC; !e.Equal(NewSimTime(time.Date(2026, 3, 2, 2, 15, 0, 0, time.UTC))) {
+		t.Errorf("expected EFC the next day, got %s", e.Time())
+	}
+
+	for _, hhmm := range []string{"2460", "-130", "+130", "1 30"} {
+		if _, err := s.ExpectFurtherClearance(tcw, "AAL1", hhmm); !errors.Is(err, ErrInvalidCommandSyntax) {
+			t.Errorf("%q: expected ErrInvalidCommandSyntax, got %v", hhmm, err)
+		}
+	}
+	if _, ok := efc("AAL3", "").(av.UnableIntent); !ok {
+		t.Errorf("expected an aircraft that isn't holding to be unable")
+	}
+
+	// Cancelling the hold takes the aircraft out of the stack.
+	s.Aircraft["AAL2"].Nav.Heading.Hold.Cancel = true
+	s.updateHoldingStacks()
+	if stack := s.State.HoldingStacks["MERIT"]; len(stack) != 1 || stack[0].ADSBCallsign != "AAL1" {
+		t.Errorf("expected only AAL1 left in the stack, got %+v", stack)
+	}
+}
+
+func TestDescendInHold(t *testing.T) {
+	s := makeTestSim(t)
+	tcw := E2ETCW()
+
+	top := addHoldingTestAircraft(s, "AAL1", 7000)
+	bottom := addHoldingTestAircraft(s, "AAL2", 5000)
+	s.updateHoldingStacks()
+
+	descend := func(callsign av.ADSBCallsign) av.CommandIntent {
+		t.Helper()
+		intent, err := s.DescendInHold(tcw, callsign)
+		if err != nil {
+			t.Fatalf("%s: %v", callsign, err)
+		}
+		return intent
+	}
+
+	if _, ok := descend("AAL2").(av.UnableIntent); !ok {
+		t.Errorf("expected AAL2 to be unable to descend below the minimum holding altitude")
+	}
+	if _, ok := descend("AAL1").(av.UnableIntent); ok {
+		t.Errorf("expected AAL1 to descend to 6000")
+	}
+	if a := top.Nav.Altitude.Assigned; a == nil || *a != 6000 {
+		t.Errorf("expected AAL1 assigned 6000, got %v", a)
+	}
+
+	// 5000 is still occupied.
+	if _, ok := descend("AAL1").(av.UnableIntent); !ok {
+		t.Errorf("expected AAL1 to be unable to descend into AAL2's altitude")
+	}
+
+	bottom.Nav.Heading.Hold = nil
+	s.updateHoldingStacks()
+	if _, ok := descend("AAL1").(av.UnableIntent); ok {
+		t.Errorf("expected AAL1 to descend to 5000 once AAL2 left the hold")
+	}
+
+	// If the pilot can't take the altitude, the stack keeps the aircraft
+	// where it is.
+	high := addHoldingTestAircraft(s, "AAL3", 9000)
+	high.Nav.Perf.Ceiling = 7000
+	s.updateHoldingStacks()
+	if _, ok := descend("AAL3").(av.UnableIntent); !ok {
+		t.Errorf("expected AAL3 to be unable to descend above its ceiling")
+	}
+	if e := s.holdingEntry(high, "MERIT"); e.Altitude != 9000 {
+		t.Errorf("stack has AAL3 at %d after it was unable to descend; expected 9000", e.Altitude)
+	}
+}
+
+func TestLostCommsLeavesHoldAtEFC(t *testing.T) {
+	s := makeTestSim(t)
+	tcw := E2ETCW()
+
+	ac := addHoldingTestAircraft(s, "AAL1", 6000)
+	s.updateHoldingStacks()
+	if _, err := s.ExpectFurtherClearance(tcw, "AAL1", ""); err != nil {
+		t.Fatal(err)
+	}
+	ac.NORDO = true
+
+	s.updateHoldingStacks()
+	if ac.Nav.Heading.Hold.Cancel {
+		t.Fatalf("left the hold before the EFC time")
+	}
+
+	s.State.SimTime = s.State.SimTime.Add(efcLead + time.Minute)
+	s.updateHoldingStacks()
+	if !ac.Nav.Heading.Hold.Cancel {
+		t.Errorf("expected the NORDO aircraft to leave the hold at its EFC time")
+	}
+	if _, ok := s.State.HoldingStacks["MERIT"]; ok {
+		t.Errorf("expected the stack to be removed")
+	}
+}
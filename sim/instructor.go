@@ -0,0 +1,567 @@
+// sim/instructor.go
+// Copyright(c) 2022-2026 vice contributors, licensed under the GNU Public License, Version 3.
+// SPDX: GPL-3.0-only
+
+package sim
+
+import (
+	"fmt"
+	"log/slog"
+	"slices"
+
+	av "github.com/mmp/vice/aviation"
+	"github.com/mmp/vice/math"
+	"github.com/mmp/vice/nav"
+	"github.com/mmp/vice/util"
+)
+
+// The instructor console lets privileged TCWs create problems on demand
+// during a live session: spawning traffic at an arbitrary point, forcing
+// pilot errors or lost communications, degrading an aircraft's
+// performance, closing runways, and switching to a different runway
+// configuration.
+
+// InstructorSpawn describes an aircraft created by an instructor. The
+// aircraft flies the given heading and maintains the given altitude until
+// it is issued instructions.
+type InstructorSpawn struct {
+	Callsign     av.ADSBCallsign
+	AircraftType string
+	Rules        av.FlightRules
+
+	Position math.Point2LL
+	Altitude float32
+	Heading  int // magnetic
+	// Speed is the initial indicated airspeed; if zero, a typical speed
+	// for the aircraft type is used.
+	Speed float32
+
+	// The departure and arrival airports default to the scenario's
+	// primary airport.
+	DepartureAirport string
+	ArrivalAirport   string
+
+	// Controller is the position that is tracking and talking to an IFR
+	// aircraft; it is ignored for VFRs, which are spawned squawking 1200.
+	Controller TCP
+}
+
+// SpawnInstructorAircraft creates a new aircraft as specified by an
+// instructor.
+func (s *Sim) SpawnInstructorAircraft(tcw TCW, spawn InstructorSpawn) error {
+	s.mu.Lock(s.lg)
+	defer s.mu.Unlock(s.lg)
+
+	if !s.PrivilegedTCWs[tcw] {
+		return ErrNotPrivilegedTCW
+	}
+
+	s.recordInput(RecordedInput{TCW: tcw, Type: RecordedInstructorSpawn, InstructorSpawn: &spawn})
+
+	ac, err := s.createInstructorAircraft(spawn)
+	if err != nil {
+		return err
+	}
+	s.addAircraftNoLock(*ac)
+
+	s.lg.Info("instructor spawn", slog.String("tcw", string(tcw)),
+		slog.String("adsb_callsign", string(ac.ADSBCallsign)))
+
+	s.publish()
+	return nil
+}
+
+func (s *Sim) createInstructorAircraft(spawn InstructorSpawn) (*Aircraft, error) {
+	if spawn.Callsign == "" {
+		return nil, ErrIllegalACID
+	}
+	if av.CallsignClashesWithExisting(s.currentCallsigns(), string(spawn.Callsign), s.EnforceUniqueCallsignSuffix) {
+		return nil, fmt.Errorf("%s: %w", spawn.Callsign, ErrDuplicateACID)
+	}
+	if spawn.AircraftType == "" {
+		return nil, ErrNoACType
+	}
+	perf, ok := av.DB.AircraftPerformance[spawn.AircraftType]
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", spawn.AircraftType, ErrUnknownAircraftType)
+	}
+	if spawn.Position.IsZero() {
+		return nil, ErrIllegalPosition
+	}
+	if spawn.Altitude <= 0 || spawn.Altitude > perf.Ceiling {
+		return nil, av.ErrInvalidAltitude
+	}
+	if spawn.Heading <= 0 || spawn.Heading > 360 {
+		return nil, av.ErrInvalidHeading
+	}
+	if spawn.Rules != av.FlightRulesVFR {
+		spawn.Rules = av.FlightRulesIFR
+		if _, ok := s.State.Controllers[spawn.Controller]; !ok {
+			return nil, fmt.Errorf("%s: %w", spawn.Controller, ErrUnknownController)
+		}
+	}
+
+	dep := util.Select(spawn.DepartureAirport != "", spawn.DepartureAirport, s.State.PrimaryAirport)
+	arr := util.Select(spawn.ArrivalAirport != "", spawn.ArrivalAirport, s.State.PrimaryAirport)
+	for _, ap := range []string{dep, arr} {
+		if _, ok := av.DB.Airports[ap]; !ok {
+			return nil, fmt.Errorf("%s: %w", ap, av.ErrUnknownAirport)
+		}
+	}
+
+	speed := spawn.Speed
+	if speed == 0 {
+		speed = min(250, perf.Speed.CruiseTAS)
+	}
+
+	ac := &Aircraft{
+		ADSBCallsign: spawn.Callsign,
+		Mode:         av.TransponderModeAltitude,
+	}
+	ac.InitializeFlightPlan(spawn.Rules, spawn.AircraftType, dep, arr)
+
+	// Build the navigation state as if for an overflight that starts at
+	// the spawn point and then put the aircraft on the heading.
+	of := av.Overflight{
+		Waypoints: av.WaypointArray{{
+			Fix:      "_instructor",
+			Location: spawn.Position,
+			Heading:  int16(spawn.Heading),
+		}},
+		InitialAltitudes: util.SingleOrArray[int]{int(spawn.Altitude)},
+		InitialSpeed:     speed,
+		AssignedAltitude: spawn.Altitude,
+	}
+	if err := ac.InitializeOverflight(&of, s.State.NmPerLongitude, s.State.MagneticVariation,
+		s.wxModel, s.State.SimTime, s.Rand, s.lg); err != nil {
+		return nil, err
+	}
+	ac.Nav.Waypoints = slices.DeleteFunc(ac.Nav.Waypoints, func(wp av.Waypoint) bool { return wp.Fix == "_instructor" })
+	hdg := math.MagneticHeading(spawn.Heading)
+	ac.Nav.Heading = nav.NavHeading{Assigned: &hdg}
+	ac.FlightPlan.Route = arr
+
+	if _, ok := s.State.ArrivalAirports[arr]; ok && arr != dep {
+		ac.TypeOfFlight = av.FlightTypeArrival
+	}
+
+	if spawn.Rules == av.FlightRulesVFR {
+		ac.Squawk = 0o1200
+		return ac, nil
+	}
+
+	nasFp := s.initNASFlightPlan(ac, ac.TypeOfFlight)
+	nasFp.Route = ac.FlightPlan.Route
+	nasFp.TrackingController = spawn.Controller
+	nasFp.OwningTCW = s.tcwForPosition(spawn.Controller)
+	nasFp.AssignedAltitude = util.Select(!av.DB.IsTRACON(s.State.Facility), int(spawn.Altitude), 0)
+	if err := s.assignSquawk(ac, &nasFp); err != nil {
+		return nil, err
+	}
+
+	// Unlike regular spawns, the aircraft starts out already tracked by
+	// and talking to the controller, so the flight plan is associated
+	// immediately.
+	created, err := s.STARSComputer.CreateFlightPlan(nasFp)
+	if err != nil {
+		return nil, err
+	}
+	if fp := s.STARSComputer.takeFlightPlanByACID(created.ACID); fp != nil {
+		ac.AssociateFlightPlan(fp)
+		s.eventStream.Post(Event{
+			Type: FlightPlanAssociatedEvent,
+			ACID: fp.ACID,
+		})
+	}
+
+	if s.isVirtualController(spawn.Controller) {
+		ac.ControllerFrequency = spawn.Controller
+	} else {
+		s.enqueueControllerContact(ac, spawn.Controller, "")
+	}
+
+	return ac, nil
+}
+
+// PilotError identifies a problem that an instructor can force on an
+// aircraft.
+type PilotError int
+
+const (
+	// PilotErrorNone clears any forced pilot error that hasn't happened
+	// yet and restores radio contact with a NORDO aircraft.
+	PilotErrorNone PilotError = iota
+	// PilotErrorMixUp causes the pilot to mix up the next instruction
+	// they are given.
+	PilotErrorMixUp
+	// PilotErrorAltitudeBust causes the pilot to fly through their
+	// assigned altitude.
+	PilotErrorAltitudeBust
+	// PilotErrorNORDO causes the aircraft to stop responding on the
+	// radio.
+	PilotErrorNORDO
+)
+
+func (pe PilotError) String() string {
+	return []string{"None", "Mix-up", "Altitude bust", "NORDO"}[pe]
+}
+
+// ForcePilotError causes the specified aircraft's pilot to make the given
+// error.
+func (s *Sim) ForcePilotError(tcw TCW, callsign av.ADSBCallsign, pe PilotError) error {
+	s.mu.Lock(s.lg)
+	defer s.mu.Unlock(s.lg)
+
+	if !s.PrivilegedTCWs[tcw] {
+		return ErrNotPrivilegedTCW
+	}
+
+	s.recordInput(RecordedInput{TCW: tcw, Type: RecordedPilotError, Callsign: callsign, PilotError: pe})
+
+	ac, ok := s.Aircraft[callsign]
+	if !ok {
+		return av.ErrNoAircraftForCallsign
+	}
+
+	switch pe {
+	case PilotErrorNone:
+		ac.ForcePilotMixUp = false
+		ac.NORDO = false
+	case PilotErrorMixUp:
+		ac.ForcePilotMixUp = true
+	case PilotErrorAltitudeBust:
+		// Overshoot by 500-1000', in hundreds of feet.
+		if !ac.Nav.BustAltitude(float32(100 * s.Rand.IntRange(5, 10))) {
+			return ErrNoAssignedAltitude
+		}
+	case PilotErrorNORDO:
+		ac.NORDO = true
+	default:
+		return fmt.Errorf("%d: unknown pilot error", pe)
+	}
+
+	s.lg.Info("forced pilot error", slog.String("tcw", string(tcw)),
+		slog.String("adsb_callsign", string(callsign)), slog.String("error", pe.String()))
+
+	s.publish()
+	return nil
+}
+
+// PerformanceAdjustment gives factors that scale an aircraft's climb,
+// descent, and acceleration rates relative to the standard performance
+// for its type; 1 is normal performance.
+type PerformanceAdjustment struct {
+	Climb        float32
+	Descent      float32
+	Acceleration float32
+}
+
+const (
+	minPerformanceFactor = 0.1
+	maxPerformanceFactor = 2
+)
+
+func (p PerformanceAdjustment) valid() bool {
+	ok := func(f float32) bool { return f >= minPerformanceFactor && f <= maxPerformanceFactor }
+	return ok(p.Climb) && ok(p.Descent) && ok(p.Acceleration)
+}
+
+// AdjustPerformance changes the specified aircraft's climb, descent, and
+// acceleration rates, e.g. to create a slow climber.
+func (s *Sim) AdjustPerformance(tcw TCW, callsign av.ADSBCallsign, adj PerformanceAdjustment) error {
+	s.mu.Lock(s.lg)
+	defer s.mu.Unlock(s.lg)
+
+	if !s.PrivilegedTCWs[tcw] {
+		return ErrNotPrivilegedTCW
+	}
+
+	s.recordInput(RecordedInput{TCW: tcw, Type: RecordedPerformance, Callsign: callsign, Performance: &adj})
+
+	ac, ok := s.Aircraft[callsign]
+	if !ok {
+		return av.ErrNoAircraftForCallsign
+	}
+	if !adj.valid() {
+		return ErrInvalidPerformance
+	}
+	perf, ok := av.DB.AircraftPerformance[ac.FlightPlan.AircraftType]
+	if !ok {
+		return ErrUnknownAircraftType
+	}
+
+	// Always scale the standard rates so that repeated adjustments don't
+	// compound.
+	rate := &ac.Nav.Perf.Rate
+	rate.Climb = adj.Climb * perf.Rate.Climb
+	rate.Descent = adj.Descent * perf.Rate.Descent
+	rate.Accelerate = adj.Acceleration * perf.Rate.Accelerate
+	rate.Decelerate = adj.Acceleration * perf.Rate.Decelerate
+
+	s.lg.Info("adjusted performance", slog.String("tcw", string(tcw)),
+		slog.String("adsb_callsign", string(callsign)), slog.Any("adjustment", adj))
+
+	s.publish()
+	return nil
+}
+
+// closedRunwayGoAroundDistance is the distance from the end of the
+// approach at which arrivals cleared to a closed runway go around.
+const closedRunwayGoAroundDistance = 1.5
+
+// SetRunwayClosed closes or reopens a runway. Both ends of the runway are
+// affected: no departures are launched from a closed runway and arrivals
+// cleared for an approach to it go around.
+func (s *Sim) SetRunwayClosed(tcw TCW, airport string, runway string, closed bool) error {
+	s.mu.Lock(s.lg)
+	defer s.mu.Unlock(s.lg)
+
+	if !s.PrivilegedTCWs[tcw] {
+		return ErrNotPrivilegedTCW
+	}
+
+	s.recordInput(RecordedInput{TCW: tcw, Type: RecordedRunwayClosure, Airport: airport, Runway: runway,
+		RunwayClosed: closed})
+
+	if !av.AirportHasRunway(airport, av.RunwayID(runway)) {
+		return fmt.Errorf("%s %s: %w", airport, runway, av.ErrUnknownRunway)
+	}
+
+	base := av.RunwayID(runway).Base()
+	rwys := slices.DeleteFunc(s.State.ClosedRunways[airport], func(r string) bool {
+		return r == base || r == av.OppositeRunwayId(base)
+	})
+	if closed {
+		rwys = append(rwys, base)
+		slices.Sort(rwys)
+	}
+
+	if len(rwys) > 0 {
+		if s.State.ClosedRunways == nil {
+			s.State.ClosedRunways = make(map[string][]string)
+		}
+		s.State.ClosedRunways[airport] = rwys
+	} else {
+		delete(s.State.ClosedRunways, airport)
+	}
+
+	s.lg.Info("runway closure", slog.String("tcw", string(tcw)), slog.String("airport", airport),
+		slog.String("runway", base), slog.Bool("closed", closed))
+
+	s.publish()
+	return nil
+}
+
+// runwayIsClosed reports whether either end of the given runway has been
+// closed.
+func (s *Sim) runwayIsClosed(airport string, runway string) bool {
+	base := av.RunwayID(runway).Base()
+	return slices.ContainsFunc(s.State.ClosedRunways[airport], func(r string) bool {
+		return r == base || r == av.OppositeRunwayId(base)
+	})
+}
+
+// ConfigurationSwitch describes a different runway configuration from the
+// sim's scenario group that an instructor has switched the running sim
+// to, e.g. when the wind shifts and an airport turns around.
+type ConfigurationSwitch struct {
+	ConfigurationId      string
+	Scenario             string
+	DepartureRunways     []DepartureRunway
+	ArrivalRunways       []ArrivalRunway
+	InboundAssignments   map[string]TCP
+	DepartureAssignments map[string]TCP
+	GoAroundAssignments  map[string]TCP
+
+	// Only the departure and inbound flow rates are taken from
+	// LaunchConfig; the launch modes and rate scales that the controllers
+	// have set are kept.
+	LaunchConfig LaunchConfig
+}
+
+// SwitchConfiguration changes the sim's active runways and the
+// controller assignments that go with them. Aircraft already in the air
+// are kept: arrivals that have been told to expect an approach to a
+// runway that is no longer in use are given one to an active runway.
+// Arrivals that have already been cleared for such an approach have their
+// clearance cancelled and ask the controller for a new one, or are
+// cleared for the new approach if a virtual controller is working them;
+// only those already talking to the tower continue to their runway. Departures
+// waiting to launch from runways that are no longer in use are removed.
+func (s *Sim) SwitchConfiguration(tcw TCW, sw ConfigurationSwitch) error {
+	s.mu.Lock(s.lg)
+	defer s.mu.Unlock(s.lg)
+
+	if !s.PrivilegedTCWs[tcw] {
+		return ErrNotPrivilegedTCW
+	}
+
+	s.recordInput(RecordedInput{TCW: tcw, Type: RecordedConfigurationSwitch, Configuration: &sw})
+
+	for _, ap := range configurationChangedAirports(s.State.DepartureRunways, s.State.ArrivalRunways,
+		sw.DepartureRunways, sw.ArrivalRunways) {
+		s.advanceATIS(ap)
+	}
+
+	s.State.ConfigurationId = sw.ConfigurationId
+	s.State.Scenario = sw.Scenario
+	s.State.DepartureRunways = sw.DepartureRunways
+	s.State.ArrivalRunways = sw.ArrivalRunways
+	s.InboundAssignments = sw.InboundAssignments
+	s.DepartureAssignments = sw.DepartureAssignments
+	s.GoAroundAssignments = sw.GoAroundAssignments
+
+	lc := s.State.LaunchConfig
+	lc.DepartureRates = sw.LaunchConfig.DepartureRates
+	lc.InboundFlowRates = sw.LaunchConfig.InboundFlowRates
+
+	// Stop launching from runways that are no longer in use; setting the
+	// rate to zero also culls the departures waiting for them.
+	for ap, rwys := range util.SortedMap(s.DepartureState) {
+		for rwy, state := range util.SortedMap(rwys) {
+			if _, ok := lc.DepartureRates[ap][rwy]; !ok {
+				state.setIFRRate(s, 0)
+			}
+		}
+	}
+	for ap, rwyRates := range util.SortedMap(lc.DepartureRates) {
+		if _, ok := s.DepartureState[ap]; !ok {
+			s.DepartureState[ap] = make(map[av.RunwayID]*RunwayLaunchState)
+		}
+		for rwy, categoryRates := range util.SortedMap(rwyRates) {
+			r := sumRateMap(categoryRates, lc.DepartureRateScale)
+			if state, ok := s.DepartureState[ap][rwy]; ok {
+				state.setIFRRate(s, r)
+			} else {
+				s.DepartureState[ap][rwy] = &RunwayLaunchState{
+					IFRSpawnRate: r,
+					NextIFRSpawn: s.State.SimTime.Add(randomInitialWait(r, s.Rand)),
+				}
+			}
+		}
+	}
+
+	// The old and new configurations may have different airports in a
+	// group, so each sum is over its own configuration's; they're summed
+	// in a fixed order so that unchanged totals compare equal.
+	sumFlowRates := func(rates map[string]float32) float32 {
+		var sum float32
+		for _, rate := range util.SortedMap(rates) {
+			sum += rate
+		}
+		return sum
+	}
+	for group, groupRates := range util.SortedMap(lc.InboundFlowRates) {
+		newSum := sumFlowRates(groupRates)
+		oldSum := sumFlowRates(s.State.LaunchConfig.InboundFlowRates[group])
+		if _, ok := s.NextInboundSpawn[group]; !ok || newSum != oldSum {
+			s.NextInboundSpawn[group] = s.State.SimTime.Add(randomInitialWait(newSum*lc.InboundFlowRateScale, s.Rand))
+		}
+	}
+
+	s.State.LaunchConfig = lc
+
+	s.State.DepartureAirports = make(map[string]any)
+	for name := range lc.DepartureRates {
+		s.State.DepartureAirports[name] = nil
+	}
+	for name, ap := range s.State.Airports {
+		if ap.VFRRateSum() > 0 {
+			s.State.DepartureAirports[name] = nil
+		}
+	}
+	s.State.ArrivalAirports = make(map[string]any)
+	for _, airportRates := range lc.InboundFlowRates {
+		for name := range airportRates {
+			if name != "overflights" {
+				s.State.ArrivalAirports[name] = nil
+			}
+		}
+	}
+
+	// Arrivals expecting or cleared for an approach to a runway that is no
+	// longer in use get one to an active runway; they will have heard
+	// about the change on the new ATIS.
+	for _, ac := range util.SortedMap(s.Aircraft) {
+		appr := ac.Nav.Approach.Assigned
+		if !ac.IsArrival() || appr == nil || ac.GotContactTower ||
+			s.isArrivalRunway(ac.FlightPlan.ArrivalAirport, appr.Runway) {
+			continue
+		}
+		wasCleared := ac.Nav.Approach.Cleared
+		if wasCleared {
+			ac.CancelApproachClearance()
+		}
+		id := s.autoPickApproach(ac)
+		if id != "" {
+			ac.ExpectApproach(id, s.State.Airports[ac.FlightPlan.ArrivalAirport])
+			s.lg.Info("reassigned approach", slog.String("adsb_callsign", string(ac.ADSBCallsign)),
+				slog.String("approach", id), slog.Bool("was_cleared", wasCleared))
+		}
+		if !wasCleared {
+			continue
+		}
+		if ac.ControllerFrequency == "" || s.isVirtualController(ac.ControllerFrequency) {
+			// Nobody to ask, so the virtual controller clears it for the
+			// new approach.
+			if id != "" {
+				if _, unable := ac.ClearedApproach(id, s.State.SimTime, nil).(av.UnableIntent); unable {
+					s.lg.Warn("unable to clear for new approach", slog.String("adsb_callsign", string(ac.ADSBCallsign)),
+						slog.String("approach", id))
+				}
+			}
+		} else if !ac.NORDO {
+			s.enqueuePilotTransmission(ac.ADSBCallsign, TCP(ac.ControllerFrequency),
+				PendingTransmissionRequestApproachClearance)
+		}
+	}
+
+	s.lg.Info("configuration switch", slog.String("tcw", string(tcw)),
+		slog.String("configuration", sw.ConfigurationId))
+
+	s.publish()
+	return nil
+}
+
+// isArrivalRunway reports whether the given runway is one of the active
+// arrival runways at the airport.
+func (s *Sim) isArrivalRunway(airport string, runway string) bool {
+	return slices.ContainsFunc(s.State.ArrivalRunways, func(ar ArrivalRunway) bool {
+		return ar.Airport == airport && ar.Runway.Base() == runway
+	})
+}
+
+// configurationChangedAirports returns the airports whose active
+// departure or arrival runways differ between two runway configurations.
+func configurationChangedAirports(oldDep []DepartureRunway, oldArr []ArrivalRunway,
+	newDep []DepartureRunway, newArr []ArrivalRunway) []string {
+	runways := func(dep []DepartureRunway, arr []ArrivalRunway) map[string][]string {
+		m := make(map[string][]string)
+		for _, rwy := range dep {
+			m[rwy.Airport] = append(m[rwy.Airport], "D"+string(rwy.Runway))
+		}
+		for _, rwy := range arr {
+			m[rwy.Airport] = append(m[rwy.Airport], "A"+string(rwy.Runway))
+		}
+		for ap := range m {
+			slices.Sort(m[ap])
+			m[ap] = slices.Compact(m[ap])
+		}
+		return m
+	}
+
+	oldRunways, newRunways := runways(oldDep, oldArr), runways(newDep, newArr)
+	var changed []string
+	for ap, rwys := range oldRunways {
+		if !slices.Equal(rwys, newRunways[ap]) {
+			changed = append(changed, ap)
+		}
+	}
+	for ap := range newRunways {
+		if _, ok := oldRunways[ap]; !ok {
+			changed = append(changed, ap)
+		}
+	}
+	slices.Sort(changed)
+	return changed
+}
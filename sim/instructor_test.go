@@ -0,0 +1,352 @@
+// sim/instructor_test.go
+// Copyright(c) 2022-2026 vice contributors, licensed under the GNU Public License, Version 3.
+// SPDX: GPL-3.0-only
+
+package sim
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	av "github.com/mmp/vice/aviation"
+	"github.com/mmp/vice/math"
+)
+
+func installInstructorPerformanceFixture(t *testing.T) {
+	t.Helper()
+
+	const acType = "XT20"
+	orig, ok := av.DB.AircraftPerformance[acType]
+	t.Cleanup(func() {
+		if ok {
+			av.DB.AircraftPerformance[acType] = orig
+		} else {
+			delete(av.DB.AircraftPerformance, acType)
+		}
+	})
+
+	var perf av.AircraftPerformance
+	perf.ICAO = acType
+	perf.Ceiling = 25000
+	perf.Rate.Climb = 2000
+	perf.Rate.Descent = 2500
+	perf.Rate.Accelerate = 6
+	perf.Rate.Decelerate = 4
+	perf.Speed.Min = 100
+	perf.Speed.Landing = 120
+	perf.Speed.CruiseTAS = 300
+	perf.Speed.MaxTAS = 350
+	av.DB.AircraftPerformance[acType] = perf
+}
+
+func TestInstructorRequiresPrivilege(t *testing.T) {
+	s := makeTestSim(t)
+	addTestAircraft(s, "AAL1", math.Point2LL{0, 0}, 5000)
+
+	const student = TCW("STUDENT")
+	for name, err := range map[string]error{
+		"spawn":       s.SpawnInstructorAircraft(student, InstructorSpawn{Callsign: "N123AB"}),
+		"pilot error": s.ForcePilotError(student, "AAL1", PilotErrorNORDO),
+		"performance": s.AdjustPerformance(student, "AAL1", PerformanceAdjustment{Climb: 1, Descent: 1, Acceleration: 1}),
+		"runway":      s.SetRunwayClosed(student, "XTST", "9", true),
+	} {
+		if !errors.Is(err, ErrNotPrivilegedTCW) {
+			t.Errorf("%s: expected ErrNotPrivilegedTCW, got %v", name, err)
+		}
+	}
+	if s.Aircraft["AAL1"].NORDO {
+		t.Errorf("unprivileged TCW made the aircraft NORDO")
+	}
+}
+
+func TestForcePilotError(t *testing.T) {
+	s := makeTestSim(t)
+	s.PilotErrorInterval = 0
+	tcw := E2ETCW()
+	ac := addTestAircraft(s, "AAL1", math.Point2LL{0, 0}, 5000)
+
+	if err := s.ForcePilotError(tcw, "AAL2", PilotErrorMixUp); !errors.Is(err, av.ErrNoAircraftForCallsign) {
+		t.Errorf("expected ErrNoAircraftForCallsign, got %v", err)
+	}
+
+	if err := s.ForcePilotError(tcw, "AAL1", PilotErrorMixUp); err != nil {
+		t.Fatalf("mix-up: %v", err)
+	}
+	if !ac.ForcePilotMixUp {
+		t.Errorf("expected a pending mix-up")
+	}
+
+	// A NORDO aircraft doesn't mix anything up since it doesn't hear the
+	// controller in the first place.
+	if err := s.ForcePilotError(tcw, "AAL1", PilotErrorNORDO); err != nil {
+		t.Fatalf("NORDO: %v", err)
+	}
+	if s.ShouldTriggerPilotMixUp("AAL1") {
+		t.Errorf("NORDO aircraft mixed up an instruction")
+	}
+	s.RunAircraftControlCommands(tcw, "AAL1", "D40", 0)
+	if ac.Nav.Altitude.Assigned != nil {
+		t.Errorf("NORDO aircraft followed an instruction")
+	}
+	if spoken, written := s.GenerateContactTransmission(&PendingContact{ADSBCallsign: "AAL1"}); spoken != "" || written != "" {
+		t.Errorf("NORDO aircraft transmitted %q", written)
+	}
+
+	if err := s.ForcePilotError(tcw, "AAL1", PilotErrorNone); err != nil {
+		t.Fatalf("none: %v", err)
+	}
+	if ac.NORDO || ac.ForcePilotMixUp {
+		t.Errorf("expected NORDO and the pending mix-up to be cleared")
+	}
+
+	if err := s.ForcePilotError(tcw, "AAL1", PilotErrorAltitudeBust); !errors.Is(err, ErrNoAssignedAltitude) {
+		t.Errorf("expected ErrNoAssignedAltitude, got %v", err)
+	}
+	alt := float32(4000)
+	ac.Nav.Altitude.Assigned = &alt
+	ac.Nav.Perf.Ceiling = 40000
+	if err := s.ForcePilotError(tcw, "AAL1", PilotErrorAltitudeBust); err != nil {
+		t.Fatalf("altitude bust: %v", err)
+	}
+	if a := ac.Nav.Altitude.ActiveAssigned; a == nil {
+		t.Errorf("expected an active assigned altitude")
+	} else if *a < 3000 || *a > 3500 {
+		t.Errorf("expected the aircraft to descend 500-1000' below 4000, got %.0f", *a)
+	}
+}
+
+func TestAdjustPerformance(t *testing.T) {
+	installInstructorPerformanceFixture(t)
+	s := makeTestSim(t)
+	tcw := E2ETCW()
+	ac := addTestAircraft(s, "AAL1", math.Point2LL{0, 0}, 5000)
+	ac.FlightPlan.AircraftType = "XT20"
+	ac.Nav.Perf = av.DB.AircraftPerformance["XT20"]
+
+	for _, adj := range []PerformanceAdjustment{{}, {Climb: 0.05, Descent: 1, Acceleration: 1},
+		{Climb: 1, Descent: 3, Acceleration: 1}} {
+		if err := s.AdjustPerformance(tcw, "AAL1", adj); !errors.Is(err, ErrInvalidPerformance) {
+			t.Errorf("%+v: expected ErrInvalidPerformance, got %v", adj, err)
+		}
+	}
+
+	// Adjustments are relative to the standard performance, so applying
+	// one twice gives the same result.
+	slow := PerformanceAdjustment{Climb: 0.5, Descent: 1, Acceleration: 0.25}
+	for range 2 {
+		if err := s.AdjustPerformance(tcw, "AAL1", slow); err != nil {
+			t.Fatalf("%v", err)
+		}
+	}
+	rate := ac.Nav.Perf.Rate
+	if rate.Climb != 1000 || rate.Descent != 2500 || rate.Accelerate != 1.5 || rate.Decelerate != 1 {
+		t.Errorf("unexpected rates %+v", rate)
+	}
+}
+
+func TestRunwayClosure(t *testing.T) {
+	installIntersectingRunwayFixture(t)
+	s := makeTestSim(t)
+	tcw := E2ETCW()
+
+	if err := s.SetRunwayClosed(tcw, "XTST", "4", true); !errors.Is(err, av.ErrUnknownRunway) {
+		t.Errorf("expected ErrUnknownRunway, got %v", err)
+	}
+
+	if err := s.SetRunwayClosed(tcw, "XTST", "9", true); err != nil {
+		t.Fatalf("%v", err)
+	}
+	for _, rwy := range []string{"9", "27", "9.Noise"} {
+		if !s.runwayIsClosed("XTST", rwy) {
+			t.Errorf("%s should be closed", rwy)
+		}
+	}
+	if s.runwayIsClosed("XTST", "36") {
+		t.Errorf("36 should be open")
+	}
+	if s.canLaunch(&RunwayLaunchState{}, DepartureAircraft{}, false, "XTST", "27") {
+		t.Errorf("launched from a closed runway")
+	}
+
+	// Either end reopens the runway.
+	if err := s.SetRunwayClosed(tcw, "XTST", "27", false); err != nil {
+		t.Fatalf("%v", err)
+	}
+	if s.runwayIsClosed("XTST", "9") {
+		t.Errorf("9 should have been reopened")
+	}
+	if _, ok := s.State.ClosedRunways["XTST"]; ok {
+		t.Errorf("expected no closures left at XTST: %v", s.State.ClosedRunways)
+	}
+}
+
+func TestSpawnInstructorAircraft(t *testing.T) {
+	installIntersectingRunwayFixture(t)
+	installInstructorPerformanceFixture(t)
+	s := makeTestSim(t)
+	s.State.PrimaryAirport = "XTST"
+	s.STARSComputer = makeSTARSComputer("TST")
+	tcw := E2ETCW()
+
+	spawn := InstructorSpawn{
+		Callsign:     "N123AB",
+		AircraftType: "XT20",
+		Rules:        av.FlightRulesVFR,
+		Position:     math.Point2LL{-73, 41},
+		Altitude:     4500,
+		Heading:      270,
+	}
+
+	for name, tc := range map[string]struct {
+		modify func(*InstructorSpawn)
+		err    error
+	}{
+		"callsign": {func(sp *InstructorSpawn) { sp.Callsign = "" }, ErrIllegalACID},
+		"type":     {func(sp *InstructorSpawn) { sp.AircraftType = "ZZZZ" }, ErrUnknownAircraftType},
+		"altitude": {func(sp *InstructorSpawn) { sp.Altitude = 30000 }, av.ErrInvalidAltitude},
+		"heading":  {func(sp *InstructorSpawn) { sp.Heading = 0 }, av.ErrInvalidHeading},
+		"position": {func(sp *InstructorSpawn) { sp.Position = math.Point2LL{} }, ErrIllegalPosition},
+		"airport":  {func(sp *InstructorSpawn) { sp.ArrivalAirport = "XXXX" }, av.ErrUnknownAirport},
+		"controller": {func(sp *InstructorSpawn) { sp.Rules = av.FlightRulesIFR; sp.Controller = "1X" },
+			ErrUnknownController},
+	} {
+		sp := spawn
+		tc.modify(&sp)
+		if err := s.SpawnInstructorAircraft(tcw, sp); !errors.Is(err, tc.err) {
+			t.Errorf("%s: expected %v, got %v", name, tc.err, err)
+		}
+	}
+	if len(s.Aircraft) != 0 {
+		t.Fatalf("invalid spawns created aircraft")
+	}
+
+	if err := s.SpawnInstructorAircraft(tcw, spawn); err != nil {
+		t.Fatalf("%v", err)
+	}
+	ac, ok := s.Aircraft["N123AB"]
+	if !ok {
+		t.Fatalf("aircraft wasn't created")
+	}
+	if ac.Position() != spawn.Position || ac.Altitude() != 4500 || ac.Squawk != 0o1200 {
+		t.Errorf("unexpected initial state: %v %.0f %s", ac.Position(), ac.Altitude(), ac.Squawk)
+	}
+	if hdg := ac.Nav.Heading.Assigned; hdg == nil || *hdg != 270 {
+		t.Errorf("expected assigned heading 270, got %v", hdg)
+	}
+	if ac.Nav.FlightState.IAS != 250 {
+		t.Errorf("expected default speed 250, got %.0f", ac.Nav.FlightState.IAS)
+	}
+
+	if err := s.SpawnInstructorAircraft(tcw, spawn); !errors.Is(err, ErrDuplicateACID) {
+		t.Errorf("expected ErrDuplicateACID, got %v", err)
+	}
+}
+
+func TestSwitchConfiguration(t *testing.T) {
+	s := makeTestSim(t)
+	tcw := E2ETCW()
+
+	s.State.Airports["KJFK"] = &av.Airport{
+		Approaches: map[string]*av.Approach{
+			"I22L": {Type: av.ILSApproach, Runway: "22L"},
+			"I31R": {Type: av.ILSApproach, Runway: "31R"},
+		},
+	}
+	s.State.ArrivalRunways = []ArrivalRunway{{Airport: "KJFK", Runway: "22L"}}
+	s.State.DepartureRunways = []DepartureRunway{{Airport: "KJFK", Runway: "22R", DefaultRate: 30}}
+	s.State.LaunchConfig = MakeLaunchConfig(s.State.DepartureRunways, 1, 0, nil,
+		map[string]map[string]float32{"north": {"KJFK": 10, "KLGA": 10}, "south": {"KJFK": 10}}, false)
+	s.State.ATISLetter = map[string]string{"KJFK": "A"}
+	s.ATISChangedTime = make(map[string]Time)
+	nextSpawn := s.State.SimTime.Add(time.Hour)
+	s.NextInboundSpawn = map[string]Time{"north": nextSpawn, "south": nextSpawn}
+	s.DepartureState = map[string]map[av.RunwayID]*RunwayLaunchState{
+		"KJFK": {"22R": {IFRSpawnRate: 30}},
+	}
+
+	expecting := addTestAircraft(s, "AAL1", math.Point2LL{0, 0}, 5000)
+	cleared := addTestAircraft(s, "AAL2", math.Point2LL{0, 0.1}, 3000)
+	cleared.Nav.Approach.Cleared = true
+	unattended := addTestAircraft(s, "AAL3", math.Point2LL{0, 0.2}, 3000)
+	unattended.Nav.Approach.Cleared = true
+	unattended.ControllerFrequency = ""
+	hdg := math.MagneticHeading(310)
+	unattended.Nav.Heading.Assigned = &hdg
+	tower := addTestAircraft(s, "AAL4", math.Point2LL{0, 0.05}, 1500)
+	tower.Nav.Approach.Cleared = true
+	tower.GotContactTower = true
+
+	sw := ConfigurationSwitch{
+		ConfigurationId:  "31S",
+		Scenario:         "JFK 31s",
+		DepartureRunways: []DepartureRunway{{Airport: "KJFK", Runway: "31L", DefaultRate: 30}},
+		ArrivalRunways:   []ArrivalRunway{{Airport: "KJFK", Runway: "31R"}},
+	}
+	sw.LaunchConfig = MakeLaunchConfig(sw.DepartureRunways, 1, 0, nil,
+		map[string]map[string]float32{"north": {"KJFK": 20}, "south": {"KJFK": 30}}, false)
+
+	if err := s.SwitchConfiguration("STUDENT", sw); !errors.Is(err, ErrNotPrivilegedTCW) {
+		t.Errorf("expected ErrNotPrivilegedTCW, got %v", err)
+	}
+	if err := s.SwitchConfiguration(tcw, sw); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	if s.State.ConfigurationId != "31S" || s.State.Scenario != "JFK 31s" {
+		t.Errorf("configuration id %q from %q, expected 31S from \"JFK 31s\"", s.State.ConfigurationId,
+			s.State.Scenario)
+	}
+	if s.State.ATISLetter["KJFK"] != "B" {
+		t.Errorf("ATIS %q, expected B", s.State.ATISLetter["KJFK"])
+	}
+	if id := expecting.Nav.Approach.AssignedId; id != "I31R" {
+		t.Errorf("AAL1 expecting %q, expected I31R", id)
+	}
+	if id := cleared.Nav.Approach.AssignedId; id != "I31R" || cleared.Nav.Approach.Cleared {
+		t.Errorf("cleared AAL2 expecting %q (cleared %v), expected I31R and not cleared",
+			id, cleared.Nav.Approach.Cleared)
+	}
+	if pc := s.PendingContacts["125.0"]; len(pc) != 1 || pc[0].ADSBCallsign != "AAL2" ||
+		pc[0].Type != PendingTransmissionRequestApproachClearance {
+		t.Errorf("expected AAL2 to ask for the approach, got %+v", pc)
+	}
+	if id := unattended.Nav.Approach.AssignedId; id != "I31R" || !unattended.Nav.Approach.Cleared {
+		t.Errorf("AAL3 without a controller: %q (cleared %v), expected cleared for I31R",
+			id, unattended.Nav.Approach.Cleared)
+	}
+	if id := tower.Nav.Approach.AssignedId; id != "I22L" || !tower.Nav.Approach.Cleared {
+		t.Errorf("AAL4 talking to the tower was reassigned to %q", id)
+	}
+	if r := s.DepartureState["KJFK"]["22R"].IFRSpawnRate; r != 0 {
+		t.Errorf("22R still launching at %f/hour", r)
+	}
+	if r := s.DepartureState["KJFK"]["31L"].IFRSpawnRate; r != 30 {
+		t.Errorf("31L launching at %f/hour, expected 30", r)
+	}
+	if _, ok := s.State.LaunchConfig.DepartureRates["KJFK"]["31L"]; !ok {
+		t.Errorf("launch config not updated: %+v", s.State.LaunchConfig.DepartureRates)
+	}
+	// The north flow's total rate is unchanged even though it's now all
+	// to KJFK, so its next arrival stays as scheduled.
+	if !s.NextInboundSpawn["north"].Equal(nextSpawn) {
+		t.Errorf("north arrivals rescheduled though their rate didn't change")
+	}
+	if s.NextInboundSpawn["south"].Equal(nextSpawn) {
+		t.Errorf("south arrivals not rescheduled after their rate changed")
+	}
+}
+
+func TestConfigurationChangedAirports(t *testing.T) {
+	dep := []DepartureRunway{{Airport: "KJFK", Runway: "31L"}, {Airport: "KLGA", Runway: "13"}}
+	arr := []ArrivalRunway{{Airport: "KJFK", Runway: "31R"}, {Airport: "KLGA", Runway: "22"}}
+	newDep := []DepartureRunway{{Airport: "KJFK", Runway: "22R"}, {Airport: "KLGA", Runway: "13"}}
+	newArr := []ArrivalRunway{{Airport: "KJFK", Runway: "22L"}, {Airport: "KLGA", Runway: "22"},
+		{Airport: "KISP", Runway: "6"}}
+
+	changed := configurationChangedAirports(dep, arr, newDep, newArr)
+	if !slices.Equal(changed, []string{"KISP", "KJFK"}) {
+		t.Errorf("got %v, expected [KISP KJFK]", changed)
+	}
+}
@@ -0,0 +1,151 @@
+// sim/metering.go
+// Copyright(c) 2022-2026 vice contributors, licensed under the GNU Public License, Version 3.
+// SPDX: GPL-3.0-only
+
+package sim
+
+import (
+	"cmp"
+	"slices"
+	"time"
+
+	av "github.com/mmp/vice/aviation"
+	"github.com/mmp/vice/util"
+)
+
+// MeteredArrival is an arrival's entry in its airport's TBFM timeline.
+type MeteredArrival struct {
+	ADSBCallsign av.ADSBCallsign
+	MeterFix     string
+	ETA          Time // estimated time of arrival at the meter fix
+	STA          Time // scheduled time of arrival at the meter fix
+	Frozen       bool // STA no longer changes
+}
+
+// Delay returns the time the arrival must lose to cross the meter fix at
+// its scheduled time. It counts down as the aircraft absorbs the delay and
+// is negative if the aircraft is running behind its schedule.
+func (ma MeteredArrival) Delay() time.Duration {
+	return ma.STA.Sub(ma.ETA)
+}
+
+// DelayMinutes returns the delay rounded to the nearest minute, as it is
+// shown in datablocks and lists.
+func (ma MeteredArrival) DelayMinutes() int {
+	return int(ma.Delay().Round(time.Minute) / time.Minute)
+}
+
+// MeteredArrival returns the timeline entry for the given aircraft, if it
+// is being metered.
+func (ss *CommonState) MeteredArrival(callsign av.ADSBCallsign) (MeteredArrival, bool) {
+	for _, tl := range ss.MeteringTimelines {
+		if idx := slices.IndexFunc(tl, func(ma MeteredArrival) bool { return ma.ADSBCallsign == callsign }); idx != -1 {
+			return tl[idx], true
+		}
+	}
+	return MeteredArrival{}, false
+}
+
+// meterFixETA returns the meter fix the aircraft will cross and when it
+// is expected to get there. Aircraft flying their route are estimated
+// using the distance along the route; vectored aircraft are estimated
+// direct to the meter fix they were previously scheduled at.
+func (s *Sim) meterFixETA(ac *Aircraft, m *av.Metering, prev *MeteredArrival) (string, Time, bool) {
+	gs := ac.Nav.FlightState.GS
+	if gs <= 0 {
+		return "", Time{}, false
+	}
+	eta := func(seconds float32) Time {
+		return s.State.SimTime.Add(time.Duration(seconds * float32(time.Second)))
+	}
+
+	for _, wp := range ac.Nav.Waypoints {
+		if !slices.Contains(m.MeterFixes, wp.Fix) {
+			continue
+		}
+		if dist, err := ac.Nav.DistanceAlongRoute(wp.Fix); err == nil {
+			return wp.Fix, eta(dist / gs * 3600), true
+		}
+		break
+	}
+
+	if prev != nil && ac.Nav.Heading.Assigned != nil {
+		if p, ok := s.State.Locate(prev.MeterFix); ok {
+			return prev.MeterFix, eta(ac.Nav.ETA(p)), true
+		}
+	}
+	return "", Time{}, false
+}
+
+// updateMetering rebuilds the TBFM timelines. Arrivals that haven't yet
+// reached their meter fix are scheduled in ETA order, each at least the
+// acceptance interval after the ones before it; an arrival's STA is frozen
+// once it is within the freeze horizon of the meter fix.
+func (s *Sim) updateMetering() {
+	for icao, ap := range s.State.Airports {
+		m := ap.Metering
+		if m == nil || m.AcceptanceRate <= 0 {
+			continue
+		}
+
+		prev := make(map[av.ADSBCallsign]*MeteredArrival)
+		for i, ma := range s.State.MeteringTimelines[icao] {
+			prev[ma.ADSBCallsign] = &s.State.MeteringTimelines[icao][i]
+		}
+
+		var frozen, unfrozen []MeteredArrival
+		for callsign, ac := range util.SortedMap(s.Aircraft) {
+			if ac.TypeOfFlight != av.FlightTypeArrival || ac.FlightPlan.ArrivalAirport != icao {
+				continue
+			}
+			fix, eta, ok := s.meterFixETA(ac, m, prev[callsign])
+			if !ok {
+				continue
+			}
+
+			ma := MeteredArrival{ADSBCallsign: callsign, MeterFix: fix, ETA: eta}
+			if p := prev[callsign]; p != nil && p.Frozen {
+				ma.STA, ma.Frozen = p.STA, true
+				frozen = append(frozen, ma)
+			} else {
+				unfrozen = append(unfrozen, ma)
+			}
+		}
+
+		byTime := func(t func(MeteredArrival) Time) func(a, b MeteredArrival) int {
+			return func(a, b MeteredArrival) int {
+				return cmp.Or(t(a).Compare(t(b)), cmp.Compare(a.ADSBCallsign, b.ADSBCallsign))
+			}
+		}
+		slices.SortFunc(frozen, byTime(func(ma MeteredArrival) Time { return ma.STA }))
+		slices.SortFunc(unfrozen, byTime(func(ma MeteredArrival) Time { return ma.ETA }))
+
+		interval := time.Hour / time.Duration(m.AcceptanceRate)
+		horizon := time.Duration(m.FreezeHorizon) * time.Minute
+		timeline := frozen
+		for _, ma := range unfrozen {
+			// Find the first slot at or after the ETA that is at least an
+			// interval away from everyone already scheduled.
+			sta := ma.ETA
+			for _, sched := range timeline {
+				if sta.After(sched.STA.Add(-interval)) && sta.Before(sched.STA.Add(interval)) {
+					sta = sched.STA.Add(interval)
+				}
+			}
+			ma.STA = sta
+			ma.Frozen = ma.ETA.Sub(s.State.SimTime) <= horizon
+
+			idx, _ := slices.BinarySearchFunc(timeline, ma, byTime(func(ma MeteredArrival) Time { return ma.STA }))
+			timeline = slices.Insert(timeline, idx, ma)
+		}
+
+		if len(timeline) == 0 {
+			delete(s.State.MeteringTimelines, icao)
+			continue
+		}
+		if s.State.MeteringTimelines == nil {
+			s.State.MeteringTimelines = make(map[string][]MeteredArrival)
+		}
+		s.State.MeteringTimelines[icao] = timeline
+	}
+}
@@ -0,0 +1,91 @@
+// sim/metering_test.go
+// Copyright(c) 2022-2026 vice contributors, licensed under the GNU Public License, Version 3.
+// SPDX: GPL-3.0-only
+
+package sim
+
+import (
+	"testing"
+	"time"
+
+	av "github.com/mmp/vice/aviation"
+	"github.com/mmp/vice/math"
+)
+
+func addMeteringTestAircraft(s *Sim, callsign av.ADSBCallsign, nmToFix float32) *Aircraft {
+	ac := addTestAircraft(s, callsign, math.Point2LL{0, -nmToFix / 60}, 11000)
+	ac.Nav.FlightState.GS = 300
+	ac.Nav.Waypoints = []av.Waypoint{{Fix: "ROBER", Location: math.Point2LL{0, 0}}, ac.Nav.FlightState.ArrivalAirport}
+	return ac
+}
+
+func TestMeteringSchedule(t *testing.T) {
+	s := makeTestSim(t)
+	s.State.Airports = map[string]*av.Airport{
+		"KJFK": {Metering: &av.Metering{MeterFixes: []string{"ROBER"}, AcceptanceRate: 30, FreezeHorizon: 5}},
+	}
+	now := s.State.SimTime
+
+	checkTime := func(what string, got Time, want time.Duration) {
+		t.Helper()
+		if d := got.Sub(now) - want; d < -time.Second || d > time.Second {
+			t.Errorf("%s: expected %s from now, got %s", what, want, got.Sub(now))
+		}
+	}
+
+	// 30nm and 31nm from the fix at 300 knots: ETAs of 6:00 and 6:12, but
+	// the acceptance rate requires two minutes between them.
+	lead := addMeteringTestAircraft(s, "AAL1", 30)
+	addMeteringTestAircraft(s, "AAL2", 31)
+	s.updateMetering()
+
+	tl := s.State.MeteringTimelines["KJFK"]
+	if len(tl) != 2 || tl[0].ADSBCallsign != "AAL1" || tl[1].ADSBCallsign != "AAL2" {
+		t.Fatalf("expected AAL1 then AAL2 in the timeline, got %+v", tl)
+	}
+	checkTime("AAL1 STA", tl[0].STA, 6*time.Minute)
+	checkTime("AAL2 ETA", tl[1].ETA, 6*time.Minute+12*time.Second)
+	checkTime("AAL2 STA", tl[1].STA, 8*time.Minute)
+	if tl[0].Frozen || tl[1].Frozen {
+		t.Errorf("expected neither arrival to be frozen yet")
+	}
+
+	ma, ok := s.State.MeteredArrival("AAL2")
+	if !ok {
+		t.Fatalf("AAL2 not found in the timeline")
+	}
+	if d := ma.Delay(); d < 107*time.Second || d > 109*time.Second {
+		t.Errorf("expected AAL2 to have 1:48 of delay, got %s", d)
+	}
+	if m := ma.DelayMinutes(); m != 2 {
+		t.Errorf("expected AAL2's delay to round to 2 minutes, got %d", m)
+	}
+	if _, ok := s.State.MeteredArrival("AAL3"); ok {
+		t.Errorf("unexpected timeline entry for AAL3")
+	}
+
+	// Within the freeze horizon the STA stays put even as the ETA changes.
+	lead.Nav.FlightState.Position = math.Point2LL{0, -20.0 / 60}
+	s.updateMetering()
+	if ma, _ := s.State.MeteredArrival("AAL1"); !ma.Frozen {
+		t.Fatalf("expected AAL1 to be frozen")
+	}
+	lead.Nav.FlightState.GS = 200
+	s.updateMetering()
+	ma, _ = s.State.MeteredArrival("AAL1")
+	checkTime("frozen AAL1 STA", ma.STA, 4*time.Minute)
+	checkTime("frozen AAL1 ETA", ma.ETA, 6*time.Minute)
+
+	// The second arrival is rescheduled behind the frozen one.
+	ma, _ = s.State.MeteredArrival("AAL2")
+	checkTime("AAL2 STA", ma.STA, 6*time.Minute+12*time.Second)
+
+	// Arrivals leave the timeline once they've passed the meter fix.
+	for _, ac := range s.Aircraft {
+		ac.Nav.Waypoints = ac.Nav.Waypoints[1:]
+	}
+	s.updateMetering()
+	if _, ok := s.State.MeteringTimelines["KJFK"]; ok {
+		t.Errorf("expected the timeline to be removed")
+	}
+}
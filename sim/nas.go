@@ -96,7 +96,7 @@ func (sc *STARSComputer) Update(s *Sim) {
 		return true
 	})
 
-	for _, ac := range s.Aircraft {
+	for _, ac := range util.SortedMap(s.Aircraft) {
 		if !ac.IsAirborne() || ac.Squawk == 0o1200 {
 			continue
 		}
@@ -115,8 +115,10 @@ func (sc *STARSComputer) Update(s *Sim) {
 			if ac.TypeOfFlight == av.FlightTypeArrival && !ac.WentAround && inVolumes(filters.ArrivalDrop) {
 				return true
 			} else if fp := ac.NASFlightPlan; fp != nil {
+				// Keep tracks owned by federated facilities until the
+				// aircraft is transferred to the other Sim.
 				if fp.LastLocalController != "" && s.State.IsExternalController(fp.TrackingController) &&
-					inVolumes(filters.SecondaryDrop) {
+					s.federatedFacility(fp.TrackingController) == "" && inVolumes(filters.SecondaryDrop) {
 					return true
 				}
 			}
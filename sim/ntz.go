@@ -0,0 +1,134 @@
+// sim/ntz.go
+// Copyright(c) 2022-2026 vice contributors, licensed under the GNU Public License, Version 3.
+// SPDX: GPL-3.0-only
+
+package sim
+
+import (
+	"log/slog"
+	"slices"
+
+	av "github.com/mmp/vice/aviation"
+	"github.com/mmp/vice/math"
+	"github.com/mmp/vice/nav"
+)
+
+// Simultaneous independent approaches to parallel runways are monitored
+// by a final monitor controller who watches for aircraft entering the
+// No Transgression Zone (NTZ) between the finals; see av.NTZ. Arrivals
+// may be randomly chosen to blunder across their localizer toward the
+// adjacent final so that the monitor controller can practice issuing
+// breakouts to the aircraft on the other final.
+
+// maybeSetNTZBlunder determines whether an arrival will blunder toward
+// the adjacent final if it's cleared for an approach to a runway that
+// has an NTZ and sets its NTZBlunderDistance if so.
+func (s *Sim) maybeSetNTZBlunder(ac *Aircraft, blunderRate float32) {
+	if ac.FlightPlan.Rules != av.FlightRulesIFR {
+		return
+	}
+	if ap, ok := s.State.Airports[ac.FlightPlan.ArrivalAirport]; !ok || len(ap.NTZs) == 0 {
+		return
+	}
+	if s.Rand.Float32() >= blunderRate {
+		return
+	}
+	// As with go-arounds, only bother if a human is working the arrival.
+	if !slices.ContainsFunc(ac.Nav.Waypoints, func(wp av.Waypoint) bool { return wp.HumanHandoff() }) {
+		return
+	}
+	d := s.Rand.Float32Range(3, 8)
+	ac.NTZBlunderDistance = &d
+}
+
+// lookupNTZ returns the NTZ between the runway the aircraft is cleared to
+// and an adjacent parallel runway, if there is one.
+func (s *Sim) lookupNTZ(ac *Aircraft) *av.NTZ {
+	na := &ac.Nav.Approach
+	if !na.Cleared || na.Assigned == nil || na.Maneuver != av.StraightInLanding {
+		return nil
+	}
+	ap, ok := s.State.Airports[ac.FlightPlan.ArrivalAirport]
+	if !ok {
+		return nil
+	}
+	for i := range ap.NTZs {
+		if ap.NTZs[i].HasRunway(na.Assigned.Runway) {
+			return &ap.NTZs[i]
+		}
+	}
+	return nil
+}
+
+// updateNTZBlunder is called for each aircraft as the sim runs; it starts
+// the blunder for an aircraft that has been chosen to blunder once it is
+// established on final and within its NTZBlunderDistance of the runway.
+func (s *Sim) updateNTZBlunder(ac *Aircraft) {
+	if ac.NTZBlunderDistance == nil {
+		return
+	}
+	ntz := s.lookupNTZ(ac)
+	if ntz == nil {
+		return
+	}
+	na := &ac.Nav.Approach
+	if na.InterceptState != nav.OnApproachCourse && !na.PassedApproachFix {
+		return
+	}
+	if d, err := ac.DistanceToEndOfApproach(); err != nil || d > *ac.NTZBlunderDistance {
+		return
+	}
+	ac.NTZBlunderDistance = nil // only blunder once
+
+	icao := ac.FlightPlan.ArrivalAirport
+	rwy, ok := av.LookupRunway(icao, na.Assigned.Runway)
+	if !ok {
+		return
+	}
+	other, ok := av.LookupRunway(icao, ntz.OtherRunway(na.Assigned.Runway))
+	if !ok {
+		return
+	}
+
+	// Turn toward the other runway's final: figure out which side of our
+	// final approach course it's on.
+	nmPerLongitude := ac.NmPerLongitude()
+	hdg := math.MagneticToTrue(rwy.Heading, ac.MagneticVariation())
+	d := math.SinCos(math.Radians(float32(hdg)))
+	v := math.Sub2f(math.LL2NM(other.Threshold, nmPerLongitude), math.LL2NM(rwy.Threshold, nmPerLongitude))
+	turn, delta := av.TurnRight, s.Rand.Intn(11)+20
+	if d[0]*v[1]-d[1]*v[0] > 0 {
+		turn, delta = av.TurnLeft, -delta
+	}
+
+	blunderHeading := math.MagneticHeading(math.NormalizeHeading(float32(rwy.Heading) + float32(delta)))
+	s.lg.Debug("NTZ blunder", slog.String("callsign", string(ac.ADSBCallsign)),
+		slog.Float64("heading", float64(blunderHeading)))
+	ac.Nav.Blunder(blunderHeading, turn)
+}
+
+// tcwMonitorsNTZ returns true if the TCW controls the final monitor
+// position for the NTZ along the aircraft's final.
+func (s *Sim) tcwMonitorsNTZ(tcw TCW, ac *Aircraft) bool {
+	ntz := s.lookupNTZ(ac)
+	return ntz != nil && ntz.Monitor != "" && s.State.TCWControlsPosition(tcw, ntz.Monitor)
+}
+
+// Breakout handles a breakout instruction from the final monitor
+// controller: the aircraft turns immediately to the given heading and
+// climbs or descends to the given altitude (or maintains its current
+// altitude if alt is nil).
+func (s *Sim) Breakout(tcw TCW, callsign av.ADSBCallsign, hdg int, turn av.TurnDirection, alt *float32) (av.CommandIntent, error) {
+	s.mu.Lock(s.lg)
+	defer s.mu.Unlock(s.lg)
+
+	cmd := func(tcw TCW, ac *Aircraft) av.CommandIntent {
+		return ac.Breakout(hdg, turn, alt)
+	}
+	if ac, ok := s.Aircraft[callsign]; ok && !s.TCWCanCommandAircraft(tcw, ac) && s.tcwMonitorsNTZ(tcw, ac) {
+		// The monitor transmits on the tower frequency, so the aircraft
+		// doesn't need to be talking to it.
+		return s.dispatchAircraftCommand(tcw, callsign, nil, cmd)
+	}
+	return s.dispatchControlledAircraftCommand(tcw, callsign, cmd)
+}
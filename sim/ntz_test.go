@@ -0,0 +1,171 @@
+// sim/ntz_test.go
+// Copyright(c) 2022-2026 vice contributors, licensed under the GNU Public License, Version 3.
+// SPDX: GPL-3.0-only
+
+package sim
+
+import (
+	"errors"
+	"testing"
+
+	av "github.com/mmp/vice/aviation"
+	"github.com/mmp/vice/log"
+	"github.com/mmp/vice/math"
+	"github.com/mmp/vice/nav"
+)
+
+func TestNTZBlunder(t *testing.T) {
+	setupTestRunways(t, "KJFK", []av.Runway{
+		{Id: "36L", Heading: 360, Threshold: math.Point2LL{0, 0}},
+		{Id: "36R", Heading: 360, Threshold: math.Point2LL{0.8 / 60, 0}},
+	})
+
+	s := makeTestSim(t)
+	s.State.Airports = map[string]*av.Airport{
+		"KJFK": {NTZs: []av.NTZ{{Runways: [2]string{"36L", "36R"}}}},
+	}
+
+	makeArrival := func(callsign av.ADSBCallsign, rwy string, lon float32) *Aircraft {
+		ac := addTestAircraft(s, callsign, math.Point2LL{lon, -6.0 / 60}, 2000)
+		ac.Nav.FlightState.Heading = 360
+		ac.Nav.FlightState.NmPerLongitude = 60
+		ac.Nav.Approach.Assigned.Runway = rwy
+		ac.Nav.Approach.AssignedId = "I" + rwy
+		ac.Nav.Approach.Cleared = true
+		ac.Nav.Approach.InterceptState = nav.OnApproachCourse
+		ac.Nav.Waypoints = av.WaypointArray{
+			{Fix: "_RWY", Location: math.Point2LL{lon, 0}},
+			ac.Nav.FlightState.ArrivalAirport,
+		}
+		d := float32(5)
+		ac.NTZBlunderDistance = &d
+		return ac
+	}
+
+	left := makeArrival("AAL1", "36L", 0)
+	right := makeArrival("AAL2", "36R", 0.8/60)
+
+	// Not yet within the blunder distance
+	s.updateNTZBlunder(left)
+	if left.NTZBlunderDistance == nil || !left.Nav.Approach.Cleared {
+		t.Fatalf("blundered too early")
+	}
+
+	for _, ac := range []*Aircraft{left, right} {
+		ac.Nav.FlightState.Position[1] = -4.0 / 60
+		s.updateNTZBlunder(ac)
+
+		if ac.NTZBlunderDistance != nil {
+			t.Errorf("%s: expected NTZBlunderDistance to be cleared", ac.ADSBCallsign)
+		}
+		if ac.Nav.Approach.Cleared {
+			t.Errorf("%s: expected approach clearance to be canceled", ac.ADSBCallsign)
+		}
+		if ac.Nav.Approach.Assigned == nil {
+			t.Errorf("%s: expected assigned approach to be kept", ac.ADSBCallsign)
+		}
+		if alt := ac.Nav.Altitude.Assigned; alt == nil || *alt != 2000 {
+			t.Errorf("%s: expected to level off at 2000, got %v", ac.ADSBCallsign, alt)
+		}
+	}
+
+	// Each aircraft should turn toward the other final.
+	if hdg := left.Nav.Heading.Assigned; hdg == nil || *hdg < 20 || *hdg > 30 || *left.Nav.Heading.Turn != av.TurnRight {
+		t.Errorf("36L arrival: expected right turn to 020-030, got %v", hdg)
+	}
+	if hdg := right.Nav.Heading.Assigned; hdg == nil || *hdg < 330 || *hdg > 340 || *right.Nav.Heading.Turn != av.TurnLeft {
+		t.Errorf("36R arrival: expected left turn to 330-340, got %v", hdg)
+	}
+
+	// Arrivals to runways without an NTZ don't blunder.
+	other := makeArrival("AAL3", "4", 0)
+	other.Nav.FlightState.Position[1] = -4.0 / 60
+	s.updateNTZBlunder(other)
+	if other.NTZBlunderDistance == nil || !other.Nav.Approach.Cleared {
+		t.Errorf("arrival to a runway without an NTZ blundered")
+	}
+}
+
+func TestBreakoutCommand(t *testing.T) {
+	callsign := av.ADSBCallsign("AAL1")
+	ac := MakeTestAircraft(callsign, "36R")
+	ac.ControllerFrequency = "1A"
+	ac.Nav.Perf.Ceiling = 41000
+	ac.Nav.Approach.Cleared = true
+	ac.Nav.Approach.InterceptState = nav.OnApproachCourse
+
+	s := &Sim{
+		State: &CommonState{
+			DynamicState: DynamicState{
+				CurrentConsolidation: map[TCW]*TCPConsolidation{
+					"TCW1": {PrimaryTCP: "1A"},
+					"TCW2": {PrimaryTCP: "1F"},
+					"TCW3": {PrimaryTCP: "1B"},
+				},
+			},
+			Airports: map[string]*av.Airport{
+				"KJFK": {NTZs: []av.NTZ{{Runways: [2]string{"36L", "36R"}, Monitor: "1F"}}},
+			},
+		},
+		Aircraft:        map[av.ADSBCallsign]*Aircraft{callsign: ac},
+		PendingContacts: map[TCP][]PendingContact{},
+		lg:              log.New(true, "error", t.TempDir()),
+	}
+
+	for _, cmd := range []string{"B270", "BX270", "BL", "BL270/", "BL270/X30", "BL270/CAB"} {
+		if _, err := s.runOneControlCommand("TCW1", callsign, cmd, 0); err == nil {
+			t.Errorf("%s: expected an error", cmd)
+		}
+	}
+
+	intent, err := s.runOneControlCommand("TCW1", callsign, "BL270/C40", 0)
+	if err != nil {
+		t.Fatalf("runOneControlCommand() returned error: %v", err)
+	}
+	bi, ok := intent.(av.BreakoutIntent)
+	if !ok {
+		t.Fatalf("runOneControlCommand() returned %T, want av.BreakoutIntent", intent)
+	}
+	if bi.Heading != 270 || bi.Turn != av.HeadingTurnToLeft || bi.Altitude != 4000 || bi.Direction != av.AltitudeClimb {
+		t.Errorf("unexpected intent %+v", bi)
+	}
+
+	// The turn starts immediately, without the usual pilot delay.
+	if ac.Nav.DeferredNavHeading != nil {
+		t.Errorf("expected no deferred heading")
+	}
+	if hdg := ac.Nav.Heading.Assigned; hdg == nil || *hdg != 270 || *ac.Nav.Heading.Turn != av.TurnLeft {
+		t.Errorf("expected left turn to 270, got %v", hdg)
+	}
+	if alt := ac.Nav.Altitude.Assigned; alt == nil || *alt != 4000 {
+		t.Errorf("expected assigned altitude 4000, got %v", alt)
+	}
+	if ac.Nav.Approach.Cleared {
+		t.Errorf("expected approach clearance to be canceled")
+	}
+
+	// Without an altitude, the aircraft maintains its current altitude.
+	ac.Nav.FlightState.Altitude = 2480
+	intent, err = s.runOneControlCommand("TCW1", callsign, "BR090", 0)
+	if err != nil {
+		t.Fatalf("runOneControlCommand() returned error: %v", err)
+	}
+	if bi := intent.(av.BreakoutIntent); bi.Altitude != 2500 || bi.Direction != av.AltitudeMaintain {
+		t.Errorf("unexpected intent %+v", bi)
+	}
+	// The final monitor can break out aircraft on the finals it's
+	// monitoring even though it doesn't have them; other controllers
+	// can't.
+	ac.Nav.Approach.Cleared = true
+	if _, err := s.runOneControlCommand("TCW3", callsign, "BL270", 0); !errors.Is(err, av.ErrOtherControllerHasTrack) {
+		t.Errorf("got %v, expected ErrOtherControllerHasTrack", err)
+	}
+	if _, err := s.runOneControlCommand("TCW2", callsign, "BL270", 0); err != nil {
+		t.Errorf("final monitor unable to break out: %v", err)
+	}
+	ac.Nav.Approach.Cleared = true
+	ac.Nav.Approach.Assigned.Runway = "4"
+	if _, err := s.runOneControlCommand("TCW2", callsign, "BL270", 0); !errors.Is(err, av.ErrOtherControllerHasTrack) {
+		t.Errorf("got %v, expected ErrOtherControllerHasTrack for an aircraft on another final", err)
+	}
+}
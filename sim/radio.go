@@ -194,6 +194,12 @@ func (s *Sim) processVirtualControllerContacts() {
 // fromPos is the controller position the aircraft is coming from, used to
 // determine whether this is the first contact in a TRACON facility (for ATIS reporting).
 func (s *Sim) enqueueControllerContact(ac *Aircraft, tcp TCP, fromPos ControlPosition) {
+	if s.federatedFacility(tcp) != "" {
+		// The aircraft will check in with the controller in the other Sim.
+		s.queueFederationTransfer(ac, tcp, fromPos)
+		return
+	}
+
 	// Aircraft will switch frequency (2-4 sec), then listen before transmitting (3-6 sec).
 	switchDelay := s.Rand.DurationRange(2*time.Second, 5*time.Second)
 	listenDelay := s.Rand.DurationRange(3*time.Second, 7*time.Second)
@@ -356,7 +362,7 @@ func (s *Sim) GenerateContactTransmission(pc *PendingContact) (spokenText, writt
 	defer s.mu.Unlock(s.lg)
 
 	ac, ok := s.Aircraft[pc.ADSBCallsign]
-	if !ok {
+	if !ok || ac.NORDO {
 		return "", ""
 	}
 
@@ -453,6 +459,8 @@ func (s *Sim) GenerateContactTransmission(pc *PendingContact) (spokenText, writt
 		}
 		if ac.GoAroundOnRunwayHeading {
 			rt.Add("[runway heading|on a runway heading]")
+		} else if ac.GoAroundOnMissedApproach {
+			rt.Add("[on the missed|flying the published missed|on the published missed approach]")
 		} else if ac.Nav.Heading.Assigned != nil {
 			rt.Add("heading {hdg}", int(*ac.Nav.Heading.Assigned+0.5))
 		}
@@ -553,13 +561,13 @@ func (s *Sim) GenerateContactTransmission(pc *PendingContact) (spokenText, writt
 	}
 
 	// For emergency aircraft, 50% of the time add "emergency aircraft" after heavy/super
-	if ac.EmergencyState != nil && s.Rand.Bool() {
+	if ac.IsEmergency() && s.Rand.Bool() {
 		heavySuper += " emergency aircraft"
 	}
 
 	csArg := av.CallsignArg{
 		Callsign:           ac.ADSBCallsign,
-		IsEmergency:        ac.EmergencyState != nil,
+		IsEmergency:        ac.IsEmergency(),
 		AlwaysFullCallsign: true,
 	}
 
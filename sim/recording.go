@@ -0,0 +1,333 @@
+// sim/recording.go
+// Copyright(c) 2022-2026 vice contributors, licensed under the GNU Public License, Version 3.
+// SPDX: GPL-3.0-only
+
+package sim
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"time"
+
+	av "github.com/mmp/vice/aviation"
+	"github.com/mmp/vice/log"
+	"github.com/mmp/vice/wx"
+)
+
+// Session recordings capture everything needed to deterministically re-run
+// a Sim: its serialized state (including the state of its Rand) at the
+// start of the recording and then every controller input applied to it,
+// tagged with the sim time at which it was received. Since the Sim only
+// advances in one-second steps and all of its randomness comes from its
+// Rand, re-applying the inputs at the same sim times reproduces the
+// original aircraft trajectories.
+//
+// Recordings are written as a stream of JSON values: a RecordingHeader
+// followed by one RecordedInput per input. Writing incrementally means
+// that a recording is usable even if the process exits unexpectedly.
+
+// recordingVersion should be incremented whenever the recording format
+// changes incompatibly.
+const recordingVersion = 1
+
+type RecordedInputType int
+
+const (
+	RecordedControlCommands RecordedInputType = iota
+	RecordedHandoff
+	RecordedAcceptHandoff
+	RecordedCancelHandoff
+	RecordedRedirectHandoff
+	RecordedAcceptRedirectedHandoff
+	RecordedPointOut
+	RecordedAcknowledgePointOut
+	RecordedRejectPointOut
+	RecordedRecallPointOut
+	RecordedLaunchConfig
+	RecordedSimRate
+	RecordedReleaseDeparture
+	RecordedConsolidateTCP
+	RecordedDeconsolidateTCP
+	RecordedPrivilegedTCW
+	RecordedWaypointCommands
+	RecordedAutoControl
+	RecordedRewind
+	RecordedInstructorSpawn
+	RecordedPilotError
+	RecordedPerformance
+	RecordedRunwayClosure
+	RecordedConfigurationSwitch
+)
+
+func (t RecordedInputType) String() string {
+	return []string{"ControlCommands", "Handoff", "AcceptHandoff", "CancelHandoff", "RedirectHandoff",
+		"AcceptRedirectedHandoff", "PointOut", "AcknowledgePointOut", "RejectPointOut", "RecallPointOut",
+		"LaunchConfig", "SimRate", "ReleaseDeparture", "ConsolidateTCP", "DeconsolidateTCP",
+		"PrivilegedTCW", "WaypointCommands", "AutoControl", "Rewind", "InstructorSpawn",
+		"PilotError", "Performance", "RunwayClosure", "ConfigurationSwitch"}[t]
+}
+
+// RecordedInput is a single controller input to a Sim. Only the fields
+// relevant to the input's Type are set.
+type RecordedInput struct {
+	Time Time
+	TCW  TCW
+	Type RecordedInputType
+
+	Callsign        av.ADSBCallsign        `json:",omitempty"`
+	ACID            ACID                   `json:",omitempty"`
+	TCP             TCP                    `json:",omitempty"`
+	Commands        string                 `json:",omitempty"`
+	AudioDuration   time.Duration          `json:",omitempty"`
+	LaunchConfig    *LaunchConfig          `json:",omitempty"`
+	SimRate         float32                `json:",omitempty"`
+	Consolidation   ConsolidationType      `json:",omitempty"`
+	Privileged      bool                   `json:",omitempty"`
+	AutoControlled  bool                   `json:",omitempty"`
+	Rewind          time.Duration          `json:",omitempty"`
+	InstructorSpawn *InstructorSpawn       `json:",omitempty"`
+	PilotError      PilotError             `json:",omitempty"`
+	Performance     *PerformanceAdjustment `json:",omitempty"`
+	Airport         string                 `json:",omitempty"`
+	Runway          string                 `json:",omitempty"`
+	RunwayClosed    bool                   `json:",omitempty"`
+	Configuration   *ConfigurationSwitch   `json:",omitempty"`
+}
+
+func (in RecordedInput) LogValue() slog.Value {
+	attrs := []slog.Attr{
+		slog.String("type", in.Type.String()),
+		slog.Time("time", in.Time.Time()),
+		slog.String("tcw", string(in.TCW)),
+	}
+	if in.Callsign != "" {
+		attrs = append(attrs, slog.String("callsign", string(in.Callsign)))
+	}
+	if in.ACID != "" {
+		attrs = append(attrs, slog.String("acid", string(in.ACID)))
+	}
+	if in.TCP != "" {
+		attrs = append(attrs, slog.String("tcp", string(in.TCP)))
+	}
+	if in.Commands != "" {
+		attrs = append(attrs, slog.String("commands", in.Commands))
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// RecordingHeader is the first value in a recording; Sim holds the Sim's
+// state as of when recording began.
+type RecordingHeader struct {
+	Version   int
+	Seed      uint64
+	StartTime time.Time // wallclock time
+	Sim       *Sim
+}
+
+// Recording is a recorded session as loaded from disk.
+type Recording struct {
+	RecordingHeader
+	Inputs []RecordedInput
+}
+
+type recorder struct {
+	w   io.WriteCloser
+	enc *json.Encoder
+}
+
+// StartRecording reseeds the Sim's Rand with the given seed and then starts
+// recording the Sim's inputs to w, which is closed when recording stops.
+func (s *Sim) StartRecording(w io.WriteCloser, seed uint64) error {
+	s.mu.Lock(s.lg)
+	defer s.mu.Unlock(s.lg)
+
+	if s.recorder != nil {
+		return ErrAlreadyRecording
+	}
+
+	s.Rand.Seed(seed)
+
+	r := &recorder{w: w, enc: json.NewEncoder(w)}
+	hdr := RecordingHeader{
+		Version:   recordingVersion,
+		Seed:      seed,
+		StartTime: time.Now(),
+		Sim:       s,
+	}
+	if err := r.enc.Encode(hdr); err != nil {
+		return err
+	}
+
+	s.recorder = r
+	s.lg.Info("started recording", slog.Uint64("seed", seed), slog.Time("sim_time", s.State.SimTime.Time()))
+
+	return nil
+}
+
+// StopRecording stops recording the Sim's inputs and closes the
+// recording's writer.
+func (s *Sim) StopRecording() error {
+	s.mu.Lock(s.lg)
+	defer s.mu.Unlock(s.lg)
+
+	return s.stopRecording()
+}
+
+func (s *Sim) stopRecording() error {
+	if s.recorder == nil {
+		return ErrNotRecording
+	}
+
+	err := s.recorder.w.Close()
+	s.recorder = nil
+	return err
+}
+
+// recordInput adds the given input to the recording, if one is active,
+// tagging it with the current sim time. The caller must hold s.mu.
+func (s *Sim) recordInput(in RecordedInput) {
+	if s.recorder == nil {
+		return
+	}
+
+	in.Time = s.State.SimTime
+	if err := s.recorder.enc.Encode(in); err != nil {
+		s.lg.Errorf("%v: unable to record input; stopping recording", err)
+		s.stopRecording()
+	}
+}
+
+// LoadRecording reads a recording written by a Sim via StartRecording.
+func LoadRecording(r io.Reader) (*Recording, error) {
+	dec := json.NewDecoder(r)
+
+	var rec Recording
+	if err := dec.Decode(&rec.RecordingHeader); err != nil {
+		return nil, err
+	}
+	if rec.Version != recordingVersion {
+		return nil, fmt.Errorf("%d: %w", rec.Version, ErrBadRecordingVersion)
+	}
+	if rec.Sim == nil {
+		return nil, errors.New("recording is missing initial Sim state")
+	}
+
+	for {
+		var in RecordedInput
+		if err := dec.Decode(&in); err == io.EOF {
+			break
+		} else if err != nil {
+			// A truncated final input is expected if the recording Sim's
+			// process exited unexpectedly; keep everything before it.
+			if errors.Is(err, io.ErrUnexpectedEOF) {
+				break
+			}
+			return nil, err
+		}
+		rec.Inputs = append(rec.Inputs, in)
+	}
+
+	return &rec, nil
+}
+
+// Playback re-runs the recording: the Sim is restored to its initial
+// state and then stepped one second at a time, with each input applied at
+// the sim time it was originally received. After the last input, the Sim
+// is run for an additional extra time. Inputs that fail are logged but
+// do not stop playback, since they failed in the original session as
+// well. The resulting Sim is returned.
+func (r *Recording) Playback(provider *wx.Provider, extra time.Duration, lg *log.Logger) (*Sim, error) {
+	s := r.Sim
+	s.Activate(lg, provider)
+
+	for _, in := range r.Inputs {
+		for s.SimTime().Before(in.Time) {
+			s.Step(time.Second)
+		}
+
+		if err := s.applyRecordedInput(in); err != nil {
+			if errors.Is(err, ErrUnknownRecordedInput) {
+				return nil, err
+			}
+			lg.Info("recorded input failed", slog.Any("input", in), slog.Any("error", err))
+		}
+	}
+
+	for range int(extra.Seconds()) {
+		s.Step(time.Second)
+	}
+
+	return s, nil
+}
+
+func (s *Sim) applyRecordedInput(in RecordedInput) error {
+	switch in.Type {
+	case RecordedControlCommands:
+		return s.RunAircraftControlCommands(in.TCW, in.Callsign, in.Commands, in.AudioDuration).Error
+	case RecordedHandoff:
+		return s.HandoffTrack(in.TCW, in.ACID, in.TCP)
+	case RecordedAcceptHandoff:
+		return s.AcceptHandoff(in.TCW, in.ACID)
+	case RecordedCancelHandoff:
+		return s.CancelHandoff(in.TCW, in.ACID)
+	case RecordedRedirectHandoff:
+		return s.RedirectHandoff(in.TCW, in.ACID, in.TCP)
+	case RecordedAcceptRedirectedHandoff:
+		return s.AcceptRedirectedHandoff(in.TCW, in.ACID)
+	case RecordedPointOut:
+		return s.PointOut(in.TCW, in.ACID, in.TCP)
+	case RecordedAcknowledgePointOut:
+		return s.AcknowledgePointOut(in.TCW, in.ACID)
+	case RecordedRejectPointOut:
+		return s.RejectPointOut(in.TCW, in.ACID)
+	case RecordedRecallPointOut:
+		return s.RecallPointOut(in.TCW, in.ACID)
+	case RecordedLaunchConfig:
+		if in.LaunchConfig == nil {
+			return errors.New("recorded launch config change is missing the launch config")
+		}
+		return s.SetLaunchConfig(in.TCW, *in.LaunchConfig)
+	case RecordedSimRate:
+		return s.SetSimRate(in.TCW, in.SimRate)
+	case RecordedReleaseDeparture:
+		return s.ReleaseDeparture(in.TCW, in.Callsign)
+	case RecordedConsolidateTCP:
+		return s.ConsolidateTCP(in.TCW, in.TCP, in.Consolidation)
+	case RecordedDeconsolidateTCP:
+		return s.DeconsolidateTCP(in.TCW, in.TCP)
+	case RecordedPrivilegedTCW:
+		s.SetPrivilegedTCW(in.TCW, in.Privileged)
+		return nil
+	case RecordedWaypointCommands:
+		return s.SetWaypointCommands(in.TCW, in.Commands)
+	case RecordedAutoControl:
+		return s.SetAutoControlled(in.TCW, in.AutoControlled)
+	case RecordedRewind:
+		_, err := s.Rewind(in.TCW, in.Rewind)
+		return err
+	case RecordedInstructorSpawn:
+		if in.InstructorSpawn == nil {
+			return errors.New("recorded instructor spawn is missing the aircraft")
+		}
+		return s.SpawnInstructorAircraft(in.TCW, *in.InstructorSpawn)
+	case RecordedPilotError:
+		return s.ForcePilotError(in.TCW, in.Callsign, in.PilotError)
+	case RecordedPerformance:
+		if in.Performance == nil {
+			return errors.New("recorded performance adjustment is missing the adjustment")
+		}
+		return s.AdjustPerformance(in.TCW, in.Callsign, *in.Performance)
+	case RecordedRunwayClosure:
+		return s.SetRunwayClosed(in.TCW, in.Airport, in.Runway, in.RunwayClosed)
+	case RecordedConfigurationSwitch:
+		if in.Configuration == nil {
+			return errors.New("recorded configuration switch is missing the configuration")
+		}
+		return s.SwitchConfiguration(in.TCW, *in.Configuration)
+	default:
+		return fmt.Errorf("%d: %w", in.Type, ErrUnknownRecordedInput)
+	}
+}
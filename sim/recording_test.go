@@ -0,0 +1,126 @@
+// sim/recording_test.go
+// Copyright(c) 2022-2026 vice contributors, licensed under the GNU Public License, Version 3.
+// SPDX: GPL-3.0-only
+
+package sim
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/mmp/vice/log"
+	"github.com/mmp/vice/math"
+	"github.com/mmp/vice/rand"
+)
+
+// nopCloser adapts a bytes.Buffer to the io.WriteCloser that
+// StartRecording expects.
+type nopCloser struct{ *bytes.Buffer }
+
+func (nopCloser) Close() error { return nil }
+
+func TestRecordingRoundtrip(t *testing.T) {
+	lg := &log.Logger{Logger: slog.New(slog.NewTextHandler(io.Discard, nil))}
+	s := NewTestSim(lg)
+
+	var buf bytes.Buffer
+	if err := s.StartRecording(nopCloser{&buf}, 6502); err != nil {
+		t.Fatalf("StartRecording: %v", err)
+	}
+	if err := s.StartRecording(nopCloser{&buf}, 6502); !errors.Is(err, ErrAlreadyRecording) {
+		t.Errorf("second StartRecording: got %v, want %v", err, ErrAlreadyRecording)
+	}
+
+	s.SetSimRate(E2ETCW(), 4)
+	s.State.SimTime = s.State.SimTime.Add(3 * time.Second)
+	s.SetWaypointCommands(E2ETCW(), "")
+
+	if err := s.StopRecording(); err != nil {
+		t.Fatalf("StopRecording: %v", err)
+	}
+	// Inputs after recording stops shouldn't be recorded.
+	s.SetSimRate(E2ETCW(), 1)
+
+	rec, err := LoadRecording(&buf)
+	if err != nil {
+		t.Fatalf("LoadRecording: %v", err)
+	}
+	if rec.Seed != 6502 {
+		t.Errorf("seed: got %d, want 6502", rec.Seed)
+	}
+	if len(rec.Inputs) != 2 {
+		t.Fatalf("got %d inputs, want 2: %+v", len(rec.Inputs), rec.Inputs)
+	}
+
+	rate, wp := rec.Inputs[0], rec.Inputs[1]
+	if rate.Type != RecordedSimRate || rate.SimRate != 4 || rate.TCW != E2ETCW() {
+		t.Errorf("unexpected sim rate input %+v", rate)
+	}
+	if wp.Type != RecordedWaypointCommands {
+		t.Errorf("unexpected waypoint commands input %+v", wp)
+	}
+	if d := wp.Time.Sub(rate.Time); d != 3*time.Second {
+		t.Errorf("inputs recorded %s apart, expected 3s", d)
+	}
+}
+
+func TestLoadRecordingTruncated(t *testing.T) {
+	lg := &log.Logger{Logger: slog.New(slog.NewTextHandler(io.Discard, nil))}
+	s := NewTestSim(lg)
+
+	var buf bytes.Buffer
+	if err := s.StartRecording(nopCloser{&buf}, 1); err != nil {
+		t.Fatalf("StartRecording: %v", err)
+	}
+	s.SetSimRate(E2ETCW(), 2)
+	s.SetSimRate(E2ETCW(), 8)
+
+	// Chop off part of the last input, as would happen if the process
+	// exited mid-write.
+	b := buf.Bytes()
+	rec, err := LoadRecording(bytes.NewReader(b[:len(b)-5]))
+	if err != nil {
+		t.Fatalf("LoadRecording: %v", err)
+	}
+	if len(rec.Inputs) != 1 || rec.Inputs[0].SimRate != 2 {
+		t.Errorf("expected only the first, complete input; got %+v", rec.Inputs)
+	}
+}
+
+func TestControlCommandsNORDO(t *testing.T) {
+	s := makeTestSim(t)
+	ac := addTestAircraft(s, "AAL1", math.Point2LL{0, 0}, 5000)
+	ac.Nav.Rand = rand.Make()
+	ac.NORDO = true
+
+	var buf bytes.Buffer
+	if err := s.StartRecording(nopCloser{&buf}, 1); err != nil {
+		t.Fatalf("StartRecording: %v", err)
+	}
+
+	// The commands are recorded but the aircraft doesn't respond.
+	result := s.RunAircraftControlCommands(E2ETCW(), "AAL1", "H180", 0)
+	if !result.NoResponse || result.Error != nil || result.ReadbackSpokenText != "" {
+		t.Errorf("expected no response from a NORDO aircraft, got %+v", result)
+	}
+	if _, ok := ac.Nav.AssignedHeading(); ok {
+		t.Errorf("NORDO aircraft followed an instruction")
+	}
+
+	ac.NORDO = false
+	if result := s.RunAircraftControlCommands(E2ETCW(), "AAL1", "H180", 0); result.NoResponse {
+		t.Errorf("no response from an aircraft that isn't NORDO")
+	}
+
+	rec, err := LoadRecording(&buf)
+	if err != nil {
+		t.Fatalf("LoadRecording: %v", err)
+	}
+	if len(rec.Inputs) != 2 {
+		t.Errorf("got %d inputs, want 2: %+v", len(rec.Inputs), rec.Inputs)
+	}
+}
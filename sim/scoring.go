@@ -0,0 +1,489 @@
+// sim/scoring.go
+// Copyright(c) 2022-2026 vice contributors, licensed under the GNU Public License, Version 3.
+// SPDX: GPL-3.0-only
+
+package sim
+
+import (
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	av "github.com/mmp/vice/aviation"
+	"github.com/mmp/vice/math"
+	"github.com/mmp/vice/util"
+)
+
+// Scoring keeps a per-TCW scorecard of the things an instructor would
+// debrief a student on after a session: losses of separation, aircraft
+// vectored below the MVA, airspace violations, late handoffs, go-arounds
+// caused by insufficient spacing on final, and departures that were kept
+// waiting for a release. Events are attributed to the TCW that owns the
+// track at the time.
+//
+// Losses of separation and MVA busts persist for some time; a single event
+// is logged when one starts and it is then updated each second until it
+// ends, so that it records the duration and the worst case.
+
+const (
+	// Aircraft within this many feet of the MVA aren't flagged, to allow
+	// for altitude rounding when leveling at the MVA.
+	scoreMVASlop = 50
+
+	// Departures held for release for longer than this are logged.
+	scoreDepartureDelayThreshold = 2 * time.Minute
+)
+
+type ScoreEventType int
+
+const (
+	ScoreLossOfSeparation ScoreEventType = iota
+	ScoreMVABust
+	ScoreAirspaceViolation
+	ScoreLateHandoff
+	ScoreSpacingGoAround
+	ScoreDepartureDelay
+)
+
+func (t ScoreEventType) String() string {
+	return []string{"Loss of separation", "MVA bust", "Airspace violation", "Late handoff",
+		"Go-around for spacing", "Departure delay"}[t]
+}
+
+// ScoreEvent records a single scored event. Only the fields relevant to
+// the event's Type are set.
+type ScoreEvent struct {
+	Type      ScoreEventType
+	Time      Time
+	Callsigns []av.ADSBCallsign
+
+	// Duration is set for losses of separation and MVA busts; it is
+	// updated while the event is ongoing.
+	Duration time.Duration `json:",omitempty"`
+
+	// Losses of separation and go-arounds: the closest lateral (nm) and
+	// vertical (ft) distances and the required lateral separation.
+	Lateral         float32 `json:",omitempty"`
+	Vertical        float32 `json:",omitempty"`
+	RequiredLateral float32 `json:",omitempty"`
+
+	// MVA busts: the lowest altitude the aircraft reached and the MVA.
+	Altitude int `json:",omitempty"`
+	MVA      int `json:",omitempty"`
+
+	// Airspace violations: the position whose airspace was entered.
+	Position ControlPosition `json:",omitempty"`
+
+	// Departure delays: how long the departure waited for a release.
+	Delay time.Duration `json:",omitempty"`
+}
+
+func (e ScoreEvent) Description() string {
+	switch e.Type {
+	case ScoreLossOfSeparation:
+		return fmt.Sprintf("%s/%s: %.1fnm/%.0fft (%.1fnm required) for %s", e.Callsigns[0], e.Callsigns[1],
+			e.Lateral, e.Vertical, e.RequiredLateral, e.Duration)
+	case ScoreMVABust:
+		return fmt.Sprintf("%s: %d' with MVA %d' for %s", e.Callsigns[0], e.Altitude, e.MVA, e.Duration)
+	case ScoreAirspaceViolation:
+		return fmt.Sprintf("%s: entered %s airspace", e.Callsigns[0], e.Position)
+	case ScoreLateHandoff:
+		return fmt.Sprintf("%s: left airspace without a handoff", e.Callsigns[0])
+	case ScoreSpacingGoAround:
+		return fmt.Sprintf("%s behind %s: %.1fnm (%.1fnm required)", e.Callsigns[1], e.Callsigns[0],
+			e.Lateral, e.RequiredLateral)
+	case ScoreDepartureDelay:
+		return fmt.Sprintf("%s: waited %s for release", e.Callsigns[0], e.Delay)
+	default:
+		return "unknown"
+	}
+}
+
+func (e ScoreEvent) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("type", e.Type.String()),
+		slog.Time("time", e.Time.Time()),
+		slog.String("description", e.Description()))
+}
+
+// Scorecard is the record of scored events for a single TCW, in the order
+// in which they occurred.
+type Scorecard struct {
+	TCW    TCW
+	Events []ScoreEvent
+}
+
+// Count returns the number of events of the given type.
+func (sc Scorecard) Count(t ScoreEventType) int {
+	n := 0
+	for _, e := range sc.Events {
+		if e.Type == t {
+			n++
+		}
+	}
+	return n
+}
+
+// scoreEventRef identifies an event in a TCW's scorecard so that an
+// ongoing event can be updated.
+type scoreEventRef struct {
+	tcw TCW
+	idx int
+}
+
+type scoreAirspaceKey struct {
+	callsign av.ADSBCallsign
+	pos      ControlPosition
+}
+
+// scoringState tracks ongoing events across updates. It isn't serialized;
+// after a Sim is restored, ongoing events are logged anew.
+type scoringState struct {
+	separation map[[2]av.ADSBCallsign][]scoreEventRef
+	mva        map[av.ADSBCallsign][]scoreEventRef
+	airspace   map[scoreAirspaceKey]struct{}
+	// TCW whose airspace the aircraft was inside when last checked.
+	insideOwnAirspace map[av.ADSBCallsign]TCW
+}
+
+// GetScorecard returns the scorecard for the target TCW. TCWs may always
+// get their own scorecard but privileged TCWs may get anyone's.
+func (s *Sim) GetScorecard(tcw TCW, target TCW) (Scorecard, error) {
+	s.mu.Lock(s.lg)
+	defer s.mu.Unlock(s.lg)
+
+	if target != tcw && !s.PrivilegedTCWs[tcw] {
+		return Scorecard{}, ErrNotPrivilegedTCW
+	}
+	if _, ok := s.State.CurrentConsolidation[target]; !ok {
+		return Scorecard{}, ErrTCWNotFound
+	}
+
+	sc := Scorecard{TCW: target}
+	if card, ok := s.Scorecards[target]; ok {
+		sc.Events = slices.Clone(card.Events)
+	}
+	return sc, nil
+}
+
+// scoringTCW returns the TCW that should be scored for the aircraft: the
+// one that owns its track, if it is a human-controlled TCW.
+func (s *Sim) scoringTCW(ac *Aircraft) (TCW, bool) {
+	fp := ac.NASFlightPlan
+	if fp == nil || fp.OwningTCW == "" {
+		return "", false
+	}
+	_, ok := s.State.CurrentConsolidation[fp.OwningTCW]
+	return fp.OwningTCW, ok
+}
+
+func (s *Sim) addScoreEvent(tcw TCW, e ScoreEvent) scoreEventRef {
+	if s.Scorecards == nil {
+		s.Scorecards = make(map[TCW]*Scorecard)
+	}
+	card, ok := s.Scorecards[tcw]
+	if !ok {
+		card = &Scorecard{TCW: tcw}
+		s.Scorecards[tcw] = card
+	}
+
+	e.Time = s.State.SimTime
+	card.Events = append(card.Events, e)
+	s.lg.Info("scored event", slog.String("tcw", string(tcw)), slog.Any("event", e))
+
+	return scoreEventRef{tcw: tcw, idx: len(card.Events) - 1}
+}
+
+func (s *Sim) scoreEvent(ref scoreEventRef) *ScoreEvent {
+	return &s.Scorecards[ref.tcw].Events[ref.idx]
+}
+
+// updateScoring is called once a second to check for ongoing events.
+func (s *Sim) updateScoring() {
+	if s.prespawn {
+		return
+	}
+
+	s.updateSeparationScoring()
+	s.updateMVAScoring()
+	s.updateAirspaceScoring()
+}
+
+func (s *Sim) updateSeparationScoring() {
+	// Separation is only required between IFR aircraft and is only scored
+	// once they're airborne and visible to radar.
+	var ifr []*Aircraft
+	for ac := range util.SortedMapValues(s.Aircraft) {
+		if ac.FlightPlan.Rules == av.FlightRulesIFR && ac.IsAirborne() && s.isRadarVisible(ac) {
+			ifr = append(ifr, ac)
+		}
+	}
+
+	active := make(map[[2]av.ADSBCallsign][]scoreEventRef)
+	for i, a := range ifr {
+		for _, b := range ifr[i+1:] {
+			vert := math.Abs(a.Altitude() - b.Altitude())
+			if vert >= av.VerticalSeparation-av.VerticalSeparationSlop {
+				continue
+			}
+			lat := math.NMDistance2LLFast(a.Position(), b.Position(), s.State.NmPerLongitude)
+			if lat >= 10 { // greater than any of the minima
+				continue
+			}
+			if s.separationExempt(a, b) {
+				continue
+			}
+			req := s.requiredLateralSeparation(a, b)
+			if lat >= req {
+				continue
+			}
+
+			key := [2]av.ADSBCallsign{a.ADSBCallsign, b.ADSBCallsign}
+			refs, ok := s.scoring.separation[key]
+			if !ok {
+				var tcws []TCW
+				for _, ac := range []*Aircraft{a, b} {
+					if tcw, ok := s.scoringTCW(ac); ok && !slices.Contains(tcws, tcw) {
+						tcws = append(tcws, tcw)
+					}
+				}
+				for _, tcw := range tcws {
+					refs = append(refs, s.addScoreEvent(tcw, ScoreEvent{
+						Type:            ScoreLossOfSeparation,
+						Callsigns:       key[:],
+						Lateral:         lat,
+						Vertical:        vert,
+						RequiredLateral: req,
+					}))
+				}
+				if len(refs) == 0 {
+					// Neither aircraft is being worked by a human.
+					continue
+				}
+			}
+
+			for _, ref := range refs {
+				e := s.scoreEvent(ref)
+				e.Duration = s.State.SimTime.Sub(e.Time)
+				if lat < e.Lateral {
+					e.Lateral, e.Vertical = lat, vert
+				}
+				e.RequiredLateral = max(e.RequiredLateral, req)
+			}
+			active[key] = refs
+		}
+	}
+	s.scoring.separation = active
+}
+
+// separationExempt returns true if the controller isn't responsible for
+// separation between the two aircraft.
+func (s *Sim) separationExempt(a, b *Aircraft) bool {
+	if a.GotContactTower && b.GotContactTower {
+		// Tower's responsibility.
+		return true
+	}
+	if _, ok := s.scoringTCW(a); !ok {
+		if _, ok := s.scoringTCW(b); !ok {
+			return true
+		}
+	}
+
+	visual := func(ac *Aircraft, traffic av.ADSBCallsign) bool {
+		return slices.ContainsFunc(ac.SeenTraffic, func(sa SeenAircraft) bool {
+			return sa.Callsign == traffic && sa.MaintainingVisualSeparation
+		})
+	}
+	if visual(a, b.ADSBCallsign) || visual(b, a.ADSBCallsign) {
+		return true
+	}
+
+	// Approaches to different runways at the same airport are assumed to
+	// be authorized simultaneous approaches.
+	aa, ba := a.Nav.Approach, b.Nav.Approach
+	if aa.Cleared && ba.Cleared && aa.Assigned != nil && ba.Assigned != nil &&
+		a.FlightPlan.ArrivalAirport == b.FlightPlan.ArrivalAirport && aa.Assigned.Runway != ba.Assigned.Runway {
+		return true
+	}
+
+	return false
+}
+
+// requiredLateralSeparation returns the lateral separation in nm required
+// between the two aircraft; for aircraft in trail on the same final
+// approach, this accounts for wake turbulence.
+func (s *Sim) requiredLateralSeparation(a, b *Aircraft) float32 {
+	req := float32(av.LateralSeparation)
+	if a.Altitude() <= av.ReducedSeparationCeiling && b.Altitude() <= av.ReducedSeparationCeiling {
+		req = av.ReducedLateralSeparation
+	}
+
+	aa, ba := a.Nav.Approach.Assigned, b.Nav.Approach.Assigned
+	if a.OnApproach(false) && b.OnApproach(false) && aa != nil && ba != nil &&
+		a.FlightPlan.ArrivalAirport == b.FlightPlan.ArrivalAirport && aa.Runway == ba.Runway {
+		front, trailing := a, b
+		if math.NMDistance2LL(b.Position(), ba.Threshold) < math.NMDistance2LL(a.Position(), aa.Threshold) {
+			front, trailing = b, a
+		}
+		req = max(req, s.approachSeparation(front, trailing))
+	}
+
+	return req
+}
+
+func (s *Sim) updateMVAScoring() {
+	mvas := s.getMVAGrid()
+
+	active := make(map[av.ADSBCallsign][]scoreEventRef)
+	for callsign, ac := range util.SortedMap(s.Aircraft) {
+		tcw, ok := s.scoringTCW(ac)
+		if !ok || ac.FlightPlan.Rules != av.FlightRulesIFR || !ac.IsAirborne() || !ac.MVAsApply() {
+			continue
+		}
+		// The MVA only applies to aircraft that are being vectored and
+		// departures are allowed to be vectored while they climb through
+		// it.
+		if _, vectored := ac.Nav.AssignedHeading(); !vectored {
+			continue
+		}
+		if ac.IsDeparture() && ac.Nav.FlightState.AltitudeRate > 0 {
+			continue
+		}
+
+		alt := int(ac.Altitude())
+		if s.State.FacilityAdaptation.Filters.InhibitMSAW.Inside(ac.Position(), alt) {
+			continue
+		}
+		mva := mvas.GetMVA(ac.Position())
+		if mva == 0 || alt+scoreMVASlop >= mva {
+			continue
+		}
+
+		refs, ok := s.scoring.mva[callsign]
+		if !ok {
+			refs = []scoreEventRef{s.addScoreEvent(tcw, ScoreEvent{
+				Type:      ScoreMVABust,
+				Callsigns: []av.ADSBCallsign{callsign},
+				Altitude:  alt,
+				MVA:       mva,
+			})}
+		}
+		for _, ref := range refs {
+			e := s.scoreEvent(ref)
+			e.Duration = s.State.SimTime.Sub(e.Time)
+			if mva-alt > e.MVA-e.Altitude {
+				e.Altitude, e.MVA = alt, mva
+			}
+		}
+		active[callsign] = refs
+	}
+	s.scoring.mva = active
+}
+
+func (s *Sim) updateAirspaceScoring() {
+	if len(s.State.Airspace) == 0 {
+		return
+	}
+
+	inAirspace := func(ac *Aircraft, pos ControlPosition) bool {
+		for _, vols := range s.State.Airspace[pos] {
+			if inside, _ := av.InAirspace(ac.Position(), ac.Altitude(), vols); inside {
+				return true
+			}
+		}
+		return false
+	}
+
+	airspace := make(map[scoreAirspaceKey]struct{})
+	insideOwn := make(map[av.ADSBCallsign]TCW)
+	for callsign, ac := range util.SortedMap(s.Aircraft) {
+		tcw, ok := s.scoringTCW(ac)
+		if !ok || !ac.IsAirborne() {
+			continue
+		}
+		fp := ac.NASFlightPlan
+
+		own := slices.ContainsFunc(s.State.GetPositionsForTCW(tcw),
+			func(pos ControlPosition) bool { return inAirspace(ac, pos) })
+		if own {
+			insideOwn[callsign] = tcw
+			continue
+		}
+
+		// Left the TCW's airspace while still owning the track and
+		// without having started a handoff.
+		if s.scoring.insideOwnAirspace[callsign] == tcw && fp.HandoffController == "" &&
+			fp.RedirectedHandoff.RedirectedTo == "" && !ac.GotContactTower {
+			s.addScoreEvent(tcw, ScoreEvent{
+				Type:      ScoreLateHandoff,
+				Callsigns: []av.ADSBCallsign{callsign},
+			})
+		}
+
+		// Entered someone else's airspace without a handoff or an
+		// approved point out.
+		for pos := range util.SortedMap(s.State.Airspace) {
+			if s.State.TCWControlsPosition(tcw, pos) || slices.Contains(fp.PointOutHistory, pos) ||
+				!inAirspace(ac, pos) {
+				continue
+			}
+			key := scoreAirspaceKey{callsign: callsign, pos: pos}
+			if _, ok := s.scoring.airspace[key]; !ok {
+				s.addScoreEvent(tcw, ScoreEvent{
+					Type:      ScoreAirspaceViolation,
+					Callsigns: []av.ADSBCallsign{callsign},
+					Position:  pos,
+				})
+			}
+			airspace[key] = struct{}{}
+		}
+	}
+	s.scoring.airspace = airspace
+	s.scoring.insideOwnAirspace = insideOwn
+}
+
+// scoreSpacingGoAround is called when tower sends the trailing aircraft
+// around due to insufficient spacing behind front on final.
+func (s *Sim) scoreSpacingGoAround(front, trailing *Aircraft, actual, required float32) {
+	if s.prespawn {
+		return
+	}
+	if tcw, ok := s.scoringTCW(trailing); ok {
+		s.addScoreEvent(tcw, ScoreEvent{
+			Type:            ScoreSpacingGoAround,
+			Callsigns:       []av.ADSBCallsign{front.ADSBCallsign, trailing.ADSBCallsign},
+			Lateral:         actual,
+			RequiredLateral: required,
+		})
+	}
+}
+
+// scoreDepartureLaunch is called when a departure starts its takeoff
+// roll; departures that were held for release for too long are scored
+// against the departure controller.
+func (s *Sim) scoreDepartureLaunch(ac *Aircraft, dep DepartureAircraft) {
+	if s.prespawn || !ac.HoldForRelease {
+		return
+	}
+
+	fp := ac.NASFlightPlan
+	if fp == nil {
+		fp = s.STARSComputer.lookupFlightPlanByACID(ACID(ac.ADSBCallsign))
+	}
+	if fp == nil {
+		return
+	}
+	tcw := s.State.TCWForPosition(fp.InboundHandoffController)
+	if _, ok := s.State.CurrentConsolidation[tcw]; !ok {
+		return
+	}
+
+	if delay := ac.ReleaseTime.Sub(dep.RequestReleaseTime); delay > scoreDepartureDelayThreshold {
+		s.addScoreEvent(tcw, ScoreEvent{
+			Type:      ScoreDepartureDelay,
+			Callsigns: []av.ADSBCallsign{ac.ADSBCallsign},
+			Delay:     delay,
+		})
+	}
+}
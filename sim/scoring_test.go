@@ -0,0 +1,161 @@
+// sim/scoring_test.go
+// Copyright(c) 2022-2026 vice contributors, licensed under the GNU Public License, Version 3.
+// SPDX: GPL-3.0-only
+
+package sim
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	av "github.com/mmp/vice/aviation"
+	"github.com/mmp/vice/math"
+)
+
+func square(x0, y0, x1, y1 float32) []math.Point2LL {
+	return []math.Point2LL{{x0, y0}, {x1, y0}, {x1, y1}, {x0, y1}}
+}
+
+func TestScoreLossOfSeparation(t *testing.T) {
+	s := makeTestSim(t)
+
+	a := addTestAircraft(s, "AAL1", math.Point2LL{0, 0}, 5000)
+	b := addTestAircraft(s, "AAL2", math.Point2LL{0, 2.0 / 60}, 5500)
+
+	for range 10 {
+		s.updateScoring()
+		s.State.SimTime = s.State.SimTime.Add(time.Second)
+	}
+	// Closest approach is later in the event.
+	b.Nav.FlightState.Position = math.Point2LL{0, 1.5 / 60}
+	s.updateScoring()
+
+	sc, err := s.GetScorecard(E2ETCW(), E2ETCW())
+	if err != nil {
+		t.Fatalf("GetScorecard: %v", err)
+	}
+	if n := sc.Count(ScoreLossOfSeparation); n != 1 {
+		t.Fatalf("got %d losses of separation, expected 1: %+v", n, sc.Events)
+	}
+	e := sc.Events[0]
+	if e.Duration != 10*time.Second {
+		t.Errorf("duration %s, expected 10s", e.Duration)
+	}
+	if e.RequiredLateral != av.ReducedLateralSeparation {
+		t.Errorf("required lateral %.1f, expected %.1f", e.RequiredLateral, float32(av.ReducedLateralSeparation))
+	}
+	if e.Lateral < 1.49 || e.Lateral > 1.51 {
+		t.Errorf("closest lateral %.2f, expected 1.5", e.Lateral)
+	}
+
+	// Once they're separated, a subsequent loss is a new event.
+	a.Nav.FlightState.Altitude = 7000
+	s.updateScoring()
+	a.Nav.FlightState.Altitude = 5000
+	s.updateScoring()
+	if sc, _ := s.GetScorecard(E2ETCW(), E2ETCW()); sc.Count(ScoreLossOfSeparation) != 2 {
+		t.Errorf("expected a second loss of separation: %+v", sc.Events)
+	}
+
+	// VFR aircraft aren't separated from IFR.
+	s = makeTestSim(t)
+	addTestAircraft(s, "AAL1", math.Point2LL{0, 0}, 5000)
+	vfr := addTestAircraft(s, "N123", math.Point2LL{0, 1.0 / 60}, 5000)
+	vfr.FlightPlan.Rules = av.FlightRulesVFR
+	s.updateScoring()
+	if len(s.Scorecards) != 0 {
+		t.Errorf("unexpected events for IFR/VFR pair: %+v", s.Scorecards)
+	}
+}
+
+func TestScoreMVABust(t *testing.T) {
+	s := makeTestSim(t)
+	s.mvaGrid = av.MakeMVAGrid([]av.MVA{{
+		MinimumLimit: 3000,
+		Bounds:       math.Extent2D{P0: [2]float32{-1, -1}, P1: [2]float32{1, 1}},
+		ExteriorRing: [][2]float32{{-1, -1}, {1, -1}, {1, 1}, {-1, 1}, {-1, -1}},
+	}})
+
+	ac := addTestAircraft(s, "AAL1", math.Point2LL{0, 0}, 2500)
+
+	// Not flagged when flying its own navigation.
+	s.updateScoring()
+	if len(s.Scorecards) != 0 {
+		t.Fatalf("unexpected events for non-vectored aircraft: %+v", s.Scorecards)
+	}
+
+	hdg := math.MagneticHeading(90)
+	ac.Nav.Heading.Assigned = &hdg
+	s.updateScoring()
+	ac.Nav.FlightState.Altitude = 2200
+	s.State.SimTime = s.State.SimTime.Add(5 * time.Second)
+	s.updateScoring()
+
+	sc, _ := s.GetScorecard(E2ETCW(), E2ETCW())
+	if sc.Count(ScoreMVABust) != 1 {
+		t.Fatalf("expected a single MVA bust: %+v", sc.Events)
+	}
+	if e := sc.Events[0]; e.Altitude != 2200 || e.MVA != 3000 || e.Duration != 5*time.Second {
+		t.Errorf("unexpected MVA bust %+v", e)
+	}
+}
+
+func TestScoreAirspace(t *testing.T) {
+	s := makeTestSim(t)
+	vol := func(pts []math.Point2LL) []av.ControllerAirspaceVolume {
+		return []av.ControllerAirspaceVolume{{LowerLimit: 0, UpperLimit: 10000, Boundaries: [][]math.Point2LL{pts}}}
+	}
+	s.State.Airspace = map[ControlPosition]map[string][]av.ControllerAirspaceVolume{
+		"125.0": {"MINE": vol(square(0, 0, 1, 1))},
+		"2A":    {"THEIRS": vol(square(1, 0, 2, 1))},
+	}
+
+	ac := addTestAircraft(s, "AAL1", math.Point2LL{0.9, 0.5}, 5000)
+	s.updateScoring()
+	if len(s.Scorecards) != 0 {
+		t.Fatalf("unexpected events inside own airspace: %+v", s.Scorecards)
+	}
+
+	ac.Nav.FlightState.Position = math.Point2LL{1.1, 0.5}
+	s.updateScoring()
+	s.updateScoring()
+
+	sc, _ := s.GetScorecard(E2ETCW(), E2ETCW())
+	if sc.Count(ScoreLateHandoff) != 1 {
+		t.Errorf("expected a single late handoff: %+v", sc.Events)
+	}
+	if sc.Count(ScoreAirspaceViolation) != 1 {
+		t.Errorf("expected a single airspace violation: %+v", sc.Events)
+	}
+
+	// An approved point out means it's not a violation.
+	s = makeTestSim(t)
+	s.State.Airspace = map[ControlPosition]map[string][]av.ControllerAirspaceVolume{
+		"2A": {"THEIRS": vol(square(1, 0, 2, 1))},
+	}
+	ac = addTestAircraft(s, "AAL1", math.Point2LL{1.1, 0.5}, 5000)
+	ac.NASFlightPlan.AddPointOutHistory("2A")
+	s.updateScoring()
+	if len(s.Scorecards) != 0 {
+		t.Errorf("unexpected events after point out: %+v", s.Scorecards)
+	}
+}
+
+func TestGetScorecardPrivileges(t *testing.T) {
+	s := makeTestSim(t)
+	s.State.CurrentConsolidation["STUDENT"] = &TCPConsolidation{PrimaryTCP: "2A"}
+
+	if _, err := s.GetScorecard(E2ETCW(), "STUDENT"); err != nil {
+		t.Errorf("privileged TCW: unexpected error %v", err)
+	}
+	if _, err := s.GetScorecard("STUDENT", E2ETCW()); !errors.Is(err, ErrNotPrivilegedTCW) {
+		t.Errorf("got %v, expected %v", err, ErrNotPrivilegedTCW)
+	}
+	if _, err := s.GetScorecard("STUDENT", "STUDENT"); err != nil {
+		t.Errorf("own scorecard: unexpected error %v", err)
+	}
+	if _, err := s.GetScorecard(E2ETCW(), "NOBODY"); !errors.Is(err, ErrTCWNotFound) {
+		t.Errorf("got %v, expected %v", err, ErrTCWNotFound)
+	}
+}
@@ -5,6 +5,7 @@
 package sim
 
 import (
+	"encoding/json"
 	"fmt"
 	"log/slog"
 	"maps"
@@ -30,6 +31,11 @@ type Sim struct {
 	State *CommonState
 
 	mu util.LoggingMutex
+	// commandMu is held while a controller's aircraft commands are
+	// recorded and run and while the sim is stepped, so that the commands
+	// run at the sim time they're recorded with. It must be acquired
+	// before mu.
+	commandMu util.LoggingMutex
 
 	Aircraft map[av.ADSBCallsign]*Aircraft
 
@@ -71,6 +77,9 @@ type Sim struct {
 	Handoffs  map[ACID]Handoff
 	PointOuts map[ACID][]PointOut
 
+	// Facilities that are being run in other Sims; see federation.go.
+	Federation map[string]*FederatedFacility
+
 	PrivilegedTCWs map[TCW]bool // TCWs with elevated privileges (can control any aircraft)
 
 	ReportingPoints []av.ReportingPoint
@@ -90,6 +99,11 @@ type Sim struct {
 
 	NextEmergencyTime Time
 
+	// Scripted spawns from the scenario, sorted by time; see timeline.go.
+	Timeline          []TimelineSpawn
+	NextTimelineSpawn int
+	TimelineStart     Time
+
 	PilotErrorInterval time.Duration
 	LastPilotError     Time
 
@@ -128,6 +142,20 @@ type Sim struct {
 
 	AvailableStripCIDs []int
 
+	// Per-TCW scorecards; see scoring.go.
+	Scorecards map[TCW]*Scorecard
+	scoring    scoringState
+
+	// recorder is non-nil when the Sim's inputs are being recorded; see
+	// StartRecording.
+	recorder *recorder
+
+	// Bookkeeping for the autocontroller; see autocontroller.go.
+	autoControl autoController
+
+	// Ring of recent checkpoints, oldest first; see checkpoint.go.
+	checkpoints []checkpoint
+
 	// State publication for server-paced long-poll delivery. pubGen is incremented whenever the
 	// visible sim state changes; pubCh is closed to wake parked GetStateUpdate waiters and is
 	// replaced with a fresh channel after each publication. simDoneCh is closed in Destroy() so
@@ -166,6 +194,7 @@ type NewSimConfiguration struct {
 	VirtualControllers      []TCP
 	ControllerConfiguration *ControllerConfiguration
 	ConfigurationId         string
+	Scenario                string
 
 	TFRs                       []av.TFR
 	FacilityAdaptation         FacilityAdaptation
@@ -192,6 +221,8 @@ type NewSimConfiguration struct {
 
 	Emergencies []Emergency
 
+	Timeline []TimelineSpawn
+
 	HandoffIDs         []HandoffID
 	FixPairs           []FixPairDefinition
 	FixPairAssignments []FixPairAssignment
@@ -238,6 +269,8 @@ func NewSim(config NewSimConfiguration, lg *log.Logger) *Sim {
 
 		NextEmergencyTime: util.Select(config.LaunchConfig.EmergencyAircraftRate > 0, NewSimTime(config.StartTime), Time{}),
 
+		Timeline: config.Timeline,
+
 		lastSimUpdateTime: time.Now(),
 
 		Handoffs:  make(map[ACID]Handoff),
@@ -326,6 +359,8 @@ func (s *Sim) SetWaypointCommands(tcw TCW, commands string) error {
 	s.mu.Lock(s.lg)
 	defer s.mu.Unlock(s.lg)
 
+	s.recordInput(RecordedInput{TCW: tcw, Type: RecordedWaypointCommands, Commands: commands})
+
 	tcp := s.State.PrimaryPositionForTCW(tcw)
 	if s.waypointCommands == nil {
 		s.waypointCommands = make(map[TCP]map[string]string)
@@ -475,6 +510,12 @@ func (s *Sim) Destroy() {
 
 	s.mu.Lock(s.lg)
 	defer s.mu.Unlock(s.lg)
+
+	if s.recorder != nil {
+		if err := s.stopRecording(); err != nil {
+			s.lg.Errorf("%v: error closing recording", err)
+		}
+	}
 	select {
 	case <-s.simDoneCh:
 		// already closed
@@ -518,6 +559,15 @@ func (s *Sim) GetSerializeSim() Sim {
 	return *s
 }
 
+// MarshalState returns the JSON encoding of the Sim that is used for saved
+// sims; unlike encoding the result of GetSerializeSim, it is consistent
+// even if the Sim is running.
+func (s *Sim) MarshalState() ([]byte, error) {
+	s.mu.Lock(s.lg)
+	defer s.mu.Unlock(s.lg)
+	return json.Marshal(s)
+}
+
 func (s *Sim) LogValue() slog.Value {
 	return slog.GroupValue(
 		slog.Any("state", s.State),
@@ -589,6 +639,8 @@ func (s *Sim) SetSimRate(tcw TCW, rate float32) error {
 	s.mu.Lock(s.lg)
 	defer s.mu.Unlock(s.lg)
 
+	s.recordInput(RecordedInput{TCW: tcw, Type: RecordedSimRate, SimRate: rate})
+
 	s.State.SimRate = rate
 	s.lastControlCommandTime = time.Now()
 
@@ -629,12 +681,12 @@ func (s *Sim) prepareRadioTransmissions(tcw TCW, events []Event) []Event {
 		case av.RadioTransmissionContact:
 			// For emergency aircraft, 50% of the time add "emergency aircraft" after heavy/super.
 			// Only on initial contact, not subsequent transmissions.
-			if ac.EmergencyState != nil && s.Rand.Bool() {
+			if ac.IsEmergency() && s.Rand.Bool() {
 				heavySuper += " emergency aircraft"
 			}
 			csArg := av.CallsignArg{
 				Callsign:           ac.ADSBCallsign,
-				IsEmergency:        ac.EmergencyState != nil,
+				IsEmergency:        ac.IsEmergency(),
 				AlwaysFullCallsign: true,
 			}
 			var tr *av.RadioTransmission
@@ -652,7 +704,7 @@ func (s *Sim) prepareRadioTransmissions(tcw TCW, events []Event) []Event {
 		default:
 			csArg := av.CallsignArg{
 				Callsign:    ac.ADSBCallsign,
-				IsEmergency: ac.EmergencyState != nil,
+				IsEmergency: ac.IsEmergency(),
 			}
 			tr := av.MakeReadbackTransmission("{callsign}"+heavySuper, csArg)
 			events[i].WrittenText = e.WrittenText + ", " + tr.Written(s.Rand)
@@ -749,6 +801,11 @@ func (s *Sim) isVirtualController(pos ControlPosition) bool {
 	if _, ok := s.ControlPositions[TCP(pos)]; !ok {
 		return false
 	}
+	if s.federatedFacility(pos) != "" {
+		// Federated facilities' controllers are (presumably) humans in
+		// another Sim.
+		return false
+	}
 	humanPositions := s.ScenarioDefaultConsolidation.AllPositions()
 	return !slices.Contains(humanPositions, TCP(pos))
 }
@@ -757,6 +814,8 @@ func (s *Sim) isVirtualController(pos ControlPosition) bool {
 // Simulation
 
 func (s *Sim) Update() {
+	s.commandMu.Lock(s.lg)
+	defer s.commandMu.Unlock(s.lg)
 	s.mu.Lock(s.lg)
 	defer s.mu.Unlock(s.lg)
 
@@ -934,6 +993,8 @@ func (s *Sim) applyWaypointActionEvent(ac *Aircraft, actions av.WaypointActions)
 // Step advances the simulation by the given elapsed time duration.
 // It acquires the sim mutex for the duration of the step.
 func (s *Sim) Step(elapsed time.Duration) bool {
+	s.commandMu.Lock(s.lg)
+	defer s.commandMu.Unlock(s.lg)
 	s.mu.Lock(s.lg)
 	defer s.mu.Unlock(s.lg)
 	return s.step(elapsed)
@@ -1011,6 +1072,8 @@ func (s *Sim) updateState() {
 				fp.OwningTCW = s.tcwForPosition(fp.TrackingController)
 				fp.HandoffController = ""
 
+				s.sendFederatedReply(NASHandoffAccept, acid, newTrackingController, previousTrackingController)
+
 				if ac != nil {
 					haveTransferComms := slices.ContainsFunc(ac.Nav.Waypoints,
 						func(wp av.Waypoint) bool { return wp.HasTransferCommsAction() })
@@ -1039,12 +1102,14 @@ func (s *Sim) updateState() {
 					slog.String("by", string(po.ToController)), slog.String("to", string(po.FromController)))
 
 				fp.AddPointOutHistory(po.ToController)
+				s.sendFederatedReply(NASPointOutAcknowledge, acid, po.ToController, po.FromController)
 				return false // cull it
 			}
 			return true // keep
 		})
 		if fp == nil || len(s.PointOuts[acid]) == 0 {
 			delete(s.PointOuts, acid)
+			s.pruneFederatedTrack(acid)
 		}
 	}
 
@@ -1052,7 +1117,13 @@ func (s *Sim) updateState() {
 	if now.Sub(s.lastSimUpdate) >= time.Second {
 		s.lastSimUpdate = now
 
-		for callsign, ac := range s.Aircraft {
+		// Visit aircraft in a fixed order so that a Sim's evolution is
+		// reproducible given its Rand state.
+		for callsign, ac := range util.SortedMap(s.Aircraft) {
+			if ac == nil {
+				// Deleted earlier in this pass (e.g., by waypoint commands).
+				continue
+			}
 			if ac.HoldForRelease && !ac.Released {
 				// nvm...
 				continue
@@ -1065,6 +1136,7 @@ func (s *Sim) updateState() {
 			updateResult := ac.Update(s.wxModel, s.State.SimTime, &arrivalMETAR, s.bravoAirspace, nil /* s.lg*/)
 			passedWaypoint := updateResult.PassedWaypoint
 			s.refreshSeenTraffic(ac)
+			s.updateFuel(ac)
 
 			if ac.Nav.Approach.RequestApproachClearance && ac.IsAssociated() {
 				ac.Nav.Approach.RequestApproachClearance = false
@@ -1104,7 +1176,7 @@ func (s *Sim) updateState() {
 			if passedWaypoint != nil {
 				for tcp, wpCommands := range s.waypointCommands {
 					if cmds, ok := wpCommands[passedWaypoint.Fix]; ok {
-						// Moderately hacky: the mutex is held when we get here, but then RunAircraftControlCommands
+						// Moderately hacky: the mutex is held when we get here, but then runAircraftControlCommands
 						// will end up calling methods like Sim AssignAltitude that in turn need to acquire the mutex.
 						// So... we'll just unlock it for now and grab the lock again before we continue.
 						s.mu.Unlock(s.lg)
@@ -1112,7 +1184,7 @@ func (s *Sim) updateState() {
 						// Execute waypoint commands using the waypoint commands controller (typically an instructor)
 						nav.NavLog(string(callsign), s.State.SimTime.NavTime(), nav.NavLogCommand, "aircraft=%s fix=%s commands=%s", callsign, passedWaypoint.Fix, cmds)
 						s.lg.Infof("Waypoint commands: Aircraft %s passed %s, executing: %s", callsign, passedWaypoint.Fix, cmds)
-						result := s.RunAircraftControlCommands(TCW(tcp), callsign, cmds, 0)
+						result := s.runAircraftControlCommands(TCW(tcp), callsign, cmds, 0)
 						if result.Error != nil {
 							nav.NavLog(string(callsign), s.State.SimTime.NavTime(), nav.NavLogCommand, "aircraft=%s error=%v remaining=%s", callsign, result.Error,
 								result.RemainingInput)
@@ -1184,7 +1256,7 @@ func (s *Sim) updateState() {
 						// Determine the runway for sequencing records.
 						var runway string
 						if ac.Nav.Approach.Assigned != nil {
-							runway = ac.Nav.Approach.Assigned.Runway
+							runway = ac.Nav.Approach.LandingRunway()
 						} else {
 							runway = s.bestRunwayForWind(ac.FlightPlan.ArrivalAirport)
 						}
@@ -1221,6 +1293,17 @@ func (s *Sim) updateState() {
 				}
 			}
 
+			s.updateNTZBlunder(ac)
+
+			// Arrivals cleared to a closed runway go around rather than land.
+			if ap := ac.Nav.Approach.Assigned; ap != nil && (ac.Nav.Approach.Cleared || ac.Nav.Approach.LostComms) &&
+				s.runwayIsClosed(ac.FlightPlan.ArrivalAirport, ac.Nav.Approach.LandingRunway()) {
+				if d, err := ac.DistanceToEndOfApproach(); err == nil && d < closedRunwayGoAroundDistance {
+					s.lg.Debug("going around for closed runway")
+					s.goAround(ac)
+				}
+			}
+
 			// Cull any departures withn ~5NM of their first /tc point
 			culled := false
 			if s.prespawnUncontrolledOnly && ac.IsDeparture() && ac.DepartureContactAltitude == 0 {
@@ -1286,8 +1369,13 @@ func (s *Sim) updateState() {
 		s.processFutureTrafficChecks()
 
 		s.updateEmergencies()
+		s.updateHoldingStacks()
+		s.updateMetering()
 
 		s.checkFinalApproachSpacing()
+		s.updateScoring()
+		s.updateAutoControllers()
+		s.updateFederation()
 
 		s.updatePatternPhases()
 		s.relievePatternPressure()
@@ -1310,14 +1398,13 @@ func (s *Sim) updateState() {
 				}
 				old := s.State.METAR[ap]
 				if old.Raw != "" && old.Raw != metar[0].Raw {
-					if cur, ok := s.State.ATISLetter[ap]; ok {
-						s.State.ATISLetter[ap] = string(rune((cur[0]-'A'+1)%26 + 'A'))
-						s.ATISChangedTime[ap] = s.State.SimTime
-					}
+					s.advanceATIS(ap)
 				}
 				s.State.METAR[ap] = metar[0]
 			}
 		}
+
+		s.takeCheckpoint()
 	}
 }
 
@@ -1358,6 +1445,15 @@ func (s *Sim) AddMETARAirport(icao string) error {
 	return nil
 }
 
+// advanceATIS moves the airport's ATIS to the next letter, e.g. after a
+// new METAR or a runway configuration change.
+func (s *Sim) advanceATIS(ap string) {
+	if cur, ok := s.State.ATISLetter[ap]; ok {
+		s.State.ATISLetter[ap] = string(rune((cur[0]-'A'+1)%26 + 'A'))
+		s.ATISChangedTime[ap] = s.State.SimTime
+	}
+}
+
 // loadMETARWindow decodes msoa for icao, appends the 24-hour window of
 // entries starting at-or-before startTime into s.METAR[icao], and sets
 // s.ATISChangedTime[icao] to the first entry's observation time. Returns
@@ -1965,6 +2061,15 @@ func (s *Sim) Facility() string {
 	return s.State.Facility
 }
 
+// Scenario returns the name of the scenario that the sim's current
+// runway configuration comes from.
+func (s *Sim) Scenario() string {
+	s.mu.Lock(s.lg)
+	defer s.mu.Unlock(s.lg)
+
+	return s.State.Scenario
+}
+
 // GetUserState returns a deep copy of the simulation state for a client.
 // Server-only fields (like Airport.Departures) are pruned to reduce bandwidth.
 func (s *Sim) GetUserState() *UserState {
@@ -98,6 +98,9 @@ type LaunchConfig struct {
 
 	GoAroundRate         float32
 	EnableTowerGoArounds bool
+	// Probability that an arrival on a simultaneous approach blunders
+	// into the NTZ.
+	NTZBlunderRate float32
 	// airport -> runway -> category -> rate
 	DepartureRates     map[string]map[av.RunwayID]map[string]float32
 	DepartureRateScale float32
@@ -115,12 +118,16 @@ type LaunchConfig struct {
 	ArrivalPushLengthMinutes    int
 
 	EmergencyAircraftRate float32 // Aircraft per hour
+	// Whether arrivals that are low on fuel declare minimum and emergency
+	// fuel and divert.
+	FuelEmergencies bool
 }
 
 func MakeLaunchConfig(dep []DepartureRunway, vfrRateScale float32, vffRequestRate int32,
 	vfrAirports map[string]*av.Airport, inbound map[string]map[string]float32, haveVFRReportingRegions bool) LaunchConfig {
 	lc := LaunchConfig{
 		GoAroundRate:                0.01,
+		NTZBlunderRate:              0.02,
 		DepartureRateScale:          1,
 		VFRDepartureRateScale:       vfrRateScale,
 		VFRAirportRates:             make(map[string]float32),
@@ -266,6 +273,8 @@ func (s *Sim) SetLaunchConfig(tcw TCW, lc LaunchConfig) error {
 	s.mu.Lock(s.lg)
 	defer s.mu.Unlock(s.lg)
 
+	s.recordInput(RecordedInput{TCW: tcw, Type: RecordedLaunchConfig, LaunchConfig: &lc})
+
 	// Update the next spawn time for any rates that changed.
 	for ap, rwyRates := range lc.DepartureRates {
 		for rwy, categoryRates := range rwyRates {
@@ -594,6 +603,9 @@ func randomInitialWait(rate float32, r *rand.Rand) time.Duration {
 }
 
 func (s *Sim) spawnAircraft() {
+	// Scripted spawns happen regardless of the launch modes.
+	s.spawnTimeline()
+
 	// Spawn each type independently based on its mode
 	if s.State.LaunchConfig.ArrivalMode == LaunchAutomatic ||
 		s.State.LaunchConfig.OverflightMode == LaunchAutomatic {
@@ -63,9 +63,9 @@ func (s *Sim) spawnArrivalsAndOverflights() {
 			var ac *Aircraft
 			var err error
 			if flow == "overflights" {
-				ac, err = s.createOverflightNoLock(group)
+				ac, err = s.createOverflightNoLock(group, nil)
 			} else {
-				ac, err = s.createArrivalNoLock(group, flow)
+				ac, err = s.createArrivalNoLock(group, flow, nil)
 			}
 
 			if err != nil {
@@ -81,7 +81,7 @@ func (s *Sim) spawnArrivalsAndOverflights() {
 func (s *Sim) CreateArrival(arrivalGroup string, arrivalAirport string) (*Aircraft, error) {
 	s.mu.Lock(s.lg)
 	defer s.mu.Unlock(s.lg)
-	ac, err := s.createArrivalNoLock(arrivalGroup, arrivalAirport)
+	ac, err := s.createArrivalNoLock(arrivalGroup, arrivalAirport, nil)
 	if err == nil {
 		s.publish()
 	}
@@ -92,12 +92,13 @@ func (s *Sim) CreateArrival(arrivalGroup string, arrivalAirport string) (*Aircra
 // It selects a random arrival route to the airport, samples an aircraft/airline,
 // initializes the flight plan and navigation, builds the NAS flight plan with
 // controller assignments, optionally sets up a go-around, and registers with STARS.
-func (s *Sim) createArrivalNoLock(group string, arrivalAirport string) (*Aircraft, error) {
+// If tl is non-nil, the route and aircraft are chosen according to it.
+func (s *Sim) createArrivalNoLock(group string, arrivalAirport string, tl *TimelineSpawn) (*Aircraft, error) {
 	// Select a random arrival route that serves this airport
 	arrivals := s.State.InboundFlows[group].Arrivals
 	idx := rand.SampleFiltered(s.Rand, arrivals, func(ar av.Arrival) bool {
 		_, ok := ar.Airlines[arrivalAirport]
-		return ok
+		return ok && tl.MatchesArrival(ar)
 	})
 
 	if idx == -1 {
@@ -105,6 +106,7 @@ func (s *Sim) createArrivalNoLock(group string, arrivalAirport string) (*Aircraf
 			group, arrivalAirport)
 	}
 	arr := arrivals[idx]
+	tl.overrideInitialState(&arr.InitialAltitudes, &arr.InitialSpeed)
 
 	airlines := arr.Airlines[arrivalAirport]
 	if len(airlines) == 0 {
@@ -118,9 +120,12 @@ func (s *Sim) createArrivalNoLock(group string, arrivalAirport string) (*Aircraf
 	if err != nil {
 		return nil, err
 	}
+	if err := s.overrideTimelineAircraft(ac, tl); err != nil {
+		return nil, err
+	}
 
 	err = ac.InitializeArrival(s.State.Airports[arrivalAirport], &arr,
-		s.State.NmPerLongitude, s.State.MagneticVariation, s.wxModel, s.State.SimTime, s.lg)
+		s.State.NmPerLongitude, s.State.MagneticVariation, s.wxModel, s.State.SimTime, s.Rand, s.lg)
 	if err != nil {
 		return nil, err
 	}
@@ -161,6 +166,8 @@ func (s *Sim) createArrivalNoLock(group string, arrivalAirport string) (*Aircraf
 	}
 
 	s.maybeSetGoAround(ac, s.State.LaunchConfig.GoAroundRate)
+	s.maybeSetNTZBlunder(ac, s.State.LaunchConfig.NTZBlunderRate)
+	s.initializeFuel(ac)
 
 	// Decide at creation whether this pilot will spontaneously report field in sight and, among
 	// those, whether they will also request the visual approach. VisualRequestDistance, when set,
@@ -330,7 +337,7 @@ func (s *Sim) maybeSetGoAround(ac *Aircraft, goAroundRate float32) {
 func (s *Sim) CreateOverflight(group string) (*Aircraft, error) {
 	s.mu.Lock(s.lg)
 	defer s.mu.Unlock(s.lg)
-	ac, err := s.createOverflightNoLock(group)
+	ac, err := s.createOverflightNoLock(group, nil)
 	if err == nil {
 		s.publish()
 	}
@@ -340,11 +347,20 @@ func (s *Sim) CreateOverflight(group string) (*Aircraft, error) {
 // createOverflightNoLock creates an overflight aircraft from the specified inbound flow group.
 // It selects a random overflight route, samples an aircraft/airline, initializes the
 // flight plan and navigation, builds the NAS flight plan with controller assignments,
-// and registers with STARS.
-func (s *Sim) createOverflightNoLock(group string) (*Aircraft, error) {
+// and registers with STARS. If tl is non-nil, the route and aircraft are chosen
+// according to it.
+func (s *Sim) createOverflightNoLock(group string, tl *TimelineSpawn) (*Aircraft, error) {
 	// Select a random overflight from the group
 	overflights := s.State.InboundFlows[group].Overflights
-	of := rand.SampleSlice(s.Rand, overflights)
+	var of av.Overflight
+	if tl == nil {
+		of = rand.SampleSlice(s.Rand, overflights)
+	} else if idx := rand.SampleFiltered(s.Rand, overflights, tl.MatchesOverflight); idx == -1 {
+		return nil, fmt.Errorf("unable to find overflight route in %q", group)
+	} else {
+		of = overflights[idx]
+	}
+	tl.overrideInitialState(&of.InitialAltitudes, &of.InitialSpeed)
 
 	if len(of.Airlines) == 0 {
 		return nil, fmt.Errorf("no airlines for overflights in %q", group)
@@ -357,11 +373,15 @@ func (s *Sim) createOverflightNoLock(group string) (*Aircraft, error) {
 	if err != nil {
 		return nil, err
 	}
+	if err := s.overrideTimelineAircraft(ac, tl); err != nil {
+		return nil, err
+	}
 
 	if err := ac.InitializeOverflight(&of, s.State.NmPerLongitude, s.State.MagneticVariation,
-		s.wxModel, s.State.SimTime, s.lg); err != nil {
+		s.wxModel, s.State.SimTime, s.Rand, s.lg); err != nil {
 		return nil, err
 	}
+	s.initializeFuel(ac)
 
 	isTRACON := av.DB.IsTRACON(s.State.Facility)
 	nasFp := s.initNASFlightPlan(ac, av.FlightTypeOverflight)
@@ -213,6 +213,7 @@ func (s *Sim) launchSequencedDeparture(depState *RunwayLaunchState, airport stri
 
 	ac.WaitingForLaunch = false
 	dep.LaunchTime = now
+	s.scoreDepartureLaunch(ac, dep)
 	depState.LastDeparture = &dep
 	depState.Sequenced = depState.Sequenced[1:]
 
@@ -265,6 +266,11 @@ func (s *Sim) canLaunch(depState *RunwayLaunchState, dep DepartureAircraft, cons
 		return false
 	}
 
+	// Nothing departs from a closed runway.
+	if s.runwayIsClosed(airport, string(runway)) {
+		return false
+	}
+
 	// Check if enough time has passed since the last departure
 	if depState.LastDeparture != nil {
 		elapsed := s.State.SimTime.Sub(depState.LastDeparture.LaunchTime)
@@ -309,7 +315,7 @@ func (s *Sim) canLaunch(depState *RunwayLaunchState, dep DepartureAircraft, cons
 	// Check for imminent arrivals on this runway
 	// Skip this check if both arriving and departing aircraft are VFR
 	for _, ac := range s.Aircraft {
-		if ac.Nav.Approach.Assigned != nil && ac.Nav.Approach.Assigned.Runway == runway.Base() {
+		if ac.Nav.Approach.Assigned != nil && ac.Nav.Approach.LandingRunway() == runway.Base() {
 			// Skip if both aircraft are VFR
 			if ac.FlightPlan.Rules == av.FlightRulesVFR && depAc.FlightPlan.Rules == av.FlightRulesVFR {
 				continue
@@ -428,7 +434,7 @@ func (s *Sim) makeNewIFRDeparture(airport string, runway av.RunwayID) (ac *Aircr
 	if rates, ok := s.State.LaunchConfig.DepartureRates[airport][runway]; ok {
 		category, rateSum := sampleRateMap(rates, s.State.LaunchConfig.DepartureRateScale, s.Rand)
 		if rateSum > 0 {
-			ac, err = s.createIFRDepartureNoLock(airport, runway, category)
+			ac, err = s.createIFRDepartureNoLock(airport, runway, category, nil)
 
 			if ac != nil && !ac.HoldForRelease {
 				ac.ReleaseTime = s.State.SimTime
@@ -640,7 +646,7 @@ func (s *Sim) assignDepartureController(ac *Aircraft, nasFp *NASFlightPlan,
 func (s *Sim) CreateIFRDeparture(departureAirport string, runway av.RunwayID, category string) (*Aircraft, error) {
 	s.mu.Lock(s.lg)
 	defer s.mu.Unlock(s.lg)
-	ac, err := s.createIFRDepartureNoLock(departureAirport, runway, category)
+	ac, err := s.createIFRDepartureNoLock(departureAirport, runway, category, nil)
 	if err == nil {
 		s.publish()
 	}
@@ -651,7 +657,8 @@ func (s *Sim) CreateIFRDeparture(departureAirport string, runway av.RunwayID, ca
 // It validates the airport and runway, selects a random departure route, samples an
 // aircraft/airline, initializes the flight plan and navigation, builds the NAS flight
 // plan, assigns controller (handling virtual vs human controllers), and registers with STARS.
-func (s *Sim) createIFRDepartureNoLock(departureAirport string, runway av.RunwayID, category string) (*Aircraft, error) {
+// If tl is non-nil, the exit and aircraft are chosen according to it.
+func (s *Sim) createIFRDepartureNoLock(departureAirport string, runway av.RunwayID, category string, tl *TimelineSpawn) (*Aircraft, error) {
 	// Validate airport exists
 	ap := s.State.Airports[departureAirport]
 	if ap == nil {
@@ -674,7 +681,7 @@ func (s *Sim) createIFRDepartureNoLock(departureAirport string, runway av.Runway
 	idx = rand.SampleFiltered(s.Rand, ap.Departures,
 		func(d av.Departure) bool {
 			_, ok := exitRoutes[d.Exit] // make sure the runway handles the exit
-			return ok && (rwy.Category == "" || rwy.Category == ap.ExitCategories[d.Exit])
+			return ok && (rwy.Category == "" || rwy.Category == ap.ExitCategories[d.Exit]) && tl.MatchesDeparture(d)
 		})
 	if idx == -1 {
 		// This shouldn't ever happen...
@@ -693,10 +700,13 @@ func (s *Sim) createIFRDepartureNoLock(departureAirport string, runway av.Runway
 	if err != nil {
 		return nil, err
 	}
+	if err := s.overrideTimelineAircraft(ac, tl); err != nil {
+		return nil, err
+	}
 
 	exitRoute := exitRoutes[dep.Exit]
 	err = ac.InitializeDeparture(ap, departureAirport, dep, string(runway), *exitRoute, s.State.NmPerLongitude,
-		s.State.MagneticVariation, s.wxModel, s.State.SimTime, s.lg)
+		s.State.MagneticVariation, s.wxModel, s.State.SimTime, s.Rand, s.lg)
 	if err != nil {
 		return nil, err
 	}
@@ -730,6 +740,7 @@ func (s *Sim) createIFRDepartureNoLock(departureAirport string, runway av.Runway
 
 	ac.HoldForRelease = (ap.HoldForRelease || exitRoute.HoldForRelease) && ac.FlightPlan.Rules == av.FlightRulesIFR // VFRs aren't held
 	s.assignDepartureController(ac, &nasFp, ap, exitRoute, departureAirport, string(runway))
+	s.initializeFuel(ac)
 
 	if err := s.assignSquawk(ac, &nasFp); err != nil {
 		return nil, err
@@ -958,7 +969,7 @@ func (s *Sim) createUncontrolledVFRDeparture(depart, arrive, fleet string, route
 	wps[len(wps)-1].SetSequenceVFRLanding(true)
 
 	if err := ac.InitializeVFRDeparture(s.State.Airports[depart], wps, randomizeAltitudeRange,
-		s.State.NmPerLongitude, s.State.MagneticVariation, s.wxModel, simTime, s.lg); err != nil {
+		s.State.NmPerLongitude, s.State.MagneticVariation, s.wxModel, simTime, s.Rand, s.lg); err != nil {
 		return nil, "", err
 	}
 
@@ -1065,15 +1076,30 @@ func (s *Sim) initializeAirspaceGrids() {
 	s.mvaGrid = av.MakeMVAGrid(av.DB.MVAs[s.State.Facility])
 }
 
+// getMVAGrid returns the MVA grid for the sim's facility, creating it the
+// first time it's needed.
+func (s *Sim) getMVAGrid() *av.MVAGrid {
+	if s.mvaGrid == nil {
+		s.mvaGrid = av.MakeMVAGrid(av.DB.MVAs[s.State.Facility])
+	}
+	return s.mvaGrid
+}
+
 // adjustRouteForMVA modifies the waypoint altitude restrictions to ensure
-// the aircraft stays above MVA - vfrMVABuffer along the route.
+// the aircraft stays above MVA - vfrMVABuffer along the route and, where
+// terrain data is available, keeps nav.VFRTerrainClearance above the
+// terrain.
 func (s *Sim) adjustRouteForMVA(callsign string, wps []av.Waypoint) []av.Waypoint {
 	if s.mvaGrid == nil || len(wps) < 2 {
 		return wps
 	}
 
+	terrainAlt := func(p math.Point2LL) float32 {
+		return nav.MinimumVFRAltitude(p, nav.VFRTerrainRadius, s.State.NmPerLongitude)
+	}
+
 	result := make([]av.Waypoint, 0, len(wps)*2)
-	mvaWpNum := 0
+	mvaWpNum, terrainWpNum := 0, 0
 
 	for i, wp := range wps {
 		if i > 0 {
@@ -1084,6 +1110,7 @@ func (s *Sim) adjustRouteForMVA(callsign string, wps []av.Waypoint) []av.Waypoin
 
 			prevMVA := s.mvaGrid.GetMVA(prevWp.Location)
 			prevPos := prevWp.Location
+			prevTerrainAlt := terrainAlt(prevWp.Location)
 
 			for j := range nSamples {
 				// Sample between waypoints, not at them
@@ -1110,14 +1137,33 @@ func (s *Sim) adjustRouteForMVA(callsign string, wps []av.Waypoint) []av.Waypoin
 					result = append(result, mvaWp)
 				}
 
+				// Similarly, climb ahead of rising terrain between the
+				// waypoints.
+				if ta := terrainAlt(pos); ta > prevTerrainAlt+500 {
+					terrainWpNum++
+					terrainWp := av.Waypoint{
+						Fix:      fmt.Sprintf("_terrain%d@%.0f", terrainWpNum, ta),
+						Location: prevPos,
+					}
+					terrainWp.SetAltitudeRestriction(av.MakeAtOrAboveAltitudeRestriction(ta))
+					result = append(result, terrainWp)
+					prevTerrainAlt = ta
+				} else {
+					prevTerrainAlt = min(prevTerrainAlt, ta)
+				}
+
 				prevMVA = mva
 				prevPos = pos
 			}
 		}
 
-		// Apply MVA constraints to this waypoint and add it
+		// Apply MVA and terrain constraints to this waypoint and add it
+		var minAlt float32
 		if mva := s.mvaGrid.GetMVA(wp.Location); mva > 0 {
-			minAlt := min(float32(mva-vfrMVABuffer), maxVFRAltitude)
+			minAlt = min(float32(mva-vfrMVABuffer), maxVFRAltitude)
+		}
+		minAlt = max(minAlt, terrainAlt(wp.Location))
+		if minAlt > 0 {
 			if wp.AltitudeRestriction() == nil {
 				wp.SetAltitudeRestriction(av.MakeAtOrAboveAltitudeRestriction(minAlt))
 			} else {
@@ -233,7 +233,7 @@ func (s *Sim) spawnPatternAircraft() {
 		wps := generatePatternLap(rwy, opp, faaAP.Elevation, s.State.NmPerLongitude, s.State.MagneticVariation)
 
 		err := ac.InitializeVFRDeparture(ap, wps, false, s.State.NmPerLongitude,
-			s.State.MagneticVariation, s.wxModel, now, s.lg)
+			s.State.MagneticVariation, s.wxModel, now, s.Rand, s.lg)
 		if err != nil {
 			s.lg.Warn("failed to initialize pattern aircraft", slog.Any("error", err))
 			ps.NextSpawn = now.Add(randomWait(s.effectivePatternSpawnRate(), false, s.Rand))
@@ -581,8 +581,9 @@ func (s *Sim) finalClear(airport string) bool {
 
 // generateOrbitWaypoints returns 4 waypoints forming a ~1nm-radius left
 // circle centered ~3nm to the right of the runway (opposite the pattern
-// side) near TPA. The center and altitude are randomized slightly so
-// multiple arrivals don't stack on top of each other.
+// side) near TPA, or higher if needed for terrain clearance. The center
+// and altitude are randomized slightly so multiple arrivals don't stack
+// on top of each other.
 func (s *Sim) generateOrbitWaypoints(airport string) []av.Waypoint {
 	rwy, _, ok := s.currentVFRRunway(airport)
 	if !ok {
@@ -594,7 +595,7 @@ func (s *Sim) generateOrbitWaypoints(airport string) []av.Waypoint {
 	}
 
 	// Randomize altitude ±200ft around TPA.
-	tpa := float32(faaAP.Elevation+1000) + float32(s.Rand.Intn(401)-200)
+	alt := float32(faaAP.Elevation+1000) + float32(s.Rand.Intn(401)-200)
 
 	depHdg := math.MagneticToTrue(rwy.Heading, s.State.MagneticVariation)
 	// Right of runway (opposite the left-traffic pattern side).
@@ -608,6 +609,11 @@ func (s *Sim) generateOrbitWaypoints(airport string) []av.Waypoint {
 	center = math.Offset2LL(center, depHdg, alongDist, s.State.NmPerLongitude)
 
 	radius := float32(1) // nm
+
+	// Orbits are away from the runway, so at airports in the mountains
+	// they may need to be above TPA to stay clear of the terrain.
+	alt = max(alt, nav.MinimumVFRAltitude(center, radius, s.State.NmPerLongitude))
+
 	wps := make([]av.Waypoint, 4)
 	for i, hdg := range []math.TrueHeading{0, 90, 180, 270} {
 		p := math.Offset2LL(center, hdg, radius, s.State.NmPerLongitude)
@@ -616,7 +622,7 @@ func (s *Sim) generateOrbitWaypoints(airport string) []av.Waypoint {
 			Location: p,
 			VFRPhase: av.VFRPhaseOrbit,
 		}
-		wp.SetAltitudeRestriction(av.MakeAtAltitudeRestriction(tpa))
+		wp.SetAltitudeRestriction(av.MakeAtAltitudeRestriction(alt))
 		wp.SetSpeedRestriction(av.MakeAtSpeedRestriction(70))
 		wps[i] = wp
 	}
@@ -39,11 +39,19 @@ type DynamicState struct {
 
 	RestrictionAreas map[int]av.RestrictionArea
 
+	ClosedRunways map[string][]string // airport ICAO -> runways closed by an instructor
+
+	HoldingStacks map[string][]HoldingAircraft // fix -> aircraft, lowest altitude first
+
+	MeteringTimelines map[string][]MeteredArrival // airport ICAO -> metered arrivals in STA order
+
 	Paused  bool
 	SimRate float32
 
 	ATPAEnabled     bool                                   // True if ATPA is enabled system-wide
 	ATPAVolumeState map[string]map[string]*ATPAVolumeState // airport -> volumeId -> state
+
+	FederatedFacilities []string // neighboring facilities being run in other Sims
 }
 
 type ATPAVolumeState struct {
@@ -63,6 +71,7 @@ type CommonState struct {
 	VFRRunways        map[string]av.Runway // assume just one runway per airport
 
 	ConfigurationId string // Short identifier for the configuration (from scenario's "configuration" field)
+	Scenario        string // Name of the scenario the configuration comes from
 
 	Airspace map[ControlPosition]map[string][]av.ControllerAirspaceVolume // position -> vol name -> definition
 
@@ -200,6 +209,14 @@ func makeDerivedState(s *Sim) DerivedState {
 			rt.CWTCategory = perf.Category.CWT
 		}
 
+		if ac.Nav.Approach.Assigned != nil {
+			rt.ArrivalRunwayThreshold = landingThreshold(ac)
+			rt.ArrivalRunwayElevation = ac.ArrivalAirportElevation()
+			if rwy, ok := av.LookupRunway(ac.FlightPlan.ArrivalAirport, ac.Nav.Approach.LandingRunway()); ok {
+				rt.ArrivalRunwayElevation = float32(rwy.Elevation)
+			}
+		}
+
 		for _, wp := range ac.Nav.Waypoints {
 			rt.Route = append(rt.Route, wp.Location)
 		}
@@ -254,6 +271,7 @@ func newCommonState(config NewSimConfiguration, startTime time.Time, model *wx.M
 		VFRRunways:  make(map[string]av.Runway),
 
 		ConfigurationId: config.ConfigurationId,
+		Scenario:        config.Scenario,
 
 		DepartureRunways: config.DepartureRunways,
 		ArrivalRunways:   config.ArrivalRunways,
@@ -531,6 +549,8 @@ type Track struct {
 	ArrivalAirport            string
 	ArrivalAirportElevation   float32
 	ArrivalAirportLocation    math.Point2LL
+	ArrivalRunwayThreshold    math.Point2LL // of the runway it will land on; zero if it has no approach
+	ArrivalRunwayElevation    float32
 	FiledRoute                string
 	FiledAltitude             int
 	OnExtendedCenterline      bool
@@ -0,0 +1,228 @@
+// sim/timeline.go
+// Copyright(c) 2022-2026 vice contributors, licensed under the GNU Public License, Version 3.
+// SPDX: GPL-3.0-only
+
+package sim
+
+import (
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	av "github.com/mmp/vice/aviation"
+	"github.com/mmp/vice/util"
+)
+
+// A scenario may include a timeline of scripted spawns, each at a fixed
+// offset from the start of the sim, so that a training scenario presents
+// the same traffic problem at the same time in every session. Timeline
+// spawns happen in addition to the random spawns from the LaunchConfig;
+// scenarios that only want the scripted traffic start with all of the
+// launch modes set to manual.
+
+const (
+	TimelineArrival    = "arrival"
+	TimelineDeparture  = "departure"
+	TimelineOverflight = "overflight"
+)
+
+// TimelineSpawn describes a single scripted spawn in a scenario timeline.
+// Fields that are left unset are sampled as they are for random spawns.
+type TimelineSpawn struct {
+	// AtMinutes gives the spawn time, in minutes after the sim starts.
+	AtMinutes float32 `json:"at_minutes"`
+	// Type is one of TimelineArrival, TimelineDeparture, or
+	// TimelineOverflight.
+	Type string `json:"type"`
+
+	Callsign     string `json:"callsign,omitempty"`
+	AircraftType string `json:"aircraft_type,omitempty"`
+
+	// InboundFlow is required for arrivals and overflights.
+	InboundFlow string `json:"inbound_flow,omitempty"`
+	// Airport is the arrival airport for arrivals and the departure
+	// airport for departures. Runway is the departure runway.
+	Airport string      `json:"airport,omitempty"`
+	Runway  av.RunwayID `json:"runway,omitempty"`
+	// Route optionally selects among the available routes: for arrivals
+	// it is the STAR or the first fix, for overflights the first fix, and
+	// for departures the exit.
+	Route string `json:"route,omitempty"`
+
+	// Altitude and Speed override the initial altitude and speed of
+	// arrivals and overflights.
+	Altitude int     `json:"altitude,omitempty"`
+	Speed    float32 `json:"speed,omitempty"`
+
+	// Emergency is the name of an emergency from emergencies.json; it
+	// begins when the aircraft first contacts a human controller.
+	// PilotMixUp causes the pilot to mix up the first instruction they
+	// are given.
+	Emergency  string `json:"emergency,omitempty"`
+	PilotMixUp bool   `json:"pilot_mixup,omitempty"`
+}
+
+// Offset returns the time after the start of the sim at which the
+// aircraft is spawned.
+func (t *TimelineSpawn) Offset() time.Duration {
+	return time.Duration(t.AtMinutes * float32(time.Minute))
+}
+
+// The following methods are used when creating aircraft and may be called
+// with a nil *TimelineSpawn for random spawns, in which case any route
+// matches and nothing is overridden.
+
+// MatchesArrival reports whether the arrival route may be used for the
+// spawn.
+func (t *TimelineSpawn) MatchesArrival(ar av.Arrival) bool {
+	return t == nil || t.Route == "" || ar.STAR == t.Route ||
+		(len(ar.Waypoints) > 0 && ar.Waypoints[0].Fix == t.Route)
+}
+
+// MatchesOverflight reports whether the overflight route may be used for
+// the spawn.
+func (t *TimelineSpawn) MatchesOverflight(of av.Overflight) bool {
+	return t == nil || t.Route == "" || (len(of.Waypoints) > 0 && of.Waypoints[0].Fix == t.Route)
+}
+
+// MatchesDeparture reports whether the departure may be used for the
+// spawn.
+func (t *TimelineSpawn) MatchesDeparture(dep av.Departure) bool {
+	return t == nil || t.Route == "" || string(dep.Exit) == t.Route || dep.Exit.Base() == t.Route
+}
+
+// overrideInitialState replaces a route's initial altitudes and speed
+// with the ones given in the timeline.
+func (t *TimelineSpawn) overrideInitialState(alts *util.SingleOrArray[int], speed *float32) {
+	if t == nil {
+		return
+	}
+	if t.Altitude != 0 {
+		*alts = util.SingleOrArray[int]{t.Altitude}
+	}
+	if t.Speed != 0 {
+		*speed = t.Speed
+	}
+}
+
+// overrideTimelineAircraft applies the timeline's callsign and aircraft
+// type to a newly-sampled aircraft. It must be called before the
+// aircraft's navigation state is initialized.
+func (s *Sim) overrideTimelineAircraft(ac *Aircraft, t *TimelineSpawn) error {
+	if t == nil {
+		return nil
+	}
+
+	if t.Callsign != "" {
+		if av.CallsignClashesWithExisting(s.currentCallsigns(), t.Callsign, s.EnforceUniqueCallsignSuffix) {
+			return fmt.Errorf("%s: %w", t.Callsign, ErrDuplicateACID)
+		}
+		ac.ADSBCallsign = av.ADSBCallsign(t.Callsign)
+	}
+	if t.AircraftType != "" {
+		if _, ok := av.DB.AircraftPerformance[t.AircraftType]; !ok {
+			return fmt.Errorf("%s: %w", t.AircraftType, ErrUnknownAircraftType)
+		}
+		fp := ac.FlightPlan
+		ac.InitializeFlightPlan(fp.Rules, t.AircraftType, fp.DepartureAirport, fp.ArrivalAirport)
+	}
+	return nil
+}
+
+// spawnTimeline creates the aircraft for all of the timeline spawns that
+// are due. Offsets are measured from the end of prespawn, when the
+// controllers first see the scenario. The caller must hold s.mu.
+func (s *Sim) spawnTimeline() {
+	if s.prespawn || s.NextTimelineSpawn >= len(s.Timeline) {
+		return
+	}
+	if s.TimelineStart.IsZero() {
+		s.TimelineStart = s.State.SimTime
+	}
+
+	for s.NextTimelineSpawn < len(s.Timeline) {
+		t := &s.Timeline[s.NextTimelineSpawn]
+		if s.State.SimTime.Before(s.TimelineStart.Add(t.Offset())) {
+			break
+		}
+
+		// Advance regardless of success so that a bad entry is only
+		// reported once.
+		s.NextTimelineSpawn++
+		if err := s.spawnTimelineAircraft(t); err != nil {
+			s.lg.Errorf("timeline spawn at %.1f minutes: %v", t.AtMinutes, err)
+		}
+	}
+}
+
+func (s *Sim) spawnTimelineAircraft(t *TimelineSpawn) error {
+	var ac *Aircraft
+	var err error
+	switch t.Type {
+	case TimelineArrival:
+		ac, err = s.createArrivalNoLock(t.InboundFlow, t.Airport, t)
+	case TimelineOverflight:
+		ac, err = s.createOverflightNoLock(t.InboundFlow, t)
+	case TimelineDeparture:
+		category, ok := s.timelineDepartureCategory(t)
+		if !ok {
+			return fmt.Errorf("%s/%s: %w", t.Airport, t.Runway, av.ErrUnknownRunway)
+		}
+		ac, err = s.createIFRDepartureNoLock(t.Airport, t.Runway, category, t)
+	default:
+		return fmt.Errorf("%q: unknown timeline spawn type", t.Type)
+	}
+	if err != nil {
+		return err
+	}
+
+	ac.ForcePilotMixUp = t.PilotMixUp
+
+	if t.Type == TimelineDeparture {
+		if !ac.HoldForRelease {
+			ac.ReleaseTime = s.State.SimTime
+		}
+		s.addDepartureToPool(ac, t.Runway, true /* manual launch */)
+	} else {
+		s.addAircraftNoLock(*ac)
+	}
+
+	if t.Emergency != "" {
+		idx := slices.IndexFunc(s.State.Emergencies, func(em Emergency) bool { return em.Name == t.Emergency })
+		if idx == -1 {
+			return fmt.Errorf("%s: unknown emergency", t.Emergency)
+		}
+		if ac, ok := s.Aircraft[ac.ADSBCallsign]; ok {
+			// Unlike random emergencies, scripted ones always wait until
+			// the aircraft checks in with a human controller.
+			ac.EmergencyState = &EmergencyState{Emergency: &s.State.Emergencies[idx], CurrentStage: -1}
+		}
+	}
+
+	s.lg.Info("timeline spawn", slog.String("adsb_callsign", string(ac.ADSBCallsign)),
+		slog.String("type", t.Type), slog.Float64("at_minutes", float64(t.AtMinutes)))
+
+	return nil
+}
+
+// timelineDepartureCategory returns the category of a scenario departure
+// runway that can launch the timeline's departure.
+func (s *Sim) timelineDepartureCategory(t *TimelineSpawn) (string, bool) {
+	ap := s.State.Airports[t.Airport]
+	if ap == nil {
+		return "", false
+	}
+
+	for _, rwy := range s.State.DepartureRunways {
+		if rwy.Airport != t.Airport || rwy.Runway != t.Runway {
+			continue
+		}
+		if rwy.Category == "" || slices.ContainsFunc(ap.Departures, func(dep av.Departure) bool {
+			return t.MatchesDeparture(dep) && ap.ExitCategories[dep.Exit] == rwy.Category
+		}) {
+			return rwy.Category, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,125 @@
+// sim/timeline_test.go
+// Copyright(c) 2022-2026 vice contributors, licensed under the GNU Public License, Version 3.
+// SPDX: GPL-3.0-only
+
+package sim
+
+import (
+	"testing"
+	"time"
+
+	av "github.com/mmp/vice/aviation"
+	"github.com/mmp/vice/math"
+	"github.com/mmp/vice/util"
+)
+
+func TestTimelineSpawnMatches(t *testing.T) {
+	var none *TimelineSpawn
+	ar := av.Arrival{STAR: "CAMRN4", Waypoints: av.WaypointArray{{Fix: "CAMRN"}}}
+	of := av.Overflight{Waypoints: av.WaypointArray{{Fix: "SIE"}}}
+	dep := av.Departure{Exit: "WAVEY.N"}
+
+	if !none.MatchesArrival(ar) || !none.MatchesOverflight(of) || !none.MatchesDeparture(dep) {
+		t.Errorf("a nil timeline spawn should match all routes")
+	}
+
+	for _, route := range []string{"", "CAMRN4", "CAMRN"} {
+		if tl := (&TimelineSpawn{Route: route}); !tl.MatchesArrival(ar) {
+			t.Errorf("%q should match arrival", route)
+		}
+	}
+	if tl := (&TimelineSpawn{Route: "LENDY8"}); tl.MatchesArrival(ar) {
+		t.Errorf("LENDY8 should not match arrival")
+	}
+	if tl := (&TimelineSpawn{Route: "SIE"}); !tl.MatchesOverflight(of) {
+		t.Errorf("SIE should match overflight")
+	}
+	for _, route := range []string{"WAVEY.N", "WAVEY"} {
+		if tl := (&TimelineSpawn{Route: route}); !tl.MatchesDeparture(dep) {
+			t.Errorf("%q should match departure", route)
+		}
+	}
+	if tl := (&TimelineSpawn{Route: "MERIT"}); tl.MatchesDeparture(dep) {
+		t.Errorf("MERIT should not match departure")
+	}
+
+	alts, speed := util.SingleOrArray[int]{9000, 10000}, float32(250)
+	none.overrideInitialState(&alts, &speed)
+	if len(alts) != 2 || speed != 250 {
+		t.Errorf("nil timeline spawn changed initial state: %v %v", alts, speed)
+	}
+	(&TimelineSpawn{Altitude: 7000}).overrideInitialState(&alts, &speed)
+	if len(alts) != 1 || alts[0] != 7000 || speed != 250 {
+		t.Errorf("got %v %v, expected [7000] 250", alts, speed)
+	}
+	(&TimelineSpawn{Speed: 210}).overrideInitialState(&alts, &speed)
+	if len(alts) != 1 || alts[0] != 7000 || speed != 210 {
+		t.Errorf("got %v %v, expected [7000] 210", alts, speed)
+	}
+}
+
+func TestSpawnTimeline(t *testing.T) {
+	s := makeTestSim(t)
+	t0 := NewSimTime(time.Date(2025, time.November, 1, 12, 0, 0, 0, time.UTC))
+	s.State.SimTime = t0
+
+	// The departure airport isn't in the sim, so the spawns fail, but they
+	// should still be consumed in order at their scheduled times.
+	s.Timeline = []TimelineSpawn{
+		{AtMinutes: 0, Type: TimelineDeparture, Airport: "KXXX", Runway: "1"},
+		{AtMinutes: 12, Type: TimelineDeparture, Airport: "KXXX", Runway: "1"},
+		{AtMinutes: 12.5, Type: TimelineDeparture, Airport: "KXXX", Runway: "1"},
+	}
+
+	s.prespawn = true
+	s.spawnTimeline()
+	if s.NextTimelineSpawn != 0 || !s.TimelineStart.IsZero() {
+		t.Fatalf("timeline should not start during prespawn")
+	}
+
+	s.prespawn = false
+	s.spawnTimeline()
+	if !s.TimelineStart.Equal(t0) {
+		t.Errorf("timeline start %s, expected %s", s.TimelineStart, t0)
+	}
+	if s.NextTimelineSpawn != 1 {
+		t.Errorf("expected the first spawn to be consumed, next is %d", s.NextTimelineSpawn)
+	}
+
+	s.State.SimTime = t0.Add(12*time.Minute - time.Second)
+	s.spawnTimeline()
+	if s.NextTimelineSpawn != 1 {
+		t.Errorf("spawned early at %s", s.State.SimTime)
+	}
+
+	s.State.SimTime = t0.Add(12 * time.Minute)
+	s.spawnTimeline()
+	if s.NextTimelineSpawn != 2 {
+		t.Errorf("expected the minute 12 spawn at %s, next is %d", s.State.SimTime, s.NextTimelineSpawn)
+	}
+
+	s.State.SimTime = t0.Add(time.Hour)
+	s.spawnTimeline()
+	if s.NextTimelineSpawn != 3 || !s.TimelineStart.Equal(t0) {
+		t.Errorf("expected all spawns consumed with an unchanged start, next is %d", s.NextTimelineSpawn)
+	}
+}
+
+func TestForcedPilotMixUp(t *testing.T) {
+	s := makeTestSim(t)
+	s.State.SimTime = NewSimTime(time.Date(2025, time.November, 1, 12, 0, 0, 0, time.UTC))
+	s.PilotErrorInterval = 0
+
+	ac := addTestAircraft(s, "AAL1", math.Point2LL{0, 0}, 5000)
+	if s.ShouldTriggerPilotMixUp("AAL1") {
+		t.Errorf("mix-up with pilot errors disabled")
+	}
+
+	ac.ForcePilotMixUp = true
+	if !s.ShouldTriggerPilotMixUp("AAL1") {
+		t.Errorf("expected a scripted mix-up")
+	}
+	if s.ShouldTriggerPilotMixUp("AAL1") {
+		t.Errorf("scripted mix-up should only happen once")
+	}
+}
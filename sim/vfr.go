@@ -5,7 +5,6 @@
 package sim
 
 import (
-	"maps"
 	"slices"
 	"strconv"
 	"time"
@@ -13,6 +12,7 @@ import (
 	av "github.com/mmp/vice/aviation"
 	"github.com/mmp/vice/math"
 	"github.com/mmp/vice/rand"
+	"github.com/mmp/vice/util"
 )
 
 // processInterfacilityVFR handles auto-association of interfacility VFR
@@ -87,8 +87,9 @@ func (s *Sim) TriggerEmergency(name string) {
 
 func (s *Sim) requestRandomFlightFollowing() error {
 	candidates := make(map[*Aircraft]TCP)
+	var order []*Aircraft // candidates in a deterministic order for sampling
 
-	for _, ac := range s.Aircraft {
+	for ac := range util.SortedMapValues(s.Aircraft) {
 		if ac.IsAssociated() || ac.FlightPlan.Rules != av.FlightRulesVFR || ac.RequestedFlightFollowing || !ac.IsAirborne() {
 			continue
 		}
@@ -109,13 +110,16 @@ func (s *Sim) requestRandomFlightFollowing() error {
 			continue
 		}
 
-		for tcpStr, cc := range s.State.FacilityAdaptation.Controllers {
+		for tcpStr, cc := range util.SortedMap(s.State.FacilityAdaptation.Controllers) {
 			tcp := s.State.ResolveController(TCP(tcpStr))
 			if s.isVirtualController(tcp) {
 				continue
 			}
 			for _, vol := range cc.FlightFollowingAirspace {
 				if vol.Inside(ac.Position(), int(ac.Altitude())) {
+					if _, ok := candidates[ac]; !ok {
+						order = append(order, ac)
+					}
 					candidates[ac] = tcp // first come, first served
 					break
 				}
@@ -127,7 +131,7 @@ func (s *Sim) requestRandomFlightFollowing() error {
 		return ErrNoVFRAircraftForFlightFollowing
 	}
 
-	ac, ok := rand.SampleSeq(s.Rand, maps.Keys(candidates))
+	ac, ok := rand.SampleSeq(s.Rand, slices.Values(order))
 	if !ok {
 		return ErrNoVFRAircraftForFlightFollowing
 	}
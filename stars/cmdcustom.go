@@ -38,6 +38,16 @@ func registerCustomCommands() {
 
 	registerCommand(CommandModeNone, ".VFR", func(sp *STARSPane) { sp.showVFRAirports = !sp.showVFRAirports })
 
+	// .AUTO[TCW]: Toggle the autocontroller working an unstaffed TCW
+	registerCommand(CommandModeNone, ".AUTO[TCW]", func(sp *STARSPane, ctx *panes.Context, tcw sim.TCW) error {
+		cons, ok := ctx.Client.State.CurrentConsolidation[tcw]
+		if !ok {
+			return ErrSTARSIllegalTCW
+		}
+		ctx.Client.SetAutoControlled(tcw, !cons.AutoControlled, func(err error) { sp.displayError(err, ctx, "") })
+		return nil
+	})
+
 	// .WIND: Enter wind drawing mode
 	registerCommand(CommandModeNone, ".WIND", func(sp *STARSPane, ctx *panes.Context) CommandStatus {
 		sp.setCommandMode(ctx, CommandModeDrawWind)
@@ -77,7 +77,7 @@ func registerSlewCommands() {
 				}
 			}
 
-			// 7.3 Acknowledge CA / MSAW / SPC / FMA track
+			// 7.3 Acknowledge CA / MSAW / NTZ / SPC / FMA track
 			if idx := slices.IndexFunc(sp.CAAircraft, func(ca CAAircraft) bool {
 				return (ca.ADSBCallsigns[0] == trk.ADSBCallsign || ca.ADSBCallsigns[1] == trk.ADSBCallsign) &&
 					!ca.Acknowledged
@@ -95,6 +95,10 @@ func registerSlewCommands() {
 				state.MSAWAcknowledged = true
 				return CommandStatus{}
 			}
+			if state.NTZ && !state.NTZAcknowledged {
+				state.NTZAcknowledged = true
+				return CommandStatus{}
+			}
 			if state.SPCAlert && !state.SPCAcknowledged {
 				// Acknowledged SPC alert part 1
 				state.SPCAcknowledged = true
@@ -77,6 +77,20 @@ func registerToolsCommands() {
 		return nil
 	})
 
+	// Enable / inhibit final monitoring of simultaneous approaches (NTZ
+	// display and alerts) at this TCW/TDW
+	registerCommand(CommandModeMultiFunc, "NZ", func(sp *STARSPane, ctx *panes.Context, ps *Preferences) (CommandStatus, error) {
+		haveNTZs := false
+		for _, ap := range ctx.Client.State.Airports {
+			haveNTZs = haveNTZs || len(ap.NTZs) > 0
+		}
+		if !haveNTZs {
+			return CommandStatus{}, ErrSTARSIllegalFunction
+		}
+		ps.NTZMonitor = !ps.NTZMonitor
+		return CommandStatus{Output: "NTZ MONITOR " + util.Select(ps.NTZMonitor, "ENABLED", "INHIBITED")}, nil
+	})
+
 	// 6.5.2 Toggle display of ghost data blocks for specified runway pair
 	toggleCRDAGhostsForRunwayPair := func(sp *STARSPane, ctx *panes.Context, ps *Preferences, ap string, idx int) error {
 		if len(sp.CRDAPairs) == 0 {
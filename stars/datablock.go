@@ -906,6 +906,9 @@ func (sp *STARSPane) buildFullDatablock(ctx *panes.Context, trk sim.Track, sfp *
 	// Phase 4: when an alert is active, force altitude here too (normally blank).
 	if alertForceAlt {
 		writeAlt34(3)
+	} else if ma, ok := ctx.Client.State.MeteredArrival(trk.ADSBCallsign); ok {
+		// Metered arrivals show their TBFM delay countdown in minutes.
+		formatDBText(db.field34[3][:], fmtPad(fmt.Sprintf("%+d", ma.DelayMinutes())), color, false)
 	}
 	// Otherwise intentionally blank. In real STARS, all FDB_L2_C1 rules use explicit clock_phase
 	// 1/2/3 guards with no unguarded fallthrough, so phase 4 only shows all-phases overrides
@@ -1496,6 +1499,9 @@ func (sp *STARSPane) haveActiveWarnings(ctx *panes.Context, trk sim.Track) bool
 	if state.MSAW && !state.InhibitMSAW && !sfp.DisableMSAW && !ps.DisableMSAW {
 		return true
 	}
+	if state.NTZ {
+		return true
+	}
 	if trk.IsAssociated() {
 		if spc := sfp.SPCOverride; spc != "" && av.StringIsSPC(spc) /* only alerts, not custom warning SPCs */ {
 			return true
@@ -1577,6 +1583,9 @@ func (sp *STARSPane) getDatablockAlerts(ctx *panes.Context, trk sim.Track, dbtyp
 		if state.MSAW && !state.InhibitMSAW && !sfp.DisableMSAW && !ps.DisableMSAW {
 			addAlert("LA", !state.MSAWAcknowledged, true)
 		}
+		if state.NTZ {
+			addAlert("NTZ", !state.NTZAcknowledged, true)
+		}
 		if spc := sfp.SPCOverride; spc != "" {
 			// squawked SPC takes priority
 			if sqspc, _ := trk.Squawk.IsSPC(); !sqspc || trk.Mode == av.TransponderModeStandby {
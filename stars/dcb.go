@@ -752,7 +752,7 @@ func (sp *STARSPane) drawDCB(ctx *panes.Context, transforms radar.ScopeTransform
 		ssaButton("CRDA", &ps.SSAList.Filter.ActiveCRDAPairs)
 		sp.unsupportedButton(ctx, "FLOW", buttonHalfVertical, buttonScale) // TODO
 		sp.unsupportedButton(ctx, "AMZ", buttonHalfVertical, buttonScale)  // TODO
-		sp.unsupportedButton(ctx, "TBFM", buttonHalfVertical, buttonScale) // TODO
+		ssaButton("TBFM", &ps.SSAList.Filter.TBFM)
 		if sp.selectButton(ctx, "DONE", buttonFull, buttonScale) {
 			sp.setCommandMode(ctx, CommandModeNone)
 		} else {
@@ -777,6 +777,25 @@ func (sp *STARSPane) drawSSAList(ctx *panes.Context, pw [2]float32, listStyle re
 		}
 	}
 
+	if filter.All || filter.TBFM {
+		for icao, tl := range util.SortedMap(ctx.Client.State.MeteringTimelines) {
+			ap, ok := ctx.Client.State.Airports[icao]
+			if !ok || ap.Metering == nil {
+				continue
+			}
+			text := "TBFM " + stripPrefix(icao) + " AAR " + strconv.Itoa(ap.Metering.AcceptanceRate)
+			pw = td.AddText(text, pw, listStyle)
+			newline()
+
+			// Show the next few arrivals to cross the meter fixes.
+			for _, ma := range tl[:min(len(tl), 5)] {
+				text := fmt.Sprintf(" %-7s %-5s %s %+d", ma.ADSBCallsign, ma.MeterFix, ma.STA.UTC().Format("1504"), ma.DelayMinutes())
+				pw = td.AddText(text, pw, listStyle)
+				newline()
+			}
+		}
+	}
+
 	maxX = max(maxX, pw[0])
 	bounds := math.Extent2D{
 		P0: [2]float32{startX, pw[1]},
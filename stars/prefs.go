@@ -128,6 +128,10 @@ type Preferences struct {
 		ForceAllGhosts  bool
 	}
 
+	// NTZMonitor enables final monitoring of simultaneous approaches:
+	// NTZs are drawn and tracks that enter them are alerted.
+	NTZMonitor bool
+
 	DisplayLDBBeaconCodes bool // TODO: default?
 	SelectedBeacons       []av.Squawk
 
@@ -241,6 +245,7 @@ type CommonPreferences struct {
 			ActiveCRDAPairs     bool
 			WxHistory           bool
 			Consolidation       bool
+			TBFM                bool
 			GIText              [10]bool
 		}
 	}
@@ -1310,6 +1310,7 @@ func (sp *STARSPane) Draw(ctx *panes.Context, cb *renderer.CommandBuffer) {
 	sp.drawScenarioRoutes(ctx, transforms, sp.systemFont(ctx, ps.CharSize.Tools), cb)
 
 	sp.drawCRDARegions(ctx, transforms, cb)
+	sp.drawNTZs(ctx, transforms, cb)
 	sp.drawSelectedRoute(ctx, transforms, cb)
 	sp.drawPlotPoints(ctx, transforms, cb)
 	sp.drawWind(ctx, transforms, cb)
@@ -1754,6 +1755,36 @@ func (sp *STARSPane) drawCRDARegions(ctx *panes.Context, transforms radar.ScopeT
 	}
 }
 
+// drawNTZs draws the NTZs at the arrival airports when final monitoring
+// is enabled.
+func (sp *STARSPane) drawNTZs(ctx *panes.Context, transforms radar.ScopeTransformations, cb *renderer.CommandBuffer) {
+	ps := sp.currentPrefs()
+	if !ps.NTZMonitor {
+		return
+	}
+
+	transforms.LoadLatLongViewingMatrices(cb)
+	ld := renderer.GetLinesDrawBuilder()
+	defer renderer.ReturnLinesDrawBuilder(ld)
+
+	for _, icao := range util.SortedMapKeys(ctx.Client.State.ArrivalAirports) {
+		ap, ok := ctx.Client.State.Airports[icao]
+		if !ok {
+			continue
+		}
+		for _, ntz := range ap.NTZs {
+			poly := make([][2]float32, len(ntz.Polygon))
+			for i, p := range ntz.Polygon {
+				poly[i] = p
+			}
+			ld.AddLineLoop(poly)
+		}
+	}
+
+	cb.SetRGB(ps.Brightness.Lines.ScaleRGB(sp.Colors.ATPAAlert))
+	ld.GenerateCommands(cb)
+}
+
 func (sp *STARSPane) drawMouseCursor(ctx *panes.Context, mouseOverDCB bool) {
 	if mouseOverDCB {
 		// panes/display.go already called ClearCursorOverride this frame, so
@@ -2091,6 +2122,11 @@ func (sp *STARSPane) updateAudio(ctx *panes.Context) {
 					ctx.InterpolatedSimTime.Before(ca.SoundEnd)
 			})
 	}
+	// NTZ penetrations use the CA alarm as well.
+	playCASound = playCASound || slices.ContainsFunc(sp.visibleTracks, func(trk sim.Track) bool {
+		state := sp.TrackState[trk.ADSBCallsign]
+		return state.NTZ && !state.NTZAcknowledged && ctx.InterpolatedSimTime.Before(state.NTZSoundEnd)
+	})
 	updateContinuous(playCASound, AudioConflictAlert)
 
 	playMSAWSound := !ps.DisableMSAW && func() bool {
@@ -93,6 +93,11 @@ type TrackState struct {
 	MSAWAcknowledged bool
 	MSAWSoundEnd     sim.Time
 
+	NTZ             bool // track is inside an NTZ between parallel finals
+	NTZStart        sim.Time
+	NTZAcknowledged bool
+	NTZSoundEnd     sim.Time
+
 	SPCAlert        bool
 	SPCAcknowledged bool
 	SPCSoundEnd     sim.Time
@@ -443,11 +448,6 @@ func (sp *STARSPane) isQuicklooked(ctx *panes.Context, trk sim.Track) bool {
 func (sp *STARSPane) updateMSAWs(ctx *panes.Context) {
 	for _, trk := range sp.visibleTracks {
 		state := sp.TrackState[trk.ADSBCallsign]
-		if !trk.MVAsApply {
-			state.MSAW = false
-			continue
-		}
-
 		if trk.IsUnassociated() {
 			// No MSAW for unassociated tracks.
 			state.MSAW = false
@@ -470,8 +470,17 @@ func (sp *STARSPane) updateMSAWs(ctx *panes.Context) {
 			continue
 		}
 
-		mva := sp.mvaGrid.GetMVA(state.track.Location)
-		warn := mva > 0 && alt < mva
+		var warn bool
+		if trk.MVAsApply {
+			// General terrain monitoring: the track is below the MVA or
+			// is predicted to get too close to the terrain.
+			mva := sp.mvaGrid.GetMVA(state.track.Location)
+			warn = (mva > 0 && alt < mva) ||
+				predictTerrainConflict(av.DB.Terrain, trk, state, alt, ctx.NmPerLongitude, ctx.MagneticVariation)
+		} else {
+			// Approach path monitoring for aircraft on final.
+			warn = approachPathConflict(av.DB.Terrain, trk, state, alt)
+		}
 
 		if !warn && state.InhibitMSAW {
 			// The warning has cleared, so the inhibit is disabled (p.7-25)
@@ -487,6 +496,100 @@ func (sp *STARSPane) updateMSAWs(ctx *panes.Context) {
 	}
 }
 
+const (
+	// MSAW terrain prediction extrapolates tracks this far ahead (in
+	// seconds) and alerts if they'll be less than msawTerrainClearance
+	// feet above the terrain.
+	msawTerrainLookahead = 30
+	msawTerrainClearance = 500
+	// Terrain prediction isn't done for tracks this close (nm) to their
+	// departure or arrival airport, where they are legitimately close to
+	// the ground.
+	msawAirportRadius = 5
+
+	// Approach path monitoring covers arrivals this far out (nm) from
+	// their runway's threshold and alerts if they're below a
+	// msawApproachPathAngle degree path to it.
+	msawApproachPathDistance = 10
+	msawApproachPathAngle    = 2
+)
+
+// predictTerrainConflict extrapolates the track's position and altitude
+// using its current ground track and vertical rate and returns true if it
+// will be too close to the terrain within msawTerrainLookahead seconds.
+// Unlike MVAs, this uses the terrain DEM and so only applies where it is
+// available.
+func predictTerrainConflict(terrain *av.Terrain, trk sim.Track, state *TrackState, alt int,
+	nmPerLongitude, magneticVariation float32) bool {
+	loc := state.track.Location
+	if (trk.DepartureAirport != "" && math.NMDistance2LL(loc, trk.DepartureAirportLocation) < msawAirportRadius) ||
+		(trk.ArrivalAirport != "" && math.NMDistance2LL(loc, trk.ArrivalAirportLocation) < msawAirportRadius) {
+		return false
+	}
+
+	// The heading vector gives the distance covered in a minute; the
+	// vertical rate is in feet per second.
+	v := state.HeadingVector(nmPerLongitude, magneticVariation)
+	var rate float32
+	if dt := state.trackTime.Sub(state.previousTrackTime).Seconds(); state.HaveHeading() && dt > 0 {
+		rate = float32(state.TrackDeltaAltitude()) / float32(dt)
+	}
+
+	for t := 0; t <= msawTerrainLookahead; t += 5 {
+		p := math.Add2LL(loc, math.Point2LL(math.Scale2f(v, float32(t)/60)))
+		elev := terrain.Elevation(p)
+		if elev > 0 && float32(alt)+rate*float32(t) < float32(elev+msawTerrainClearance) {
+			return true
+		}
+	}
+	return false
+}
+
+// approachPathConflict returns true if an arrival on approach is below a
+// shallow glidepath to the threshold of the runway it's landing on, which
+// catches aircraft that are descending prematurely where MVAs no longer
+// apply.
+func approachPathConflict(terrain *av.Terrain, trk sim.Track, state *TrackState, alt int) bool {
+	threshold := trk.ArrivalRunwayThreshold
+	if threshold.IsZero() || !terrain.Covers(threshold) {
+		// Approach path monitoring is only adapted along with the
+		// terrain and needs to know which runway the arrival is landing on.
+		return false
+	}
+
+	d := math.NMDistance2LL(state.track.Location, threshold)
+	if d > msawApproachPathDistance || d < 1 {
+		// Too far out to be monitored or too close to the runway to alert.
+		return false
+	}
+
+	const feetPerNM = 6076.12
+	minAlt := trk.ArrivalRunwayElevation + d*feetPerNM*math.Tan(math.Radians(float32(msawApproachPathAngle)))
+	return float32(alt) < minAlt
+}
+
+// updateNTZAlerts checks arrivals to airports with NTZs defined to see
+// if they have entered one when final monitoring is enabled.
+func (sp *STARSPane) updateNTZAlerts(ctx *panes.Context) {
+	ps := sp.currentPrefs()
+	for _, trk := range sp.visibleTracks {
+		state := sp.TrackState[trk.ADSBCallsign]
+
+		inside := false
+		if ap, ok := ctx.Client.State.Airports[trk.ArrivalAirport]; ok && ps.NTZMonitor && trk.IsAssociated() {
+			inside = slices.ContainsFunc(ap.NTZs, func(ntz av.NTZ) bool { return ntz.Inside(state.track.Location) })
+		}
+
+		if inside && !state.NTZ {
+			// It's a new alert
+			state.NTZAcknowledged = false
+			state.NTZSoundEnd = ctx.InterpolatedSimTime.Add(AlertAudioDuration)
+			state.NTZStart = ctx.InterpolatedSimTime
+		}
+		state.NTZ = inside
+	}
+}
+
 func (sp *STARSPane) updateRadarTracks(ctx *panes.Context) {
 	// FIXME: all aircraft radar tracks are updated at the same time.
 	fa := ctx.Client.State.FacilityAdaptation
@@ -524,6 +627,7 @@ func (sp *STARSPane) updateRadarTracks(ctx *panes.Context) {
 
 	// Update low altitude alerts now that we have updated tracks
 	sp.updateMSAWs(ctx)
+	sp.updateNTZAlerts(ctx)
 
 	// History tracks are updated after a radar track update, only if
 	// H_RATE seconds have elapsed (4-94).
@@ -0,0 +1,186 @@
+// stars/track_test.go
+// Copyright(c) 2022-2026 vice contributors, licensed under the GNU Public License, Version 3.
+// SPDX: GPL-3.0-only
+
+package stars
+
+import (
+	"archive/zip"
+	"bytes"
+	"testing"
+	"time"
+
+	av "github.com/mmp/vice/aviation"
+	"github.com/mmp/vice/math"
+	"github.com/mmp/vice/sim"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// The test terrain is the N35W107 tile; these tests are all around its
+// center.
+const testNmPerLongitude = 49
+
+var testTerrainCenter = math.Point2LL{-106.5, 35.5}
+
+// makeTestTerrain returns a DEM with a single tile where the elevation
+// is given by the elev callback for each cell's center.
+func makeTestTerrain(t *testing.T, elev func(p math.Point2LL) int16) *av.Terrain {
+	t.Helper()
+
+	const n = av.TerrainSamplesPerDegree
+	samples := make([]int16, n*n)
+	for row := range n {
+		for col := range n {
+			samples[row*n+col] = elev(math.Point2LL{-107 + (float32(col)+0.5)/n, 35 + (float32(row)+0.5)/n})
+		}
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	w, err := zw.Create(av.TerrainTileName(35, -107) + ".zst")
+	if err != nil {
+		t.Fatal(err)
+	}
+	enc, err := zstd.NewWriter(w)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := enc.Write(av.EncodeTerrainTile(samples)); err != nil {
+		t.Fatal(err)
+	}
+	if err := enc.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	terrain, err := av.MakeTerrain(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return terrain
+}
+
+// makeTestTrackState returns the state for a track that has flown from
+// 0.25nm behind p to p on the given heading over the last 5 seconds,
+// changing altitude at the given rate in feet per minute.
+func makeTestTrackState(p math.Point2LL, hdg math.TrueHeading, alt int, fpm int) *TrackState {
+	t0 := sim.NewSimTime(time.Date(2026, time.March, 1, 12, 0, 0, 0, time.UTC))
+	return &TrackState{
+		track: av.RadarTrack{
+			Location:            p,
+			TransponderAltitude: float32(alt),
+			Groundspeed:         180,
+		},
+		trackTime: t0.Add(5 * time.Second),
+		previousTrack: av.RadarTrack{
+			Location:            math.Offset2LL(p, math.OppositeHeading(hdg), 0.25, testNmPerLongitude),
+			TransponderAltitude: float32(alt - fpm/12),
+		},
+		previousTrackTime: t0,
+	}
+}
+
+func TestPredictTerrainConflict(t *testing.T) {
+	// 6000' everywhere except for a 9000' ridge 1.25nm north of the
+	// center, which fills the row of DEM cells from 1nm to 1.5nm north.
+	ridge := math.Offset2LL(testTerrainCenter, 0, 1.25, testNmPerLongitude)
+	terrain := makeTestTerrain(t, func(p math.Point2LL) int16 {
+		if math.Abs(p[1]-ridge[1]) < 0.25/math.NMPerLatitude {
+			return 9000
+		}
+		return 6000
+	})
+
+	for _, tc := range []struct {
+		name string
+		hdg  math.TrueHeading
+		alt  int
+		fpm  int
+		trk  sim.Track
+		warn bool
+	}{
+		{name: "level above the ridge", hdg: 360, alt: 10000},
+		{name: "level toward the ridge", hdg: 360, alt: 9200, warn: true},
+		{name: "level away from the ridge", hdg: 180, alt: 9200},
+		{name: "level above the terrain", hdg: 180, alt: 6800},
+		{name: "descending toward the terrain", hdg: 180, alt: 6800, fpm: -1200, warn: true},
+		{name: "level short of the ridge", hdg: 360, alt: 8800, warn: true},
+		{name: "climbing over the ridge", hdg: 360, alt: 8800, fpm: 4000},
+		{name: "near the arrival airport", hdg: 360, alt: 9200,
+			trk: sim.Track{ArrivalAirport: "KTST", ArrivalAirportLocation: ridge}},
+		{name: "near the departure airport", hdg: 360, alt: 9200,
+			trk: sim.Track{DepartureAirport: "KTST", DepartureAirportLocation: testTerrainCenter}},
+	} {
+		state := makeTestTrackState(testTerrainCenter, tc.hdg, tc.alt, tc.fpm)
+		if warn := predictTerrainConflict(terrain, tc.trk, state, tc.alt, testNmPerLongitude, 0); warn != tc.warn {
+			t.Errorf("%s: got %v, expected %v", tc.name, warn, tc.warn)
+		}
+	}
+
+	// Nothing to predict without a DEM.
+	state := makeTestTrackState(testTerrainCenter, 360, 9200, 0)
+	if predictTerrainConflict(nil, sim.Track{}, state, 9200, testNmPerLongitude, 0) {
+		t.Errorf("alert without terrain data")
+	}
+}
+
+func TestApproachPathConflict(t *testing.T) {
+	terrain := makeTestTerrain(t, func(p math.Point2LL) int16 { return 5000 })
+
+	// Runway 36's threshold is at the center of the tile and the airport
+	// reference point is 2nm north of it.
+	threshold := testTerrainCenter
+	trk := sim.Track{
+		ArrivalAirport:          "KTST",
+		ArrivalAirportElevation: 5100,
+		ArrivalAirportLocation:  math.Offset2LL(threshold, 0, 2, testNmPerLongitude),
+		ArrivalRunwayThreshold:  threshold,
+		ArrivalRunwayElevation:  5000,
+	}
+	final := func(d float32) *TrackState {
+		return makeTestTrackState(math.Offset2LL(threshold, 180, d, testNmPerLongitude), 360, 0, 0)
+	}
+
+	// 5nm from the threshold, the 2 degree path is at ~6061'.
+	for _, tc := range []struct {
+		name string
+		d    float32
+		alt  int
+		warn bool
+	}{
+		{name: "above the path", d: 5, alt: 6200},
+		{name: "below the path", d: 5, alt: 5900, warn: true},
+		{name: "too far out", d: 12, alt: 5500},
+		{name: "short final", d: 0.5, alt: 5050},
+	} {
+		if warn := approachPathConflict(terrain, trk, final(tc.d), tc.alt); warn != tc.warn {
+			t.Errorf("%s: got %v, expected %v", tc.name, warn, tc.warn)
+		}
+	}
+
+	// Measuring from the airport reference point, 7nm away, would put
+	// the path at ~6585' and alert for an aircraft at 6200' that's above
+	// the path to the threshold.
+	if approachPathConflict(terrain, trk, final(5), 6200) {
+		t.Errorf("path should be measured from the runway threshold")
+	}
+
+	// No alerts without a runway or terrain data.
+	noRunway := trk
+	noRunway.ArrivalRunwayThreshold = math.Point2LL{}
+	if approachPathConflict(terrain, noRunway, final(5), 5500) {
+		t.Errorf("alert without a known runway")
+	}
+	if approachPathConflict(nil, trk, final(5), 5500) {
+		t.Errorf("alert without terrain data")
+	}
+	elsewhere := trk
+	elsewhere.ArrivalRunwayThreshold = math.Point2LL{-105.5, 35.5}
+	if approachPathConflict(terrain, elsewhere, makeTestTrackState(math.Offset2LL(elsewhere.ArrivalRunwayThreshold,
+		180, 5, testNmPerLongitude), 360, 0, 0), 5500) {
+		t.Errorf("alert for a runway outside of the DEM")
+	}
+}
@@ -74,6 +74,7 @@ type Aircraft struct {
 	TrackingController        string                     // Controller tracking this aircraft (from flight plan)
 	AddressingForm            sim.CallsignAddressingForm // How this aircraft was addressed (based on which key matched)
 	LAHSORunways              []string                   // Runways that intersect the approach runway (for LAHSO matching)
+	Runways                   []string                   // Arrival airport runways (for circling and sidestep matching)
 }
 
 // findWeightClassTokenIndex checks the early tokens (callsign region) for "heavy" or "super".
@@ -52,6 +52,8 @@ var categoryRules = []categoryRule{
 	{match: func(cmd string) bool {
 		return cmd[0] == 'D' && strings.Contains(cmd, "/H")
 	}, category: "depart_heading"},
+	// DIH → altitude (descend in hold)
+	{match: func(cmd string) bool { return cmd == "DIH" }, category: "altitude"},
 	// D + letter → navigation (direct-to-fix)
 	{match: func(cmd string) bool { return cmd[0] == 'D' }, category: "navigation"},
 	// A with / → cleared_approach (at fix cleared approach: AHOLID/CI0L, AHOLID/I)
@@ -60,6 +62,8 @@ var categoryRules = []categoryRule{
 	}, category: "cleared_approach"},
 	// A + letter → navigation
 	{match: func(cmd string) bool { return cmd[0] == 'A' }, category: "navigation"},
+	// C with /CIR or /SS → cleared_approach (circle to land or sidestep: CI4R/CIR31, CI22L/SS22R)
+	{match: isManeuveringApproachClearance, category: "cleared_approach"},
 	// C with / → crossing (cross fix at altitude/speed/mach: CFIX/A40, CFIX/S250, CFIX/M80)
 	{match: func(cmd string) bool {
 		return cmd[0] == 'C' && strings.Contains(cmd, "/")
@@ -72,6 +76,10 @@ var categoryRules = []categoryRule{
 	{match: func(cmd string) bool { return cmd[0] == 'S' }, category: "speed"},
 	// H → heading
 	{match: func(cmd string) bool { return cmd[0] == 'H' }, category: "heading"},
+	// B → heading (breakout: BL270/C30)
+	{match: func(cmd string) bool { return cmd[0] == 'B' }, category: "heading"},
+	// EFC → efc (expect further clearance time)
+	{match: func(cmd string) bool { return strings.HasPrefix(cmd, "EFC") }, category: "efc"},
 	// E → expect_approach
 	{match: func(cmd string) bool { return cmd[0] == 'E' }, category: "expect_approach"},
 }
@@ -1639,6 +1647,72 @@ func matchLAHSORunway(tokens []Token, lahsoRunways []string) (string, int) {
 	return "", 0
 }
 
+// isManeuveringApproachClearance reports whether cmd is an approach
+// clearance with a circle-to-land or sidestep runway (e.g., "CI4R/CIR31"),
+// which otherwise looks like a cross-fix command.
+func isManeuveringApproachClearance(cmd string) bool {
+	return strings.HasPrefix(cmd, "C") && (strings.Contains(cmd, "/CIR") || strings.Contains(cmd, "/SS"))
+}
+
+// extractApproachManeuver extracts a circle-to-land or sidestep runway that
+// follows an approach in a clearance: "circle to land runway 31" or
+// "sidestep runway 22 right". Returns the suffix for the approach command
+// (e.g., "/CIR31" or "/SS22R") and the number of tokens consumed.
+func extractApproachManeuver(tokens []Token, runways []string) (string, int) {
+	if len(tokens) == 0 || len(runways) == 0 {
+		return "", 0
+	}
+
+	// Skip a trailing "approach" and conjunctions: "ILS 4R approach, then circle..."
+	idx := 0
+	for idx < len(tokens) {
+		text := strings.ToLower(tokens[idx].Text)
+		if text == "approach" || text == "and" || text == "then" {
+			idx++
+			continue
+		}
+		break
+	}
+	if idx >= len(tokens) {
+		return "", 0
+	}
+
+	var suffix string
+	text := strings.ToLower(tokens[idx].Text)
+	switch {
+	case text == "circle" || text == "circling" || FuzzyMatch(text, "circle", 0.8):
+		suffix = "/CIR"
+		idx++
+	case text == "side" && idx+1 < len(tokens) && strings.ToLower(tokens[idx+1].Text) == "step":
+		suffix = "/SS"
+		idx += 2
+	case text == "sidestep" || FuzzyMatch(text, "sidestep", 0.8):
+		suffix = "/SS"
+		idx++
+	default:
+		return "", 0
+	}
+
+	// Skip fillers before the runway: "circle to land runway", "sidestep to runway"
+	for idx < len(tokens) {
+		text := strings.ToLower(tokens[idx].Text)
+		if text == "to" || text == "land" || text == "for" || text == "runway" || text == "the" {
+			idx++
+			continue
+		}
+		break
+	}
+	if idx >= len(tokens) {
+		return "", 0
+	}
+
+	if rwy, consumed := matchLAHSORunway(tokens[idx:], runways); rwy != "" {
+		logLocalStt("  extractApproachManeuver: %s%s", suffix, rwy)
+		return suffix + rwy, idx + consumed
+	}
+	return "", 0
+}
+
 // spokenRunway returns the spoken form of a runway (e.g., "31L" -> "three one left")
 func spokenRunway(rwy string) string {
 	var parts []string
@@ -1944,6 +2018,35 @@ func extractSquawk(tokens []Token) (string, int) {
 	return "", 0
 }
 
+// extractEFCTime extracts an HHMM expect-further-clearance time, given
+// either as individual digits ("one two three zero") or as a number.
+func extractEFCTime(tokens []Token) (int, int) {
+	var hhmm strings.Builder
+	consumed := 0
+
+	for consumed < len(tokens) && hhmm.Len() < 4 {
+		t := tokens[consumed]
+		if IsDigit(t.Text) {
+			hhmm.WriteString(t.Text)
+			consumed++
+		} else if t.Type == TokenNumber && t.Value >= 0 && t.Value <= 2359 && hhmm.Len() == 0 {
+			fmt.Fprintf(&hhmm, "%04d", t.Value)
+			consumed++
+		} else {
+			break
+		}
+	}
+
+	if hhmm.Len() != 4 {
+		return 0, 0
+	}
+	v, err := strconv.Atoi(hhmm.String())
+	if err != nil || v/100 > 23 || v%100 > 59 {
+		return 0, 0
+	}
+	return v, consumed
+}
+
 // extractDegrees extracts a degree turn value and direction.
 // Uses word order to disambiguate: "turn 20 left" is a degrees turn,
 // but "turn left 20" is interpreted as heading (direction before number).
@@ -2532,9 +2635,6 @@ func extractHoldParams(tokens []Token, fix string) (string, int) {
 		}
 	}
 
-	// Skip "expect further clearance" and any following digits if present
-	consumed = skipExpectFurtherClearance(tokens, consumed)
-
 	// Build the command string: HFIX/Rradial/minutesM/L or R
 	// Format from parseHold: HFIX/R{radial}/{minutes}M/{L|R}
 	var cmd strings.Builder
@@ -2553,37 +2653,6 @@ func extractHoldParams(tokens []Token, fix string) (string, int) {
 	return cmd.String(), consumed
 }
 
-// skipExpectFurtherClearance skips "expect further clearance" and any following digits.
-// Returns the new consumed position.
-func skipExpectFurtherClearance(tokens []Token, start int) int {
-	consumed := start
-
-	// Skip filler words first
-	for consumed < len(tokens) && IsFillerWord(strings.ToLower(tokens[consumed].Text)) {
-		consumed++
-	}
-
-	// Look for "expect further clearance" pattern
-	if consumed+2 < len(tokens) {
-		t1 := strings.ToLower(tokens[consumed].Text)
-		t2 := strings.ToLower(tokens[consumed+1].Text)
-		t3 := strings.ToLower(tokens[consumed+2].Text)
-		if t1 == "expect" && t2 == "further" && t3 == "clearance" {
-			consumed += 3
-			// Skip any following digits (the time, e.g., "1 2 3 0")
-			for consumed < len(tokens) {
-				if tokens[consumed].Type == TokenNumber || IsDigit(tokens[consumed].Text) {
-					consumed++
-				} else {
-					break
-				}
-			}
-		}
-	}
-
-	return consumed
-}
-
 // speedUntilResult represents the result of extracting a speed "until" specification.
 type speedUntilResult struct {
 	suffix string // e.g., "ROSLY", "5DME", "6"
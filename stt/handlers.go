@@ -69,7 +69,15 @@ func registerAllCommands() {
 		WithPriority(12),
 	)
 
-	// Absorb "expect further clearance" so it doesn't trigger "expect approach"
+	// "expect further clearance [at] 1530" issues an EFC time to a holding
+	// aircraft. Without a time, absorb the phrase so it doesn't trigger
+	// "expect approach".
+	registerSTTCommand(
+		"expect further clearance [at] {efc_time}",
+		func(hhmm int) string { return fmt.Sprintf("EFC%04d", hhmm) },
+		WithName("expect_further_clearance_time"),
+		WithPriority(26),
+	)
 	registerSTTCommand(
 		"expect further clearance",
 		func() string { return "" },
@@ -219,6 +227,13 @@ func registerAllCommands() {
 		WithPriority(16),
 	)
 
+	registerSTTCommand(
+		"descend in [the] hold",
+		func() string { return "DIH" },
+		WithName("descend_in_hold"),
+		WithPriority(16),
+	)
+
 	// "arrival" is often used as a synonym for "star" in ATC phraseology
 	registerSTTCommand(
 		"descend via [the] arrival",
@@ -274,6 +289,28 @@ func registerAllCommands() {
 		WithPriority(7),
 	)
 
+	// Breakouts from the final monitor controller on simultaneous
+	// approaches: "traffic alert, turn left immediately heading 270,
+	// climb and maintain 3000". "Immediately" right after the direction
+	// distinguishes them from regular turns.
+	for _, turn := range []struct{ dir, cmd string }{{"left", "BL"}, {"right", "BR"}} {
+		registerSTTCommand(
+			"[traffic] [alert] [turn] "+turn.dir+" immediately [heading] {heading}"+
+				" [climb [and] maintain {altitude_fl}] [descend [and] maintain {altitude_fl}]",
+			func(hdg int, climb, descend *int) string {
+				cmd := fmt.Sprintf("%s%03d", turn.cmd, hdg)
+				if climb != nil {
+					cmd += fmt.Sprintf("/C%d", *climb)
+				} else if descend != nil {
+					cmd += fmt.Sprintf("/D%d", *descend)
+				}
+				return cmd
+			},
+			WithName("breakout_"+turn.dir),
+			WithPriority(15),
+		)
+	}
+
 	// "fly heading 090" - specific command with "fly" before "heading"
 	registerSTTCommand(
 		"fly heading {heading}",
@@ -1441,7 +1478,7 @@ func registerAllCommands() {
 	)
 
 	registerSTTCommand(
-		"cleared [approach] [for] {approach}",
+		"cleared [approach] [for] {approach_maneuver}",
 		func(appr string) string { return fmt.Sprintf("C%s", appr) },
 		WithName("cleared_approach"),
 		WithPriority(13),
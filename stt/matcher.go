@@ -248,7 +248,11 @@ func (m *optionalGroupMatcher) match(tokens []Token, pos int, ac Aircraft, skipW
 			// Group failed - return no match but don't fail the overall parse
 			return matchResult{consumed: 0}
 		}
-		values = append(values, res.value)
+		// Only typed parameters become handler arguments; keywords in the
+		// group don't.
+		if _, ok := inner.(*typedMatcher); ok {
+			values = append(values, res.value)
+		}
 		pos = res.consumed
 	}
 
@@ -399,7 +399,7 @@ func coalesceAfterFixAltitudes(commands []string) []string {
 }
 
 func extractPlainCrossFixTarget(cmd string) string {
-	if !strings.HasPrefix(cmd, "C") {
+	if !strings.HasPrefix(cmd, "C") || isManeuveringApproachClearance(cmd) {
 		return ""
 	}
 	parts := strings.Split(cmd, "/")
@@ -511,6 +511,13 @@ func (p *Transcriber) BuildAircraftContext(
 					}
 				}
 			}
+			// Any of the airport's runways may be given for a circling
+			// approach or a sidestep.
+			if faa, ok := av.DB.Airports[trk.ArrivalAirport]; ok {
+				for _, rwy := range faa.Runways {
+					sttAc.Runways = append(sttAc.Runways, rwy.Id)
+				}
+			}
 		}
 
 		// Key by telephony (spoken callsign). Use the true CWT category
@@ -1420,7 +1420,7 @@ func TestNavigationCommands(t *testing.T) {
 			expected: "UAL300 HBETTE A60",
 		},
 		{
-			name:       "hold with expect further clearance ignored",
+			name:       "hold with expect further clearance",
 			transcript: "Southwest 400 hold at MERIT as published expect further clearance 1 2 3 0",
 			aircraft: map[string]Aircraft{
 				"Southwest 400": {
@@ -1430,7 +1430,33 @@ func TestNavigationCommands(t *testing.T) {
 					Fixes:    map[string]string{"MERIT": "MERIT"},
 				},
 			},
-			expected: "SWA400 HMERIT",
+			expected: "SWA400 HMERIT EFC1230",
+		},
+		{
+			name:       "controller specified hold with expect further clearance at",
+			transcript: "JetBlue 600 hold west of MERIT on the 280 radial 2 minute legs left turns expect further clearance at 0 9 4 5",
+			aircraft: map[string]Aircraft{
+				"JetBlue 600": {
+					Callsign: "JBU600",
+					Altitude: 8000,
+					State:    "arrival",
+					Fixes:    map[string]string{"MERIT": "MERIT"},
+				},
+			},
+			expected: "JBU600 HMERIT/R280/2M/L EFC0945",
+		},
+		{
+			name:       "descend in the hold",
+			transcript: "Southwest 400 descend in the hold",
+			aircraft: map[string]Aircraft{
+				"Southwest 400": {
+					Callsign: "SWA400",
+					Altitude: 8000,
+					State:    "arrival",
+					Fixes:    map[string]string{"MERIT": "MERIT"},
+				},
+			},
+			expected: "SWA400 DIH",
 		},
 		{
 			name:       "hold controller specified with radial and turns",
@@ -4515,6 +4541,114 @@ func TestAssignedApproachPreference(t *testing.T) {
 	}
 }
 
+func TestApproachManeuverClearance(t *testing.T) {
+	tests := []struct {
+		name       string
+		transcript string
+		expected   string
+	}{
+		{
+			name:       "circle to land",
+			transcript: "Delta 456 cleared ILS runway four right approach circle to land runway three one left",
+			expected:   "DAL456 CI4R/CIR31L",
+		},
+		{
+			name:       "sidestep",
+			transcript: "Delta 456 cleared ILS runway two two left approach sidestep runway two two right",
+			expected:   "DAL456 CI22L/SS22R",
+		},
+		{
+			name:       "sidestep then altitude",
+			transcript: "Delta 456 cleared ILS runway two two left approach side step to runway two two right maintain two thousand until established",
+			expected:   "DAL456 CI22L/SS22R A20",
+		},
+		{
+			name:       "straight in",
+			transcript: "Delta 456 cleared ILS runway four right approach",
+			expected:   "DAL456 CI4R",
+		},
+	}
+
+	aircraft := map[string]Aircraft{
+		"Delta 456": {
+			Callsign: "DAL456",
+			State:    "arrival",
+			CandidateApproaches: map[string]string{
+				"i l s runway four right":   "I4R",
+				"i l s runway two two left": "I22L",
+			},
+			Runways: []string{"4L", "4R", "22L", "22R", "13L", "13R", "31L", "31R"},
+		},
+	}
+
+	provider := NewTranscriber(nil)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := provider.DecodeTranscript(aircraft, tt.transcript, "")
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+			if result != tt.expected {
+				t.Errorf("got %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestBreakout(t *testing.T) {
+	tests := []struct {
+		name       string
+		transcript string
+		expected   string
+	}{
+		{
+			name:       "traffic alert with climb",
+			transcript: "Delta 456 traffic alert turn left immediately heading two seven zero climb and maintain three thousand",
+			expected:   "DAL456 BL270/C30",
+		},
+		{
+			name:       "traffic alert before callsign",
+			transcript: "traffic alert Delta 456 turn right immediately heading zero niner zero descend and maintain two thousand",
+			expected:   "DAL456 BR090/D20",
+		},
+		{
+			name:       "immediately without altitude",
+			transcript: "Delta 456 turn left immediately heading two four zero",
+			expected:   "DAL456 BL240",
+		},
+		{
+			name:       "regular turn",
+			transcript: "Delta 456 turn left heading two four zero",
+			expected:   "DAL456 L240",
+		},
+	}
+
+	aircraft := map[string]Aircraft{
+		"Delta 456": {
+			Callsign: "DAL456",
+			State:    "arrival",
+			Altitude: 2500,
+		},
+	}
+
+	provider := NewTranscriber(nil)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := provider.DecodeTranscript(aircraft, tt.transcript, "")
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+			if result != tt.expected {
+				t.Errorf("got %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
 func TestDetectNegativeThatWasFor(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -492,7 +492,8 @@ func (p *fixParser) parse(tokens []Token, pos int, ac Aircraft) (any, int, strin
 
 // approachParser extracts approach names.
 type approachParser struct {
-	allowLAHSO bool
+	allowLAHSO    bool
+	allowManeuver bool // circle to land / sidestep after the approach
 }
 
 func (p *approachParser) goType() reflect.Type {
@@ -518,6 +519,12 @@ func (p *approachParser) parse(tokens []Token, pos int, ac Aircraft) (any, int,
 		}
 	}
 
+	if p.allowManeuver && pos+consumed < len(tokens) {
+		if suffix, mConsumed := extractApproachManeuver(tokens[pos+consumed:], ac.Runways); mConsumed > 0 {
+			return appr + suffix, consumed + mConsumed, ""
+		}
+	}
+
 	return appr, consumed, ""
 }
 
@@ -591,6 +598,24 @@ func (p *squawkParser) parse(tokens []Token, pos int, ac Aircraft) (any, int, st
 	return nil, 0, "SQUAWK"
 }
 
+// efcTimeParser extracts an expect-further-clearance time as an HHMM int.
+type efcTimeParser struct{}
+
+func (p *efcTimeParser) goType() reflect.Type {
+	return reflect.TypeOf(0)
+}
+
+func (p *efcTimeParser) parse(tokens []Token, pos int, ac Aircraft) (any, int, string) {
+	if pos >= len(tokens) {
+		return nil, 0, ""
+	}
+
+	if hhmm, consumed := extractEFCTime(tokens[pos:]); consumed > 0 {
+		return hhmm, consumed, ""
+	}
+	return nil, 0, ""
+}
+
 // degreesParser extracts turn degrees (1-45) with direction.
 type degreesParser struct{}
 
@@ -1072,12 +1097,16 @@ func getTypeParser(typeID string) typeParser {
 		return &approachParser{}
 	case "approach_lahso":
 		return &approachParser{allowLAHSO: true}
+	case "approach_maneuver":
+		return &approachParser{allowManeuver: true}
 	case "visual_approach_lahso":
 		return &visualApproachParser{allowLAHSO: true}
 	case "squawk":
 		return &squawkParser{}
 	case "degrees":
 		return &degreesParser{}
+	case "efc_time":
+		return &efcTimeParser{}
 	case "sid":
 		return &sidParser{}
 	case "star":
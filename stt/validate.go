@@ -348,7 +348,7 @@ func filterIncompatibleCommands(commands []string) ([]string, []string) {
 	for _, cmd := range commands {
 		if len(cmd) > 1 && cmd[0] == 'C' && cmd != "CVS" && cmd != "CAC" && !IsNumber(cmd[1:]) {
 			// Check it's not a cross-fix command (contains /)
-			if !strings.Contains(cmd, "/") {
+			if !strings.Contains(cmd, "/") || isManeuveringApproachClearance(cmd) {
 				hasApproachClearance = true
 				break
 			}
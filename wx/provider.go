@@ -11,6 +11,7 @@ import (
 	"net"
 	"net/rpc"
 	"os"
+	"sync/atomic"
 	"time"
 
 	av "github.com/mmp/vice/aviation"
@@ -29,6 +30,8 @@ type Provider struct {
 	backend        weatherBackend
 	resources      *resourcesBackend
 	atmosGridCache *expirable.LRU[atmosGridCacheKey, atmosGridResult]
+
+	atmosGridCacheHits, atmosGridCacheMisses atomic.Int64
 }
 
 // weatherBackend is the package-local abstraction for some of the mechanics of
@@ -118,8 +121,10 @@ func (p *Provider) GetPrecipURL(facility string, t time.Time) (string, time.Time
 // fallback grid from the primary airport's METAR wind data.
 func (p *Provider) GetAtmosGrid(facility string, t time.Time, primaryAirport string) (*AtmosByPointSOA, time.Time, time.Time, error) {
 	if ar, ok := p.lookupAtmosGridCache(facility, t, primaryAirport); ok {
+		p.atmosGridCacheHits.Add(1)
 		return ar.atmos, ar.time, ar.nextTime, nil
 	}
+	p.atmosGridCacheMisses.Add(1)
 
 	ar := p.getAtmosGridFromBackend(facility, t, primaryAirport)
 	if ar.err != nil && p.backend != p.resources {
@@ -133,6 +138,12 @@ func (p *Provider) GetAtmosGrid(facility string, t time.Time, primaryAirport str
 	return ar.atmos, ar.time, ar.nextTime, ar.err
 }
 
+// AtmosGridCacheStats returns the number of GetAtmosGrid calls that were
+// and weren't serviced from the provider's cache.
+func (p *Provider) AtmosGridCacheStats() (hits, misses int64) {
+	return p.atmosGridCacheHits.Load(), p.atmosGridCacheMisses.Load()
+}
+
 func (p *Provider) lookupAtmosGridCache(facility string, t time.Time, primaryAirport string) (atmosGridResult, bool) {
 	if p.atmosGridCache == nil {
 		return atmosGridResult{}, false